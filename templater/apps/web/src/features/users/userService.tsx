@@ -9,13 +9,20 @@ export interface User {
     created_at: string;
 }
 
+export interface UserPage {
+    users: User[];
+    next_cursor?: string;
+    total?: number;
+}
+
 export const userApi = {
     async getAllUsers(): Promise<User[]> {
         const response = await fetch(`${API_URL}/users`, {
             method: 'GET',
             headers: getAuthHeaders(),
         });
-        return handleResponse<User[]>(response);
+        const page = await handleResponse<UserPage>(response);
+        return page.users;
     },
 
     async updatePassword(id: string, newPassword: string): Promise<void> {
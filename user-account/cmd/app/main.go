@@ -2,18 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/hkdf"
+	"crypto/sha256"
 	"errors"
+	"expvar"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 	"user-account/cmd/internal/config"
 	"user-account/cmd/internal/handler"
+	"user-account/cmd/internal/keyring"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/passhash"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/ratelimit"
 	"user-account/cmd/internal/repository"
 	router "user-account/cmd/internal/server"
 	"user-account/cmd/internal/service"
+	"user-account/cmd/internal/signedtoken"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -25,20 +37,211 @@ func main() {
 	}
 	defer dbPool.Close()
 
+	keys, err := keyring.New(keyring.Options{
+		Algorithm: cfg.JWTSigningAlg,
+		Secret:    cfg.JWTSecret,
+		Dir:       cfg.JWTKeysDir,
+		// Выведенный ключ нужен, пока не истекли подписанные им токены,
+		// включая токены OAuth со своим сроком жизни
+		Overlap: max(cfg.AccessTokenTTL, cfg.OAuthAccessTTL),
+	})
+	if err != nil {
+		log.Fatalf("failed to load jwt keys: %v", err)
+	}
+	if cfg.JWTKeyRotation > 0 {
+		go keys.RunRotation(context.Background(), cfg.JWTKeyRotation)
+	}
+
 	userRepo := repository.NewPostgresUserRepository(dbPool)
+	sessionRepo := repository.NewPostgresSessionRepository(dbPool)
+	revokedTokenRepo := repository.NewPostgresRevokedTokenRepository(dbPool)
+	roleRepo := repository.NewPostgresRoleRepository(dbPool)
+	emailVerificationRepo := repository.NewPostgresEmailVerificationRepository(dbPool)
+	passwordResetRepo := repository.NewPostgresPasswordResetRepository(dbPool)
+	totpRepo := repository.NewPostgresTOTPRepository(dbPool)
+	loginAttemptRepo := repository.NewPostgresLoginAttemptRepository(dbPool)
+	apiKeyRepo := repository.NewPostgresAPIKeyRepository(dbPool)
+	identityRepo := repository.NewPostgresIdentityRepository(dbPool)
+	oidcStateRepo := repository.NewPostgresOIDCStateRepository(dbPool)
+	oauthClientRepo := repository.NewPostgresOAuthClientRepository(dbPool)
+	oauthCodeRepo := repository.NewPostgresOAuthCodeRepository(dbPool)
+	organizationRepo := repository.NewPostgresOrganizationRepository(dbPool)
+	invitationRepo := repository.NewPostgresInvitationRepository(dbPool)
+	userStatusRepo := repository.NewPostgresUserStatusRepository(dbPool)
+	dataExportRepo := repository.NewPostgresDataExportRepository(dbPool)
+	personalDataRepo := repository.NewPostgresPersonalDataRepository(dbPool)
+	auditEventRepo := repository.NewPostgresAuditEventRepository(dbPool)
+	if cfg.LoginAttemptStore == "memory" {
+		loginAttemptRepo = repository.NewMemoryLoginAttemptRepository()
+	}
 
-	authService := service.NewAuthService(userRepo, cfg.JWTSecret)
-	userService := service.NewUserService(userRepo)
+	var mail mailer.Mailer = mailer.NewLogMailer(nil)
+	if cfg.Mailer == "smtp" {
+		mail = mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	}
+	actionTokens := signedtoken.New(cfg.ActionTokenSecret)
+	passwords := passhash.New(passhash.Params{
+		Memory:      uint32(cfg.PasswordArgon2Memory),
+		Iterations:  uint32(cfg.PasswordArgon2Iterations),
+		Parallelism: uint8(cfg.PasswordArgon2Parallelism),
+		SaltLength:  passhash.DefaultParams.SaltLength,
+		KeyLength:   passhash.DefaultParams.KeyLength,
+	}, []byte(cfg.PasswordPepper))
+	var breaches passpolicy.BreachChecker
+	if cfg.PasswordBreachedCorpus != "" {
+		corpus, err := passpolicy.NewDirCorpus(cfg.PasswordBreachedCorpus)
+		if err != nil {
+			log.Fatalf("failed to open breached password corpus: %v", err)
+		}
+		breaches = corpus
+	}
+	passwordPolicy := passpolicy.New(passpolicy.Config{
+		MinLength:      cfg.PasswordMinLength,
+		MaxLength:      cfg.PasswordMaxLength,
+		MinCharClasses: cfg.PasswordMinCharClasses,
+		ForbidPersonal: cfg.PasswordForbidPersonal,
+	}, breaches)
+
+	// Адреса неизвестных аккаунтов в журнале хэшируются отдельным ключом,
+	// выведенным из секрета ссылок из писем: один ключ не служит двум целям
+	auditDigestKey, err := hkdf.Key(sha256.New, []byte(cfg.ActionTokenSecret), nil, "audit-email", sha256.Size)
+	if err != nil {
+		log.Fatalf("failed to derive audit digest key: %v", err)
+	}
+	auditLog := service.NewAuditLog(auditEventRepo, auditDigestKey)
+	expvar.Publish("audit_write_failures", expvar.Func(func() interface{} { return auditLog.WriteFailures() }))
+	revocationService := service.NewRevocationService(revokedTokenRepo, sessionRepo, cfg.RevocationCacheTTL, cfg.AccessTokenTTL)
+	// Отзыв нужен, только пока токен не истёк, поэтому чистить чаще срока
+	// жизни access-токена незачем
+	go revocationService.Run(context.Background(), cfg.AccessTokenTTL)
+	verificationService := service.NewEmailVerificationService(userRepo, emailVerificationRepo, actionTokens, mail, cfg.EmailVerificationTTL, cfg.AppPublicURL)
+	loginGuard := service.NewLoginGuard(loginAttemptRepo, service.LockoutConfig{
+		AccountThreshold: cfg.LoginAccountMaxFailures,
+		IPThreshold:      cfg.LoginIPMaxFailures,
+		BaseLockout:      cfg.LoginLockoutBase,
+		MaxLockout:       cfg.LoginLockoutMax,
+		FailureWindow:    cfg.LoginFailureWindow,
+	})
+	// Счётчик старше окна уже ничего не решает
+	go loginGuard.Run(context.Background(), cfg.LoginFailureWindow)
+	twoFactorService := service.NewTwoFactorService(totpRepo, userRepo, actionTokens, loginGuard, service.TwoFactorConfig{
+		Issuer:       cfg.TOTPIssuer,
+		ChallengeTTL: cfg.MFAChallengeTTL,
+	})
+	authService := service.NewAuthService(userRepo, sessionRepo, organizationRepo, revocationService, verificationService, twoFactorService, loginGuard, passwords, passwordPolicy, keys, auditLog, service.AuthConfig{
+		AccessTTL:            cfg.AccessTokenTTL,
+		RefreshTTL:           cfg.RefreshTokenTTL,
+		RequireVerifiedEmail: cfg.EmailVerificationRequired,
+	})
+	passwordResetService := service.NewPasswordResetService(userRepo, passwordResetRepo, revocationService, mail, passwords, passwordPolicy, auditLog, service.PasswordResetConfig{
+		TTL:             cfg.PasswordResetTTL,
+		PublicURL:       cfg.AppPublicURL,
+		MinResponseTime: cfg.PasswordForgotMinResponse,
+	})
+	userService := service.NewUserService(userRepo, revocationService, loginGuard, passwords, passwordPolicy, auditLog)
+	go service.NewUserPurger(userRepo, cfg.DeletedUserRetention).Run(context.Background(), cfg.UserPurgeInterval)
+	userImportService := service.NewUserImportService(userRepo, verificationService, passwords, passwordPolicy)
+	userStatusService := service.NewUserStatusService(userStatusRepo, userRepo, revocationService, auditLog, cfg.RevocationCacheTTL)
+	personalDataService := service.NewPersonalDataService(dataExportRepo, personalDataRepo, userService, revocationService, loginGuard, auditLog)
+	go service.NewDataExporter(dataExportRepo, personalDataRepo, cfg.DataExportTTL).Run(context.Background(), cfg.DataExportPollInterval)
+	rbacService := service.NewRBACService(roleRepo, userRepo, auditLog, cfg.PermissionCacheTTL)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, organizationRepo, rbacService, service.APIKeyConfig{
+		DefaultTTL: cfg.APIKeyDefaultTTL,
+		MaxTTL:     cfg.APIKeyMaxTTL,
+	})
+	identityProviders := make(map[string]service.IdentityProvider, len(cfg.OIDCProviders))
+	for _, provider := range cfg.OIDCProviders {
+		identityProviders[provider.Name] = oidc.NewProvider(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			Scopes:       provider.Scopes,
+		}, nil)
+	}
+	oidcService := service.NewOIDCService(identityProviders, oidcStateRepo, identityRepo, userRepo, authService, passwords, service.OIDCConfig{
+		RedirectURL: cfg.OIDCRedirectURL,
+		StateTTL:    cfg.OIDCStateTTL,
+	})
+	oauthService := service.NewOAuthService(oauthClientRepo, oauthCodeRepo, userRepo, revocationService, userStatusService, keys, service.OAuthConfig{
+		Issuer:           cfg.OAuthIssuer,
+		AuthorizationURL: cfg.OAuthAuthorizeURL,
+		AccessTTL:        cfg.OAuthAccessTTL,
+		CodeTTL:          cfg.OAuthCodeTTL,
+	})
+	invitationService := service.NewInvitationService(invitationRepo, organizationRepo, userRepo, actionTokens, mail, passwords, passwordPolicy, cfg.InvitationTTL, cfg.AppPublicURL)
+	organizationService := service.NewOrganizationService(organizationRepo, invitationService)
 
 	healthHandler := handler.NewHealthHandler()
 	authHandler := handler.NewAuthHandler(authService)
+	verificationHandler := handler.NewVerificationHandler(verificationService)
+	passwordHandler := handler.NewPasswordResetHandler(passwordResetService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
 	userHandler := handler.NewUserHandler(userService)
+	roleHandler := handler.NewRoleHandler(rbacService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	jwksHandler := handler.NewJWKSHandler(keys)
+	oidcHandler := handler.NewOIDCHandler(oidcService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	userStatusHandler := handler.NewUserStatusHandler(userStatusService)
+	userImportHandler := handler.NewUserImportHandler(userImportService)
+	personalDataHandler := handler.NewPersonalDataHandler(personalDataService)
+	auditHandler := handler.NewAuditHandler(auditLog)
+
+	var limiter middleware.RateLimiter
+	switch cfg.RateLimitBackend {
+	case "memory":
+		limiter = ratelimit.NewMemoryLimiter()
+	case "redis":
+		redisOpts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("invalid REDIS_URL: %v", err)
+		}
+		redisClient := redis.NewClient(redisOpts)
+		defer redisClient.Close()
+		limiter = ratelimit.NewRedisLimiter(redisClient)
+	}
 
 	mux := router.NewRouter(
-		healthHandler,
-		authHandler,
-		userHandler,
-		cfg.JWTSecret,
+		router.Handlers{
+			Health:       healthHandler,
+			Auth:         authHandler,
+			Verification: verificationHandler,
+			Password:     passwordHandler,
+			User:         userHandler,
+			TwoFactor:    twoFactorHandler,
+			Role:         roleHandler,
+			APIKey:       apiKeyHandler,
+			JWKS:         jwksHandler,
+			OIDC:         oidcHandler,
+			OAuth:        oauthHandler,
+			Organization: organizationHandler,
+			Invitation:   invitationHandler,
+			UserStatus:   userStatusHandler,
+			UserImport:   userImportHandler,
+			PersonalData: personalDataHandler,
+			Audit:        auditHandler,
+		},
+		router.Security{
+			Keyfunc:     keys.Keyfunc,
+			Revocations: revocationService,
+			Permissions: rbacService,
+			APIKeys:     apiKeyService,
+			Members:     organizationService,
+			Statuses:    userStatusService,
+		},
+		router.RateLimits{
+			Limiter: limiter,
+			Auth:    cfg.RateLimitAuth,
+			API:     cfg.RateLimitAPI,
+		},
 		cfg.CORSAllowedOrigins,
 	)
 
@@ -2,9 +2,46 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"user-account/cmd/internal/passhash"
+	"user-account/cmd/internal/ratelimit"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultRevocationCache = 10 * time.Second
+	defaultPermissionCache = 10 * time.Second
+	defaultEmailVerifyTTL  = 24 * time.Hour
+	defaultPasswordReset   = time.Hour
+	defaultInvitationTTL   = 7 * 24 * time.Hour
+	defaultUserRetention   = 30 * 24 * time.Hour
+	defaultUserPurgeEvery  = time.Hour
+	defaultDataExportTTL   = 7 * 24 * time.Hour
+	defaultDataExportEvery = time.Minute
+	defaultForgotResponse  = 500 * time.Millisecond
+	defaultMFAChallengeTTL = 5 * time.Minute
+	defaultLockoutBase     = time.Minute
+	defaultLockoutMax      = time.Hour
+	defaultFailureWindow   = time.Hour
+	defaultAPIKeyTTL       = 90 * 24 * time.Hour
+	defaultAPIKeyMaxTTL    = 365 * 24 * time.Hour
+	defaultOIDCStateTTL    = 10 * time.Minute
+	defaultOAuthAccessTTL  = 15 * time.Minute
+	defaultOAuthCodeTTL    = time.Minute
+)
+
+// oidcProviderName - имя провайдера входит в имена переменных окружения
+var oidcProviderName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+var (
+	defaultRateLimitAuth = ratelimit.Rate{Limit: 10, Period: time.Minute}
+	defaultRateLimitAPI  = ratelimit.Rate{Limit: 300, Period: time.Minute}
 )
 
 // Config - модель конфига
@@ -18,11 +55,94 @@ type Config struct {
 	DBName             string
 	DBUrl              string
 	JWTSecret          string
+	JWTSigningAlg      string
+	JWTKeysDir         string
+	JWTKeyRotation     time.Duration
+	AccessTokenTTL     time.Duration
+	RefreshTokenTTL    time.Duration
+	RevocationCacheTTL time.Duration
+	PermissionCacheTTL time.Duration
 	CORSAllowedOrigins []string
+
+	AppPublicURL              string
+	ActionTokenSecret         string
+	EmailVerificationRequired bool
+	EmailVerificationTTL      time.Duration
+	PasswordResetTTL          time.Duration
+	InvitationTTL             time.Duration
+	DeletedUserRetention      time.Duration
+	UserPurgeInterval         time.Duration
+	// DataExportTTL - сколько хранится архив выгрузки персональных данных
+	DataExportTTL             time.Duration
+	DataExportPollInterval    time.Duration
+	PasswordForgotMinResponse time.Duration
+	TOTPIssuer                string
+	MFAChallengeTTL           time.Duration
+
+	LoginAccountMaxFailures int
+	LoginIPMaxFailures      int
+	LoginLockoutBase        time.Duration
+	LoginLockoutMax         time.Duration
+	LoginFailureWindow      time.Duration
+	LoginAttemptStore       string
+
+	RateLimitBackend string
+	RateLimitAuth    ratelimit.Rate
+	RateLimitAPI     ratelimit.Rate
+	RedisURL         string
+
+	APIKeyDefaultTTL time.Duration
+	APIKeyMaxTTL     time.Duration
+
+	// PasswordArgon2Memory - память argon2id на один хэш, КиБ
+	PasswordArgon2Memory      int
+	PasswordArgon2Iterations  int
+	PasswordArgon2Parallelism int
+	PasswordPepper            string
+
+	PasswordMinLength      int
+	PasswordMaxLength      int
+	PasswordMinCharClasses int
+	PasswordForbidPersonal bool
+	// PasswordBreachedCorpus - каталог с базой утёкших паролей, пусто - без проверки
+	PasswordBreachedCorpus string
+
+	// OIDCProviders - провайдеры входа OpenID Connect из OIDC_PROVIDERS
+	OIDCProviders []OIDCProviderConfig
+	// OIDCRedirectURL - страница фронтенда, куда провайдеры возвращают пользователя
+	OIDCRedirectURL string
+	OIDCStateTTL    time.Duration
+
+	// OAuthIssuer - публичный адрес сервиса как сервера авторизации OAuth2
+	OAuthIssuer string
+	// OAuthAuthorizeURL - страница согласия на фронтенде
+	OAuthAuthorizeURL string
+	OAuthAccessTTL    time.Duration
+	OAuthCodeTTL      time.Duration
+
+	Mailer       string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+}
+
+// OIDCProviderConfig - клиент одного провайдера OpenID Connect. Настройки
+// читаются из OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET и OIDC_<NAME>_SCOPES.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
 }
 
 // Load - прочитать из файла конфигурации
 func Load() *Config {
+	p := &envParser{}
+
 	cfg := &Config{
 		AppHost:            getEnv("APP_HOST"),
 		AppPort:            getEnv("APP_PORT"),
@@ -32,10 +152,83 @@ func Load() *Config {
 		DBPassword:         getEnv("DB_PASSWORD"),
 		DBName:             getEnv("DB_NAME"),
 		JWTSecret:          getEnv("JWT_SECRET"),
+		JWTSigningAlg:      getEnvDefault("JWT_SIGNING_ALG", "HS256"),
+		JWTKeysDir:         getEnv("JWT_KEYS_DIR"),
+		JWTKeyRotation:     p.duration("JWT_KEY_ROTATION_INTERVAL", 0),
+		AccessTokenTTL:     p.duration("JWT_ACCESS_TTL", defaultAccessTokenTTL),
+		RefreshTokenTTL:    p.duration("JWT_REFRESH_TTL", defaultRefreshTokenTTL),
+		RevocationCacheTTL: p.duration("TOKEN_REVOCATION_CACHE_TTL", defaultRevocationCache),
+		PermissionCacheTTL: p.duration("PERMISSION_CACHE_TTL", defaultPermissionCache),
 		CORSAllowedOrigins: parseCommaSeparatedList(getEnv("CORS_ALLOWED_ORIGINS")),
+
+		AppPublicURL:              getEnvDefault("APP_PUBLIC_URL", "http://localhost:5173"),
+		ActionTokenSecret:         getEnv("ACTION_TOKEN_SECRET"),
+		EmailVerificationRequired: p.bool("EMAIL_VERIFICATION_REQUIRED", false),
+		EmailVerificationTTL:      p.duration("EMAIL_VERIFICATION_TTL", defaultEmailVerifyTTL),
+		PasswordResetTTL:          p.duration("PASSWORD_RESET_TTL", defaultPasswordReset),
+		InvitationTTL:             p.duration("INVITATION_TTL", defaultInvitationTTL),
+		DeletedUserRetention:      p.duration("DELETED_USER_RETENTION", defaultUserRetention),
+		UserPurgeInterval:         p.duration("USER_PURGE_INTERVAL", defaultUserPurgeEvery),
+		DataExportTTL:             p.duration("DATA_EXPORT_TTL", defaultDataExportTTL),
+		DataExportPollInterval:    p.duration("DATA_EXPORT_POLL_INTERVAL", defaultDataExportEvery),
+		PasswordForgotMinResponse: p.duration("PASSWORD_FORGOT_MIN_RESPONSE", defaultForgotResponse),
+		TOTPIssuer:                getEnvDefault("TOTP_ISSUER", "Actium"),
+		MFAChallengeTTL:           p.duration("MFA_CHALLENGE_TTL", defaultMFAChallengeTTL),
+
+		LoginAccountMaxFailures: p.int("LOGIN_ACCOUNT_MAX_FAILURES", 5),
+		LoginIPMaxFailures:      p.int("LOGIN_IP_MAX_FAILURES", 50),
+		LoginLockoutBase:        p.duration("LOGIN_LOCKOUT_BASE", defaultLockoutBase),
+		LoginLockoutMax:         p.duration("LOGIN_LOCKOUT_MAX", defaultLockoutMax),
+		LoginFailureWindow:      p.duration("LOGIN_FAILURE_WINDOW", defaultFailureWindow),
+		LoginAttemptStore:       getEnvDefault("LOGIN_ATTEMPT_STORE", "postgres"),
+
+		RateLimitBackend: getEnvDefault("RATE_LIMIT_BACKEND", "memory"),
+		RateLimitAuth:    p.rate("RATE_LIMIT_AUTH", defaultRateLimitAuth),
+		RateLimitAPI:     p.rate("RATE_LIMIT_API", defaultRateLimitAPI),
+		RedisURL:         getEnv("REDIS_URL"),
+
+		APIKeyDefaultTTL: p.duration("API_KEY_DEFAULT_TTL", defaultAPIKeyTTL),
+		APIKeyMaxTTL:     p.duration("API_KEY_MAX_TTL", defaultAPIKeyMaxTTL),
+
+		PasswordArgon2Memory:      p.int("PASSWORD_ARGON2_MEMORY", int(passhash.DefaultParams.Memory)),
+		PasswordArgon2Iterations:  p.int("PASSWORD_ARGON2_ITERATIONS", int(passhash.DefaultParams.Iterations)),
+		PasswordArgon2Parallelism: p.int("PASSWORD_ARGON2_PARALLELISM", int(passhash.DefaultParams.Parallelism)),
+		PasswordPepper:            getEnv("PASSWORD_PEPPER"),
+
+		PasswordMinLength:      p.int("PASSWORD_MIN_LENGTH", 8),
+		PasswordMaxLength:      p.int("PASSWORD_MAX_LENGTH", 128),
+		PasswordMinCharClasses: p.int("PASSWORD_MIN_CHAR_CLASSES", 1),
+		PasswordForbidPersonal: p.bool("PASSWORD_FORBID_PERSONAL", true),
+		PasswordBreachedCorpus: getEnv("PASSWORD_BREACHED_CORPUS_DIR"),
+
+		OIDCProviders:   loadOIDCProviders(),
+		OIDCRedirectURL: getEnv("OIDC_REDIRECT_URL"),
+		OIDCStateTTL:    p.duration("OIDC_STATE_TTL", defaultOIDCStateTTL),
+
+		OAuthIssuer:       getEnv("OAUTH_ISSUER"),
+		OAuthAuthorizeURL: getEnv("OAUTH_AUTHORIZE_URL"),
+		OAuthAccessTTL:    p.duration("OAUTH_ACCESS_TTL", defaultOAuthAccessTTL),
+		OAuthCodeTTL:      p.duration("OAUTH_CODE_TTL", defaultOAuthCodeTTL),
+
+		Mailer:       getEnvDefault("MAILER", "log"),
+		SMTPHost:     getEnv("SMTP_HOST"),
+		SMTPPort:     getEnvDefault("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME"),
+		SMTPPassword: getEnv("SMTP_PASSWORD"),
+		SMTPFrom:     getEnv("SMTP_FROM"),
+	}
+
+	if cfg.OIDCRedirectURL == "" {
+		cfg.OIDCRedirectURL = strings.TrimSuffix(cfg.AppPublicURL, "/") + "/oauth/callback"
+	}
+	if cfg.OAuthIssuer == "" {
+		cfg.OAuthIssuer = "http://localhost:" + cfg.AppPort
+	}
+	if cfg.OAuthAuthorizeURL == "" {
+		cfg.OAuthAuthorizeURL = strings.TrimSuffix(cfg.AppPublicURL, "/") + "/oauth/authorize"
 	}
 
-	if errs := cfg.Validate(); len(errs) > 0 {
+	if errs := append(p.errs, cfg.Validate()...); len(errs) > 0 {
 		panic(formatErrors(errs))
 	}
 
@@ -75,12 +268,152 @@ func (c *Config) Validate() []error {
 	if c.DBName == "" {
 		errs = append(errs, fmt.Errorf("DB_NAME is required"))
 	}
-	if c.JWTSecret == "" {
-		errs = append(errs, fmt.Errorf("JWT_SECRET is required"))
+	switch c.JWTSigningAlg {
+	case "HS256":
+		if c.JWTSecret == "" {
+			errs = append(errs, fmt.Errorf("JWT_SECRET is required"))
+		}
+		if c.JWTKeyRotation > 0 {
+			errs = append(errs, fmt.Errorf("JWT_KEY_ROTATION_INTERVAL requires JWT_SIGNING_ALG=RS256 or EdDSA"))
+		}
+	case "RS256", "EdDSA":
+		if c.JWTKeyRotation < 0 {
+			errs = append(errs, fmt.Errorf("JWT_KEY_ROTATION_INTERVAL must not be negative"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("JWT_SIGNING_ALG must be one of HS256, RS256, EdDSA"))
+	}
+	if c.AccessTokenTTL <= 0 {
+		errs = append(errs, fmt.Errorf("JWT_ACCESS_TTL must be positive"))
+	}
+	if c.RefreshTokenTTL <= c.AccessTokenTTL {
+		errs = append(errs, fmt.Errorf("JWT_REFRESH_TTL must be longer than JWT_ACCESS_TTL"))
+	}
+	if c.RevocationCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("TOKEN_REVOCATION_CACHE_TTL must not be negative"))
+	}
+	if c.PermissionCacheTTL < 0 {
+		errs = append(errs, fmt.Errorf("PERMISSION_CACHE_TTL must not be negative"))
 	}
 	if len(c.CORSAllowedOrigins) == 0 {
 		errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS is required (comma-separated, e.g. http://localhost:5173)"))
 	}
+	if c.ActionTokenSecret == "" {
+		errs = append(errs, fmt.Errorf("ACTION_TOKEN_SECRET is required"))
+	}
+	if c.EmailVerificationTTL <= 0 {
+		errs = append(errs, fmt.Errorf("EMAIL_VERIFICATION_TTL must be positive"))
+	}
+	if c.PasswordResetTTL <= 0 {
+		errs = append(errs, fmt.Errorf("PASSWORD_RESET_TTL must be positive"))
+	}
+	if c.InvitationTTL <= 0 {
+		errs = append(errs, fmt.Errorf("INVITATION_TTL must be positive"))
+	}
+	if c.DeletedUserRetention <= 0 {
+		errs = append(errs, fmt.Errorf("DELETED_USER_RETENTION must be positive"))
+	}
+	if c.UserPurgeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("USER_PURGE_INTERVAL must be positive"))
+	}
+	if c.DataExportTTL <= 0 {
+		errs = append(errs, fmt.Errorf("DATA_EXPORT_TTL must be positive"))
+	}
+	if c.DataExportPollInterval <= 0 {
+		errs = append(errs, fmt.Errorf("DATA_EXPORT_POLL_INTERVAL must be positive"))
+	}
+	if c.PasswordForgotMinResponse < 0 {
+		errs = append(errs, fmt.Errorf("PASSWORD_FORGOT_MIN_RESPONSE must not be negative"))
+	}
+	if c.MFAChallengeTTL <= 0 {
+		errs = append(errs, fmt.Errorf("MFA_CHALLENGE_TTL must be positive"))
+	}
+	if c.LoginAccountMaxFailures <= 0 {
+		errs = append(errs, fmt.Errorf("LOGIN_ACCOUNT_MAX_FAILURES must be positive"))
+	}
+	if c.LoginIPMaxFailures <= 0 {
+		errs = append(errs, fmt.Errorf("LOGIN_IP_MAX_FAILURES must be positive"))
+	}
+	if c.LoginLockoutBase <= 0 {
+		errs = append(errs, fmt.Errorf("LOGIN_LOCKOUT_BASE must be positive"))
+	}
+	if c.LoginLockoutMax < c.LoginLockoutBase {
+		errs = append(errs, fmt.Errorf("LOGIN_LOCKOUT_MAX must not be shorter than LOGIN_LOCKOUT_BASE"))
+	}
+	if c.LoginFailureWindow <= 0 {
+		errs = append(errs, fmt.Errorf("LOGIN_FAILURE_WINDOW must be positive"))
+	}
+	switch c.LoginAttemptStore {
+	case "postgres", "memory":
+	default:
+		errs = append(errs, fmt.Errorf("LOGIN_ATTEMPT_STORE must be one of postgres, memory"))
+	}
+	switch c.RateLimitBackend {
+	case "memory", "off":
+	case "redis":
+		if c.RedisURL == "" {
+			errs = append(errs, fmt.Errorf("REDIS_URL is required when RATE_LIMIT_BACKEND=redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_BACKEND must be one of memory, redis, off"))
+	}
+	if c.RateLimitBackend != "off" && (c.RateLimitAuth.Limit <= 0 || c.RateLimitAPI.Limit <= 0) {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_AUTH and RATE_LIMIT_API are required unless RATE_LIMIT_BACKEND=off"))
+	}
+	if c.APIKeyDefaultTTL <= 0 {
+		errs = append(errs, fmt.Errorf("API_KEY_DEFAULT_TTL must be positive"))
+	}
+	if c.APIKeyMaxTTL < c.APIKeyDefaultTTL {
+		errs = append(errs, fmt.Errorf("API_KEY_MAX_TTL must not be shorter than API_KEY_DEFAULT_TTL"))
+	}
+	if c.PasswordArgon2Iterations <= 0 {
+		errs = append(errs, fmt.Errorf("PASSWORD_ARGON2_ITERATIONS must be positive"))
+	}
+	if c.PasswordArgon2Parallelism <= 0 || c.PasswordArgon2Parallelism > 255 {
+		errs = append(errs, fmt.Errorf("PASSWORD_ARGON2_PARALLELISM must be between 1 and 255"))
+	}
+	// argon2 требует не меньше 8 КиБ на поток
+	if c.PasswordArgon2Memory < 8*max(c.PasswordArgon2Parallelism, 1) {
+		errs = append(errs, fmt.Errorf("PASSWORD_ARGON2_MEMORY must be at least 8 KiB per thread"))
+	}
+	if c.PasswordMinLength <= 0 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH must be positive"))
+	}
+	if c.PasswordMaxLength < c.PasswordMinLength {
+		errs = append(errs, fmt.Errorf("PASSWORD_MAX_LENGTH must not be less than PASSWORD_MIN_LENGTH"))
+	}
+	if c.PasswordMinCharClasses < 0 || c.PasswordMinCharClasses > 4 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_CHAR_CLASSES must be between 0 and 4"))
+	}
+	errs = append(errs, c.validateOIDC()...)
+	// iss сравнивается посимвольно, поэтому адрес должен быть точным
+	if !isHTTPURL(c.OAuthIssuer) || strings.HasSuffix(c.OAuthIssuer, "/") {
+		errs = append(errs, fmt.Errorf("OAUTH_ISSUER must be an http(s) URL without a trailing slash"))
+	}
+	if !isHTTPURL(c.OAuthAuthorizeURL) {
+		errs = append(errs, fmt.Errorf("OAUTH_AUTHORIZE_URL must be an http(s) URL"))
+	}
+	if c.OAuthAccessTTL <= 0 {
+		errs = append(errs, fmt.Errorf("OAUTH_ACCESS_TTL must be positive"))
+	}
+	if c.OAuthCodeTTL <= 0 {
+		errs = append(errs, fmt.Errorf("OAUTH_CODE_TTL must be positive"))
+	}
+	switch c.Mailer {
+	case "log":
+	case "smtp":
+		if c.SMTPHost == "" {
+			errs = append(errs, fmt.Errorf("SMTP_HOST is required when MAILER=smtp"))
+		}
+		if c.SMTPFrom == "" {
+			errs = append(errs, fmt.Errorf("SMTP_FROM is required when MAILER=smtp"))
+		}
+		if _, err := strconv.Atoi(c.SMTPPort); err != nil {
+			errs = append(errs, fmt.Errorf("SMTP_PORT must be a number"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("MAILER must be one of log, smtp"))
+	}
 
 	if c.AppPort != "" {
 		if _, err := strconv.Atoi(c.AppPort); err != nil {
@@ -97,10 +430,142 @@ func (c *Config) Validate() []error {
 	return errs
 }
 
+func (c *Config) validateOIDC() []error {
+	if len(c.OIDCProviders) == 0 {
+		return nil
+	}
+
+	var errs []error
+	seen := make(map[string]bool, len(c.OIDCProviders))
+	for _, provider := range c.OIDCProviders {
+		if !oidcProviderName.MatchString(provider.Name) {
+			errs = append(errs, fmt.Errorf("OIDC_PROVIDERS: %q must be lowercase letters, digits and underscores", provider.Name))
+			continue
+		}
+		if seen[provider.Name] {
+			errs = append(errs, fmt.Errorf("OIDC_PROVIDERS: %q is listed twice", provider.Name))
+			continue
+		}
+		seen[provider.Name] = true
+
+		prefix := oidcEnvPrefix(provider.Name)
+		if !isHTTPURL(provider.Issuer) {
+			errs = append(errs, fmt.Errorf("%sISSUER must be an http(s) URL", prefix))
+		}
+		if provider.ClientID == "" {
+			errs = append(errs, fmt.Errorf("%sCLIENT_ID is required", prefix))
+		}
+		if provider.ClientSecret == "" {
+			errs = append(errs, fmt.Errorf("%sCLIENT_SECRET is required", prefix))
+		}
+	}
+	if !isHTTPURL(c.OIDCRedirectURL) {
+		errs = append(errs, fmt.Errorf("OIDC_REDIRECT_URL must be an http(s) URL"))
+	}
+	if c.OIDCStateTTL <= 0 {
+		errs = append(errs, fmt.Errorf("OIDC_STATE_TTL must be positive"))
+	}
+	return errs
+}
+
+// loadOIDCProviders читает настройки провайдеров, перечисленных в OIDC_PROVIDERS
+func loadOIDCProviders() []OIDCProviderConfig {
+	names := parseCommaSeparatedList(getEnv("OIDC_PROVIDERS"))
+	providers := make([]OIDCProviderConfig, 0, len(names))
+	for _, name := range names {
+		prefix := oidcEnvPrefix(name)
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix + "ISSUER"),
+			ClientID:     getEnv(prefix + "CLIENT_ID"),
+			ClientSecret: getEnv(prefix + "CLIENT_SECRET"),
+			Scopes:       parseCommaSeparatedList(getEnv(prefix + "SCOPES")),
+		})
+	}
+	return providers
+}
+
+func oidcEnvPrefix(name string) string {
+	return "OIDC_" + strings.ToUpper(name) + "_"
+}
+
+func isHTTPURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
 func getEnv(key string) string {
 	return strings.TrimSpace(os.Getenv(key))
 }
 
+func getEnvDefault(key, fallback string) string {
+	if v := getEnv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envParser читает типизированные переменные окружения и копит ошибки разбора
+type envParser struct {
+	errs []error
+}
+
+// duration читает длительность в формате time.ParseDuration (например, 15m, 720h)
+func (p *envParser) duration(key string, fallback time.Duration) time.Duration {
+	raw := getEnv(key)
+	if raw == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be a duration (e.g. 15m, 720h)", key))
+		return fallback
+	}
+	return d
+}
+
+// bool читает флаг в формате strconv.ParseBool (true/false, 1/0)
+func (p *envParser) bool(key string, fallback bool) bool {
+	raw := getEnv(key)
+	if raw == "" {
+		return fallback
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be a boolean (true or false)", key))
+		return fallback
+	}
+	return b
+}
+
+// int читает целое число
+func (p *envParser) int(key string, fallback int) int {
+	raw := getEnv(key)
+	if raw == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be an integer", key))
+		return fallback
+	}
+	return n
+}
+
+// rate читает лимит в формате ratelimit.ParseRate (например, 10/1m)
+func (p *envParser) rate(key string, fallback ratelimit.Rate) ratelimit.Rate {
+	raw := getEnv(key)
+	if raw == "" {
+		return fallback
+	}
+	r, err := ratelimit.ParseRate(raw)
+	if err != nil {
+		p.errs = append(p.errs, fmt.Errorf("%s must be requests per period (e.g. 10/1m)", key))
+		return fallback
+	}
+	return r
+}
+
 func parseCommaSeparatedList(s string) []string {
 	if s == "" {
 		return nil
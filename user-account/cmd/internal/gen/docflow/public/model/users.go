@@ -13,10 +13,16 @@ import (
 )
 
 type Users struct {
-	ID           uuid.UUID `sql:"primary_key"`
-	Email        string
-	PasswordHash string
-	Role         string
-	CreatedAt    *time.Time
-	Nickname     string
+	ID              uuid.UUID `sql:"primary_key"`
+	Email           string
+	PasswordHash    string
+	CreatedAt       *time.Time
+	Nickname        string
+	Preferences     string
+	EmailVerifiedAt *time.Time
+	DeletedAt       *time.Time
+	Status          string
+	StatusReason    string
+	StatusExpiresAt *time.Time
+	ErasedAt        *time.Time
 }
@@ -10,6 +10,28 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	APIKeys = APIKeys.FromSchema(schema)
+	AuditEvents = AuditEvents.FromSchema(schema)
+	DataExports = DataExports.FromSchema(schema)
+	EmailVerifications = EmailVerifications.FromSchema(schema)
 	GooseDbVersion = GooseDbVersion.FromSchema(schema)
+	LoginAttempts = LoginAttempts.FromSchema(schema)
+	OauthAuthorizationCodes = OauthAuthorizationCodes.FromSchema(schema)
+	OauthClients = OauthClients.FromSchema(schema)
+	OidcLoginStates = OidcLoginStates.FromSchema(schema)
+	OrganizationInvitations = OrganizationInvitations.FromSchema(schema)
+	OrganizationMembers = OrganizationMembers.FromSchema(schema)
+	Organizations = Organizations.FromSchema(schema)
+	PasswordResets = PasswordResets.FromSchema(schema)
+	Permissions = Permissions.FromSchema(schema)
+	RecoveryCodes = RecoveryCodes.FromSchema(schema)
+	RevokedTokens = RevokedTokens.FromSchema(schema)
+	RolePermissions = RolePermissions.FromSchema(schema)
+	Roles = Roles.FromSchema(schema)
+	Sessions = Sessions.FromSchema(schema)
+	UserIdentities = UserIdentities.FromSchema(schema)
+	UserRoles = UserRoles.FromSchema(schema)
+	UserStatusHistory = UserStatusHistory.FromSchema(schema)
+	UserTotp = UserTotp.FromSchema(schema)
 	Users = Users.FromSchema(schema)
 }
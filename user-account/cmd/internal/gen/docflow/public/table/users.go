@@ -17,12 +17,18 @@ type usersTable struct {
 	postgres.Table
 
 	// Columns
-	ID           postgres.ColumnString
-	Email        postgres.ColumnString
-	PasswordHash postgres.ColumnString
-	Role         postgres.ColumnString
-	CreatedAt    postgres.ColumnTimestampz
-	Nickname     postgres.ColumnString
+	ID              postgres.ColumnString
+	Email           postgres.ColumnString
+	PasswordHash    postgres.ColumnString
+	CreatedAt       postgres.ColumnTimestampz
+	Nickname        postgres.ColumnString
+	Preferences     postgres.ColumnString
+	EmailVerifiedAt postgres.ColumnTimestampz
+	DeletedAt       postgres.ColumnTimestampz
+	Status          postgres.ColumnString
+	StatusReason    postgres.ColumnString
+	StatusExpiresAt postgres.ColumnTimestampz
+	ErasedAt        postgres.ColumnTimestampz
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -64,27 +70,39 @@ func newUsersTable(schemaName, tableName, alias string) *UsersTable {
 
 func newUsersTableImpl(schemaName, tableName, alias string) usersTable {
 	var (
-		IDColumn           = postgres.StringColumn("id")
-		EmailColumn        = postgres.StringColumn("email")
-		PasswordHashColumn = postgres.StringColumn("password_hash")
-		RoleColumn         = postgres.StringColumn("role")
-		CreatedAtColumn    = postgres.TimestampzColumn("created_at")
-		NicknameColumn     = postgres.StringColumn("nickname")
-		allColumns         = postgres.ColumnList{IDColumn, EmailColumn, PasswordHashColumn, RoleColumn, CreatedAtColumn, NicknameColumn}
-		mutableColumns     = postgres.ColumnList{EmailColumn, PasswordHashColumn, RoleColumn, CreatedAtColumn, NicknameColumn}
-		defaultColumns     = postgres.ColumnList{RoleColumn, CreatedAtColumn}
+		IDColumn              = postgres.StringColumn("id")
+		EmailColumn           = postgres.StringColumn("email")
+		PasswordHashColumn    = postgres.StringColumn("password_hash")
+		CreatedAtColumn       = postgres.TimestampzColumn("created_at")
+		NicknameColumn        = postgres.StringColumn("nickname")
+		PreferencesColumn     = postgres.StringColumn("preferences")
+		EmailVerifiedAtColumn = postgres.TimestampzColumn("email_verified_at")
+		DeletedAtColumn       = postgres.TimestampzColumn("deleted_at")
+		StatusColumn          = postgres.StringColumn("status")
+		StatusReasonColumn    = postgres.StringColumn("status_reason")
+		StatusExpiresAtColumn = postgres.TimestampzColumn("status_expires_at")
+		ErasedAtColumn        = postgres.TimestampzColumn("erased_at")
+		allColumns            = postgres.ColumnList{IDColumn, EmailColumn, PasswordHashColumn, CreatedAtColumn, NicknameColumn, PreferencesColumn, EmailVerifiedAtColumn, DeletedAtColumn, StatusColumn, StatusReasonColumn, StatusExpiresAtColumn, ErasedAtColumn}
+		mutableColumns        = postgres.ColumnList{EmailColumn, PasswordHashColumn, CreatedAtColumn, NicknameColumn, PreferencesColumn, EmailVerifiedAtColumn, DeletedAtColumn, StatusColumn, StatusReasonColumn, StatusExpiresAtColumn, ErasedAtColumn}
+		defaultColumns        = postgres.ColumnList{CreatedAtColumn, PreferencesColumn, StatusColumn, StatusReasonColumn}
 	)
 
 	return usersTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:           IDColumn,
-		Email:        EmailColumn,
-		PasswordHash: PasswordHashColumn,
-		Role:         RoleColumn,
-		CreatedAt:    CreatedAtColumn,
-		Nickname:     NicknameColumn,
+		ID:              IDColumn,
+		Email:           EmailColumn,
+		PasswordHash:    PasswordHashColumn,
+		CreatedAt:       CreatedAtColumn,
+		Nickname:        NicknameColumn,
+		Preferences:     PreferencesColumn,
+		EmailVerifiedAt: EmailVerifiedAtColumn,
+		DeletedAt:       DeletedAtColumn,
+		Status:          StatusColumn,
+		StatusReason:    StatusReasonColumn,
+		StatusExpiresAt: StatusExpiresAtColumn,
+		ErasedAt:        ErasedAtColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/middleware/api_key_middleware.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAPIKeyAuthenticator is a mock of APIKeyAuthenticator interface.
+type MockAPIKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyAuthenticatorMockRecorder
+}
+
+// MockAPIKeyAuthenticatorMockRecorder is the mock recorder for MockAPIKeyAuthenticator.
+type MockAPIKeyAuthenticatorMockRecorder struct {
+	mock *MockAPIKeyAuthenticator
+}
+
+// NewMockAPIKeyAuthenticator creates a new mock instance.
+func NewMockAPIKeyAuthenticator(ctrl *gomock.Controller) *MockAPIKeyAuthenticator {
+	mock := &MockAPIKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyAuthenticator) EXPECT() *MockAPIKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockAPIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockAPIKeyAuthenticatorMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockAPIKeyAuthenticator)(nil).Authenticate), ctx, key)
+}
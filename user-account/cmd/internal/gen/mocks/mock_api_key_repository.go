@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/api_key_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAPIKeyRepository is a mock of APIKeyRepository interface.
+type MockAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyRepositoryMockRecorder
+}
+
+// MockAPIKeyRepositoryMockRecorder is the mock recorder for MockAPIKeyRepository.
+type MockAPIKeyRepositoryMockRecorder struct {
+	mock *MockAPIKeyRepository
+}
+
+// NewMockAPIKeyRepository creates a new mock instance.
+func NewMockAPIKeyRepository(ctrl *gomock.Controller) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyRepositoryMockRecorder) Create(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeyRepository)(nil).Create), ctx, key)
+}
+
+// GetByHash mocks base method.
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockAPIKeyRepositoryMockRecorder) GetByHash(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockAPIKeyRepository)(nil).GetByHash), ctx, keyHash)
+}
+
+// ListByUser mocks base method.
+func (m *MockAPIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAPIKeyRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAPIKeyRepository)(nil).ListByUser), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyRepositoryMockRecorder) Revoke(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeyRepository)(nil).Revoke), ctx, userID, id)
+}
+
+// TouchLastUsed mocks base method.
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, every time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastUsed", ctx, id, at, every)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastUsed indicates an expected call of TouchLastUsed.
+func (mr *MockAPIKeyRepositoryMockRecorder) TouchLastUsed(ctx, id, at, every interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastUsed", reflect.TypeOf((*MockAPIKeyRepository)(nil).TouchLastUsed), ctx, id, at, every)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/api_key_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockAPIKeyProvider is a mock of APIKeyProvider interface.
+type MockAPIKeyProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPIKeyProviderMockRecorder
+}
+
+// MockAPIKeyProviderMockRecorder is the mock recorder for MockAPIKeyProvider.
+type MockAPIKeyProviderMockRecorder struct {
+	mock *MockAPIKeyProvider
+}
+
+// NewMockAPIKeyProvider creates a new mock instance.
+func NewMockAPIKeyProvider(ctrl *gomock.Controller) *MockAPIKeyProvider {
+	mock := &MockAPIKeyProvider{ctrl: ctrl}
+	mock.recorder = &MockAPIKeyProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPIKeyProvider) EXPECT() *MockAPIKeyProviderMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPIKeyProvider) Create(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID, name string, scopes []string, ttl time.Duration) (*model.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, orgID, name, scopes, ttl)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPIKeyProviderMockRecorder) Create(ctx, userID, orgID, name, scopes, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPIKeyProvider)(nil).Create), ctx, userID, orgID, name, scopes, ttl)
+}
+
+// List mocks base method.
+func (m *MockAPIKeyProvider) List(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAPIKeyProviderMockRecorder) List(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAPIKeyProvider)(nil).List), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockAPIKeyProvider) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPIKeyProviderMockRecorder) Revoke(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPIKeyProvider)(nil).Revoke), ctx, userID, id)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/audit_event_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditEventRepository is a mock of AuditEventRepository interface.
+type MockAuditEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditEventRepositoryMockRecorder
+}
+
+// MockAuditEventRepositoryMockRecorder is the mock recorder for MockAuditEventRepository.
+type MockAuditEventRepositoryMockRecorder struct {
+	mock *MockAuditEventRepository
+}
+
+// NewMockAuditEventRepository creates a new mock instance.
+func NewMockAuditEventRepository(ctrl *gomock.Controller) *MockAuditEventRepository {
+	mock := &MockAuditEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAuditEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditEventRepository) EXPECT() *MockAuditEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockAuditEventRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockAuditEventRepositoryMockRecorder) Append(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockAuditEventRepository)(nil).Append), ctx, event)
+}
+
+// ForEach mocks base method.
+func (m *MockAuditEventRepository) ForEach(ctx context.Context, fn func(model.AuditEvent) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockAuditEventRepositoryMockRecorder) ForEach(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockAuditEventRepository)(nil).ForEach), ctx, fn)
+}
+
+// List mocks base method.
+func (m *MockAuditEventRepository) List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*model.AuditEventPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditEventRepositoryMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditEventRepository)(nil).List), ctx, query)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/audit_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuditProvider is a mock of AuditProvider interface.
+type MockAuditProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditProviderMockRecorder
+}
+
+// MockAuditProviderMockRecorder is the mock recorder for MockAuditProvider.
+type MockAuditProviderMockRecorder struct {
+	mock *MockAuditProvider
+}
+
+// NewMockAuditProvider creates a new mock instance.
+func NewMockAuditProvider(ctrl *gomock.Controller) *MockAuditProvider {
+	mock := &MockAuditProvider{ctrl: ctrl}
+	mock.recorder = &MockAuditProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditProvider) EXPECT() *MockAuditProviderMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockAuditProvider) List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*model.AuditEventPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditProviderMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditProvider)(nil).List), ctx, query)
+}
+
+// Verify mocks base method.
+func (m *MockAuditProvider) Verify(ctx context.Context) (*model.AuditChainCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx)
+	ret0, _ := ret[0].(*model.AuditChainCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockAuditProviderMockRecorder) Verify(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAuditProvider)(nil).Verify), ctx)
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/middleware/auth_middleware.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRevocationChecker is a mock of RevocationChecker interface.
+type MockRevocationChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationCheckerMockRecorder
+}
+
+// MockRevocationCheckerMockRecorder is the mock recorder for MockRevocationChecker.
+type MockRevocationCheckerMockRecorder struct {
+	mock *MockRevocationChecker
+}
+
+// NewMockRevocationChecker creates a new mock instance.
+func NewMockRevocationChecker(ctrl *gomock.Controller) *MockRevocationChecker {
+	mock := &MockRevocationChecker{ctrl: ctrl}
+	mock.recorder = &MockRevocationCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationChecker) EXPECT() *MockRevocationCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevocationChecker) IsRevoked(ctx context.Context, tokenID string, sessionID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, tokenID, sessionID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevocationCheckerMockRecorder) IsRevoked(ctx, tokenID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevocationChecker)(nil).IsRevoked), ctx, tokenID, sessionID)
+}
+
+// MockStatusChecker is a mock of StatusChecker interface.
+type MockStatusChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusCheckerMockRecorder
+}
+
+// MockStatusCheckerMockRecorder is the mock recorder for MockStatusChecker.
+type MockStatusCheckerMockRecorder struct {
+	mock *MockStatusChecker
+}
+
+// NewMockStatusChecker creates a new mock instance.
+func NewMockStatusChecker(ctrl *gomock.Controller) *MockStatusChecker {
+	mock := &MockStatusChecker{ctrl: ctrl}
+	mock.recorder = &MockStatusCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusChecker) EXPECT() *MockStatusCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsBlocked mocks base method.
+func (m *MockStatusChecker) IsBlocked(ctx context.Context, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsBlocked", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsBlocked indicates an expected call of IsBlocked.
+func (mr *MockStatusCheckerMockRecorder) IsBlocked(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsBlocked", reflect.TypeOf((*MockStatusChecker)(nil).IsBlocked), ctx, userID)
+}
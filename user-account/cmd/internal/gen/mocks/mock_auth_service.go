@@ -7,8 +7,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	model "user-account/cmd/internal/model"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 )
 
 // MockAuthProvider is a mock of AuthProvider interface.
@@ -35,18 +37,76 @@ func (m *MockAuthProvider) EXPECT() *MockAuthProviderMockRecorder {
 }
 
 // Login mocks base method.
-func (m *MockAuthProvider) Login(ctx context.Context, email, password string) (string, error) {
+func (m *MockAuthProvider) Login(ctx context.Context, email, password string, client model.ClientInfo) (*model.LoginResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, email, password)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", ctx, email, password, client)
+	ret0, _ := ret[0].(*model.LoginResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthProviderMockRecorder) Login(ctx, email, password interface{}) *gomock.Call {
+func (mr *MockAuthProviderMockRecorder) Login(ctx, email, password, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthProvider)(nil).Login), ctx, email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthProvider)(nil).Login), ctx, email, password, client)
+}
+
+// LoginTwoFactor mocks base method.
+func (m *MockAuthProvider) LoginTwoFactor(ctx context.Context, mfaToken, code string, client model.ClientInfo) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginTwoFactor", ctx, mfaToken, code, client)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoginTwoFactor indicates an expected call of LoginTwoFactor.
+func (mr *MockAuthProviderMockRecorder) LoginTwoFactor(ctx, mfaToken, code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginTwoFactor", reflect.TypeOf((*MockAuthProvider)(nil).LoginTwoFactor), ctx, mfaToken, code, client)
+}
+
+// Logout mocks base method.
+func (m *MockAuthProvider) Logout(ctx context.Context, token model.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthProviderMockRecorder) Logout(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthProvider)(nil).Logout), ctx, token)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthProvider) LogoutAll(ctx context.Context, userID uuid.UUID, token model.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userID, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthProviderMockRecorder) LogoutAll(ctx, userID, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthProvider)(nil).LogoutAll), ctx, userID, token)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthProvider) Refresh(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken, client)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthProviderMockRecorder) Refresh(ctx, refreshToken, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthProvider)(nil).Refresh), ctx, refreshToken, client)
 }
 
 // Register mocks base method.
@@ -62,3 +122,18 @@ func (mr *MockAuthProviderMockRecorder) Register(ctx, email, password, nickname
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthProvider)(nil).Register), ctx, email, password, nickname)
 }
+
+// SwitchOrganization mocks base method.
+func (m *MockAuthProvider) SwitchOrganization(ctx context.Context, userID uuid.UUID, token model.TokenClaims, orgID uuid.UUID, client model.ClientInfo) (*model.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwitchOrganization", ctx, userID, token, orgID, client)
+	ret0, _ := ret[0].(*model.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SwitchOrganization indicates an expected call of SwitchOrganization.
+func (mr *MockAuthProviderMockRecorder) SwitchOrganization(ctx, userID, token, orgID, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwitchOrganization", reflect.TypeOf((*MockAuthProvider)(nil).SwitchOrganization), ctx, userID, token, orgID, client)
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/data_export_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockDataExportRepository is a mock of DataExportRepository interface.
+type MockDataExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDataExportRepositoryMockRecorder
+}
+
+// MockDataExportRepositoryMockRecorder is the mock recorder for MockDataExportRepository.
+type MockDataExportRepositoryMockRecorder struct {
+	mock *MockDataExportRepository
+}
+
+// NewMockDataExportRepository creates a new mock instance.
+func NewMockDataExportRepository(ctrl *gomock.Controller) *MockDataExportRepository {
+	mock := &MockDataExportRepository{ctrl: ctrl}
+	mock.recorder = &MockDataExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDataExportRepository) EXPECT() *MockDataExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Active mocks base method.
+func (m *MockDataExportRepository) Active(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Active", ctx, userID)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Active indicates an expected call of Active.
+func (mr *MockDataExportRepositoryMockRecorder) Active(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Active", reflect.TypeOf((*MockDataExportRepository)(nil).Active), ctx, userID)
+}
+
+// Archive mocks base method.
+func (m *MockDataExportRepository) Archive(ctx context.Context, userID, id uuid.UUID, now time.Time) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, userID, id, now)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockDataExportRepositoryMockRecorder) Archive(ctx, userID, id, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockDataExportRepository)(nil).Archive), ctx, userID, id, now)
+}
+
+// Claim mocks base method.
+func (m *MockDataExportRepository) Claim(ctx context.Context, staleBefore time.Time) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, staleBefore)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockDataExportRepositoryMockRecorder) Claim(ctx, staleBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockDataExportRepository)(nil).Claim), ctx, staleBefore)
+}
+
+// Complete mocks base method.
+func (m *MockDataExportRepository) Complete(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, id, archive, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockDataExportRepositoryMockRecorder) Complete(ctx, id, archive, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockDataExportRepository)(nil).Complete), ctx, id, archive, expiresAt)
+}
+
+// Create mocks base method.
+func (m *MockDataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, export)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDataExportRepositoryMockRecorder) Create(ctx, export interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDataExportRepository)(nil).Create), ctx, export)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockDataExportRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockDataExportRepositoryMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockDataExportRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// Fail mocks base method.
+func (m *MockDataExportRepository) Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fail", ctx, id, reason, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Fail indicates an expected call of Fail.
+func (mr *MockDataExportRepositoryMockRecorder) Fail(ctx, id, reason, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fail", reflect.TypeOf((*MockDataExportRepository)(nil).Fail), ctx, id, reason, expiresAt)
+}
+
+// Get mocks base method.
+func (m *MockDataExportRepository) Get(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID, id)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockDataExportRepositoryMockRecorder) Get(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDataExportRepository)(nil).Get), ctx, userID, id)
+}
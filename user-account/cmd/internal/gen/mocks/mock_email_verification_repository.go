@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/email_verification_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockEmailVerificationRepository is a mock of EmailVerificationRepository interface.
+type MockEmailVerificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailVerificationRepositoryMockRecorder
+}
+
+// MockEmailVerificationRepositoryMockRecorder is the mock recorder for MockEmailVerificationRepository.
+type MockEmailVerificationRepositoryMockRecorder struct {
+	mock *MockEmailVerificationRepository
+}
+
+// NewMockEmailVerificationRepository creates a new mock instance.
+func NewMockEmailVerificationRepository(ctrl *gomock.Controller) *MockEmailVerificationRepository {
+	mock := &MockEmailVerificationRepository{ctrl: ctrl}
+	mock.recorder = &MockEmailVerificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailVerificationRepository) EXPECT() *MockEmailVerificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockEmailVerificationRepository) Consume(ctx context.Context, id uuid.UUID) (*model.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, id)
+	ret0, _ := ret[0].(*model.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockEmailVerificationRepositoryMockRecorder) Consume(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockEmailVerificationRepository)(nil).Consume), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockEmailVerificationRepository) Create(ctx context.Context, verification *model.EmailVerification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, verification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailVerificationRepositoryMockRecorder) Create(ctx, verification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailVerificationRepository)(nil).Create), ctx, verification)
+}
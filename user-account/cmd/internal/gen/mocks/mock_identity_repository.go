@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/identity_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockIdentityRepository is a mock of IdentityRepository interface.
+type MockIdentityRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityRepositoryMockRecorder
+}
+
+// MockIdentityRepositoryMockRecorder is the mock recorder for MockIdentityRepository.
+type MockIdentityRepositoryMockRecorder struct {
+	mock *MockIdentityRepository
+}
+
+// NewMockIdentityRepository creates a new mock instance.
+func NewMockIdentityRepository(ctrl *gomock.Controller) *MockIdentityRepository {
+	mock := &MockIdentityRepository{ctrl: ctrl}
+	mock.recorder = &MockIdentityRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityRepository) EXPECT() *MockIdentityRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIdentityRepositoryMockRecorder) Create(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIdentityRepository)(nil).Create), ctx, identity)
+}
+
+// GetByProviderSubject mocks base method.
+func (m *MockIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByProviderSubject", ctx, provider, subject)
+	ret0, _ := ret[0].(*model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByProviderSubject indicates an expected call of GetByProviderSubject.
+func (mr *MockIdentityRepositoryMockRecorder) GetByProviderSubject(ctx, provider, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByProviderSubject", reflect.TypeOf((*MockIdentityRepository)(nil).GetByProviderSubject), ctx, provider, subject)
+}
+
+// ListByUser mocks base method.
+func (m *MockIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockIdentityRepositoryMockRecorder) ListByUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockIdentityRepository)(nil).ListByUser), ctx, userID)
+}
+
+// TouchLogin mocks base method.
+func (m *MockIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLogin", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLogin indicates an expected call of TouchLogin.
+func (mr *MockIdentityRepositoryMockRecorder) TouchLogin(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLogin", reflect.TypeOf((*MockIdentityRepository)(nil).TouchLogin), ctx, id)
+}
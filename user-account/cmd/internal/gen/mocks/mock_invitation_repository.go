@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/invitation_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockInvitationRepository is a mock of InvitationRepository interface.
+type MockInvitationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationRepositoryMockRecorder
+}
+
+// MockInvitationRepositoryMockRecorder is the mock recorder for MockInvitationRepository.
+type MockInvitationRepositoryMockRecorder struct {
+	mock *MockInvitationRepository
+}
+
+// NewMockInvitationRepository creates a new mock instance.
+func NewMockInvitationRepository(ctrl *gomock.Controller) *MockInvitationRepository {
+	mock := &MockInvitationRepository{ctrl: ctrl}
+	mock.recorder = &MockInvitationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationRepository) EXPECT() *MockInvitationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockInvitationRepository) Accept(ctx context.Context, id, userID uuid.UUID) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, id, userID)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockInvitationRepositoryMockRecorder) Accept(ctx, id, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockInvitationRepository)(nil).Accept), ctx, id, userID)
+}
+
+// Create mocks base method.
+func (m *MockInvitationRepository) Create(ctx context.Context, invitation *model.Invitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationRepositoryMockRecorder) Create(ctx, invitation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitationRepository)(nil).Create), ctx, invitation)
+}
+
+// Decline mocks base method.
+func (m *MockInvitationRepository) Decline(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockInvitationRepositoryMockRecorder) Decline(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockInvitationRepository)(nil).Decline), ctx, id)
+}
+
+// GetByID mocks base method.
+func (m *MockInvitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockInvitationRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockInvitationRepository)(nil).GetByID), ctx, id)
+}
+
+// ListPending mocks base method.
+func (m *MockInvitationRepository) ListPending(ctx context.Context, orgID uuid.UUID) ([]model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPending", ctx, orgID)
+	ret0, _ := ret[0].([]model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPending indicates an expected call of ListPending.
+func (mr *MockInvitationRepositoryMockRecorder) ListPending(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPending", reflect.TypeOf((*MockInvitationRepository)(nil).ListPending), ctx, orgID)
+}
+
+// Revoke mocks base method.
+func (m *MockInvitationRepository) Revoke(ctx context.Context, orgID, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, orgID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockInvitationRepositoryMockRecorder) Revoke(ctx, orgID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockInvitationRepository)(nil).Revoke), ctx, orgID, id)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/invitation_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockInvitationProvider is a mock of InvitationProvider interface.
+type MockInvitationProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitationProviderMockRecorder
+}
+
+// MockInvitationProviderMockRecorder is the mock recorder for MockInvitationProvider.
+type MockInvitationProviderMockRecorder struct {
+	mock *MockInvitationProvider
+}
+
+// NewMockInvitationProvider creates a new mock instance.
+func NewMockInvitationProvider(ctrl *gomock.Controller) *MockInvitationProvider {
+	mock := &MockInvitationProvider{ctrl: ctrl}
+	mock.recorder = &MockInvitationProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitationProvider) EXPECT() *MockInvitationProviderMockRecorder {
+	return m.recorder
+}
+
+// Accept mocks base method.
+func (m *MockInvitationProvider) Accept(ctx context.Context, token, nickname, password string) (*model.InvitationAcceptance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accept", ctx, token, nickname, password)
+	ret0, _ := ret[0].(*model.InvitationAcceptance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Accept indicates an expected call of Accept.
+func (mr *MockInvitationProviderMockRecorder) Accept(ctx, token, nickname, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accept", reflect.TypeOf((*MockInvitationProvider)(nil).Accept), ctx, token, nickname, password)
+}
+
+// Create mocks base method.
+func (m *MockInvitationProvider) Create(ctx context.Context, orgID, actorID uuid.UUID, email, role string) (*model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, orgID, actorID, email, role)
+	ret0, _ := ret[0].(*model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitationProviderMockRecorder) Create(ctx, orgID, actorID, email, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitationProvider)(nil).Create), ctx, orgID, actorID, email, role)
+}
+
+// Decline mocks base method.
+func (m *MockInvitationProvider) Decline(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decline", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decline indicates an expected call of Decline.
+func (mr *MockInvitationProviderMockRecorder) Decline(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decline", reflect.TypeOf((*MockInvitationProvider)(nil).Decline), ctx, token)
+}
+
+// List mocks base method.
+func (m *MockInvitationProvider) List(ctx context.Context, orgID, actorID uuid.UUID) ([]model.Invitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, orgID, actorID)
+	ret0, _ := ret[0].([]model.Invitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockInvitationProviderMockRecorder) List(ctx, orgID, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockInvitationProvider)(nil).List), ctx, orgID, actorID)
+}
+
+// Revoke mocks base method.
+func (m *MockInvitationProvider) Revoke(ctx context.Context, orgID, actorID, invitationID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, orgID, actorID, invitationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockInvitationProviderMockRecorder) Revoke(ctx, orgID, actorID, invitationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockInvitationProvider)(nil).Revoke), ctx, orgID, actorID, invitationID)
+}
@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/login_attempt_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLoginAttemptRepository is a mock of LoginAttemptRepository interface.
+type MockLoginAttemptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptRepositoryMockRecorder
+}
+
+// MockLoginAttemptRepositoryMockRecorder is the mock recorder for MockLoginAttemptRepository.
+type MockLoginAttemptRepositoryMockRecorder struct {
+	mock *MockLoginAttemptRepository
+}
+
+// NewMockLoginAttemptRepository creates a new mock instance.
+func NewMockLoginAttemptRepository(ctrl *gomock.Controller) *MockLoginAttemptRepository {
+	mock := &MockLoginAttemptRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptRepository) EXPECT() *MockLoginAttemptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteStale mocks base method.
+func (m *MockLoginAttemptRepository) DeleteStale(ctx context.Context, staleBefore, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStale", ctx, staleBefore, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStale indicates an expected call of DeleteStale.
+func (mr *MockLoginAttemptRepositoryMockRecorder) DeleteStale(ctx, staleBefore, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStale", reflect.TypeOf((*MockLoginAttemptRepository)(nil).DeleteStale), ctx, staleBefore, now)
+}
+
+// Get mocks base method.
+func (m *MockLoginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*model.LoginAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Get), ctx, key)
+}
+
+// Lock mocks base method.
+func (m *MockLoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", ctx, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Lock(ctx, key, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Lock), ctx, key, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginAttemptRepository) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, key, at, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginAttemptRepositoryMockRecorder) RecordFailure(ctx, key, at, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginAttemptRepository)(nil).RecordFailure), ctx, key, at, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptRepository) Reset(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptRepositoryMockRecorder) Reset(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptRepository)(nil).Reset), ctx, key)
+}
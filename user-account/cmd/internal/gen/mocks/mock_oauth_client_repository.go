@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/oauth_client_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOAuthClientRepository is a mock of OAuthClientRepository interface.
+type MockOAuthClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthClientRepositoryMockRecorder
+}
+
+// MockOAuthClientRepositoryMockRecorder is the mock recorder for MockOAuthClientRepository.
+type MockOAuthClientRepositoryMockRecorder struct {
+	mock *MockOAuthClientRepository
+}
+
+// NewMockOAuthClientRepository creates a new mock instance.
+func NewMockOAuthClientRepository(ctrl *gomock.Controller) *MockOAuthClientRepository {
+	mock := &MockOAuthClientRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthClientRepository) EXPECT() *MockOAuthClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockOAuthClientRepository) Create(ctx context.Context, client *model.OAuthClient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOAuthClientRepositoryMockRecorder) Create(ctx, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOAuthClientRepository)(nil).Create), ctx, client)
+}
+
+// GetByID mocks base method.
+func (m *MockOAuthClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOAuthClientRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOAuthClientRepository)(nil).GetByID), ctx, id)
+}
+
+// List mocks base method.
+func (m *MockOAuthClientRepository) List(ctx context.Context) ([]model.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOAuthClientRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOAuthClientRepository)(nil).List), ctx)
+}
+
+// Revoke mocks base method.
+func (m *MockOAuthClientRepository) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockOAuthClientRepositoryMockRecorder) Revoke(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOAuthClientRepository)(nil).Revoke), ctx, id)
+}
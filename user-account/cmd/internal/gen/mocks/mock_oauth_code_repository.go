@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/oauth_code_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOAuthCodeRepository is a mock of OAuthCodeRepository interface.
+type MockOAuthCodeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthCodeRepositoryMockRecorder
+}
+
+// MockOAuthCodeRepositoryMockRecorder is the mock recorder for MockOAuthCodeRepository.
+type MockOAuthCodeRepositoryMockRecorder struct {
+	mock *MockOAuthCodeRepository
+}
+
+// NewMockOAuthCodeRepository creates a new mock instance.
+func NewMockOAuthCodeRepository(ctrl *gomock.Controller) *MockOAuthCodeRepository {
+	mock := &MockOAuthCodeRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthCodeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthCodeRepository) EXPECT() *MockOAuthCodeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockOAuthCodeRepository) Consume(ctx context.Context, codeHash string, clientID uuid.UUID) (*model.OAuthAuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, codeHash, clientID)
+	ret0, _ := ret[0].(*model.OAuthAuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockOAuthCodeRepositoryMockRecorder) Consume(ctx, codeHash, clientID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockOAuthCodeRepository)(nil).Consume), ctx, codeHash, clientID)
+}
+
+// Create mocks base method.
+func (m *MockOAuthCodeRepository) Create(ctx context.Context, code *model.OAuthAuthorizationCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOAuthCodeRepositoryMockRecorder) Create(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOAuthCodeRepository)(nil).Create), ctx, code)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockOAuthCodeRepository) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockOAuthCodeRepositoryMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockOAuthCodeRepository)(nil).DeleteExpired), ctx)
+}
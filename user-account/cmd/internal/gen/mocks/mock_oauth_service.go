@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/oauth_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOAuthProvider is a mock of OAuthProvider interface.
+type MockOAuthProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthProviderMockRecorder
+}
+
+// MockOAuthProviderMockRecorder is the mock recorder for MockOAuthProvider.
+type MockOAuthProviderMockRecorder struct {
+	mock *MockOAuthProvider
+}
+
+// NewMockOAuthProvider creates a new mock instance.
+func NewMockOAuthProvider(ctrl *gomock.Controller) *MockOAuthProvider {
+	mock := &MockOAuthProvider{ctrl: ctrl}
+	mock.recorder = &MockOAuthProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthProvider) EXPECT() *MockOAuthProviderMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOAuthProvider) Authorize(ctx context.Context, userID uuid.UUID, req model.AuthorizationRequest, approved bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, userID, req, approved)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOAuthProviderMockRecorder) Authorize(ctx, userID, req, approved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOAuthProvider)(nil).Authorize), ctx, userID, req, approved)
+}
+
+// Introspect mocks base method.
+func (m *MockOAuthProvider) Introspect(ctx context.Context, clientID, clientSecret, token string) (*model.TokenIntrospection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ctx, clientID, clientSecret, token)
+	ret0, _ := ret[0].(*model.TokenIntrospection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockOAuthProviderMockRecorder) Introspect(ctx, clientID, clientSecret, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockOAuthProvider)(nil).Introspect), ctx, clientID, clientSecret, token)
+}
+
+// ListClients mocks base method.
+func (m *MockOAuthProvider) ListClients(ctx context.Context) ([]model.OAuthClient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClients", ctx)
+	ret0, _ := ret[0].([]model.OAuthClient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClients indicates an expected call of ListClients.
+func (mr *MockOAuthProviderMockRecorder) ListClients(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClients", reflect.TypeOf((*MockOAuthProvider)(nil).ListClients), ctx)
+}
+
+// Metadata mocks base method.
+func (m *MockOAuthProvider) Metadata() model.OAuthServerMetadata {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Metadata")
+	ret0, _ := ret[0].(model.OAuthServerMetadata)
+	return ret0
+}
+
+// Metadata indicates an expected call of Metadata.
+func (mr *MockOAuthProviderMockRecorder) Metadata() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockOAuthProvider)(nil).Metadata))
+}
+
+// PreviewAuthorization mocks base method.
+func (m *MockOAuthProvider) PreviewAuthorization(ctx context.Context, req model.AuthorizationRequest) (*model.AuthorizationPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewAuthorization", ctx, req)
+	ret0, _ := ret[0].(*model.AuthorizationPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewAuthorization indicates an expected call of PreviewAuthorization.
+func (mr *MockOAuthProviderMockRecorder) PreviewAuthorization(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewAuthorization", reflect.TypeOf((*MockOAuthProvider)(nil).PreviewAuthorization), ctx, req)
+}
+
+// RegisterClient mocks base method.
+func (m *MockOAuthProvider) RegisterClient(ctx context.Context, createdBy uuid.UUID, reg model.OAuthClientRegistration) (*model.OAuthClient, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterClient", ctx, createdBy, reg)
+	ret0, _ := ret[0].(*model.OAuthClient)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RegisterClient indicates an expected call of RegisterClient.
+func (mr *MockOAuthProviderMockRecorder) RegisterClient(ctx, createdBy, reg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterClient", reflect.TypeOf((*MockOAuthProvider)(nil).RegisterClient), ctx, createdBy, reg)
+}
+
+// RevokeClient mocks base method.
+func (m *MockOAuthProvider) RevokeClient(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeClient", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeClient indicates an expected call of RevokeClient.
+func (mr *MockOAuthProviderMockRecorder) RevokeClient(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeClient", reflect.TypeOf((*MockOAuthProvider)(nil).RevokeClient), ctx, id)
+}
+
+// Token mocks base method.
+func (m *MockOAuthProvider) Token(ctx context.Context, req model.TokenRequest) (*model.OAuthTokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Token", ctx, req)
+	ret0, _ := ret[0].(*model.OAuthTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Token indicates an expected call of Token.
+func (mr *MockOAuthProviderMockRecorder) Token(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Token", reflect.TypeOf((*MockOAuthProvider)(nil).Token), ctx, req)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/oidc_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOIDCProvider is a mock of OIDCProvider interface.
+type MockOIDCProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCProviderMockRecorder
+}
+
+// MockOIDCProviderMockRecorder is the mock recorder for MockOIDCProvider.
+type MockOIDCProviderMockRecorder struct {
+	mock *MockOIDCProvider
+}
+
+// NewMockOIDCProvider creates a new mock instance.
+func NewMockOIDCProvider(ctrl *gomock.Controller) *MockOIDCProvider {
+	mock := &MockOIDCProvider{ctrl: ctrl}
+	mock.recorder = &MockOIDCProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCProvider) EXPECT() *MockOIDCProviderMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockOIDCProvider) Authorize(ctx context.Context, provider string) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, provider)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockOIDCProviderMockRecorder) Authorize(ctx, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockOIDCProvider)(nil).Authorize), ctx, provider)
+}
+
+// Callback mocks base method.
+func (m *MockOIDCProvider) Callback(ctx context.Context, code, state, binding string, client model.ClientInfo) (*model.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Callback", ctx, code, state, binding, client)
+	ret0, _ := ret[0].(*model.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Callback indicates an expected call of Callback.
+func (mr *MockOIDCProviderMockRecorder) Callback(ctx, code, state, binding, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Callback", reflect.TypeOf((*MockOIDCProvider)(nil).Callback), ctx, code, state, binding, client)
+}
+
+// Identities mocks base method.
+func (m *MockOIDCProvider) Identities(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identities", ctx, userID)
+	ret0, _ := ret[0].([]model.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Identities indicates an expected call of Identities.
+func (mr *MockOIDCProviderMockRecorder) Identities(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identities", reflect.TypeOf((*MockOIDCProvider)(nil).Identities), ctx, userID)
+}
+
+// Providers mocks base method.
+func (m *MockOIDCProvider) Providers() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Providers")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Providers indicates an expected call of Providers.
+func (mr *MockOIDCProviderMockRecorder) Providers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Providers", reflect.TypeOf((*MockOIDCProvider)(nil).Providers))
+}
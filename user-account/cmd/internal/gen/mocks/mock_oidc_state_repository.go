@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/oidc_state_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOIDCStateRepository is a mock of OIDCStateRepository interface.
+type MockOIDCStateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCStateRepositoryMockRecorder
+}
+
+// MockOIDCStateRepositoryMockRecorder is the mock recorder for MockOIDCStateRepository.
+type MockOIDCStateRepositoryMockRecorder struct {
+	mock *MockOIDCStateRepository
+}
+
+// NewMockOIDCStateRepository creates a new mock instance.
+func NewMockOIDCStateRepository(ctrl *gomock.Controller) *MockOIDCStateRepository {
+	mock := &MockOIDCStateRepository{ctrl: ctrl}
+	mock.recorder = &MockOIDCStateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCStateRepository) EXPECT() *MockOIDCStateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockOIDCStateRepository) Consume(ctx context.Context, stateHash, bindingHash string) (*model.OIDCLoginState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, stateHash, bindingHash)
+	ret0, _ := ret[0].(*model.OIDCLoginState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockOIDCStateRepositoryMockRecorder) Consume(ctx, stateHash, bindingHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockOIDCStateRepository)(nil).Consume), ctx, stateHash, bindingHash)
+}
+
+// Create mocks base method.
+func (m *MockOIDCStateRepository) Create(ctx context.Context, state *model.OIDCLoginState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOIDCStateRepositoryMockRecorder) Create(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOIDCStateRepository)(nil).Create), ctx, state)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockOIDCStateRepository) DeleteExpired(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockOIDCStateRepositoryMockRecorder) DeleteExpired(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockOIDCStateRepository)(nil).DeleteExpired), ctx)
+}
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/middleware/organization_middleware.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockMembershipChecker is a mock of MembershipChecker interface.
+type MockMembershipChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockMembershipCheckerMockRecorder
+}
+
+// MockMembershipCheckerMockRecorder is the mock recorder for MockMembershipChecker.
+type MockMembershipCheckerMockRecorder struct {
+	mock *MockMembershipChecker
+}
+
+// NewMockMembershipChecker creates a new mock instance.
+func NewMockMembershipChecker(ctrl *gomock.Controller) *MockMembershipChecker {
+	mock := &MockMembershipChecker{ctrl: ctrl}
+	mock.recorder = &MockMembershipCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMembershipChecker) EXPECT() *MockMembershipCheckerMockRecorder {
+	return m.recorder
+}
+
+// MemberRole mocks base method.
+func (m *MockMembershipChecker) MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MemberRole", ctx, orgID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MemberRole indicates an expected call of MemberRole.
+func (mr *MockMembershipCheckerMockRecorder) MemberRole(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemberRole", reflect.TypeOf((*MockMembershipChecker)(nil).MemberRole), ctx, orgID, userID)
+}
@@ -0,0 +1,170 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/organization_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOrganizationRepository is a mock of OrganizationRepository interface.
+type MockOrganizationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationRepositoryMockRecorder
+}
+
+// MockOrganizationRepositoryMockRecorder is the mock recorder for MockOrganizationRepository.
+type MockOrganizationRepositoryMockRecorder struct {
+	mock *MockOrganizationRepository
+}
+
+// NewMockOrganizationRepository creates a new mock instance.
+func NewMockOrganizationRepository(ctrl *gomock.Controller) *MockOrganizationRepository {
+	mock := &MockOrganizationRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationRepository) EXPECT() *MockOrganizationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, orgID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockOrganizationRepositoryMockRecorder) AddMember(ctx, orgID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockOrganizationRepository)(nil).AddMember), ctx, orgID, userID, role)
+}
+
+// Create mocks base method.
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *model.Organization, ownerID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, org, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrganizationRepositoryMockRecorder) Create(ctx, org, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrganizationRepository)(nil).Create), ctx, org, ownerID)
+}
+
+// GetByID mocks base method.
+func (m *MockOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockOrganizationRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrganizationRepository)(nil).GetByID), ctx, id)
+}
+
+// ListForUser mocks base method.
+func (m *MockOrganizationRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForUser", ctx, userID)
+	ret0, _ := ret[0].([]model.OrganizationMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForUser indicates an expected call of ListForUser.
+func (mr *MockOrganizationRepositoryMockRecorder) ListForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForUser", reflect.TypeOf((*MockOrganizationRepository)(nil).ListForUser), ctx, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockOrganizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]model.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, orgID)
+	ret0, _ := ret[0].([]model.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockOrganizationRepositoryMockRecorder) ListMembers(ctx, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockOrganizationRepository)(nil).ListMembers), ctx, orgID)
+}
+
+// MemberRole mocks base method.
+func (m *MockOrganizationRepository) MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MemberRole", ctx, orgID, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MemberRole indicates an expected call of MemberRole.
+func (mr *MockOrganizationRepositoryMockRecorder) MemberRole(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MemberRole", reflect.TypeOf((*MockOrganizationRepository)(nil).MemberRole), ctx, orgID, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, orgID, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockOrganizationRepositoryMockRecorder) RemoveMember(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockOrganizationRepository)(nil).RemoveMember), ctx, orgID, userID)
+}
+
+// Rename mocks base method.
+func (m *MockOrganizationRepository) Rename(ctx context.Context, id uuid.UUID, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, id, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockOrganizationRepositoryMockRecorder) Rename(ctx, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockOrganizationRepository)(nil).Rename), ctx, id, name)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockOrganizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, orgID, userID, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockOrganizationRepositoryMockRecorder) UpdateMemberRole(ctx, orgID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockOrganizationRepository)(nil).UpdateMemberRole), ctx, orgID, userID, role)
+}
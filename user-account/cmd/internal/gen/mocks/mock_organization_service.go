@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/organization_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockOrganizationProvider is a mock of OrganizationProvider interface.
+type MockOrganizationProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizationProviderMockRecorder
+}
+
+// MockOrganizationProviderMockRecorder is the mock recorder for MockOrganizationProvider.
+type MockOrganizationProviderMockRecorder struct {
+	mock *MockOrganizationProvider
+}
+
+// NewMockOrganizationProvider creates a new mock instance.
+func NewMockOrganizationProvider(ctrl *gomock.Controller) *MockOrganizationProvider {
+	mock := &MockOrganizationProvider{ctrl: ctrl}
+	mock.recorder = &MockOrganizationProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizationProvider) EXPECT() *MockOrganizationProviderMockRecorder {
+	return m.recorder
+}
+
+// AddMember mocks base method.
+func (m *MockOrganizationProvider) AddMember(ctx context.Context, orgID, actorID uuid.UUID, email, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMember", ctx, orgID, actorID, email, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMember indicates an expected call of AddMember.
+func (mr *MockOrganizationProviderMockRecorder) AddMember(ctx, orgID, actorID, email, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMember", reflect.TypeOf((*MockOrganizationProvider)(nil).AddMember), ctx, orgID, actorID, email, role)
+}
+
+// ChangeRole mocks base method.
+func (m *MockOrganizationProvider) ChangeRole(ctx context.Context, orgID, actorID, userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", ctx, orgID, actorID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockOrganizationProviderMockRecorder) ChangeRole(ctx, orgID, actorID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockOrganizationProvider)(nil).ChangeRole), ctx, orgID, actorID, userID, role)
+}
+
+// Create mocks base method.
+func (m *MockOrganizationProvider) Create(ctx context.Context, ownerID uuid.UUID, name string) (*model.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, ownerID, name)
+	ret0, _ := ret[0].(*model.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrganizationProviderMockRecorder) Create(ctx, ownerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrganizationProvider)(nil).Create), ctx, ownerID, name)
+}
+
+// Get mocks base method.
+func (m *MockOrganizationProvider) Get(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, orgID, userID)
+	ret0, _ := ret[0].(*model.OrganizationMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockOrganizationProviderMockRecorder) Get(ctx, orgID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOrganizationProvider)(nil).Get), ctx, orgID, userID)
+}
+
+// ListForUser mocks base method.
+func (m *MockOrganizationProvider) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListForUser", ctx, userID)
+	ret0, _ := ret[0].([]model.OrganizationMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListForUser indicates an expected call of ListForUser.
+func (mr *MockOrganizationProviderMockRecorder) ListForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForUser", reflect.TypeOf((*MockOrganizationProvider)(nil).ListForUser), ctx, userID)
+}
+
+// Members mocks base method.
+func (m *MockOrganizationProvider) Members(ctx context.Context, orgID, actorID uuid.UUID) ([]model.OrganizationMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Members", ctx, orgID, actorID)
+	ret0, _ := ret[0].([]model.OrganizationMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Members indicates an expected call of Members.
+func (mr *MockOrganizationProviderMockRecorder) Members(ctx, orgID, actorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Members", reflect.TypeOf((*MockOrganizationProvider)(nil).Members), ctx, orgID, actorID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockOrganizationProvider) RemoveMember(ctx context.Context, orgID, actorID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, orgID, actorID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockOrganizationProviderMockRecorder) RemoveMember(ctx, orgID, actorID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockOrganizationProvider)(nil).RemoveMember), ctx, orgID, actorID, userID)
+}
+
+// Rename mocks base method.
+func (m *MockOrganizationProvider) Rename(ctx context.Context, orgID, actorID uuid.UUID, name string) (*model.OrganizationMembership, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, orgID, actorID, name)
+	ret0, _ := ret[0].(*model.OrganizationMembership)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockOrganizationProviderMockRecorder) Rename(ctx, orgID, actorID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockOrganizationProvider)(nil).Rename), ctx, orgID, actorID, name)
+}
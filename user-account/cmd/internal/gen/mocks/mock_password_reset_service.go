@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/password_reset_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPasswordResetProvider is a mock of PasswordResetProvider interface.
+type MockPasswordResetProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasswordResetProviderMockRecorder
+}
+
+// MockPasswordResetProviderMockRecorder is the mock recorder for MockPasswordResetProvider.
+type MockPasswordResetProviderMockRecorder struct {
+	mock *MockPasswordResetProvider
+}
+
+// NewMockPasswordResetProvider creates a new mock instance.
+func NewMockPasswordResetProvider(ctrl *gomock.Controller) *MockPasswordResetProvider {
+	mock := &MockPasswordResetProvider{ctrl: ctrl}
+	mock.recorder = &MockPasswordResetProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPasswordResetProvider) EXPECT() *MockPasswordResetProviderMockRecorder {
+	return m.recorder
+}
+
+// RequestReset mocks base method.
+func (m *MockPasswordResetProvider) RequestReset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockPasswordResetProviderMockRecorder) RequestReset(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockPasswordResetProvider)(nil).RequestReset), ctx, email)
+}
+
+// ResetPassword mocks base method.
+func (m *MockPasswordResetProvider) ResetPassword(ctx context.Context, token, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockPasswordResetProviderMockRecorder) ResetPassword(ctx, token, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockPasswordResetProvider)(nil).ResetPassword), ctx, token, newPassword)
+}
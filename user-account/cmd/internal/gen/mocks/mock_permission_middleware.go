@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/middleware/permission_middleware.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPermissionChecker is a mock of PermissionChecker interface.
+type MockPermissionChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionCheckerMockRecorder
+}
+
+// MockPermissionCheckerMockRecorder is the mock recorder for MockPermissionChecker.
+type MockPermissionCheckerMockRecorder struct {
+	mock *MockPermissionChecker
+}
+
+// NewMockPermissionChecker creates a new mock instance.
+func NewMockPermissionChecker(ctrl *gomock.Controller) *MockPermissionChecker {
+	mock := &MockPermissionChecker{ctrl: ctrl}
+	mock.recorder = &MockPermissionCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionChecker) EXPECT() *MockPermissionCheckerMockRecorder {
+	return m.recorder
+}
+
+// HasPermission mocks base method.
+func (m *MockPermissionChecker) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasPermission", ctx, userID, permission)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasPermission indicates an expected call of HasPermission.
+func (mr *MockPermissionCheckerMockRecorder) HasPermission(ctx, userID, permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasPermission", reflect.TypeOf((*MockPermissionChecker)(nil).HasPermission), ctx, userID, permission)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/personal_data_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPersonalDataRepository is a mock of PersonalDataRepository interface.
+type MockPersonalDataRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalDataRepositoryMockRecorder
+}
+
+// MockPersonalDataRepositoryMockRecorder is the mock recorder for MockPersonalDataRepository.
+type MockPersonalDataRepositoryMockRecorder struct {
+	mock *MockPersonalDataRepository
+}
+
+// NewMockPersonalDataRepository creates a new mock instance.
+func NewMockPersonalDataRepository(ctrl *gomock.Controller) *MockPersonalDataRepository {
+	mock := &MockPersonalDataRepository{ctrl: ctrl}
+	mock.recorder = &MockPersonalDataRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalDataRepository) EXPECT() *MockPersonalDataRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Collect mocks base method.
+func (m *MockPersonalDataRepository) Collect(ctx context.Context, userID uuid.UUID) (*model.PersonalData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Collect", ctx, userID)
+	ret0, _ := ret[0].(*model.PersonalData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Collect indicates an expected call of Collect.
+func (mr *MockPersonalDataRepositoryMockRecorder) Collect(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockPersonalDataRepository)(nil).Collect), ctx, userID)
+}
+
+// Erase mocks base method.
+func (m *MockPersonalDataRepository) Erase(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, userID)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockPersonalDataRepositoryMockRecorder) Erase(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockPersonalDataRepository)(nil).Erase), ctx, userID)
+}
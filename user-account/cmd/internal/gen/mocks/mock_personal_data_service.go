@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/personal_data_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockPersonalDataProvider is a mock of PersonalDataProvider interface.
+type MockPersonalDataProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockPersonalDataProviderMockRecorder
+}
+
+// MockPersonalDataProviderMockRecorder is the mock recorder for MockPersonalDataProvider.
+type MockPersonalDataProviderMockRecorder struct {
+	mock *MockPersonalDataProvider
+}
+
+// NewMockPersonalDataProvider creates a new mock instance.
+func NewMockPersonalDataProvider(ctrl *gomock.Controller) *MockPersonalDataProvider {
+	mock := &MockPersonalDataProvider{ctrl: ctrl}
+	mock.recorder = &MockPersonalDataProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPersonalDataProvider) EXPECT() *MockPersonalDataProviderMockRecorder {
+	return m.recorder
+}
+
+// DownloadExport mocks base method.
+func (m *MockPersonalDataProvider) DownloadExport(ctx context.Context, userID, id uuid.UUID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadExport", ctx, userID, id)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadExport indicates an expected call of DownloadExport.
+func (mr *MockPersonalDataProviderMockRecorder) DownloadExport(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadExport", reflect.TypeOf((*MockPersonalDataProvider)(nil).DownloadExport), ctx, userID, id)
+}
+
+// Erase mocks base method.
+func (m *MockPersonalDataProvider) Erase(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockPersonalDataProviderMockRecorder) Erase(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockPersonalDataProvider)(nil).Erase), ctx, id)
+}
+
+// EraseAccount mocks base method.
+func (m *MockPersonalDataProvider) EraseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseAccount", ctx, id, password, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EraseAccount indicates an expected call of EraseAccount.
+func (mr *MockPersonalDataProviderMockRecorder) EraseAccount(ctx, id, password, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseAccount", reflect.TypeOf((*MockPersonalDataProvider)(nil).EraseAccount), ctx, id, password, token)
+}
+
+// GetExport mocks base method.
+func (m *MockPersonalDataProvider) GetExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExport", ctx, userID, id)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExport indicates an expected call of GetExport.
+func (mr *MockPersonalDataProviderMockRecorder) GetExport(ctx, userID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExport", reflect.TypeOf((*MockPersonalDataProvider)(nil).GetExport), ctx, userID, id)
+}
+
+// RequestExport mocks base method.
+func (m *MockPersonalDataProvider) RequestExport(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestExport", ctx, userID)
+	ret0, _ := ret[0].(*model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestExport indicates an expected call of RequestExport.
+func (mr *MockPersonalDataProviderMockRecorder) RequestExport(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestExport", reflect.TypeOf((*MockPersonalDataProvider)(nil).RequestExport), ctx, userID)
+}
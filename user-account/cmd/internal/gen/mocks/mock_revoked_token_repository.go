@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/revoked_token_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevokedTokenRepository is a mock of RevokedTokenRepository interface.
+type MockRevokedTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevokedTokenRepositoryMockRecorder
+}
+
+// MockRevokedTokenRepositoryMockRecorder is the mock recorder for MockRevokedTokenRepository.
+type MockRevokedTokenRepositoryMockRecorder struct {
+	mock *MockRevokedTokenRepository
+}
+
+// NewMockRevokedTokenRepository creates a new mock instance.
+func NewMockRevokedTokenRepository(ctrl *gomock.Controller) *MockRevokedTokenRepository {
+	mock := &MockRevokedTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockRevokedTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevokedTokenRepository) EXPECT() *MockRevokedTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockRevokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRevokedTokenRepositoryMockRecorder) DeleteExpired(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRevokedTokenRepository)(nil).DeleteExpired), ctx, now)
+}
+
+// IsRevoked mocks base method.
+func (m *MockRevokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockRevokedTokenRepositoryMockRecorder) IsRevoked(ctx, jti interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockRevokedTokenRepository)(nil).IsRevoked), ctx, jti)
+}
+
+// Revoke mocks base method.
+func (m *MockRevokedTokenRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockRevokedTokenRepositoryMockRecorder) Revoke(ctx, jti, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockRevokedTokenRepository)(nil).Revoke), ctx, jti, expiresAt)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/role_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRoleRepository is a mock of RoleRepository interface.
+type MockRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleRepositoryMockRecorder
+}
+
+// MockRoleRepositoryMockRecorder is the mock recorder for MockRoleRepository.
+type MockRoleRepositoryMockRecorder struct {
+	mock *MockRoleRepository
+}
+
+// NewMockRoleRepository creates a new mock instance.
+func NewMockRoleRepository(ctrl *gomock.Controller) *MockRoleRepository {
+	mock := &MockRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleRepository) EXPECT() *MockRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignToUser mocks base method.
+func (m *MockRoleRepository) AssignToUser(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignToUser", ctx, userID, role, grantedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignToUser indicates an expected call of AssignToUser.
+func (mr *MockRoleRepositoryMockRecorder) AssignToUser(ctx, userID, role, grantedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignToUser", reflect.TypeOf((*MockRoleRepository)(nil).AssignToUser), ctx, userID, role, grantedBy)
+}
+
+// Exists mocks base method.
+func (m *MockRoleRepository) Exists(ctx context.Context, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockRoleRepositoryMockRecorder) Exists(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockRoleRepository)(nil).Exists), ctx, name)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockRoleRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockRoleRepositoryMockRecorder) GetUserPermissions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockRoleRepository)(nil).GetUserPermissions), ctx, userID)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockRoleRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockRoleRepositoryMockRecorder) GetUserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockRoleRepository)(nil).GetUserRoles), ctx, userID)
+}
+
+// List mocks base method.
+func (m *MockRoleRepository) List(ctx context.Context) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockRoleRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockRoleRepository)(nil).List), ctx)
+}
+
+// RevokeFromUser mocks base method.
+func (m *MockRoleRepository) RevokeFromUser(ctx context.Context, userID uuid.UUID, role string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFromUser", ctx, userID, role)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeFromUser indicates an expected call of RevokeFromUser.
+func (mr *MockRoleRepositoryMockRecorder) RevokeFromUser(ctx, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFromUser", reflect.TypeOf((*MockRoleRepository)(nil).RevokeFromUser), ctx, userID, role)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/role_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockRoleProvider is a mock of RoleProvider interface.
+type MockRoleProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockRoleProviderMockRecorder
+}
+
+// MockRoleProviderMockRecorder is the mock recorder for MockRoleProvider.
+type MockRoleProviderMockRecorder struct {
+	mock *MockRoleProvider
+}
+
+// NewMockRoleProvider creates a new mock instance.
+func NewMockRoleProvider(ctrl *gomock.Controller) *MockRoleProvider {
+	mock := &MockRoleProvider{ctrl: ctrl}
+	mock.recorder = &MockRoleProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRoleProvider) EXPECT() *MockRoleProviderMockRecorder {
+	return m.recorder
+}
+
+// AssignRole mocks base method.
+func (m *MockRoleProvider) AssignRole(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignRole", ctx, userID, role, grantedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignRole indicates an expected call of AssignRole.
+func (mr *MockRoleProviderMockRecorder) AssignRole(ctx, userID, role, grantedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignRole", reflect.TypeOf((*MockRoleProvider)(nil).AssignRole), ctx, userID, role, grantedBy)
+}
+
+// ListRoles mocks base method.
+func (m *MockRoleProvider) ListRoles(ctx context.Context) ([]model.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles", ctx)
+	ret0, _ := ret[0].([]model.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockRoleProviderMockRecorder) ListRoles(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockRoleProvider)(nil).ListRoles), ctx)
+}
+
+// RevokeRole mocks base method.
+func (m *MockRoleProvider) RevokeRole(ctx context.Context, userID uuid.UUID, role string, revokedBy uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", ctx, userID, role, revokedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockRoleProviderMockRecorder) RevokeRole(ctx, userID, role, revokedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockRoleProvider)(nil).RevokeRole), ctx, userID, role, revokedBy)
+}
+
+// UserRoles mocks base method.
+func (m *MockRoleProvider) UserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserRoles", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserRoles indicates an expected call of UserRoles.
+func (mr *MockRoleProviderMockRecorder) UserRoles(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserRoles", reflect.TypeOf((*MockRoleProvider)(nil).UserRoles), ctx, userID)
+}
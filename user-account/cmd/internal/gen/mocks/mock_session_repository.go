@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/session_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockSessionRepository is a mock of SessionRepository interface.
+type MockSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryMockRecorder
+}
+
+// MockSessionRepositoryMockRecorder is the mock recorder for MockSessionRepository.
+type MockSessionRepositoryMockRecorder struct {
+	mock *MockSessionRepository
+}
+
+// NewMockSessionRepository creates a new mock instance.
+func NewMockSessionRepository(ctrl *gomock.Controller) *MockSessionRepository {
+	mock := &MockSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSessionRepository) Create(ctx context.Context, session *model.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSessionRepositoryMockRecorder) Create(ctx, session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionRepository)(nil).Create), ctx, session)
+}
+
+// GetByTokenHash mocks base method.
+func (m *MockSessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByTokenHash indicates an expected call of GetByTokenHash.
+func (mr *MockSessionRepositoryMockRecorder) GetByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTokenHash", reflect.TypeOf((*MockSessionRepository)(nil).GetByTokenHash), ctx, tokenHash)
+}
+
+// IsFamilyRevoked mocks base method.
+func (m *MockSessionRepository) IsFamilyRevoked(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsFamilyRevoked", ctx, familyID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsFamilyRevoked indicates an expected call of IsFamilyRevoked.
+func (mr *MockSessionRepositoryMockRecorder) IsFamilyRevoked(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsFamilyRevoked", reflect.TypeOf((*MockSessionRepository)(nil).IsFamilyRevoked), ctx, familyID)
+}
+
+// MarkRotated mocks base method.
+func (m *MockSessionRepository) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRotated", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRotated indicates an expected call of MarkRotated.
+func (mr *MockSessionRepositoryMockRecorder) MarkRotated(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRotated", reflect.TypeOf((*MockSessionRepository)(nil).MarkRotated), ctx, id)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockSessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockSessionRepositoryMockRecorder) RevokeAllForUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockSessionRepository)(nil).RevokeAllForUser), ctx, userID)
+}
+
+// RevokeFamily mocks base method.
+func (m *MockSessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockSessionRepositoryMockRecorder) RevokeFamily(ctx, familyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockSessionRepository)(nil).RevokeFamily), ctx, familyID)
+}
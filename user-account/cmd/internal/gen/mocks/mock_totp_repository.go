@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/totp_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTOTPRepository is a mock of TOTPRepository interface.
+type MockTOTPRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTOTPRepositoryMockRecorder
+}
+
+// MockTOTPRepositoryMockRecorder is the mock recorder for MockTOTPRepository.
+type MockTOTPRepositoryMockRecorder struct {
+	mock *MockTOTPRepository
+}
+
+// NewMockTOTPRepository creates a new mock instance.
+func NewMockTOTPRepository(ctrl *gomock.Controller) *MockTOTPRepository {
+	mock := &MockTOTPRepository{ctrl: ctrl}
+	mock.recorder = &MockTOTPRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTOTPRepository) EXPECT() *MockTOTPRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTOTPRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, step, recoveryCodeHashes)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTOTPRepositoryMockRecorder) Confirm(ctx, userID, step, recoveryCodeHashes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTOTPRepository)(nil).Confirm), ctx, userID, step, recoveryCodeHashes)
+}
+
+// ConsumeRecoveryCode mocks base method.
+func (m *MockTOTPRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockTOTPRepositoryMockRecorder) ConsumeRecoveryCode(ctx, userID, codeHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockTOTPRepository)(nil).ConsumeRecoveryCode), ctx, userID, codeHash)
+}
+
+// Delete mocks base method.
+func (m *MockTOTPRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTOTPRepositoryMockRecorder) Delete(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTOTPRepository)(nil).Delete), ctx, userID)
+}
+
+// Get mocks base method.
+func (m *MockTOTPRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TOTP, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userID)
+	ret0, _ := ret[0].(*model.TOTP)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockTOTPRepositoryMockRecorder) Get(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTOTPRepository)(nil).Get), ctx, userID)
+}
+
+// MarkStepUsed mocks base method.
+func (m *MockTOTPRepository) MarkStepUsed(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkStepUsed", ctx, userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkStepUsed indicates an expected call of MarkStepUsed.
+func (mr *MockTOTPRepositoryMockRecorder) MarkStepUsed(ctx, userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkStepUsed", reflect.TypeOf((*MockTOTPRepository)(nil).MarkStepUsed), ctx, userID, step)
+}
+
+// SavePending mocks base method.
+func (m *MockTOTPRepository) SavePending(ctx context.Context, userID uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePending", ctx, userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePending indicates an expected call of SavePending.
+func (mr *MockTOTPRepositoryMockRecorder) SavePending(ctx, userID, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePending", reflect.TypeOf((*MockTOTPRepository)(nil).SavePending), ctx, userID, secret)
+}
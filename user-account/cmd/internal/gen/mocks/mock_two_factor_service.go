@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/two_factor_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockTwoFactorProvider is a mock of TwoFactorProvider interface.
+type MockTwoFactorProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTwoFactorProviderMockRecorder
+}
+
+// MockTwoFactorProviderMockRecorder is the mock recorder for MockTwoFactorProvider.
+type MockTwoFactorProviderMockRecorder struct {
+	mock *MockTwoFactorProvider
+}
+
+// NewMockTwoFactorProvider creates a new mock instance.
+func NewMockTwoFactorProvider(ctrl *gomock.Controller) *MockTwoFactorProvider {
+	mock := &MockTwoFactorProvider{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTwoFactorProvider) EXPECT() *MockTwoFactorProviderMockRecorder {
+	return m.recorder
+}
+
+// Confirm mocks base method.
+func (m *MockTwoFactorProvider) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", ctx, userID, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockTwoFactorProviderMockRecorder) Confirm(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockTwoFactorProvider)(nil).Confirm), ctx, userID, code)
+}
+
+// Disable mocks base method.
+func (m *MockTwoFactorProvider) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disable", ctx, userID, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockTwoFactorProviderMockRecorder) Disable(ctx, userID, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockTwoFactorProvider)(nil).Disable), ctx, userID, code)
+}
+
+// Enroll mocks base method.
+func (m *MockTwoFactorProvider) Enroll(ctx context.Context, userID uuid.UUID) (*model.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", ctx, userID)
+	ret0, _ := ret[0].(*model.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enroll indicates an expected call of Enroll.
+func (mr *MockTwoFactorProviderMockRecorder) Enroll(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockTwoFactorProvider)(nil).Enroll), ctx, userID)
+}
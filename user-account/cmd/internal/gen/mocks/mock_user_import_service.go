@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/user_import_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserImportProvider is a mock of UserImportProvider interface.
+type MockUserImportProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserImportProviderMockRecorder
+}
+
+// MockUserImportProviderMockRecorder is the mock recorder for MockUserImportProvider.
+type MockUserImportProviderMockRecorder struct {
+	mock *MockUserImportProvider
+}
+
+// NewMockUserImportProvider creates a new mock instance.
+func NewMockUserImportProvider(ctrl *gomock.Controller) *MockUserImportProvider {
+	mock := &MockUserImportProvider{ctrl: ctrl}
+	mock.recorder = &MockUserImportProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserImportProvider) EXPECT() *MockUserImportProviderMockRecorder {
+	return m.recorder
+}
+
+// Import mocks base method.
+func (m *MockUserImportProvider) Import(ctx context.Context, orgID uuid.UUID, rows []model.UserImportRow, dryRun bool) ([]model.UserImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, orgID, rows, dryRun)
+	ret0, _ := ret[0].([]model.UserImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockUserImportProviderMockRecorder) Import(ctx, orgID, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockUserImportProvider)(nil).Import), ctx, orgID, rows, dryRun)
+}
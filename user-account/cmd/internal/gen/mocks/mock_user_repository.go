@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	model "user-account/cmd/internal/model"
 
 	gomock "github.com/golang/mock/gomock"
@@ -50,6 +51,20 @@ func (mr *MockUserRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockUserRepository)(nil).Create), ctx, user)
 }
 
+// CreateBatch mocks base method.
+func (m *MockUserRepository) CreateBatch(ctx context.Context, users []*model.User, orgID *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, users, orgID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockUserRepositoryMockRecorder) CreateBatch(ctx, users, orgID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockUserRepository)(nil).CreateBatch), ctx, users, orgID)
+}
+
 // Delete mocks base method.
 func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -64,6 +79,35 @@ func (mr *MockUserRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserRepository)(nil).Delete), ctx, id)
 }
 
+// ExistingAccounts mocks base method.
+func (m *MockUserRepository) ExistingAccounts(ctx context.Context, emails, nicknames []string) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingAccounts", ctx, emails, nicknames)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingAccounts indicates an expected call of ExistingAccounts.
+func (mr *MockUserRepositoryMockRecorder) ExistingAccounts(ctx, emails, nicknames interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingAccounts", reflect.TypeOf((*MockUserRepository)(nil).ExistingAccounts), ctx, emails, nicknames)
+}
+
+// ForEach mocks base method.
+func (m *MockUserRepository) ForEach(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEach", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEach indicates an expected call of ForEach.
+func (mr *MockUserRepositoryMockRecorder) ForEach(ctx, query, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEach", reflect.TypeOf((*MockUserRepository)(nil).ForEach), ctx, query, fn)
+}
+
 // GetByEmail mocks base method.
 func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	m.ctrl.T.Helper()
@@ -95,18 +139,93 @@ func (mr *MockUserRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.C
 }
 
 // List mocks base method.
-func (m *MockUserRepository) List(ctx context.Context) ([]model.User, error) {
+func (m *MockUserRepository) List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]model.User)
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*model.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserRepositoryMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockUserRepositoryMockRecorder) List(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx, query)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", ctx, id, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserRepositoryMockRecorder) MarkEmailVerified(ctx, id, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserRepository)(nil).MarkEmailVerified), ctx, id, email)
+}
+
+// Purge mocks base method.
+func (m *MockUserRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockUserRepositoryMockRecorder) Purge(ctx, deletedBefore interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserRepository)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockUserRepository)(nil).Purge), ctx, deletedBefore)
+}
+
+// RehashPassword mocks base method.
+func (m *MockUserRepository) RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RehashPassword", ctx, id, oldHash, newHash)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RehashPassword indicates an expected call of RehashPassword.
+func (mr *MockUserRepositoryMockRecorder) RehashPassword(ctx, id, oldHash, newHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehashPassword", reflect.TypeOf((*MockUserRepository)(nil).RehashPassword), ctx, id, oldHash, newHash)
+}
+
+// Restore mocks base method.
+func (m *MockUserRepository) Restore(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserRepositoryMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserRepository)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockUserRepository) Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]model.UserSearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUserRepositoryMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUserRepository)(nil).Search), ctx, query)
 }
 
 // UpdatePassword mocks base method.
@@ -122,3 +241,17 @@ func (mr *MockUserRepositoryMockRecorder) UpdatePassword(ctx, id, hash interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserRepository)(nil).UpdatePassword), ctx, id, hash)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, nickname string, preferences model.Preferences) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, id, nickname, preferences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepositoryMockRecorder) UpdateProfile(ctx, id, nickname, preferences interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepository)(nil).UpdateProfile), ctx, id, nickname, preferences)
+}
@@ -36,6 +36,34 @@ func (m *MockUserProvider) EXPECT() *MockUserProviderMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserProvider) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, id, currentPassword, newPassword)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserProviderMockRecorder) ChangePassword(ctx, id, currentPassword, newPassword interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserProvider)(nil).ChangePassword), ctx, id, currentPassword, newPassword)
+}
+
+// CloseAccount mocks base method.
+func (m *MockUserProvider) CloseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccount", ctx, id, password, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseAccount indicates an expected call of CloseAccount.
+func (mr *MockUserProviderMockRecorder) CloseAccount(ctx, id, password, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccount", reflect.TypeOf((*MockUserProvider)(nil).CloseAccount), ctx, id, password, token)
+}
+
 // Delete mocks base method.
 func (m *MockUserProvider) Delete(ctx context.Context, id uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -50,19 +78,91 @@ func (mr *MockUserProviderMockRecorder) Delete(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockUserProvider)(nil).Delete), ctx, id)
 }
 
+// Export mocks base method.
+func (m *MockUserProvider) Export(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockUserProviderMockRecorder) Export(ctx, query, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockUserProvider)(nil).Export), ctx, query, fn)
+}
+
+// Get mocks base method.
+func (m *MockUserProvider) Get(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockUserProviderMockRecorder) Get(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockUserProvider)(nil).Get), ctx, id)
+}
+
 // List mocks base method.
-func (m *MockUserProvider) List(ctx context.Context) ([]model.User, error) {
+func (m *MockUserProvider) List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "List", ctx)
-	ret0, _ := ret[0].([]model.User)
+	ret := m.ctrl.Call(m, "List", ctx, query)
+	ret0, _ := ret[0].(*model.UserPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // List indicates an expected call of List.
-func (mr *MockUserProviderMockRecorder) List(ctx interface{}) *gomock.Call {
+func (mr *MockUserProviderMockRecorder) List(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserProvider)(nil).List), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockUserProvider)(nil).List), ctx, query)
+}
+
+// Restore mocks base method.
+func (m *MockUserProvider) Restore(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockUserProviderMockRecorder) Restore(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockUserProvider)(nil).Restore), ctx, id)
+}
+
+// Search mocks base method.
+func (m *MockUserProvider) Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].([]model.UserSearchHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockUserProviderMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockUserProvider)(nil).Search), ctx, query)
+}
+
+// Unlock mocks base method.
+func (m *MockUserProvider) Unlock(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockUserProviderMockRecorder) Unlock(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockUserProvider)(nil).Unlock), ctx, id)
 }
 
 // UpdatePassword mocks base method.
@@ -78,3 +178,18 @@ func (mr *MockUserProviderMockRecorder) UpdatePassword(ctx, id, newPassword inte
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockUserProvider)(nil).UpdatePassword), ctx, id, newPassword)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockUserProvider) UpdateProfile(ctx context.Context, id uuid.UUID, update model.ProfileUpdate) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, id, update)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserProviderMockRecorder) UpdateProfile(ctx, id, update interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserProvider)(nil).UpdateProfile), ctx, id, update)
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/repository/user_status_repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserStatusRepository is a mock of UserStatusRepository interface.
+type MockUserStatusRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatusRepositoryMockRecorder
+}
+
+// MockUserStatusRepositoryMockRecorder is the mock recorder for MockUserStatusRepository.
+type MockUserStatusRepositoryMockRecorder struct {
+	mock *MockUserStatusRepository
+}
+
+// NewMockUserStatusRepository creates a new mock instance.
+func NewMockUserStatusRepository(ctrl *gomock.Controller) *MockUserStatusRepository {
+	mock := &MockUserStatusRepository{ctrl: ctrl}
+	mock.recorder = &MockUserStatusRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatusRepository) EXPECT() *MockUserStatusRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Change mocks base method.
+func (m *MockUserStatusRepository) Change(ctx context.Context, change *model.UserStatusChange) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Change", ctx, change)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Change indicates an expected call of Change.
+func (mr *MockUserStatusRepositoryMockRecorder) Change(ctx, change interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Change", reflect.TypeOf((*MockUserStatusRepository)(nil).Change), ctx, change)
+}
+
+// History mocks base method.
+func (m *MockUserStatusRepository) History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, userID)
+	ret0, _ := ret[0].([]model.UserStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockUserStatusRepositoryMockRecorder) History(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockUserStatusRepository)(nil).History), ctx, userID)
+}
@@ -0,0 +1,83 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/user_status_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	model "user-account/cmd/internal/model"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockUserStatusProvider is a mock of UserStatusProvider interface.
+type MockUserStatusProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserStatusProviderMockRecorder
+}
+
+// MockUserStatusProviderMockRecorder is the mock recorder for MockUserStatusProvider.
+type MockUserStatusProviderMockRecorder struct {
+	mock *MockUserStatusProvider
+}
+
+// NewMockUserStatusProvider creates a new mock instance.
+func NewMockUserStatusProvider(ctrl *gomock.Controller) *MockUserStatusProvider {
+	mock := &MockUserStatusProvider{ctrl: ctrl}
+	mock.recorder = &MockUserStatusProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserStatusProvider) EXPECT() *MockUserStatusProviderMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockUserStatusProvider) History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, userID)
+	ret0, _ := ret[0].([]model.UserStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockUserStatusProviderMockRecorder) History(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockUserStatusProvider)(nil).History), ctx, userID)
+}
+
+// Reinstate mocks base method.
+func (m *MockUserStatusProvider) Reinstate(ctx context.Context, actorID, userID uuid.UUID, reason string) (*model.UserStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reinstate", ctx, actorID, userID, reason)
+	ret0, _ := ret[0].(*model.UserStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reinstate indicates an expected call of Reinstate.
+func (mr *MockUserStatusProviderMockRecorder) Reinstate(ctx, actorID, userID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reinstate", reflect.TypeOf((*MockUserStatusProvider)(nil).Reinstate), ctx, actorID, userID, reason)
+}
+
+// Suspend mocks base method.
+func (m *MockUserStatusProvider) Suspend(ctx context.Context, actorID, userID uuid.UUID, status, reason string, expiresAt *time.Time) (*model.UserStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suspend", ctx, actorID, userID, status, reason, expiresAt)
+	ret0, _ := ret[0].(*model.UserStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suspend indicates an expected call of Suspend.
+func (mr *MockUserStatusProviderMockRecorder) Suspend(ctx, actorID, userID, status, reason, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suspend", reflect.TypeOf((*MockUserStatusProvider)(nil).Suspend), ctx, actorID, userID, status, reason, expiresAt)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cmd/internal/handler/verification_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockVerificationProvider is a mock of VerificationProvider interface.
+type MockVerificationProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationProviderMockRecorder
+}
+
+// MockVerificationProviderMockRecorder is the mock recorder for MockVerificationProvider.
+type MockVerificationProviderMockRecorder struct {
+	mock *MockVerificationProvider
+}
+
+// NewMockVerificationProvider creates a new mock instance.
+func NewMockVerificationProvider(ctrl *gomock.Controller) *MockVerificationProvider {
+	mock := &MockVerificationProvider{ctrl: ctrl}
+	mock.recorder = &MockVerificationProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVerificationProvider) EXPECT() *MockVerificationProviderMockRecorder {
+	return m.recorder
+}
+
+// Resend mocks base method.
+func (m *MockVerificationProvider) Resend(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resend", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Resend indicates an expected call of Resend.
+func (mr *MockVerificationProviderMockRecorder) Resend(ctx, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resend", reflect.TypeOf((*MockVerificationProvider)(nil).Resend), ctx, email)
+}
+
+// Verify mocks base method.
+func (m *MockVerificationProvider) Verify(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockVerificationProviderMockRecorder) Verify(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockVerificationProvider)(nil).Verify), ctx, token)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type APIKeyProvider interface {
+	Create(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID, name string, scopes []string, ttl time.Duration) (*model.APIKey, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type APIKeyHandler struct {
+	baseHandler
+	apiKeyService APIKeyProvider
+}
+
+func NewAPIKeyHandler(apiKeyService APIKeyProvider) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// createdAPIKey - ответ на выпуск ключа. Key отдаётся только здесь, потом
+// его уже не получить.
+type createdAPIKey struct {
+	model.APIKey
+	Key string `json:"key"`
+}
+
+// ListMine - GET /me/api-keys
+func (h *APIKeyHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.list(w, r, current.ID)
+}
+
+// CreateMine - POST /me/api-keys, ключ возвращается один раз
+func (h *APIKeyHandler) CreateMine(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Ключ действует в организации, активной в момент выпуска
+	var orgID *uuid.UUID
+	if id := middleware.OrganizationFromContext(r.Context()); id != uuid.Nil {
+		orgID = &id
+	}
+
+	key, plain, err := h.apiKeyService.Create(r.Context(), current.ID, orgID, req.Name, req.Scopes, req.TTL())
+	if err != nil {
+		h.writeAPIKeyError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, createdAPIKey{APIKey: *key, Key: plain})
+}
+
+// RevokeMine - DELETE /me/api-keys/{id}
+func (h *APIKeyHandler) RevokeMine(w http.ResponseWriter, r *http.Request, keyIDStr string) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.revoke(w, r, current.ID, keyIDStr)
+}
+
+// ListUserKeys - GET /users/{id}/api-keys
+func (h *APIKeyHandler) ListUserKeys(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	h.list(w, r, id)
+}
+
+// RevokeUserKey - DELETE /users/{id}/api-keys/{keyID}, отзыв ключа
+// администратором
+func (h *APIKeyHandler) RevokeUserKey(w http.ResponseWriter, r *http.Request, idStr, keyIDStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	h.revoke(w, r, id, keyIDStr)
+}
+
+func (h *APIKeyHandler) list(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	keys, err := h.apiKeyService.List(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, "failed to fetch api keys", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, keys)
+}
+
+func (h *APIKeyHandler) revoke(w http.ResponseWriter, r *http.Request, userID uuid.UUID, keyIDStr string) {
+	keyID, err := uuid.Parse(keyIDStr)
+	if err != nil {
+		h.writeError(w, "invalid api key ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.apiKeyService.Revoke(r.Context(), userID, keyID); err != nil {
+		h.writeAPIKeyError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *APIKeyHandler) writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidAPIKeyScope):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrAPIKeyTTLTooLong):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyHandler_CreateMine(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockAPIKeyProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			body: `{"name":"ci","scopes":["users:read"],"expires_in_days":30}`,
+			mockBehavior: func(m *mocks.MockAPIKeyProvider) {
+				m.EXPECT().Create(gomock.Any(), userID, &orgID, "ci", []string{"users:read"}, 30*24*time.Hour).
+					Return(&model.APIKey{ID: uuid.New(), UserID: userID, Name: "ci", Prefix: "ak_abcdefgh", KeyHash: "hash"}, "ak_abcdefgh-secret", nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"key":"ak_abcdefgh-secret"`,
+		},
+		{
+			name:           "2. Missing Scopes",
+			body:           `{"name":"ci"}`,
+			mockBehavior:   func(_ *mocks.MockAPIKeyProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "3. Scope Not Granted",
+			body: `{"name":"ci","scopes":["roles:manage"]}`,
+			mockBehavior: func(m *mocks.MockAPIKeyProvider) {
+				m.EXPECT().Create(gomock.Any(), userID, &orgID, "ci", []string{"roles:manage"}, time.Duration(0)).
+					Return(nil, "", fmt.Errorf("%w: roles:manage", service.ErrInvalidAPIKeyScope))
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "4. Lifetime Too Long",
+			body: `{"name":"ci","scopes":["users:read"],"expires_in_days":3650}`,
+			mockBehavior: func(m *mocks.MockAPIKeyProvider) {
+				m.EXPECT().Create(gomock.Any(), userID, &orgID, "ci", []string{"users:read"}, 3650*24*time.Hour).
+					Return(nil, "", service.ErrAPIKeyTTLTooLong)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAPIKeyProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAPIKeyHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/api-keys", bytes.NewBufferString(tt.body))
+			ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+			req = req.WithContext(middleware.ContextWithOrganization(ctx, orgID))
+			w := httptest.NewRecorder()
+			h.CreateMine(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.NotContains(t, w.Body.String(), "key_hash")
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHandler_ListMine(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	mockSvc := mocks.NewMockAPIKeyProvider(ctrl)
+	mockSvc.EXPECT().List(gomock.Any(), userID).Return([]model.APIKey{
+		{ID: uuid.New(), UserID: userID, Name: "ci", Prefix: "ak_abcdefgh", KeyHash: "hash"},
+	}, nil)
+
+	h := NewAPIKeyHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/api-keys", nil)
+	req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+	w := httptest.NewRecorder()
+	h.ListMine(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"prefix":"ak_abcdefgh"`)
+	assert.NotContains(t, w.Body.String(), "hash")
+}
+
+func TestAPIKeyHandler_RevokeUserKey(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		keyID          string
+		mockBehavior   func(m *mocks.MockAPIKeyProvider)
+		expectedStatus int
+	}{
+		{
+			name:  "1. Success",
+			id:    userID.String(),
+			keyID: keyID.String(),
+			mockBehavior: func(m *mocks.MockAPIKeyProvider) {
+				m.EXPECT().Revoke(gomock.Any(), userID, keyID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:  "2. Not Found",
+			id:    userID.String(),
+			keyID: keyID.String(),
+			mockBehavior: func(m *mocks.MockAPIKeyProvider) {
+				m.EXPECT().Revoke(gomock.Any(), userID, keyID).Return(service.ErrAPIKeyNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Invalid User ID",
+			id:             "not-a-uuid",
+			keyID:          keyID.String(),
+			mockBehavior:   func(_ *mocks.MockAPIKeyProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Invalid Key ID",
+			id:             userID.String(),
+			keyID:          "not-a-uuid",
+			mockBehavior:   func(_ *mocks.MockAPIKeyProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAPIKeyProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAPIKeyHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+tt.id+"/api-keys/"+tt.keyID, nil)
+			w := httptest.NewRecorder()
+			h.RevokeUserKey(w, req, tt.id, tt.keyID)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"user-account/cmd/internal/model"
+)
+
+type AuditProvider interface {
+	List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error)
+	Verify(ctx context.Context) (*model.AuditChainCheck, error)
+}
+
+// AuditHandler - просмотр журнала аудита администратором
+type AuditHandler struct {
+	baseHandler
+	audit AuditProvider
+}
+
+func NewAuditHandler(audit AuditProvider) *AuditHandler {
+	return &AuditHandler{audit: audit}
+}
+
+// List - GET /audit, записи журнала, новые первыми. Параметры запроса
+// описаны у parseAuditQuery, следующая страница запрашивается с next_cursor.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	query, err := parseAuditQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.audit.List(r.Context(), query)
+	if err != nil {
+		h.writeError(w, "failed to fetch audit events", http.StatusInternalServerError)
+		return
+	}
+
+	type pageResponse struct {
+		Events     []model.AuditEvent `json:"events"`
+		NextCursor int64              `json:"next_cursor,omitempty"`
+	}
+	h.writeJSON(w, http.StatusOK, pageResponse{Events: page.Events, NextCursor: page.NextCursor})
+}
+
+// Verify - GET /audit/verify, пересчитывает цепочку хэшей журнала и
+// сообщает первую запись, которая с ней не сходится
+func (h *AuditHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	check, err := h.audit.Verify(r.Context())
+	if err != nil {
+		h.writeError(w, "failed to verify audit log", http.StatusInternalServerError)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, check)
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHandler_List(t *testing.T) {
+	t.Parallel()
+
+	actorID := uuid.New()
+
+	tests := []struct {
+		name           string
+		url            string
+		mockBehavior   func(m *mocks.MockAuditProvider)
+		expectedStatus int
+		expectedCursor interface{}
+	}{
+		{
+			name: "1. Filters And Next Page",
+			url:  "/audit?actor_id=" + actorID.String() + "&action=auth.login&outcome=failure&limit=1&cursor=10",
+			mockBehavior: func(m *mocks.MockAuditProvider) {
+				m.EXPECT().List(gomock.Any(), model.AuditEventQuery{
+					ActorID: &actorID,
+					Action:  model.AuditLogin,
+					Outcome: model.AuditFailure,
+					Limit:   1,
+					Before:  10,
+				}).Return(&model.AuditEventPage{Events: []model.AuditEvent{{ID: 9, Action: model.AuditLogin}}, NextCursor: 9}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedCursor: float64(9),
+		},
+		{
+			name: "2. Last Page",
+			url:  "/audit",
+			mockBehavior: func(m *mocks.MockAuditProvider) {
+				m.EXPECT().List(gomock.Any(), model.AuditEventQuery{}).Return(&model.AuditEventPage{Events: []model.AuditEvent{}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "3. Invalid Outcome",
+			url:            "/audit?outcome=maybe",
+			mockBehavior:   func(_ *mocks.MockAuditProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Invalid Actor",
+			url:            "/audit?actor_id=nope",
+			mockBehavior:   func(_ *mocks.MockAuditProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "5. Invalid Cursor",
+			url:            "/audit?cursor=-1",
+			mockBehavior:   func(_ *mocks.MockAuditProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "6. Internal Error",
+			url:  "/audit",
+			mockBehavior: func(m *mocks.MockAuditProvider) {
+				m.EXPECT().List(gomock.Any(), gomock.Any()).Return(nil, errors.New("db failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAuditProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAuditHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			h.List(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Contains(t, resp, "events")
+				assert.Equal(t, tt.expectedCursor, resp["next_cursor"])
+			}
+		})
+	}
+}
+
+func TestAuditHandler_Verify(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockAuditProvider(ctrl)
+	brokenAt := int64(5)
+	mockSvc.EXPECT().Verify(gomock.Any()).Return(&model.AuditChainCheck{Valid: false, Checked: 4, BrokenAt: &brokenAt}, nil)
+
+	w := httptest.NewRecorder()
+	NewAuditHandler(mockSvc).Verify(w, httptest.NewRequest(http.MethodGet, "/audit/verify", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"valid":false,"checked":4,"broken_at":5}`, w.Body.String())
+}
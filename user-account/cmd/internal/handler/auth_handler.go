@@ -3,12 +3,46 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
 )
 
 type AuthProvider interface {
 	Register(ctx context.Context, email, password, nickname string) error
-	Login(ctx context.Context, email, password string) (string, error)
+	Login(ctx context.Context, email, password string, client model.ClientInfo) (*model.LoginResult, error)
+	LoginTwoFactor(ctx context.Context, mfaToken, code string, client model.ClientInfo) (*model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenPair, error)
+	Logout(ctx context.Context, token model.TokenClaims) error
+	LogoutAll(ctx context.Context, userID uuid.UUID, token model.TokenClaims) error
+	SwitchOrganization(ctx context.Context, userID uuid.UUID, token model.TokenClaims, orgID uuid.UUID, client model.ClientInfo) (*model.TokenPair, error)
+}
+
+// tokenResponse - ответ с парой токенов
+type tokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Email        string `json:"email,omitempty"`
+}
+
+// mfaRequiredResponse - ответ на пароль, когда нужен второй шаг входа
+type mfaRequiredResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+}
+
+func newTokenResponse(tokens *model.TokenPair, email string) tokenResponse {
+	return tokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int64(tokens.ExpiresIn.Seconds()),
+		Email:        email,
+	}
 }
 
 type AuthHandler struct {
@@ -34,6 +68,9 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.authService.Register(r.Context(), req.Email, req.Password, req.Nickname); err != nil {
+		if h.writePolicyError(w, err) {
+			return
+		}
 		h.writeError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -57,18 +94,159 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, middleware.ClientFromContext(r.Context()))
 	if err != nil {
+		if h.writeLocked(w, err) {
+			return
+		}
+		if errors.Is(err, service.ErrEmailNotVerified) {
+			h.writeError(w, "Email is not verified", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, service.ErrAccountSuspended) {
+			h.writeError(w, "Account is suspended", http.StatusForbidden)
+			return
+		}
 		h.writeError(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, map[string]string{
-		"token": token,
-		"email": req.Email,
-	})
+	if result.MFAToken != "" {
+		h.writeJSON(w, http.StatusOK, mfaRequiredResponse{MFARequired: true, MFAToken: result.MFAToken})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newTokenResponse(result.Tokens, req.Email))
+}
+
+// LoginTwoFactor - POST /login/2fa, второй шаг входа
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req LoginTwoFactorRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authService.LoginTwoFactor(r.Context(), req.MFAToken, req.Code, middleware.ClientFromContext(r.Context()))
+	if err != nil {
+		if h.writeLocked(w, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidMFAToken) || errors.Is(err, service.ErrInvalidTwoFactorCode) {
+			h.writeError(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, service.ErrAccountSuspended) {
+			h.writeError(w, "Account is suspended", http.StatusForbidden)
+			return
+		}
+		h.writeError(w, "failed to login", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newTokenResponse(tokens, ""))
 }
 
-func (h *AuthHandler) Logout(w http.ResponseWriter, _ *http.Request) {
+// Refresh - POST /token/refresh
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken, middleware.ClientFromContext(r.Context()))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) || errors.Is(err, service.ErrRefreshTokenReused) {
+			h.writeError(w, "Invalid refresh token", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, service.ErrAccountSuspended) {
+			h.writeError(w, "Account is suspended", http.StatusForbidden)
+			return
+		}
+		h.writeError(w, "failed to refresh token", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newTokenResponse(tokens, ""))
+}
+
+// Logout - POST /logout, выход на текущем устройстве
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token := middleware.TokenFromContext(r.Context())
+	if token == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), *token); err != nil {
+		h.writeError(w, "failed to logout", http.StatusInternalServerError)
+		return
+	}
+
 	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
+
+// LogoutAll - POST /logout/all, выход на всех устройствах
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	token := middleware.TokenFromContext(r.Context())
+	if user == nil || token == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), user.ID, *token); err != nil {
+		h.writeError(w, "failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// SwitchOrganization - POST /me/organization, смена активной организации.
+// Текущая сессия завершается, в ответе новая пара токенов.
+func (h *AuthHandler) SwitchOrganization(w http.ResponseWriter, r *http.Request) {
+	user := middleware.UserFromContext(r.Context())
+	token := middleware.TokenFromContext(r.Context())
+	if user == nil || token == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req SwitchOrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tokens, err := h.authService.SwitchOrganization(r.Context(), user.ID, *token, req.OrganizationID, middleware.ClientFromContext(r.Context()))
+	if err != nil {
+		if errors.Is(err, service.ErrOrganizationNotFound) {
+			h.writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeError(w, "failed to switch organization", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newTokenResponse(tokens, ""))
+}
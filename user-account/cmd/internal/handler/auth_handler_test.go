@@ -7,9 +7,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/service"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,28 +37,60 @@ func TestAuthHandler_Login(t *testing.T) {
 			},
 			mockBehavior: func(m *mocks.MockAuthProvider) {
 				m.EXPECT().
-					Login(gomock.Any(), "test@example.com", "password123").
-					Return("fake-jwt-token", nil)
+					Login(gomock.Any(), "test@example.com", "password123", gomock.Any()).
+					Return(&model.LoginResult{Tokens: &model.TokenPair{
+						AccessToken:  "fake-jwt-token",
+						RefreshToken: "fake-refresh-token",
+						ExpiresIn:    15 * time.Minute,
+					}}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `"token":"fake-jwt-token"`,
+			expectedBody:   `"token":"fake-jwt-token","refresh_token":"fake-refresh-token","expires_in":900`,
 		},
 		{
-			name: "2. Invalid Credentials",
+			name: "2. Second Factor Required",
+			requestBody: LoginRequest{
+				Email:    "mfa@example.com",
+				Password: "password123",
+			},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Login(gomock.Any(), "mfa@example.com", "password123", gomock.Any()).
+					Return(&model.LoginResult{MFAToken: "challenge"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"mfa_required":true,"mfa_token":"challenge"}`,
+		},
+		{
+			name: "3. Invalid Credentials",
 			requestBody: LoginRequest{
 				Email:    "wrong@test.com",
 				Password: "wrong_password",
 			},
 			mockBehavior: func(m *mocks.MockAuthProvider) {
 				m.EXPECT().
-					Login(gomock.Any(), "wrong@test.com", "wrong_password").
-					Return("", errors.New("auth failed"))
+					Login(gomock.Any(), "wrong@test.com", "wrong_password", gomock.Any()).
+					Return(nil, errors.New("auth failed"))
 			},
 			expectedStatus: http.StatusUnauthorized,
 			expectedBody:   `"error":"Invalid credentials"`,
 		},
 		{
-			name: "3. Validation Error (Empty Email)",
+			name: "4. Email Not Verified",
+			requestBody: LoginRequest{
+				Email:    "new@test.com",
+				Password: "password123",
+			},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Login(gomock.Any(), "new@test.com", "password123", gomock.Any()).
+					Return(nil, service.ErrEmailNotVerified)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `"error":"Email is not verified"`,
+		},
+		{
+			name: "5. Validation Error (Empty Email)",
 			requestBody: LoginRequest{
 				Email:    "",
 				Password: "password123",
@@ -61,6 +99,20 @@ func TestAuthHandler_Login(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedBody:   `"error":"email is required"`,
 		},
+		{
+			name: "6. Account Suspended",
+			requestBody: LoginRequest{
+				Email:    "gone@test.com",
+				Password: "password123",
+			},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Login(gomock.Any(), "gone@test.com", "password123", gomock.Any()).
+					Return(nil, service.ErrAccountSuspended)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `"error":"Account is suspended"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -88,6 +140,87 @@ func TestAuthHandler_Login(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_LoginTwoFactor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockAuthProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			body: `{"mfa_token":"challenge","code":"123456"}`,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					LoginTwoFactor(gomock.Any(), "challenge", "123456", gomock.Any()).
+					Return(&model.TokenPair{AccessToken: "jwt", RefreshToken: "refresh", ExpiresIn: time.Minute}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "2. Invalid Code",
+			body: `{"mfa_token":"challenge","code":"000000"}`,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					LoginTwoFactor(gomock.Any(), "challenge", "000000", gomock.Any()).
+					Return(nil, service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "3. Missing Code",
+			body:           `{"mfa_token":"challenge"}`,
+			mockBehavior:   func(m *mocks.MockAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAuthHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.LoginTwoFactor(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthHandler_LoginLocked(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	mockSvc := mocks.NewMockAuthProvider(ctrl)
+	mockSvc.EXPECT().
+		Login(gomock.Any(), "victim@example.com", "guess", gomock.Any()).
+		Return(nil, &service.LoginLockedError{RetryAfter: 90*time.Second + time.Millisecond})
+	mockSvc.EXPECT().
+		LoginTwoFactor(gomock.Any(), "challenge", "000000", gomock.Any()).
+		Return(nil, &service.LoginLockedError{RetryAfter: time.Millisecond})
+
+	h := NewAuthHandler(mockSvc)
+
+	w := httptest.NewRecorder()
+	h.Login(w, httptest.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(`{"email":"victim@example.com","password":"guess"}`)))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	h.LoginTwoFactor(w, httptest.NewRequest(http.MethodPost, "/login/2fa", bytes.NewBufferString(`{"mfa_token":"challenge","code":"000000"}`)))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
 func TestAuthHandler_Register(t *testing.T) {
 	t.Parallel()
 
@@ -114,15 +247,34 @@ func TestAuthHandler_Register(t *testing.T) {
 			expectedBody:   `"status":"ok"`,
 		},
 		{
-			name: "2. Validation Error (Short Password)",
+			name: "2. Validation Error (Missing Password)",
 			requestBody: RegisterRequest{
 				Email:    "test@user.com",
-				Password: "123",
+				Password: "",
 				Nickname: "nick",
 			},
 			mockBehavior:   func(m *mocks.MockAuthProvider) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `"error":"password must be at least 6 characters long"`,
+			expectedBody:   `"error":"password is required"`,
+		},
+		{
+			name: "2a. Password Policy Violation",
+			requestBody: RegisterRequest{
+				Email:    "test@user.com",
+				Password: "123",
+				Nickname: "nick",
+			},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().Register(gomock.Any(), "test@user.com", "123", "nick").Return(&passpolicy.Error{
+					Violations: []passpolicy.Violation{{
+						Rule:    passpolicy.RuleMinLength,
+						Message: "password must be at least 8 characters long",
+						Params:  map[string]int{"min": 8},
+					}},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"violations":[{"rule":"min_length","message":"password must be at least 8 characters long","params":{"min":8}}]`,
 		},
 		{
 			name: "3. Service Error (User Exists)",
@@ -168,3 +320,241 @@ func TestAuthHandler_Register(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockBehavior   func(m *mocks.MockAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "1. Success Refresh",
+			requestBody: RefreshRequest{RefreshToken: "old-refresh"},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Refresh(gomock.Any(), "old-refresh", gomock.Any()).
+					Return(&model.TokenPair{
+						AccessToken:  "new-access",
+						RefreshToken: "new-refresh",
+						ExpiresIn:    time.Minute,
+					}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"refresh_token":"new-refresh"`,
+		},
+		{
+			name:        "2. Reused Token",
+			requestBody: RefreshRequest{RefreshToken: "stolen"},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Refresh(gomock.Any(), "stolen", gomock.Any()).
+					Return(nil, service.ErrRefreshTokenReused)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `"error":"Invalid refresh token"`,
+		},
+		{
+			name:        "3. Storage Failure",
+			requestBody: RefreshRequest{RefreshToken: "any"},
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					Refresh(gomock.Any(), "any", gomock.Any()).
+					Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. Missing Token",
+			requestBody:    RefreshRequest{},
+			mockBehavior:   func(_ *mocks.MockAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"refresh_token is required"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAuthHandler(mockSvc)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/token/refresh", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			h.Refresh(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{ID: uuid.New()}
+	token := &model.TokenClaims{
+		ID:        uuid.NewString(),
+		SessionID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	tests := []struct {
+		name           string
+		all            bool
+		withToken      bool
+		mockBehavior   func(m *mocks.MockAuthProvider)
+		expectedStatus int
+	}{
+		{
+			name:      "1. Logout Current Device",
+			withToken: true,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().Logout(gomock.Any(), *token).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "2. Logout Everywhere",
+			all:       true,
+			withToken: true,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().LogoutAll(gomock.Any(), user.ID, *token).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:      "3. Revocation Failure",
+			withToken: true,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().Logout(gomock.Any(), *token).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. No Token In Context",
+			mockBehavior:   func(_ *mocks.MockAuthProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAuthHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/logout", nil)
+			if tt.withToken {
+				ctx := middleware.ContextWithUser(req.Context(), user)
+				ctx = middleware.ContextWithToken(ctx, token)
+				req = req.WithContext(ctx)
+			}
+
+			w := httptest.NewRecorder()
+			if tt.all {
+				h.LogoutAll(w, req)
+			} else {
+				h.Logout(w, req)
+			}
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestAuthHandler_SwitchOrganization(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{ID: uuid.New()}
+	token := &model.TokenClaims{
+		ID:        uuid.NewString(),
+		SessionID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			body: `{"organization_id":"` + orgID.String() + `"}`,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					SwitchOrganization(gomock.Any(), user.ID, *token, orgID, gomock.Any()).
+					Return(&model.TokenPair{AccessToken: "org-access", RefreshToken: "org-refresh", ExpiresIn: time.Minute}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"token":"org-access"`,
+		},
+		{
+			name: "2. Not A Member",
+			body: `{"organization_id":"` + orgID.String() + `"}`,
+			mockBehavior: func(m *mocks.MockAuthProvider) {
+				m.EXPECT().
+					SwitchOrganization(gomock.Any(), user.ID, *token, orgID, gomock.Any()).
+					Return(nil, service.ErrOrganizationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Missing Organization",
+			body:           `{}`,
+			mockBehavior:   func(_ *mocks.MockAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"organization_id is required"`,
+		},
+		{
+			name:           "4. Malformed Organization ID",
+			body:           `{"organization_id":"not-a-uuid"}`,
+			mockBehavior:   func(_ *mocks.MockAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewAuthHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/organization", bytes.NewBufferString(tt.body))
+			ctx := middleware.ContextWithUser(req.Context(), user)
+			req = req.WithContext(middleware.ContextWithToken(ctx, token))
+
+			w := httptest.NewRecorder()
+			h.SwitchOrganization(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
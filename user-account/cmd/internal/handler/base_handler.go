@@ -2,7 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/service"
 )
 
 // baseHandler содержит общую логику для всех HTTP хендлеров
@@ -19,3 +24,39 @@ func (h *baseHandler) writeJSON(w http.ResponseWriter, code int, payload interfa
 func (h *baseHandler) writeError(w http.ResponseWriter, message string, code int) {
 	h.writeJSON(w, code, map[string]string{"error": message})
 }
+
+// policyErrorResponse - ответ на пароль, не прошедший политику: по Rule
+// фронтенд показывает сообщение на языке пользователя
+type policyErrorResponse struct {
+	Error      string                 `json:"error"`
+	Violations []passpolicy.Violation `json:"violations"`
+}
+
+// writePolicyError отвечает 400 со списком нарушенных правил, если err -
+// отказ политики паролей, и возвращает false для прочих ошибок
+func (h *baseHandler) writePolicyError(w http.ResponseWriter, err error) bool {
+	var policyErr *passpolicy.Error
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	h.writeJSON(w, http.StatusBadRequest, policyErrorResponse{
+		Error:      passpolicy.ErrPolicyViolated.Error(),
+		Violations: policyErr.Violations,
+	})
+	return true
+}
+
+// writeLocked отвечает 429 с Retry-After, если вход или проверка пароля
+// временно заблокированы после череды неудач, и возвращает false для прочих
+// ошибок
+func (h *baseHandler) writeLocked(w http.ResponseWriter, err error) bool {
+	var locked *service.LoginLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+
+	seconds := int(math.Ceil(locked.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(seconds, 1)))
+	h.writeError(w, "Too many failed login attempts, try again later", http.StatusTooManyRequests)
+	return true
+}
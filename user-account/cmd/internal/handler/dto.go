@@ -2,8 +2,17 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/mail"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/tabular"
+
+	"github.com/google/uuid"
 )
 
 // RegisterRequest — DTO для регистрации
@@ -26,8 +35,8 @@ func (r *RegisterRequest) Validate() error {
 	if len(r.Nickname) < 3 || len(r.Nickname) > 30 {
 		return errors.New("nickname must be between 3 and 30 characters")
 	}
-	if len(r.Password) < 6 {
-		return errors.New("password must be at least 6 characters long")
+	if r.Password == "" {
+		return errors.New("password is required")
 	}
 	return nil
 }
@@ -55,8 +64,664 @@ type UpdatePasswordRequest struct {
 
 func (r *UpdatePasswordRequest) Validate() error {
 	r.NewPassword = strings.TrimSpace(r.NewPassword)
-	if len(r.NewPassword) < 6 {
-		return errors.New("new password must be at least 6 characters long")
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+	return nil
+}
+
+// RefreshRequest - DTO для ротации refresh-токена
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (r *RefreshRequest) Validate() error {
+	r.RefreshToken = strings.TrimSpace(r.RefreshToken)
+	if r.RefreshToken == "" {
+		return errors.New("refresh_token is required")
+	}
+	return nil
+}
+
+// UpdateProfileRequest - DTO для PATCH /me; отсутствующие поля не меняются,
+// preferences заменяются целиком
+type UpdateProfileRequest struct {
+	Nickname    *string            `json:"nickname"`
+	Preferences *model.Preferences `json:"preferences"`
+}
+
+func (r *UpdateProfileRequest) Validate() error {
+	if r.Nickname == nil && r.Preferences == nil {
+		return errors.New("nothing to update")
+	}
+	if r.Nickname != nil {
+		nickname := strings.TrimSpace(*r.Nickname)
+		if len(nickname) < 3 || len(nickname) > 30 {
+			return errors.New("nickname must be between 3 and 30 characters")
+		}
+		r.Nickname = &nickname
+	}
+	if r.Preferences != nil {
+		return validatePreferences(r.Preferences)
+	}
+	return nil
+}
+
+func validatePreferences(p *model.Preferences) error {
+	switch p.Language {
+	case "", "ru", "en":
+	default:
+		return errors.New("language must be one of ru, en")
+	}
+	switch p.Theme {
+	case "", "light", "dark", "system":
+	default:
+		return errors.New("theme must be one of light, dark, system")
+	}
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return errors.New("timezone must be an IANA time zone name")
+		}
+	}
+	return nil
+}
+
+// ChangePasswordRequest - DTO для смены своего пароля
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (r *ChangePasswordRequest) Validate() error {
+	if r.CurrentPassword == "" {
+		return errors.New("current_password is required")
+	}
+	r.NewPassword = strings.TrimSpace(r.NewPassword)
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+	if r.NewPassword == r.CurrentPassword {
+		return errors.New("new password must differ from the current one")
+	}
+	return nil
+}
+
+// CloseAccountRequest - DTO для удаления своего аккаунта
+type CloseAccountRequest struct {
+	Password string `json:"password"`
+}
+
+func (r *CloseAccountRequest) Validate() error {
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+	return nil
+}
+
+// VerifyEmailRequest - DTO для подтверждения почты по ссылке
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (r *VerifyEmailRequest) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+	return nil
+}
+
+// ResendVerificationRequest - DTO для повторной отправки ссылки
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+func (r *ResendVerificationRequest) Validate() error {
+	r.Email = strings.TrimSpace(r.Email)
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+	if _, err := mail.ParseAddress(r.Email); err != nil {
+		return errors.New("invalid email format")
+	}
+	return nil
+}
+
+// ForgotPasswordRequest - DTO для запроса ссылки сброса пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+func (r *ForgotPasswordRequest) Validate() error {
+	r.Email = strings.TrimSpace(r.Email)
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+	if _, err := mail.ParseAddress(r.Email); err != nil {
+		return errors.New("invalid email format")
+	}
+	return nil
+}
+
+// ResetPasswordRequest - DTO для установки нового пароля по токену
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+func (r *ResetPasswordRequest) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+	r.NewPassword = strings.TrimSpace(r.NewPassword)
+	if r.NewPassword == "" {
+		return errors.New("new_password is required")
+	}
+	return nil
+}
+
+// LoginTwoFactorRequest - DTO для второго шага входа
+type LoginTwoFactorRequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+func (r *LoginTwoFactorRequest) Validate() error {
+	if strings.TrimSpace(r.MFAToken) == "" {
+		return errors.New("mfa_token is required")
+	}
+	if strings.TrimSpace(r.Code) == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+// TwoFactorCodeRequest - DTO с кодом второго фактора
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (r *TwoFactorCodeRequest) Validate() error {
+	r.Code = strings.TrimSpace(r.Code)
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+	return nil
+}
+
+// CreateAPIKeyRequest - DTO для выпуска API-ключа
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays - срок ключа в днях, 0 - срок по умолчанию
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if len(r.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for i, scope := range r.Scopes {
+		r.Scopes[i] = strings.TrimSpace(scope)
+		if r.Scopes[i] == "" {
+			return errors.New("scope must not be empty")
+		}
+	}
+	if r.ExpiresInDays < 0 {
+		return errors.New("expires_in_days must not be negative")
+	}
+	return nil
+}
+
+// TTL - срок ключа, 0 - срок по умолчанию
+func (r *CreateAPIKeyRequest) TTL() time.Duration {
+	return time.Duration(r.ExpiresInDays) * 24 * time.Hour
+}
+
+// OIDCCallbackRequest - DTO с кодом и state, с которыми провайдер вернул
+// пользователя на фронтенд
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+func (r *OIDCCallbackRequest) Validate() error {
+	r.Code = strings.TrimSpace(r.Code)
+	r.State = strings.TrimSpace(r.State)
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+	if r.State == "" {
+		return errors.New("state is required")
+	}
+	return nil
+}
+
+// OAuthAuthorizeRequest - DTO с решением пользователя на странице согласия.
+// Параметры запроса клиента страница передаёт как получила.
+type OAuthAuthorizeRequest struct {
+	model.AuthorizationRequest
+	Approved bool `json:"approved"`
+}
+
+func (r *OAuthAuthorizeRequest) Validate() error {
+	if strings.TrimSpace(r.ClientID) == "" {
+		return errors.New("client_id is required")
+	}
+	return nil
+}
+
+// RegisterOAuthClientRequest - DTO для регистрации клиента OAuth2
+type RegisterOAuthClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes"`
+	// Public - клиент без секрета (SPA, мобильное приложение)
+	Public bool `json:"public"`
+}
+
+func (r *RegisterOAuthClientRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	if len(r.GrantTypes) == 0 {
+		return errors.New("at least one grant type is required")
+	}
+	// Списки хранятся через пробел, поэтому пробелы внутри значений запрещены
+	for _, values := range [][]string{r.RedirectURIs, r.GrantTypes, r.Scopes} {
+		for i, value := range values {
+			values[i] = strings.TrimSpace(value)
+			if values[i] == "" || strings.ContainsAny(values[i], " \t\r\n") {
+				return errors.New("redirect_uris, grant_types and scopes must be non-empty values without spaces")
+			}
+		}
+	}
+	return nil
+}
+
+// Registration - параметры регистрации для сервиса
+func (r *RegisterOAuthClientRequest) Registration() model.OAuthClientRegistration {
+	return model.OAuthClientRegistration{
+		Name:         r.Name,
+		RedirectURIs: r.RedirectURIs,
+		GrantTypes:   r.GrantTypes,
+		Scopes:       r.Scopes,
+		Public:       r.Public,
+	}
+}
+
+// OrganizationRequest - DTO для создания и переименования организации
+type OrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *OrganizationRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(r.Name) > 100 {
+		return errors.New("name must be at most 100 characters")
+	}
+	return nil
+}
+
+// SwitchOrganizationRequest - DTO для смены активной организации
+type SwitchOrganizationRequest struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+}
+
+func (r *SwitchOrganizationRequest) Validate() error {
+	if r.OrganizationID == uuid.Nil {
+		return errors.New("organization_id is required")
+	}
+	return nil
+}
+
+// AddOrganizationMemberRequest - DTO для добавления участника организации
+// и приглашения в неё. Без роли участник добавляется как member.
+type AddOrganizationMemberRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func (r *AddOrganizationMemberRequest) Validate() error {
+	r.Email = strings.TrimSpace(r.Email)
+	r.Role = strings.TrimSpace(r.Role)
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+	if _, err := mail.ParseAddress(r.Email); err != nil {
+		return errors.New("invalid email format")
+	}
+	if r.Role == "" {
+		r.Role = model.OrgRoleMember
+	}
+	if !model.ValidOrgRole(r.Role) {
+		return errors.New("role must be one of owner, admin, member")
+	}
+	return nil
+}
+
+// OrganizationMemberRoleRequest - DTO для смены роли участника организации
+type OrganizationMemberRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (r *OrganizationMemberRoleRequest) Validate() error {
+	r.Role = strings.TrimSpace(r.Role)
+	if !model.ValidOrgRole(r.Role) {
+		return errors.New("role must be one of owner, admin, member")
+	}
+	return nil
+}
+
+// AcceptInvitationRequest - DTO для принятия приглашения. Nickname и
+// Password нужны, только если на приглашённый адрес ещё нет аккаунта.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Nickname string `json:"nickname"`
+	Password string `json:"password"`
+}
+
+func (r *AcceptInvitationRequest) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	r.Nickname = strings.TrimSpace(r.Nickname)
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+	if r.Nickname != "" && (len(r.Nickname) < 3 || len(r.Nickname) > 30) {
+		return errors.New("nickname must be between 3 and 30 characters")
+	}
+	return nil
+}
+
+// DeclineInvitationRequest - DTO для отказа от приглашения
+type DeclineInvitationRequest struct {
+	Token string `json:"token"`
+}
+
+func (r *DeclineInvitationRequest) Validate() error {
+	r.Token = strings.TrimSpace(r.Token)
+	if r.Token == "" {
+		return errors.New("token is required")
 	}
 	return nil
 }
+
+// SuspendUserRequest - DTO для приостановки доступа. Без статуса доступ
+// приостанавливается (suspended), без expires_at - бессрочно.
+type SuspendUserRequest struct {
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *SuspendUserRequest) Validate() error {
+	r.Status = strings.TrimSpace(r.Status)
+	if r.Status == "" {
+		r.Status = model.UserStatusSuspended
+	}
+	if r.Status != model.UserStatusSuspended && r.Status != model.UserStatusLocked {
+		return errors.New("status must be suspended or locked")
+	}
+	return validateStatusReason(&r.Reason)
+}
+
+// ReinstateUserRequest - DTO для возврата доступа
+type ReinstateUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (r *ReinstateUserRequest) Validate() error {
+	return validateStatusReason(&r.Reason)
+}
+
+func validateStatusReason(reason *string) error {
+	*reason = strings.TrimSpace(*reason)
+	if *reason == "" {
+		return errors.New("reason is required")
+	}
+	if utf8.RuneCountInString(*reason) > model.MaxStatusReasonLength {
+		return fmt.Errorf("reason must be at most %d characters", model.MaxStatusReasonLength)
+	}
+	return nil
+}
+
+// parseUserListQuery разбирает параметры GET /users:
+//   - limit - размер страницы, от 1 до model.MaxUserPageSize;
+//   - sort - created_at, email или nickname, с "-" в начале - по убыванию;
+//   - cursor - next_cursor предыдущей страницы с той же сортировкой;
+//   - role - только пользователи с ролью;
+//   - email, nickname - подстрока без учёта регистра;
+//   - created_from, created_to - RFC 3339, created_to не включается;
+//   - include_total - посчитать всех подходящих под фильтры.
+func parseUserListQuery(values url.Values) (model.UserListQuery, error) {
+	query := model.UserListQuery{
+		Role:     strings.TrimSpace(values.Get("role")),
+		Email:    strings.TrimSpace(values.Get("email")),
+		Nickname: strings.TrimSpace(values.Get("nickname")),
+		SortBy:   model.UserSortCreatedAt,
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > model.MaxUserPageSize {
+			return query, fmt.Errorf("limit must be between 1 and %d", model.MaxUserPageSize)
+		}
+		query.Limit = limit
+	}
+
+	if raw := strings.TrimSpace(values.Get("sort")); raw != "" {
+		query.Descending = strings.HasPrefix(raw, "-")
+		query.SortBy = strings.TrimPrefix(raw, "-")
+		if !model.ValidUserSort(query.SortBy) {
+			return query, errors.New("sort must be one of created_at, email, nickname, optionally prefixed with -")
+		}
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := model.DecodeUserCursor(raw, query.SortBy, query.Descending)
+		if err != nil {
+			return query, err
+		}
+		query.After = cursor
+	}
+
+	for _, bound := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+	} {
+		raw := values.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return query, fmt.Errorf("%s must be an RFC 3339 timestamp", bound.name)
+		}
+		*bound.dest = &t
+	}
+
+	if raw := values.Get("include_total"); raw != "" {
+		withTotal, err := strconv.ParseBool(raw)
+		if err != nil {
+			return query, errors.New("include_total must be a boolean")
+		}
+		query.WithTotal = withTotal
+	}
+	return query, nil
+}
+
+// userExportOptions - параметры выгрузки GET /users/export
+type userExportOptions struct {
+	Query    model.UserListQuery
+	Format   string
+	Columns  []string
+	Language string
+}
+
+// parseUserExportOptions разбирает параметры GET /users/export:
+//   - format - csv, xlsx или json, по умолчанию csv;
+//   - columns - колонки через запятую в нужном порядке, по умолчанию все;
+//   - lang - язык заголовков, en или ru, по умолчанию en;
+//   - role, email, nickname, created_from, created_to, sort - как у GET /users.
+//
+// Выгружается вся выборка, limit и cursor не принимаются.
+func parseUserExportOptions(values url.Values) (userExportOptions, error) {
+	opts := userExportOptions{
+		Format:   tabular.FormatCSV,
+		Columns:  model.UserExportColumns,
+		Language: model.ExportLanguageEN,
+	}
+	if values.Has("limit") || values.Has("cursor") {
+		return opts, errors.New("limit and cursor are not supported by export")
+	}
+
+	query, err := parseUserListQuery(values)
+	if err != nil {
+		return opts, err
+	}
+	opts.Query = query
+
+	if raw := strings.ToLower(strings.TrimSpace(values.Get("format"))); raw != "" {
+		switch raw {
+		case tabular.FormatCSV, tabular.FormatXLSX, tabular.FormatJSON:
+			opts.Format = raw
+		default:
+			return opts, errors.New("format must be one of csv, xlsx, json")
+		}
+	}
+
+	if raw := strings.TrimSpace(values.Get("columns")); raw != "" {
+		opts.Columns = nil
+		seen := make(map[string]bool)
+		for _, column := range strings.Split(raw, ",") {
+			column = strings.ToLower(strings.TrimSpace(column))
+			if !model.IsUserExportColumn(column) {
+				return opts, fmt.Errorf("unknown column %q, expected some of %s", column, strings.Join(model.UserExportColumns, ", "))
+			}
+			if !seen[column] {
+				seen[column] = true
+				opts.Columns = append(opts.Columns, column)
+			}
+		}
+	}
+
+	if raw := strings.ToLower(strings.TrimSpace(values.Get("lang"))); raw != "" {
+		if !model.IsExportLanguage(raw) {
+			return opts, errors.New("lang must be one of en, ru")
+		}
+		opts.Language = raw
+	}
+	return opts, nil
+}
+
+// parseUserSearchQuery разбирает параметры GET /users/search:
+//   - q - строка поиска, обязательна, не длиннее model.MaxUserSearchLength;
+//   - limit - размер выдачи, от 1 до model.MaxUserSearchLimit.
+func parseUserSearchQuery(values url.Values) (model.UserSearchQuery, error) {
+	query := model.UserSearchQuery{Text: strings.TrimSpace(values.Get("q"))}
+	if query.Text == "" {
+		return query, errors.New("q is required")
+	}
+	if utf8.RuneCountInString(query.Text) > model.MaxUserSearchLength {
+		return query, fmt.Errorf("q must be at most %d characters", model.MaxUserSearchLength)
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > model.MaxUserSearchLimit {
+			return query, fmt.Errorf("limit must be between 1 and %d", model.MaxUserSearchLimit)
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// parseAuditQuery разбирает параметры GET /audit:
+//   - actor_id, target_id - UUID того, кто выполнил действие, и над кем;
+//   - action - действие, например auth.login;
+//   - outcome - success или failure;
+//   - created_from, created_to - RFC 3339, created_to не включается;
+//   - limit - размер страницы, от 1 до model.MaxAuditPageSize;
+//   - cursor - next_cursor предыдущей страницы.
+func parseAuditQuery(values url.Values) (model.AuditEventQuery, error) {
+	query := model.AuditEventQuery{Action: strings.TrimSpace(values.Get("action"))}
+
+	for _, id := range []struct {
+		name string
+		dest **uuid.UUID
+	}{
+		{"actor_id", &query.ActorID},
+		{"target_id", &query.TargetID},
+	} {
+		raw := values.Get(id.name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			return query, fmt.Errorf("%s must be a UUID", id.name)
+		}
+		*id.dest = &parsed
+	}
+
+	if raw := strings.TrimSpace(values.Get("outcome")); raw != "" {
+		if raw != model.AuditSuccess && raw != model.AuditFailure {
+			return query, errors.New("outcome must be one of success, failure")
+		}
+		query.Outcome = raw
+	}
+
+	for _, bound := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+	} {
+		raw := values.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return query, fmt.Errorf("%s must be an RFC 3339 timestamp", bound.name)
+		}
+		*bound.dest = &t
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > model.MaxAuditPageSize {
+			return query, fmt.Errorf("limit must be between 1 and %d", model.MaxAuditPageSize)
+		}
+		query.Limit = limit
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || cursor < 1 {
+			return query, errors.New("cursor must be a positive integer")
+		}
+		query.Before = cursor
+	}
+	return query, nil
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"testing"
+	"user-account/cmd/internal/model"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -51,10 +52,10 @@ func TestRegisterRequest_Validate(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "Password Too Short",
+			name: "Password Missing",
 			request: RegisterRequest{
 				Email:    "test@test.com",
-				Password: "12345",
+				Password: "",
 				Nickname: "user",
 			},
 			wantErr: true,
@@ -129,8 +130,59 @@ func TestUpdatePasswordRequest_Validate(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "Password Too Short",
-			request: UpdatePasswordRequest{NewPassword: "123"},
+			name:    "Password Missing",
+			request: UpdatePasswordRequest{NewPassword: "   "},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestUpdateProfileRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	nick := func(s string) *string { return &s }
+
+	tests := []struct {
+		name    string
+		request UpdateProfileRequest
+		wantErr bool
+	}{
+		{
+			name:    "Nickname Only",
+			request: UpdateProfileRequest{Nickname: nick("  renamed  ")},
+			wantErr: false,
+		},
+		{
+			name:    "Preferences Only",
+			request: UpdateProfileRequest{Preferences: &model.Preferences{Language: "ru", Theme: "dark", Timezone: "Europe/Moscow"}},
+			wantErr: false,
+		},
+		{
+			name:    "Empty Request",
+			request: UpdateProfileRequest{},
+			wantErr: true,
+		},
+		{
+			name:    "Nickname Too Short",
+			request: UpdateProfileRequest{Nickname: nick("ab")},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Language",
+			request: UpdateProfileRequest{Preferences: &model.Preferences{Language: "de"}},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown Timezone",
+			request: UpdateProfileRequest{Preferences: &model.Preferences{Timezone: "Mars/Olympus"}},
 			wantErr: true,
 		},
 	}
@@ -143,3 +195,201 @@ func TestUpdatePasswordRequest_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestChangePasswordRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request ChangePasswordRequest
+		wantErr bool
+	}{
+		{
+			name:    "Success",
+			request: ChangePasswordRequest{CurrentPassword: "old-pass", NewPassword: "new-secure-pass"},
+			wantErr: false,
+		},
+		{
+			name:    "Missing Current Password",
+			request: ChangePasswordRequest{NewPassword: "new-secure-pass"},
+			wantErr: true,
+		},
+		{
+			name:    "Same As Current",
+			request: ChangePasswordRequest{CurrentPassword: "same-pass", NewPassword: "same-pass"},
+			wantErr: true,
+		},
+		{
+			name:    "New Password Missing",
+			request: ChangePasswordRequest{CurrentPassword: "old-pass", NewPassword: ""},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestResendVerificationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request ResendVerificationRequest
+		wantErr bool
+	}{
+		{
+			name:    "Success",
+			request: ResendVerificationRequest{Email: " test@example.com "},
+			wantErr: false,
+		},
+		{
+			name:    "Empty Email",
+			request: ResendVerificationRequest{Email: ""},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Email Format",
+			request: ResendVerificationRequest{Email: "not-an-email"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestCreateAPIKeyRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request CreateAPIKeyRequest
+		wantErr bool
+	}{
+		{
+			name:    "Success",
+			request: CreateAPIKeyRequest{Name: " ci ", Scopes: []string{"users:read"}, ExpiresInDays: 30},
+			wantErr: false,
+		},
+		{
+			name:    "Empty Name",
+			request: CreateAPIKeyRequest{Name: "  ", Scopes: []string{"users:read"}},
+			wantErr: true,
+		},
+		{
+			name:    "No Scopes",
+			request: CreateAPIKeyRequest{Name: "ci"},
+			wantErr: true,
+		},
+		{
+			name:    "Blank Scope",
+			request: CreateAPIKeyRequest{Name: "ci", Scopes: []string{" "}},
+			wantErr: true,
+		},
+		{
+			name:    "Negative Lifetime",
+			request: CreateAPIKeyRequest{Name: "ci", Scopes: []string{"users:read"}, ExpiresInDays: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestOIDCCallbackRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request OIDCCallbackRequest
+		wantErr bool
+	}{
+		{
+			name:    "Success",
+			request: OIDCCallbackRequest{Code: " code ", State: "state"},
+			wantErr: false,
+		},
+		{
+			name:    "Empty Code",
+			request: OIDCCallbackRequest{State: "state"},
+			wantErr: true,
+		},
+		{
+			name:    "Empty State",
+			request: OIDCCallbackRequest{Code: "code", State: "  "},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestRegisterOAuthClientRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		request RegisterOAuthClientRequest
+		wantErr bool
+	}{
+		{
+			name: "Success",
+			request: RegisterOAuthClientRequest{
+				Name:         "billing",
+				RedirectURIs: []string{" https://billing.example.com/callback "},
+				GrantTypes:   []string{"authorization_code"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Empty Name",
+			request: RegisterOAuthClientRequest{Name: " ", GrantTypes: []string{"client_credentials"}},
+			wantErr: true,
+		},
+		{
+			name:    "No Grant Types",
+			request: RegisterOAuthClientRequest{Name: "billing"},
+			wantErr: true,
+		},
+		{
+			name:    "Scope With Space",
+			request: RegisterOAuthClientRequest{Name: "billing", GrantTypes: []string{"client_credentials"}, Scopes: []string{"billing read"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.request.Validate()
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
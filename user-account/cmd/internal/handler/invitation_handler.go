@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type InvitationProvider interface {
+	Create(ctx context.Context, orgID, actorID uuid.UUID, email, role string) (*model.Invitation, error)
+	List(ctx context.Context, orgID, actorID uuid.UUID) ([]model.Invitation, error)
+	Revoke(ctx context.Context, orgID, actorID, invitationID uuid.UUID) error
+	Accept(ctx context.Context, token, nickname, password string) (*model.InvitationAcceptance, error)
+	Decline(ctx context.Context, token string) error
+}
+
+// InvitationHandler - приглашения в организацию. Управление приглашениями
+// требует входа, принять или отклонить приглашение можно по токену из письма.
+type InvitationHandler struct {
+	baseHandler
+	invitationService InvitationProvider
+}
+
+func NewInvitationHandler(invitationService InvitationProvider) *InvitationHandler {
+	return &InvitationHandler{invitationService: invitationService}
+}
+
+// Create - POST /orgs/{id}/invitations. Токен уходит только в письме.
+func (h *InvitationHandler) Create(w http.ResponseWriter, r *http.Request, orgIDStr string) {
+	current, orgID, ok := h.organization(w, r, orgIDStr)
+	if !ok {
+		return
+	}
+
+	var req AddOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.invitationService.Create(r.Context(), orgID, current.ID, req.Email, req.Role)
+	if err != nil {
+		h.writeInvitationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, invitation)
+}
+
+// List - GET /orgs/{id}/invitations, ожидающие ответа приглашения
+func (h *InvitationHandler) List(w http.ResponseWriter, r *http.Request, orgIDStr string) {
+	current, orgID, ok := h.organization(w, r, orgIDStr)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.invitationService.List(r.Context(), orgID, current.ID)
+	if err != nil {
+		h.writeInvitationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, invitations)
+}
+
+// Revoke - DELETE /orgs/{id}/invitations/{invitationID}
+func (h *InvitationHandler) Revoke(w http.ResponseWriter, r *http.Request, orgIDStr, invitationIDStr string) {
+	current, orgID, ok := h.organization(w, r, orgIDStr)
+	if !ok {
+		return
+	}
+
+	invitationID, err := uuid.Parse(invitationIDStr)
+	if err != nil {
+		h.writeError(w, "invalid invitation ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.invitationService.Revoke(r.Context(), orgID, current.ID, invitationID); err != nil {
+		h.writeInvitationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Accept - POST /invitations/accept
+func (h *InvitationHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	var req AcceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	acceptance, err := h.invitationService.Accept(r.Context(), req.Token, req.Nickname, req.Password)
+	if err != nil {
+		if h.writePolicyError(w, err) {
+			return
+		}
+		h.writeInvitationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, acceptance)
+}
+
+// Decline - POST /invitations/decline
+func (h *InvitationHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	var req DeclineInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.invitationService.Decline(r.Context(), req.Token); err != nil {
+		h.writeInvitationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// organization достаёт вызывающего и организацию из пути. Если чего-то
+// нет, ответ уже записан.
+func (h *InvitationHandler) organization(w http.ResponseWriter, r *http.Request, orgIDStr string) (*model.User, uuid.UUID, bool) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return nil, uuid.Nil, false
+	}
+
+	orgID, err := uuid.Parse(orgIDStr)
+	if err != nil {
+		h.writeError(w, "invalid organization ID format", http.StatusBadRequest)
+		return nil, uuid.Nil, false
+	}
+	return current, orgID, true
+}
+
+func (h *InvitationHandler) writeInvitationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound),
+		errors.Is(err, service.ErrInvitationNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrOrganizationForbidden):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrInvalidOrgRole),
+		errors.Is(err, service.ErrInvalidInvitation),
+		errors.Is(err, service.ErrAccountDetailsRequired):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrAlreadyMember),
+		errors.Is(err, repository.ErrNicknameTaken),
+		errors.Is(err, repository.ErrEmailTaken):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		orgID          string
+		body           string
+		mockBehavior   func(m *mocks.MockInvitationProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "1. Success With Default Role",
+			orgID: orgID.String(),
+			body:  `{"email":"new@test.com"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Create(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleMember).
+					Return(&model.Invitation{ID: uuid.New(), OrganizationID: orgID, Email: "new@test.com", Role: model.OrgRoleMember}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"email":"new@test.com"`,
+		},
+		{
+			name:           "2. Invalid Organization ID",
+			orgID:          "bad-id",
+			body:           `{"email":"new@test.com"}`,
+			mockBehavior:   func(_ *mocks.MockInvitationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "3. Invalid Email",
+			orgID:          orgID.String(),
+			body:           `{"email":"not-an-email"}`,
+			mockBehavior:   func(_ *mocks.MockInvitationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid email format"`,
+		},
+		{
+			name:  "4. Member Cannot Invite",
+			orgID: orgID.String(),
+			body:  `{"email":"new@test.com","role":"admin"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Create(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleAdmin).
+					Return(nil, service.ErrOrganizationForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "5. Already A Member",
+			orgID: orgID.String(),
+			body:  `{"email":"new@test.com"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Create(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleMember).
+					Return(nil, repository.ErrAlreadyMember)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:  "6. Mailer Failure",
+			orgID: orgID.String(),
+			body:  `{"email":"new@test.com"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Create(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleMember).
+					Return(nil, errors.New("smtp unavailable"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockInvitationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewInvitationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/orgs/"+tt.orgID+"/invitations", bytes.NewBufferString(tt.body))
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Create(w, req, tt.orgID)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestInvitationHandler_Revoke(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	invitationID := uuid.New()
+
+	tests := []struct {
+		name           string
+		invitationID   string
+		mockBehavior   func(m *mocks.MockInvitationProvider)
+		expectedStatus int
+	}{
+		{
+			name:         "1. Success",
+			invitationID: invitationID.String(),
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Revoke(gomock.Any(), orgID, userID, invitationID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:         "2. Not Pending",
+			invitationID: invitationID.String(),
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Revoke(gomock.Any(), orgID, userID, invitationID).Return(service.ErrInvitationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Invalid ID",
+			invitationID:   "bad-id",
+			mockBehavior:   func(_ *mocks.MockInvitationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockInvitationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewInvitationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/orgs/"+orgID.String()+"/invitations/"+tt.invitationID, nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Revoke(w, req, orgID.String(), tt.invitationID)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestInvitationHandler_Accept(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockInvitationProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Account Created",
+			body: `{"token":"tok","nickname":" newbie ","password":"long-password"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Accept(gomock.Any(), "tok", "newbie", "long-password").
+					Return(&model.InvitationAcceptance{OrganizationID: orgID, Role: model.OrgRoleMember, AccountCreated: true}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"account_created":true`,
+		},
+		{
+			name:           "2. Missing Token",
+			body:           `{}`,
+			mockBehavior:   func(_ *mocks.MockInvitationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"token is required"`,
+		},
+		{
+			name: "3. Expired Invitation",
+			body: `{"token":"tok"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Accept(gomock.Any(), "tok", "", "").Return(nil, service.ErrInvalidInvitation)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "4. Nickname Taken",
+			body: `{"token":"tok","nickname":"taken","password":"long-password"}`,
+			mockBehavior: func(m *mocks.MockInvitationProvider) {
+				m.EXPECT().Accept(gomock.Any(), "tok", "taken", "long-password").Return(nil, repository.ErrNicknameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockInvitationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewInvitationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/invitations/accept", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.Accept(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"net/http"
+	"user-account/cmd/internal/keyring"
+)
+
+type KeySetProvider interface {
+	JWKS() keyring.JWKSet
+}
+
+type JWKSHandler struct {
+	baseHandler
+	keys KeySetProvider
+}
+
+func NewJWKSHandler(keys KeySetProvider) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// JWKS - GET /.well-known/jwks.json, публичные ключи для проверки токенов
+func (h *JWKSHandler) JWKS(w http.ResponseWriter, _ *http.Request) {
+	// Ключи ротируются, поэтому кэш у проверяющих сторон должен быть коротким
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	h.writeJSON(w, http.StatusOK, h.keys.JWKS())
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-account/cmd/internal/keyring"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler_JWKS(t *testing.T) {
+	t.Parallel()
+
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgEdDSA, Overlap: time.Minute})
+	require.NoError(t, err)
+
+	h := NewJWKSHandler(keys)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+	h.JWKS(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var set keyring.JWKSet
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&set))
+	require.Len(t, set.Keys, 1)
+	assert.Equal(t, "OKP", set.Keys[0].Kty)
+	assert.Equal(t, "Ed25519", set.Keys[0].Crv)
+	assert.NotEmpty(t, set.Keys[0].Kid)
+	assert.NotEmpty(t, set.Keys[0].X)
+}
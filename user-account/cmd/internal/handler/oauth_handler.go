@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type OAuthProvider interface {
+	Metadata() model.OAuthServerMetadata
+	PreviewAuthorization(ctx context.Context, req model.AuthorizationRequest) (*model.AuthorizationPreview, error)
+	Authorize(ctx context.Context, userID uuid.UUID, req model.AuthorizationRequest, approved bool) (string, error)
+	Token(ctx context.Context, req model.TokenRequest) (*model.OAuthTokens, error)
+	Introspect(ctx context.Context, clientID, clientSecret, token string) (*model.TokenIntrospection, error)
+	RegisterClient(ctx context.Context, createdBy uuid.UUID, reg model.OAuthClientRegistration) (*model.OAuthClient, string, error)
+	ListClients(ctx context.Context) ([]model.OAuthClient, error)
+	RevokeClient(ctx context.Context, id uuid.UUID) error
+}
+
+// oauthErrorResponse - ошибка в формате OAuth2 (RFC 6749, 5.2)
+type oauthErrorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// oauthTokenResponse - ответ /oauth/token (RFC 6749, 5.1)
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope,omitempty"`
+	IDToken     string `json:"id_token,omitempty"`
+}
+
+// registeredOAuthClient - ответ на регистрацию клиента. ClientSecret
+// отдаётся только здесь, потом его уже не получить.
+type registeredOAuthClient struct {
+	model.OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type OAuthHandler struct {
+	baseHandler
+	oauthService OAuthProvider
+}
+
+func NewOAuthHandler(oauthService OAuthProvider) *OAuthHandler {
+	return &OAuthHandler{oauthService: oauthService}
+}
+
+// Discovery - GET /.well-known/openid-configuration
+func (h *OAuthHandler) Discovery(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	h.writeJSON(w, http.StatusOK, h.oauthService.Metadata())
+}
+
+// PreviewAuthorization - GET /oauth/authorize, проверка запроса клиента для
+// страницы согласия. Параметры - как в запросе клиента (RFC 6749, 4.1.1).
+func (h *OAuthHandler) PreviewAuthorization(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	preview, err := h.oauthService.PreviewAuthorization(r.Context(), model.AuthorizationRequest{
+		ResponseType:        query.Get("response_type"),
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		Nonce:               query.Get("nonce"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	})
+	if err != nil {
+		h.writeOAuthError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, preview)
+}
+
+// Authorize - POST /oauth/authorize, решение пользователя. В ответе адрес
+// клиента, на который фронтенд перенаправляет пользователя.
+func (h *OAuthHandler) Authorize(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req OAuthAuthorizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redirectTo, err := h.oauthService.Authorize(r.Context(), current.ID, req.AuthorizationRequest, req.Approved)
+	if err != nil {
+		h.writeOAuthError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"redirect_to": redirectTo})
+}
+
+// Token - POST /oauth/token, выдача токенов (RFC 6749, 3.2). Клиент
+// аутентифицируется через Basic или полями формы.
+func (h *OAuthHandler) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeOAuthError(w, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "invalid form body"})
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+	tokens, err := h.oauthService.Token(r.Context(), model.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		Scope:        r.PostForm.Get("scope"),
+	})
+	if err != nil {
+		h.writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, http.StatusOK, oauthTokenResponse{
+		AccessToken: tokens.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tokens.ExpiresIn.Seconds()),
+		Scope:       strings.Join(tokens.Scopes, " "),
+		IDToken:     tokens.IDToken,
+	})
+}
+
+// Introspect - POST /oauth/introspect (RFC 7662). Спрашивать может только
+// конфиденциальный клиент.
+func (h *OAuthHandler) Introspect(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		h.writeOAuthError(w, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "invalid form body"})
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		h.writeOAuthError(w, &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "token is required"})
+		return
+	}
+
+	clientID, clientSecret := clientCredentials(r)
+	result, err := h.oauthService.Introspect(r.Context(), clientID, clientSecret, token)
+	if err != nil {
+		h.writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, http.StatusOK, result)
+}
+
+// ListClients - GET /oauth/clients
+func (h *OAuthHandler) ListClients(w http.ResponseWriter, r *http.Request) {
+	clients, err := h.oauthService.ListClients(r.Context())
+	if err != nil {
+		h.writeError(w, "failed to fetch oauth clients", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, clients)
+}
+
+// RegisterClient - POST /oauth/clients, секрет возвращается один раз
+func (h *OAuthHandler) RegisterClient(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req RegisterOAuthClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	client, secret, err := h.oauthService.RegisterClient(r.Context(), current.ID, req.Registration())
+	if err != nil {
+		h.writeClientError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, registeredOAuthClient{OAuthClient: *client, ClientSecret: secret})
+}
+
+// RevokeClient - DELETE /oauth/clients/{clientID}
+func (h *OAuthHandler) RevokeClient(w http.ResponseWriter, r *http.Request, clientIDStr string) {
+	clientID, err := uuid.Parse(clientIDStr)
+	if err != nil {
+		h.writeError(w, "invalid client ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.oauthService.RevokeClient(r.Context(), clientID); err != nil {
+		h.writeClientError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeOAuthError отвечает ошибкой в формате OAuth2. Неудачная
+// аутентификация клиента - 401 (RFC 6749, 5.2), прочие ошибки протокола - 400.
+func (h *OAuthHandler) writeOAuthError(w http.ResponseWriter, err error) {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		h.writeJSON(w, http.StatusInternalServerError, oauthErrorResponse{Error: "server_error"})
+		return
+	}
+
+	code := http.StatusBadRequest
+	if oauthErr.Code == service.OAuthInvalidClient {
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+		code = http.StatusUnauthorized
+	}
+	h.writeJSON(w, code, oauthErrorResponse{Error: oauthErr.Code, Description: oauthErr.Description})
+}
+
+func (h *OAuthHandler) writeClientError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrOAuthClientNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidClientMetadata):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// clientCredentials достаёт client_id и секрет из Basic или из формы. В
+// Basic они закодированы как application/x-www-form-urlencoded (RFC 6749, 2.3.1).
+func clientCredentials(r *http.Request) (string, string) {
+	if id, secret, ok := r.BasicAuth(); ok {
+		decodedID, idErr := url.QueryUnescape(id)
+		decodedSecret, secretErr := url.QueryUnescape(secret)
+		if idErr == nil && secretErr == nil {
+			return decodedID, decodedSecret
+		}
+		return "", ""
+	}
+	return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+}
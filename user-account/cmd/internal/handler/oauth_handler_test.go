@@ -0,0 +1,418 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthHandler_Discovery(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockOAuthProvider(ctrl)
+	mockSvc.EXPECT().Metadata().Return(model.OAuthServerMetadata{Issuer: "https://accounts.example.com"})
+
+	w := httptest.NewRecorder()
+	NewOAuthHandler(mockSvc).Discovery(w, httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"issuer":"https://accounts.example.com"`)
+}
+
+func TestOAuthHandler_Token(t *testing.T) {
+	t.Parallel()
+
+	clientID := uuid.NewString()
+
+	tests := []struct {
+		name           string
+		form           url.Values
+		basicAuth      []string
+		mockBehavior   func(m *mocks.MockOAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:      "1. Client Credentials With Basic Auth",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: []string{clientID, "cs%2Fsecret"},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Token(gomock.Any(), model.TokenRequest{
+					GrantType:    "client_credentials",
+					ClientID:     clientID,
+					ClientSecret: "cs/secret",
+				}).Return(&model.OAuthTokens{AccessToken: "access", ExpiresIn: 15 * time.Minute, Scopes: []string{"a", "b"}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"access_token":"access","token_type":"Bearer","expires_in":900,"scope":"a b"}`,
+		},
+		{
+			name: "2. Authorization Code With Form Credentials",
+			form: url.Values{
+				"grant_type":    {"authorization_code"},
+				"client_id":     {clientID},
+				"code":          {"code"},
+				"redirect_uri":  {"https://spa.example.com/callback"},
+				"code_verifier": {"verifier"},
+			},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Token(gomock.Any(), model.TokenRequest{
+					GrantType:    "authorization_code",
+					ClientID:     clientID,
+					Code:         "code",
+					RedirectURI:  "https://spa.example.com/callback",
+					CodeVerifier: "verifier",
+				}).Return(&model.OAuthTokens{AccessToken: "access", IDToken: "id", ExpiresIn: time.Minute}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"id_token":"id"`,
+		},
+		{
+			name:      "3. Invalid Client",
+			form:      url.Values{"grant_type": {"client_credentials"}},
+			basicAuth: []string{clientID, "wrong"},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Token(gomock.Any(), gomock.Any()).
+					Return(nil, &service.OAuthError{Code: service.OAuthInvalidClient, Description: "client authentication failed"})
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":"invalid_client","error_description":"client authentication failed"}`,
+		},
+		{
+			name: "4. Invalid Grant",
+			form: url.Values{"grant_type": {"authorization_code"}, "client_id": {clientID}, "code": {"used"}},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Token(gomock.Any(), gomock.Any()).
+					Return(nil, &service.OAuthError{Code: service.OAuthInvalidGrant, Description: "authorization code is invalid or expired"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid_grant"`,
+		},
+		{
+			name: "5. Internal Error",
+			form: url.Values{"grant_type": {"client_credentials"}},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Token(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `{"error":"server_error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basicAuth != nil {
+				req.SetBasicAuth(tt.basicAuth[0], tt.basicAuth[1])
+			}
+
+			w := httptest.NewRecorder()
+			NewOAuthHandler(mockSvc).Token(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+			}
+			if tt.expectedStatus == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestOAuthHandler_Introspect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		form           url.Values
+		mockBehavior   func(m *mocks.MockOAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Active",
+			form: url.Values{"token": {"jwt"}, "client_id": {"rs"}, "client_secret": {"secret"}},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Introspect(gomock.Any(), "rs", "secret", "jwt").
+					Return(&model.TokenIntrospection{Active: true, Subject: "user", TokenType: "Bearer"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"active":true,"token_type":"Bearer","sub":"user"}`,
+		},
+		{
+			name: "2. Inactive",
+			form: url.Values{"token": {"expired"}, "client_id": {"rs"}, "client_secret": {"secret"}},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Introspect(gomock.Any(), "rs", "secret", "expired").Return(&model.TokenIntrospection{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"active":false}`,
+		},
+		{
+			name:           "3. Missing Token",
+			form:           url.Values{"client_id": {"rs"}, "client_secret": {"secret"}},
+			mockBehavior:   func(m *mocks.MockOAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":"invalid_request","error_description":"token is required"}`,
+		},
+		{
+			name: "4. Public Client",
+			form: url.Values{"token": {"jwt"}, "client_id": {"spa"}},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Introspect(gomock.Any(), "spa", "", "jwt").
+					Return(nil, &service.OAuthError{Code: service.OAuthInvalidClient, Description: "public clients cannot introspect tokens"})
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			NewOAuthHandler(mockSvc).Introspect(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOAuthHandler_PreviewAuthorization(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockOAuthProvider(ctrl)
+
+	clientID := uuid.New()
+	mockSvc.EXPECT().PreviewAuthorization(gomock.Any(), model.AuthorizationRequest{
+		ResponseType: "code",
+		ClientID:     clientID.String(),
+		Scope:        "openid email",
+		State:        "xyz",
+	}).Return(&model.AuthorizationPreview{ClientID: clientID, ClientName: "Billing", Scopes: []string{"openid", "email"}}, nil)
+
+	query := url.Values{"response_type": {"code"}, "client_id": {clientID.String()}, "scope": {"openid email"}, "state": {"xyz"}}
+	w := httptest.NewRecorder()
+	NewOAuthHandler(mockSvc).PreviewAuthorization(w, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"client_name":"Billing"`)
+}
+
+func TestOAuthHandler_Authorize(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockBehavior   func(m *mocks.MockOAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Approved",
+			requestBody: map[string]interface{}{
+				"client_id": "client", "response_type": "code", "state": "xyz", "approved": true,
+			},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Authorize(gomock.Any(), userID, model.AuthorizationRequest{
+					ClientID: "client", ResponseType: "code", State: "xyz",
+				}, true).Return("https://spa.example.com/callback?code=c&state=xyz", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"redirect_to":"https://spa.example.com/callback?code=c\u0026state=xyz"`,
+		},
+		{
+			name:        "2. Unregistered Redirect URI",
+			requestBody: map[string]interface{}{"client_id": "client", "redirect_uri": "https://evil.example.com"},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().Authorize(gomock.Any(), userID, gomock.Any(), false).
+					Return("", &service.OAuthError{Code: service.OAuthInvalidRequest, Description: "redirect_uri is not registered for the client"})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid_request"`,
+		},
+		{
+			name:           "3. Validation Error",
+			requestBody:    map[string]interface{}{"approved": true},
+			mockBehavior:   func(m *mocks.MockOAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"client_id is required"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", bytes.NewBuffer(body))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+
+			w := httptest.NewRecorder()
+			NewOAuthHandler(mockSvc).Authorize(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
+
+func TestOAuthHandler_RegisterClient(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		mockBehavior   func(m *mocks.MockOAuthProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			requestBody: RegisterOAuthClientRequest{
+				Name:       " billing ",
+				GrantTypes: []string{"client_credentials"},
+				Scopes:     []string{"billing:read"},
+			},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().RegisterClient(gomock.Any(), adminID, model.OAuthClientRegistration{
+					Name:       "billing",
+					GrantTypes: []string{"client_credentials"},
+					Scopes:     []string{"billing:read"},
+				}).Return(&model.OAuthClient{ID: uuid.New(), Name: "billing", SecretHash: "hash"}, "cs_secret", nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"client_secret":"cs_secret"`,
+		},
+		{
+			name:        "2. Invalid Metadata",
+			requestBody: RegisterOAuthClientRequest{Name: "spa", GrantTypes: []string{"client_credentials"}, Public: true},
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().RegisterClient(gomock.Any(), adminID, gomock.Any()).Return(nil, "", service.ErrInvalidClientMetadata)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "3. Validation Error",
+			requestBody:    RegisterOAuthClientRequest{Name: "spa"},
+			mockBehavior:   func(m *mocks.MockOAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"at least one grant type is required"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/oauth/clients", bytes.NewBuffer(body))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: adminID}))
+
+			w := httptest.NewRecorder()
+			NewOAuthHandler(mockSvc).RegisterClient(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+			assert.NotContains(t, w.Body.String(), "hash")
+		})
+	}
+}
+
+func TestOAuthHandler_RevokeClient(t *testing.T) {
+	t.Parallel()
+
+	clientID := uuid.New()
+
+	tests := []struct {
+		name           string
+		clientID       string
+		mockBehavior   func(m *mocks.MockOAuthProvider)
+		expectedStatus int
+	}{
+		{
+			name:     "1. Success",
+			clientID: clientID.String(),
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().RevokeClient(gomock.Any(), clientID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "2. Not Found",
+			clientID: clientID.String(),
+			mockBehavior: func(m *mocks.MockOAuthProvider) {
+				m.EXPECT().RevokeClient(gomock.Any(), clientID).Return(service.ErrOAuthClientNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Invalid ID",
+			clientID:       "not-a-uuid",
+			mockBehavior:   func(m *mocks.MockOAuthProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOAuthProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			w := httptest.NewRecorder()
+			NewOAuthHandler(mockSvc).RevokeClient(w, httptest.NewRequest(http.MethodDelete, "/oauth/clients/"+tt.clientID, nil), tt.clientID)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
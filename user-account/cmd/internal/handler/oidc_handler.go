@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type OIDCProvider interface {
+	Providers() []string
+	Authorize(ctx context.Context, provider string) (string, string, error)
+	Callback(ctx context.Context, code, state, binding string, client model.ClientInfo) (*model.LoginResult, error)
+	Identities(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error)
+}
+
+// oidcBindingCookie - cookie, которым вход через провайдера привязан к
+// браузеру, начавшему его
+const oidcBindingCookie = "oidc_binding"
+
+type OIDCHandler struct {
+	baseHandler
+	oidcService OIDCProvider
+}
+
+func NewOIDCHandler(oidcService OIDCProvider) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService}
+}
+
+// Providers - GET /oauth/providers, провайдеры для кнопок входа
+func (h *OIDCHandler) Providers(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, map[string][]string{"providers": h.oidcService.Providers()})
+}
+
+// Authorize - POST /oauth/{provider}/authorize, адрес провайдера, на который
+// фронтенд перенаправляет пользователя
+func (h *OIDCHandler) Authorize(w http.ResponseWriter, r *http.Request, provider string) {
+	authURL, binding, err := h.oidcService.Authorize(r.Context(), provider)
+	if err != nil {
+		h.writeOIDCError(w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcBindingCookie,
+		Value:    binding,
+		Path:     "/oauth",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"authorization_url": authURL})
+}
+
+// Callback - POST /oauth/callback, завершение входа кодом от провайдера.
+// Ответ такой же, как у POST /login.
+func (h *OIDCHandler) Callback(w http.ResponseWriter, r *http.Request) {
+	var req OIDCCallbackRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var binding string
+	if cookie, err := r.Cookie(oidcBindingCookie); err == nil {
+		binding = cookie.Value
+	}
+	// Привязка одноразовая, как и сам state
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcBindingCookie,
+		Path:     "/oauth",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	result, err := h.oidcService.Callback(r.Context(), req.Code, req.State, binding, middleware.ClientFromContext(r.Context()))
+	if err != nil {
+		h.writeOIDCError(w, err)
+		return
+	}
+
+	if result.MFAToken != "" {
+		h.writeJSON(w, http.StatusOK, mfaRequiredResponse{MFARequired: true, MFAToken: result.MFAToken})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newTokenResponse(result.Tokens, ""))
+}
+
+// Identities - GET /me/identities, привязанные учётные записи провайдеров
+func (h *OIDCHandler) Identities(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	identities, err := h.oidcService.Identities(r.Context(), current.ID)
+	if err != nil {
+		h.writeError(w, "failed to list identities", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, identities)
+}
+
+func (h *OIDCHandler) writeOIDCError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUnknownProvider):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidOIDCState),
+		errors.Is(err, oidc.ErrExchange),
+		errors.Is(err, oidc.ErrInvalidIDToken):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrIdentityEmailNotVerified),
+		errors.Is(err, service.ErrEmailNotVerified),
+		errors.Is(err, service.ErrAccountSuspended):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrAccountLinkRequired),
+		errors.Is(err, repository.ErrIdentityTaken),
+		errors.Is(err, repository.ErrProviderAlreadyLinked),
+		errors.Is(err, repository.ErrEmailTaken):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, oidc.ErrDiscovery):
+		h.writeError(w, "identity provider is unavailable", http.StatusBadGateway)
+	default:
+		h.writeError(w, "failed to login", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCHandler_Providers(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockOIDCProvider(ctrl)
+	mockSvc.EXPECT().Providers().Return([]string{"google", "keycloak"})
+
+	w := httptest.NewRecorder()
+	NewOIDCHandler(mockSvc).Providers(w, httptest.NewRequest(http.MethodGet, "/oauth/providers", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"providers":["google","keycloak"]}`, w.Body.String())
+}
+
+func TestOIDCHandler_Authorize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		provider       string
+		mockBehavior   func(m *mocks.MockOIDCProvider)
+		expectedStatus int
+		expectedBody   string
+		expectedCookie string
+	}{
+		{
+			name:     "1. Success",
+			provider: "google",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Authorize(gomock.Any(), "google").Return("https://accounts.example.com/authorize?state=s", "binding", nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"authorization_url":"https://accounts.example.com/authorize?state=s"`,
+			expectedCookie: "binding",
+		},
+		{
+			name:     "2. Unknown Provider",
+			provider: "myspace",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Authorize(gomock.Any(), "myspace").Return("", "", service.ErrUnknownProvider)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:     "3. Provider Unavailable",
+			provider: "google",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Authorize(gomock.Any(), "google").Return("", "", fmt.Errorf("%w: timeout", oidc.ErrDiscovery))
+			},
+			expectedStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOIDCProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/oauth/"+tt.provider+"/authorize", nil)
+			NewOIDCHandler(mockSvc).Authorize(w, req, tt.provider)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+			cookies := w.Result().Cookies()
+			if tt.expectedCookie == "" {
+				assert.Empty(t, cookies)
+			} else if assert.Len(t, cookies, 1) {
+				assert.Equal(t, oidcBindingCookie, cookies[0].Name)
+				assert.Equal(t, tt.expectedCookie, cookies[0].Value)
+				assert.True(t, cookies[0].HttpOnly)
+				assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+			}
+		})
+	}
+}
+
+func TestOIDCHandler_Callback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		requestBody    interface{}
+		binding        string
+		mockBehavior   func(m *mocks.MockOIDCProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:        "1. Success",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).
+					Return(&model.LoginResult{Tokens: &model.TokenPair{
+						AccessToken:  "fake-jwt-token",
+						RefreshToken: "fake-refresh-token",
+						ExpiresIn:    15 * time.Minute,
+					}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"token":"fake-jwt-token","refresh_token":"fake-refresh-token","expires_in":900`,
+		},
+		{
+			name:        "2. Second Factor Required",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).
+					Return(&model.LoginResult{MFAToken: "challenge"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"mfa_required":true,"mfa_token":"challenge"}`,
+		},
+		{
+			name:        "3. Invalid State",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "forged"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "forged", "binding", gomock.Any()).Return(nil, service.ErrInvalidOIDCState)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "4. Invalid ID Token",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).
+					Return(nil, fmt.Errorf("%w: nonce mismatch", oidc.ErrInvalidIDToken))
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "5. Email Not Verified By Provider",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).Return(nil, service.ErrIdentityEmailNotVerified)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "6. Account Link Required",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).Return(nil, service.ErrAccountLinkRequired)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "7. Provider Already Linked",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).Return(nil, repository.ErrProviderAlreadyLinked)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "8. Internal Error",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			binding:     "binding",
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "binding", gomock.Any()).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   `"error":"failed to login"`,
+		},
+		{
+			name:           "9. Validation Error",
+			requestBody:    OIDCCallbackRequest{Code: "code"},
+			mockBehavior:   func(m *mocks.MockOIDCProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"state is required"`,
+		},
+		{
+			name:        "10. Missing Binding Cookie",
+			requestBody: OIDCCallbackRequest{Code: "code", State: "state"},
+			mockBehavior: func(m *mocks.MockOIDCProvider) {
+				m.EXPECT().Callback(gomock.Any(), "code", "state", "", gomock.Any()).Return(nil, service.ErrInvalidOIDCState)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOIDCProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPost, "/oauth/callback", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.binding != "" {
+				req.AddCookie(&http.Cookie{Name: oidcBindingCookie, Value: tt.binding})
+			}
+
+			w := httptest.NewRecorder()
+			NewOIDCHandler(mockSvc).Callback(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestOIDCHandler_Identities(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSvc := mocks.NewMockOIDCProvider(ctrl)
+
+	userID := uuid.New()
+	mockSvc.EXPECT().Identities(gomock.Any(), userID).Return([]model.UserIdentity{
+		{ID: uuid.New(), UserID: userID, Provider: "google", Subject: "secret-sub", Email: "me@example.com"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/identities", nil)
+	req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+	w := httptest.NewRecorder()
+	NewOIDCHandler(mockSvc).Identities(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"provider":"google"`)
+	assert.NotContains(t, w.Body.String(), "secret-sub")
+}
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationProvider interface {
+	Create(ctx context.Context, ownerID uuid.UUID, name string) (*model.Organization, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error)
+	Get(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMembership, error)
+	Rename(ctx context.Context, orgID, actorID uuid.UUID, name string) (*model.OrganizationMembership, error)
+	Members(ctx context.Context, orgID, actorID uuid.UUID) ([]model.OrganizationMember, error)
+	AddMember(ctx context.Context, orgID, actorID uuid.UUID, email, role string) error
+	ChangeRole(ctx context.Context, orgID, actorID, userID uuid.UUID, role string) error
+	RemoveMember(ctx context.Context, orgID, actorID, userID uuid.UUID) error
+}
+
+// OrganizationHandler - организации пользователя. Маршруты /organization
+// работают с активной организацией из access-токена.
+type OrganizationHandler struct {
+	baseHandler
+	orgService OrganizationProvider
+}
+
+func NewOrganizationHandler(orgService OrganizationProvider) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// ListMine - GET /me/organizations
+func (h *OrganizationHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	memberships, err := h.orgService.ListForUser(r.Context(), current.ID)
+	if err != nil {
+		h.writeError(w, "failed to fetch organizations", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, memberships)
+}
+
+// Create - POST /organizations, создатель становится владельцем. Чтобы
+// работать в новой организации, на неё нужно переключиться.
+func (h *OrganizationHandler) Create(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	org, err := h.orgService.Create(r.Context(), current.ID, req.Name)
+	if err != nil {
+		h.writeError(w, "failed to create organization", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, model.OrganizationMembership{Organization: *org, Role: model.OrgRoleOwner})
+}
+
+// Get - GET /organization
+func (h *OrganizationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	membership, err := h.orgService.Get(r.Context(), orgID, current.ID)
+	if err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, membership)
+}
+
+// Rename - PATCH /organization
+func (h *OrganizationHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	var req OrganizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	membership, err := h.orgService.Rename(r.Context(), orgID, current.ID, req.Name)
+	if err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, membership)
+}
+
+// ListMembers - GET /organization/members
+func (h *OrganizationHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := h.orgService.Members(r.Context(), orgID, current.ID)
+	if err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, members)
+}
+
+// AddMember - POST /organization/members, приглашение по почте. Отвечает
+// 202 одинаково для зарегистрированных и новых адресов: в организации
+// человек окажется, только приняв приглашение.
+func (h *OrganizationHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	var req AddOrganizationMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.orgService.AddMember(r.Context(), orgID, current.ID, req.Email, req.Role); err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ChangeMemberRole - PUT /organization/members/{userID}
+func (h *OrganizationHandler) ChangeMemberRole(w http.ResponseWriter, r *http.Request, userIDStr string) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req OrganizationMemberRoleRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.orgService.ChangeRole(r.Context(), orgID, current.ID, userID, req.Role); err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMember - DELETE /organization/members/{userID}, в том числе выход
+// из организации по своему ID
+func (h *OrganizationHandler) RemoveMember(w http.ResponseWriter, r *http.Request, userIDStr string) {
+	current, orgID, ok := h.activeOrganization(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.orgService.RemoveMember(r.Context(), orgID, current.ID, userID); err != nil {
+		h.writeOrganizationError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// activeOrganization достаёт вызывающего и его активную организацию. Если
+// чего-то нет, ответ уже записан.
+func (h *OrganizationHandler) activeOrganization(w http.ResponseWriter, r *http.Request) (*model.User, uuid.UUID, bool) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return nil, uuid.Nil, false
+	}
+
+	orgID := middleware.OrganizationFromContext(r.Context())
+	if orgID == uuid.Nil {
+		h.writeError(w, service.ErrOrganizationNotFound.Error(), http.StatusNotFound)
+		return nil, uuid.Nil, false
+	}
+	return current, orgID, true
+}
+
+func (h *OrganizationHandler) writeOrganizationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound),
+		errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrUserNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrOrganizationForbidden):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrInvalidOrgRole):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, repository.ErrLastOwner),
+		errors.Is(err, repository.ErrAlreadyMember):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
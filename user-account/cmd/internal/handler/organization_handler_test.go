@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationHandler_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockOrganizationProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			body: `{"name":"  Acme Legal  "}`,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().Create(gomock.Any(), userID, "Acme Legal").
+					Return(&model.Organization{ID: orgID, Name: "Acme Legal"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"role":"owner"`,
+		},
+		{
+			name:           "2. Missing Name",
+			body:           `{"name":" "}`,
+			mockBehavior:   func(_ *mocks.MockOrganizationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"name is required"`,
+		},
+		{
+			name: "3. Storage Failure",
+			body: `{"name":"Acme Legal"}`,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().Create(gomock.Any(), userID, "Acme Legal").Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOrganizationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewOrganizationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/organizations", bytes.NewBufferString(tt.body))
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Create(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestOrganizationHandler_ListMembers(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		withOrg        bool
+		mockBehavior   func(m *mocks.MockOrganizationProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:    "1. Success",
+			withOrg: true,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().Members(gomock.Any(), orgID, userID).Return([]model.OrganizationMember{
+					{UserID: userID, Email: "owner@test.com", Nickname: "owner", Role: model.OrgRoleOwner},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"email":"owner@test.com"`,
+		},
+		{
+			name:           "2. No Active Organization",
+			mockBehavior:   func(_ *mocks.MockOrganizationProvider) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:    "3. No Longer A Member",
+			withOrg: true,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().Members(gomock.Any(), orgID, userID).Return(nil, service.ErrOrganizationNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOrganizationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewOrganizationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodGet, "/organization/members", nil)
+			ctx := middleware.ContextWithUser(req.Context(), &model.User{ID: userID})
+			if tt.withOrg {
+				ctx = middleware.ContextWithOrganization(ctx, orgID)
+			}
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+			h.ListMembers(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestOrganizationHandler_AddMember(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockOrganizationProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success With Default Role",
+			body: `{"email":"new@test.com"}`,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().AddMember(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleMember).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "2. Unknown Role",
+			body:           `{"email":"new@test.com","role":"superuser"}`,
+			mockBehavior:   func(_ *mocks.MockOrganizationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "3. Member Cannot Add",
+			body: `{"email":"new@test.com","role":"admin"}`,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().AddMember(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleAdmin).
+					Return(service.ErrOrganizationForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "4. Already A Member",
+			body: `{"email":"new@test.com"}`,
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().AddMember(gomock.Any(), orgID, userID, "new@test.com", model.OrgRoleMember).
+					Return(repository.ErrAlreadyMember)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOrganizationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewOrganizationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/organization/members", bytes.NewBufferString(tt.body))
+			ctx := middleware.ContextWithUser(req.Context(), &model.User{ID: userID})
+			req = req.WithContext(middleware.ContextWithOrganization(ctx, orgID))
+			w := httptest.NewRecorder()
+			h.AddMember(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestOrganizationHandler_RemoveMember(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	memberID := uuid.New()
+
+	tests := []struct {
+		name           string
+		memberID       string
+		mockBehavior   func(m *mocks.MockOrganizationProvider)
+		expectedStatus int
+	}{
+		{
+			name:     "1. Success",
+			memberID: memberID.String(),
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().RemoveMember(gomock.Any(), orgID, userID, memberID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:     "2. Last Owner",
+			memberID: userID.String(),
+			mockBehavior: func(m *mocks.MockOrganizationProvider) {
+				m.EXPECT().RemoveMember(gomock.Any(), orgID, userID, userID).Return(repository.ErrLastOwner)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "3. Invalid ID",
+			memberID:       "bad-id",
+			mockBehavior:   func(_ *mocks.MockOrganizationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockOrganizationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewOrganizationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/organization/members/"+tt.memberID, nil)
+			ctx := middleware.ContextWithUser(req.Context(), &model.User{ID: userID})
+			req = req.WithContext(middleware.ContextWithOrganization(ctx, orgID))
+			w := httptest.NewRecorder()
+			h.RemoveMember(w, req, tt.memberID)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
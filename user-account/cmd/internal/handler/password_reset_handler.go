@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/service"
+)
+
+type PasswordResetProvider interface {
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+type PasswordResetHandler struct {
+	baseHandler
+	resetService PasswordResetProvider
+}
+
+func NewPasswordResetHandler(resetService PasswordResetProvider) *PasswordResetHandler {
+	return &PasswordResetHandler{resetService: resetService}
+}
+
+// Forgot - POST /password/forgot. Отвечает одинаково для любых адресов.
+func (h *PasswordResetHandler) Forgot(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.resetService.RequestReset(r.Context(), req.Email); err != nil {
+		h.writeError(w, "failed to request password reset", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "ok",
+		"message": "If the address is registered, a reset link has been sent",
+	})
+}
+
+// Reset - POST /password/reset, новый пароль по токену из письма
+func (h *PasswordResetHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.resetService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		if h.writePolicyError(w, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidResetToken) {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeError(w, "failed to reset password", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "ok",
+		"message": "Password has been reset, please log in again",
+	})
+}
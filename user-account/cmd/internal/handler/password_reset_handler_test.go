@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetHandler_Forgot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockPasswordResetProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Accepted",
+			body: `{"email":"someone@example.com"}`,
+			mockBehavior: func(m *mocks.MockPasswordResetProvider) {
+				m.EXPECT().RequestReset(gomock.Any(), "someone@example.com").Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:           "2. Invalid Email",
+			body:           `{"email":"not-an-email"}`,
+			mockBehavior:   func(m *mocks.MockPasswordResetProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "3. Internal Error",
+			body: `{"email":"someone@example.com"}`,
+			mockBehavior: func(m *mocks.MockPasswordResetProvider) {
+				m.EXPECT().RequestReset(gomock.Any(), "someone@example.com").Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPasswordResetProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPasswordResetHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.Forgot(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPasswordResetHandler_Reset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockPasswordResetProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			body: `{"token":"tok","new_password":"new-password"}`,
+			mockBehavior: func(m *mocks.MockPasswordResetProvider) {
+				m.EXPECT().ResetPassword(gomock.Any(), "tok", "new-password").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "2. Invalid Token",
+			body: `{"token":"tok","new_password":"new-password"}`,
+			mockBehavior: func(m *mocks.MockPasswordResetProvider) {
+				m.EXPECT().ResetPassword(gomock.Any(), "tok", "new-password").Return(service.ErrInvalidResetToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid or expired reset token"`,
+		},
+		{
+			name:           "3. Missing Password",
+			body:           `{"token":"tok","new_password":""}`,
+			mockBehavior:   func(m *mocks.MockPasswordResetProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "4. Password Policy Violation",
+			body: `{"token":"tok","new_password":"123"}`,
+			mockBehavior: func(m *mocks.MockPasswordResetProvider) {
+				m.EXPECT().ResetPassword(gomock.Any(), "tok", "123").Return(&passpolicy.Error{
+					Violations: []passpolicy.Violation{{Rule: passpolicy.RuleBreached, Message: "password has appeared in a data breach, choose another one"}},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"violations":[{"rule":"breached"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPasswordResetProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPasswordResetHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/password/reset", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.Reset(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
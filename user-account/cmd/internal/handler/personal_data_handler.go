@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type PersonalDataProvider interface {
+	RequestExport(ctx context.Context, userID uuid.UUID) (*model.DataExport, error)
+	GetExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error)
+	DownloadExport(ctx context.Context, userID, id uuid.UUID) ([]byte, error)
+	Erase(ctx context.Context, id uuid.UUID) error
+	EraseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error
+}
+
+// PersonalDataHandler - запросы субъекта персональных данных: выгрузка
+// своих данных и стирание
+type PersonalDataHandler struct {
+	baseHandler
+	personalData PersonalDataProvider
+}
+
+func NewPersonalDataHandler(personalData PersonalDataProvider) *PersonalDataHandler {
+	return &PersonalDataHandler{personalData: personalData}
+}
+
+// RequestExport - POST /me/data-export, ставит выгрузку своих данных в
+// очередь. Отвечает 202 с выгрузкой; её состояние смотрится через
+// GET /me/data-export/{id}, архив скачивается по download_url.
+func (h *PersonalDataHandler) RequestExport(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := h.personalData.RequestExport(r.Context(), current.ID)
+	if err != nil {
+		h.writeError(w, "failed to request data export", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/me/data-export/"+export.ID.String())
+	h.writeJSON(w, http.StatusAccepted, newDataExportResponse(export))
+}
+
+// GetExport - GET /me/data-export/{id}, состояние своей выгрузки
+func (h *PersonalDataHandler) GetExport(w http.ResponseWriter, r *http.Request, idStr string) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid data export ID format", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.personalData.GetExport(r.Context(), current.ID, id)
+	if err != nil {
+		h.writeExportError(w, err)
+		return
+	}
+	h.writeJSON(w, http.StatusOK, newDataExportResponse(export))
+}
+
+// DownloadExport - GET /me/data-export/{id}/download, ZIP-архив готовой
+// выгрузки
+func (h *PersonalDataHandler) DownloadExport(w http.ResponseWriter, r *http.Request, idStr string) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid data export ID format", http.StatusBadRequest)
+		return
+	}
+
+	archive, err := h.personalData.DownloadExport(r.Context(), current.ID, id)
+	if err != nil {
+		h.writeExportError(w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("personal-data-%s.zip", time.Now().UTC().Format("20060102"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(archive)
+}
+
+// EraseMe - POST /me/erase, стирает свой аккаунт после проверки пароля.
+// В отличие от DELETE /me, стирание необратимо.
+func (h *PersonalDataHandler) EraseMe(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	token := middleware.TokenFromContext(r.Context())
+	if current == nil || token == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CloseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.personalData.EraseAccount(r.Context(), current.ID, req.Password, *token); err != nil {
+		if h.writeLocked(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			h.writeError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, service.ErrInvalidPassword):
+			h.writeError(w, err.Error(), http.StatusForbidden)
+		default:
+			h.writeError(w, "failed to erase account", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Erase - POST /users/{id}/erase, стирание по запросу, полученному не через
+// API. Стирается и удалённый пользователь, пока его не удалили окончательно.
+func (h *PersonalDataHandler) Erase(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.personalData.Erase(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeError(w, "failed to erase user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PersonalDataHandler) writeExportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrDataExportNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrDataExportNotReady):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "failed to fetch data export", http.StatusInternalServerError)
+	}
+}
+
+// dataExportResponse - выгрузка с адресом архива, пока его можно скачать
+type dataExportResponse struct {
+	*model.DataExport
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+func newDataExportResponse(export *model.DataExport) dataExportResponse {
+	resp := dataExportResponse{DataExport: export}
+	if export.Downloadable(time.Now()) {
+		resp.DownloadURL = "/me/data-export/" + export.ID.String() + "/download"
+	}
+	return resp
+}
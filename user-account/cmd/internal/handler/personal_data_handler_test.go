@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalDataHandler_RequestExport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mocks.MockPersonalDataProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Accepted",
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().RequestExport(gomock.Any(), userID).
+					Return(&model.DataExport{ID: exportID, UserID: userID, Status: model.DataExportPending}, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name: "2. Internal Error",
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().RequestExport(gomock.Any(), userID).Return(nil, errors.New("db failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPersonalDataProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPersonalDataHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodPost, "/me/data-export", nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.RequestExport(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusAccepted {
+				assert.Equal(t, "/me/data-export/"+exportID.String(), w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestPersonalDataHandler_GetExport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exportID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name           string
+		id             string
+		mockBehavior   func(m *mocks.MockPersonalDataProvider)
+		expectedStatus int
+		expectedURL    string
+	}{
+		{
+			name: "1. Ready With Download URL",
+			id:   exportID.String(),
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().GetExport(gomock.Any(), userID, exportID).
+					Return(&model.DataExport{ID: exportID, UserID: userID, Status: model.DataExportReady, ExpiresAt: &expiresAt}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedURL:    "/me/data-export/" + exportID.String() + "/download",
+		},
+		{
+			name: "2. Pending Without Download URL",
+			id:   exportID.String(),
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().GetExport(gomock.Any(), userID, exportID).
+					Return(&model.DataExport{ID: exportID, UserID: userID, Status: model.DataExportPending}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "3. Not Found",
+			id:   exportID.String(),
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().GetExport(gomock.Any(), userID, exportID).Return(nil, service.ErrDataExportNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "4. Invalid ID",
+			id:             "not-a-uuid",
+			mockBehavior:   func(_ *mocks.MockPersonalDataProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPersonalDataProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPersonalDataHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodGet, "/me/data-export/"+tt.id, nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.GetExport(w, req, tt.id)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				if tt.expectedURL != "" {
+					assert.Equal(t, tt.expectedURL, resp["download_url"])
+				} else {
+					assert.NotContains(t, resp, "download_url")
+				}
+			}
+		})
+	}
+}
+
+func TestPersonalDataHandler_DownloadExport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exportID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mocks.MockPersonalDataProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().DownloadExport(gomock.Any(), userID, exportID).Return([]byte("PK-archive"), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "2. Not Ready",
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().DownloadExport(gomock.Any(), userID, exportID).Return(nil, service.ErrDataExportNotReady)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "3. Internal Error",
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().DownloadExport(gomock.Any(), userID, exportID).Return(nil, errors.New("db failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPersonalDataProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPersonalDataHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodGet, "/me/data-export/"+exportID.String()+"/download", nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.DownloadExport(w, req, exportID.String())
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+				assert.Equal(t, "PK-archive", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPersonalDataHandler_EraseMe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	token := &model.TokenClaims{ID: uuid.NewString(), SessionID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockPersonalDataProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			body: `{"password":"current-pass"}`,
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().EraseAccount(gomock.Any(), userID, "current-pass", *token).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "2. Password Required",
+			body:           `{}`,
+			mockBehavior:   func(_ *mocks.MockPersonalDataProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "3. Wrong Password",
+			body: `{"password":"guess"}`,
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().EraseAccount(gomock.Any(), userID, "guess", *token).Return(service.ErrInvalidPassword)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPersonalDataProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPersonalDataHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodPost, "/me/erase", strings.NewReader(tt.body))
+			ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+			ctx = middleware.ContextWithToken(ctx, token)
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+			h.EraseMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestPersonalDataHandler_Erase(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockBehavior   func(m *mocks.MockPersonalDataProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().Erase(gomock.Any(), userID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "2. Not Found",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockPersonalDataProvider) {
+				m.EXPECT().Erase(gomock.Any(), userID).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Invalid ID",
+			id:             "not-a-uuid",
+			mockBehavior:   func(_ *mocks.MockPersonalDataProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockPersonalDataProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewPersonalDataHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tt.id+"/erase", nil)
+			w := httptest.NewRecorder()
+			h.Erase(w, req, tt.id)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
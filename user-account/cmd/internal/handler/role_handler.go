@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type RoleProvider interface {
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	UserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	AssignRole(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error
+	RevokeRole(ctx context.Context, userID uuid.UUID, role string, revokedBy uuid.UUID) error
+}
+
+type RoleHandler struct {
+	baseHandler
+	roleService RoleProvider
+}
+
+func NewRoleHandler(roleService RoleProvider) *RoleHandler {
+	return &RoleHandler{roleService: roleService}
+}
+
+// ListRoles - GET /roles, все роли с их правами
+func (h *RoleHandler) ListRoles(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleService.ListRoles(r.Context())
+	if err != nil {
+		h.writeError(w, "failed to fetch roles", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, roles)
+}
+
+// ListUserRoles - GET /users/{id}/roles
+func (h *RoleHandler) ListUserRoles(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	roles, err := h.roleService.UserRoles(r.Context(), id)
+	if err != nil {
+		h.writeRoleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string][]string{"roles": roles})
+}
+
+// AssignRole - PUT /users/{id}/roles/{role}
+func (h *RoleHandler) AssignRole(w http.ResponseWriter, r *http.Request, idStr, role string) {
+	admin := middleware.UserFromContext(r.Context())
+	if admin == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.roleService.AssignRole(r.Context(), id, role, admin.ID); err != nil {
+		h.writeRoleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeRole - DELETE /users/{id}/roles/{role}
+func (h *RoleHandler) RevokeRole(w http.ResponseWriter, r *http.Request, idStr, role string) {
+	admin := middleware.UserFromContext(r.Context())
+	if admin == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.roleService.RevokeRole(r.Context(), id, role, admin.ID); err != nil {
+		h.writeRoleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RoleHandler) writeRoleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound),
+		errors.Is(err, service.ErrRoleNotFound),
+		errors.Is(err, service.ErrRoleNotGranted):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrSelfRoleRevoke):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "failed to update roles", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleHandler_ListRoles(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	mockSvc := mocks.NewMockRoleProvider(ctrl)
+	mockSvc.EXPECT().ListRoles(gomock.Any()).Return([]model.Role{
+		{Name: "admin", Permissions: []string{"users:manage"}},
+	}, nil)
+
+	h := NewRoleHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodGet, "/roles", nil)
+	w := httptest.NewRecorder()
+	h.ListRoles(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"permissions":["users:manage"]`)
+}
+
+func TestRoleHandler_AssignRole(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockBehavior   func(m *mocks.MockRoleProvider)
+		expectedStatus int
+	}{
+		{
+			name: "Success",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockRoleProvider) {
+				m.EXPECT().AssignRole(gomock.Any(), userID, "admin", adminID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Invalid User ID",
+			id:             "not-a-uuid",
+			mockBehavior:   func(_ *mocks.MockRoleProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Unknown Role",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockRoleProvider) {
+				m.EXPECT().AssignRole(gomock.Any(), userID, "admin", adminID).Return(service.ErrRoleNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "Internal Error",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockRoleProvider) {
+				m.EXPECT().AssignRole(gomock.Any(), userID, "admin", adminID).Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockRoleProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewRoleHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPut, "/users/"+tt.id+"/roles/admin", nil)
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: adminID}))
+			w := httptest.NewRecorder()
+			h.AssignRole(w, req, tt.id, "admin")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRoleHandler_RevokeRole(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             uuid.UUID
+		err            error
+		expectedStatus int
+	}{
+		{name: "Success", id: uuid.New(), expectedStatus: http.StatusNoContent},
+		{name: "Not Assigned", id: uuid.New(), err: service.ErrRoleNotGranted, expectedStatus: http.StatusNotFound},
+		{name: "Self Revoke", id: adminID, err: service.ErrSelfRoleRevoke, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockRoleProvider(ctrl)
+			mockSvc.EXPECT().RevokeRole(gomock.Any(), tt.id, "admin", adminID).Return(tt.err)
+
+			h := NewRoleHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+tt.id.String()+"/roles/admin", nil)
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: adminID}))
+			w := httptest.NewRecorder()
+			h.RevokeRole(w, req, tt.id.String(), "admin")
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type TwoFactorProvider interface {
+	Enroll(ctx context.Context, userID uuid.UUID) (*model.TOTPEnrollment, error)
+	Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	Disable(ctx context.Context, userID uuid.UUID, code string) error
+}
+
+type TwoFactorHandler struct {
+	baseHandler
+	twoFactorService TwoFactorProvider
+}
+
+func NewTwoFactorHandler(twoFactorService TwoFactorProvider) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorService: twoFactorService}
+}
+
+// Enroll - POST /me/2fa/totp, новый секрет и QR-код для приложения
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(r.Context(), current.ID)
+	if err != nil {
+		h.writeTwoFactorError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, enrollment)
+}
+
+// Confirm - POST /me/2fa/totp/confirm, включение фактора первым кодом
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(r.Context(), current.ID, req.Code)
+	if err != nil {
+		h.writeTwoFactorError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+// Disable - DELETE /me/2fa/totp, выключение фактора с подтверждением кодом
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.twoFactorService.Disable(r.Context(), current.ID, req.Code); err != nil {
+		h.writeTwoFactorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TwoFactorHandler) writeTwoFactorError(w http.ResponseWriter, err error) {
+	if h.writeLocked(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrTOTPAlreadyEnabled),
+		errors.Is(err, service.ErrTOTPNotEnrolled),
+		errors.Is(err, service.ErrTOTPNotEnabled):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTwoFactorHandler_Enroll(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mocks.MockTwoFactorProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Enroll(gomock.Any(), userID).Return(&model.TOTPEnrollment{
+					Secret: "SECRET",
+					URI:    "otpauth://totp/Actium:me@example.com?secret=SECRET",
+					QRCode: "cG5n",
+				}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `"otpauth_uri":"otpauth://totp/Actium:me@example.com?secret=SECRET"`,
+		},
+		{
+			name: "2. Already Enabled",
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Enroll(gomock.Any(), userID).Return(nil, service.ErrTOTPAlreadyEnabled)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockTwoFactorProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewTwoFactorHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/2fa/totp", nil)
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Enroll(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestTwoFactorHandler_Confirm(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockTwoFactorProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success Returns Recovery Codes",
+			body: `{"code":"123456"}`,
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Confirm(gomock.Any(), userID, "123456").Return([]string{"aaaa-bbbb-cccc-dddd"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"recovery_codes":["aaaa-bbbb-cccc-dddd"]}`,
+		},
+		{
+			name: "2. Wrong Code",
+			body: `{"code":"000000"}`,
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Confirm(gomock.Any(), userID, "000000").Return(nil, service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "3. Missing Code",
+			body:           `{}`,
+			mockBehavior:   func(m *mocks.MockTwoFactorProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockTwoFactorProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewTwoFactorHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/2fa/totp/confirm", bytes.NewBufferString(tt.body))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Confirm(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestTwoFactorHandler_Disable(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mocks.MockTwoFactorProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Disable(gomock.Any(), userID, "123456").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "2. Invalid Code",
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Disable(gomock.Any(), userID, "123456").Return(service.ErrInvalidTwoFactorCode)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "3. Too Many Invalid Codes",
+			mockBehavior: func(m *mocks.MockTwoFactorProvider) {
+				m.EXPECT().Disable(gomock.Any(), userID, "123456").Return(&service.LoginLockedError{RetryAfter: time.Minute})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockTwoFactorProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewTwoFactorHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodDelete, "/me/2fa/totp", bytes.NewBufferString(`{"code":"123456"}`))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.Disable(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusTooManyRequests {
+				assert.NotEmpty(t, w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
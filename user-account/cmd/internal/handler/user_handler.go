@@ -3,8 +3,17 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
 	"net/http"
+	"time"
+	"user-account/cmd/internal/middleware"
 	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+	"user-account/cmd/internal/tabular"
 
 	"github.com/google/uuid"
 )
@@ -12,9 +21,45 @@ import (
 type UserProvider interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	UpdatePassword(ctx context.Context, id uuid.UUID, newPassword string) error
-	List(ctx context.Context) ([]model.User, error)
+	List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error)
+	Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error)
+	Export(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error
+	Get(ctx context.Context, id uuid.UUID) (*model.User, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, update model.ProfileUpdate) (*model.User, error)
+	ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error
+	CloseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error
+	Unlock(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) error
 }
 
+// profileResponse - профиль текущего пользователя
+type profileResponse struct {
+	ID            uuid.UUID         `json:"id"`
+	Email         string            `json:"email"`
+	Nickname      string            `json:"nickname"`
+	Role          string            `json:"role"`
+	Roles         []string          `json:"roles"`
+	Preferences   model.Preferences `json:"preferences"`
+	CreatedAt     string            `json:"created_at"`
+	EmailVerified bool              `json:"email_verified"`
+}
+
+func newProfileResponse(u *model.User) profileResponse {
+	return profileResponse{
+		ID:            u.ID,
+		Email:         u.Email,
+		Nickname:      u.Nickname,
+		Role:          u.PrimaryRole(),
+		Roles:         u.Roles,
+		Preferences:   u.Preferences,
+		CreatedAt:     u.CreatedAt.Format("2006-01-02 15:04:05"),
+		EmailVerified: u.EmailVerifiedAt != nil,
+	}
+}
+
+// exportWriteTimeout - срок записи ответа выгрузки пользователей
+const exportWriteTimeout = 30 * time.Minute
+
 type UserHandler struct {
 	baseHandler
 	userService UserProvider
@@ -24,9 +69,18 @@ func NewUserHandler(userService UserProvider) *UserHandler {
 	return &UserHandler{userService: userService}
 }
 
-// List - GET /users
+// List - GET /users, только участники активной организации вызывающего.
+// Вне организаций список пуст. Параметры запроса описаны у
+// parseUserListQuery, следующая страница запрашивается с next_cursor.
 func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
-	users, err := h.userService.List(r.Context())
+	query, err := parseUserListQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.OrganizationID = middleware.OrganizationFromContext(r.Context())
+
+	page, err := h.userService.List(r.Context(), query)
 	if err != nil {
 		h.writeError(w, "failed to fetch users", http.StatusInternalServerError)
 		return
@@ -37,16 +91,27 @@ func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
 		Email     string    `json:"email"`
 		Nickname  string    `json:"nickname"`
 		Role      string    `json:"role"`
+		Roles     []string  `json:"roles"`
 		CreatedAt string    `json:"created_at"`
 	}
+	type pageResponse struct {
+		Users      []userResponse `json:"users"`
+		NextCursor string         `json:"next_cursor,omitempty"`
+		Total      *int           `json:"total,omitempty"`
+	}
 
-	resp := make([]userResponse, len(users))
-	for i, u := range users {
-		resp[i] = userResponse{
+	resp := pageResponse{
+		Users:      make([]userResponse, len(page.Users)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+	for i, u := range page.Users {
+		resp.Users[i] = userResponse{
 			ID:        u.ID,
 			Email:     u.Email,
 			Nickname:  u.Nickname,
-			Role:      u.Role,
+			Role:      u.PrimaryRole(),
+			Roles:     u.Roles,
 			CreatedAt: u.CreatedAt.Format("2006-01-02 15:04:05"),
 		}
 	}
@@ -54,6 +119,112 @@ func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
 	h.writeJSON(w, http.StatusOK, resp)
 }
 
+// Export - GET /users/export, выгрузка участников активной организации
+// файлом. Параметры описаны у parseUserExportOptions. Строки пишутся в ответ
+// по мере чтения из базы; если база отказала на середине, статус уже
+// отправлен и выгрузка просто обрывается.
+func (h *UserHandler) Export(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseUserExportOptions(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	opts.Query.OrganizationID = middleware.OrganizationFromContext(r.Context())
+
+	// Общий WriteTimeout сервера оборвал бы длинную выгрузку на середине,
+	// поэтому для этого ответа срок записи продлевается
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("user export: failed to extend write deadline: %v", err)
+	}
+
+	// Ответ начинается с первой строкой: ошибка запроса до неё ещё
+	// отдаётся обычным JSON с кодом 500
+	var out tabular.Writer
+	started := false
+	start := func() error {
+		started = true
+		filename := fmt.Sprintf("users-%s.%s", time.Now().UTC().Format("20060102"), opts.Format)
+		w.Header().Set("Content-Type", tabular.ContentType(opts.Format))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		w.WriteHeader(http.StatusOK)
+
+		var err error
+		out, err = tabular.NewWriter(opts.Format, w, opts.Columns, model.UserExportHeader(opts.Columns, opts.Language))
+		return err
+	}
+
+	err = h.userService.Export(r.Context(), opts.Query, func(u model.User) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.Write(model.UserExportRow(u, opts.Columns))
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if err != nil {
+		if !started {
+			h.writeError(w, "failed to export users", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("user export aborted: %v", err)
+		return
+	}
+	if err = out.Close(); err != nil {
+		log.Printf("user export aborted: %v", err)
+	}
+}
+
+// Search - GET /users/search?q=, поиск по части ника или адреса среди
+// участников активной организации. Лучшие совпадения идут первыми,
+// highlights - позиции совпавших фрагментов [start, end) в символах.
+func (h *UserHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query, err := parseUserSearchQuery(r.URL.Query())
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query.OrganizationID = middleware.OrganizationFromContext(r.Context())
+
+	hits, err := h.userService.Search(r.Context(), query)
+	if err != nil {
+		h.writeError(w, "failed to search users", http.StatusInternalServerError)
+		return
+	}
+
+	type hitResponse struct {
+		ID         uuid.UUID            `json:"id"`
+		Email      string               `json:"email"`
+		Nickname   string               `json:"nickname"`
+		Role       string               `json:"role"`
+		Roles      []string             `json:"roles"`
+		CreatedAt  string               `json:"created_at"`
+		Rank       float64              `json:"rank"`
+		Highlights model.UserHighlights `json:"highlights"`
+	}
+	type searchResponse struct {
+		Results []hitResponse `json:"results"`
+	}
+
+	resp := searchResponse{Results: make([]hitResponse, len(hits))}
+	for i, hit := range hits {
+		resp.Results[i] = hitResponse{
+			ID:         hit.User.ID,
+			Email:      hit.User.Email,
+			Nickname:   hit.User.Nickname,
+			Role:       hit.User.PrimaryRole(),
+			Roles:      hit.User.Roles,
+			CreatedAt:  hit.User.CreatedAt.Format("2006-01-02 15:04:05"),
+			Rank:       hit.Rank,
+			Highlights: hit.Highlights,
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
 // Delete - DELETE /users/{id}
 func (h *UserHandler) Delete(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
 	if err := h.userService.Delete(r.Context(), id); err != nil {
@@ -78,6 +249,13 @@ func (h *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request, id
 	}
 
 	if err := h.userService.UpdatePassword(r.Context(), id, req.NewPassword); err != nil {
+		if h.writePolicyError(w, err) {
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		h.writeError(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -85,6 +263,48 @@ func (h *UserHandler) UpdatePassword(w http.ResponseWriter, r *http.Request, id
 	h.writeJSON(w, http.StatusOK, map[string]string{"status": "password updated"})
 }
 
+// Unlock - POST /users/{id}/unlock, снимает блокировку входа после
+// неудачных попыток
+func (h *UserHandler) Unlock(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.userService.Unlock(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.writeError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.writeError(w, "failed to unlock user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Restore - POST /users/{id}/restore, отмена удаления до окончательной
+// очистки
+func (h *UserHandler) Restore(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	if err = h.userService.Restore(r.Context(), id); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.writeError(w, "deleted user not found", http.StatusNotFound)
+			return
+		}
+		h.writeError(w, "failed to restore user", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // ServeUserByID - роутинг для /users/{id}
 func (h *UserHandler) ServeUserByID(w http.ResponseWriter, r *http.Request, idStr string) {
 	id, err := uuid.Parse(idStr)
@@ -102,3 +322,123 @@ func (h *UserHandler) ServeUserByID(w http.ResponseWriter, r *http.Request, idSt
 		h.writeError(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+// GetMe - GET /me
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	user, err := h.userService.Get(r.Context(), current.ID)
+	if err != nil {
+		h.writeMeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// UpdateMe - PATCH /me, меняет никнейм и настройки
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(r.Context(), current.ID, model.ProfileUpdate{
+		Nickname:    req.Nickname,
+		Preferences: req.Preferences,
+	})
+	if err != nil {
+		h.writeMeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newProfileResponse(user))
+}
+
+// ChangeMyPassword - POST /me/password, в отличие от PATCH /users/{id}
+// требует текущий пароль
+func (h *UserHandler) ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.ChangePassword(r.Context(), current.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		h.writeMeError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "password updated"})
+}
+
+// DeleteMe - DELETE /me, закрытие аккаунта с подтверждением паролем
+func (h *UserHandler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	current := middleware.UserFromContext(r.Context())
+	token := middleware.TokenFromContext(r.Context())
+	if current == nil || token == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CloseAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.CloseAccount(r.Context(), current.ID, req.Password, *token); err != nil {
+		h.writeMeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *UserHandler) writeMeError(w http.ResponseWriter, err error) {
+	if h.writePolicyError(w, err) || h.writeLocked(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidPassword):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, repository.ErrNicknameTaken):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
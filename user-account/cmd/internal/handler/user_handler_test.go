@@ -2,13 +2,21 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
 	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/service"
+	"user-account/cmd/internal/tabular"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -41,12 +49,24 @@ func TestUserHandler_UpdatePassword(t *testing.T) {
 			expectedBody:   `"status":"password updated"`,
 		},
 		{
-			name:           "Validation Error (Too Short)",
+			name:           "Validation Error (Empty)",
 			id:             userID,
-			requestBody:    UpdatePasswordRequest{NewPassword: "123"},
+			requestBody:    UpdatePasswordRequest{NewPassword: ""},
 			mockBehavior:   func(_ *mocks.MockUserProvider) {},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `"error":"new password must be at least 6 characters long"`,
+			expectedBody:   `"error":"new_password is required"`,
+		},
+		{
+			name:        "Password Policy Violation",
+			id:          userID,
+			requestBody: UpdatePasswordRequest{NewPassword: "nickname1"},
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().UpdatePassword(gomock.Any(), userID, "nickname1").Return(&passpolicy.Error{
+					Violations: []passpolicy.Violation{{Rule: passpolicy.RulePersonalInfo, Message: "password must not contain your nickname or email"}},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"violations":[{"rule":"personal_info"`,
 		},
 		{
 			name:        "Service Internal Error",
@@ -94,60 +114,117 @@ func TestUserHandler_List(t *testing.T) {
 		Email     string    `json:"email"`
 		Nickname  string    `json:"nickname"`
 		Role      string    `json:"role"`
+		Roles     []string  `json:"roles"`
 		CreatedAt string    `json:"created_at"`
 	}
+	type pageResponse struct {
+		Users      []userResponse `json:"users"`
+		NextCursor string         `json:"next_cursor"`
+		Total      *int           `json:"total"`
+	}
+
+	orgID := uuid.New()
+	total := 7
+	cursor := model.UserCursor{SortBy: model.UserSortEmail, Descending: true, Value: "b@test.com", ID: uuid.New()}
+	mockUsers := []model.User{
+		{
+			ID:       uuid.New(),
+			Email:    "one@test.com",
+			Nickname: "nick1",
+			Roles:    []string{"user"},
+		},
+		{
+			ID:       uuid.New(),
+			Email:    "two@test.com",
+			Nickname: "nick2",
+			Roles:    []string{"user", "admin"},
+		},
+	}
+	createdFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name           string
-		mockUsers      []model.User
+		url            string
+		expectQuery    *model.UserListQuery
+		mockPage       *model.UserPage
 		mockErr        error
 		expectedStatus int
 		checkResponse  bool
 	}{
 		{
-			name: "Success List",
-			mockUsers: []model.User{
-				{
-					ID:       uuid.New(),
-					Email:    "one@test.com",
-					Nickname: "nick1",
-					Role:     "user",
-				},
-				{
-					ID:       uuid.New(),
-					Email:    "two@test.com",
-					Nickname: "nick2",
-					Role:     "admin",
-				},
-			},
-			mockErr:        nil,
+			name:           "1. Success List",
+			url:            "/users",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockPage:       &model.UserPage{Users: mockUsers, NextCursor: "next", Total: &total},
 			expectedStatus: http.StatusOK,
 			checkResponse:  true,
 		},
 		{
-			name:           "Internal Server Error",
-			mockUsers:      nil,
+			name: "2. Query Parameters",
+			url:  "/users?limit=10&sort=-email&cursor=" + cursor.Encode() + "&role=admin&email=%20Acme%20&nickname=bo&created_from=2026-01-01T00:00:00Z&include_total=true",
+			expectQuery: &model.UserListQuery{
+				OrganizationID: orgID,
+				Role:           "admin",
+				Email:          "Acme",
+				Nickname:       "bo",
+				CreatedFrom:    &createdFrom,
+				SortBy:         model.UserSortEmail,
+				Descending:     true,
+				Limit:          10,
+				After:          &cursor,
+				WithTotal:      true,
+			},
+			mockPage:       &model.UserPage{Users: []model.User{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "3. Limit Out Of Range",
+			url:            "/users?limit=500",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Unknown Sort Field",
+			url:            "/users?sort=password_hash",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "5. Cursor From Another Sort",
+			url:            "/users?sort=nickname&cursor=" + cursor.Encode(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "6. Invalid Date",
+			url:            "/users?created_to=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "7. Internal Server Error",
+			url:            "/users",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
 			mockErr:        errors.New("db failure"),
 			expectedStatus: http.StatusInternalServerError,
-			checkResponse:  false,
 		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
 			ctrl := gomock.NewController(t)
 
 			mockSvc := mocks.NewMockUserProvider(ctrl)
-			mockSvc.EXPECT().
-				List(gomock.Any()).
-				Return(tt.mockUsers, tt.mockErr).
-				Times(1)
+			if tt.expectQuery != nil {
+				mockSvc.EXPECT().
+					List(gomock.Any(), *tt.expectQuery).
+					Return(tt.mockPage, tt.mockErr).
+					Times(1)
+			}
 
 			h := NewUserHandler(mockSvc)
 
-			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req = req.WithContext(middleware.ContextWithOrganization(req.Context(), orgID))
 			w := httptest.NewRecorder()
 
 			h.List(w, req)
@@ -155,18 +232,633 @@ func TestUserHandler_List(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 
 			if tt.checkResponse {
-				var resp []userResponse
+				var resp pageResponse
 				err := json.NewDecoder(w.Body).Decode(&resp)
 
 				assert.NoError(t, err, "JSON decoding failed")
-				assert.Equal(t, len(tt.mockUsers), len(resp), "User list length mismatch")
+				assert.Equal(t, len(mockUsers), len(resp.Users), "User list length mismatch")
+				assert.Equal(t, "next", resp.NextCursor)
+				assert.Equal(t, &total, resp.Total)
+
+				assert.Equal(t, mockUsers[0].ID, resp.Users[0].ID)
+				assert.Equal(t, mockUsers[0].Email, resp.Users[0].Email)
+				assert.Equal(t, mockUsers[0].Nickname, resp.Users[0].Nickname)
+				assert.Equal(t, "user", resp.Users[0].Role)
+				assert.Equal(t, mockUsers[0].Roles, resp.Users[0].Roles)
+				assert.Equal(t, "admin", resp.Users[1].Role, "admin wins as the displayed role")
+				assert.NotEmpty(t, resp.Users[0].CreatedAt)
+			}
+		})
+	}
+}
+
+func TestUserHandler_Export(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockUsers := []model.User{
+		{ID: uuid.New(), Email: "one@test.com", Nickname: "nick1", Roles: []string{"user"}, CreatedAt: createdAt},
+		{ID: uuid.New(), Email: "two@test.com", Nickname: "nick2", Roles: []string{"admin", "user"}, Status: model.UserStatusSuspended, CreatedAt: createdAt},
+	}
+	streamUsers := func(users []model.User, err error) func(context.Context, model.UserListQuery, func(model.User) error) error {
+		return func(_ context.Context, _ model.UserListQuery, fn func(model.User) error) error {
+			for _, u := range users {
+				if err := fn(u); err != nil {
+					return err
+				}
+			}
+			return err
+		}
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectQuery    *model.UserListQuery
+		mockUsers      []model.User
+		mockErr        error
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+		expectedRows   []map[string]string
+	}{
+		{
+			name:           "1. CSV With Russian Headers",
+			url:            "/users/export?columns=email,roles,status&lang=ru&role=user&sort=-email",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, Role: "user", SortBy: model.UserSortEmail, Descending: true},
+			mockUsers:      mockUsers,
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "\xef\xbb\xbfЭлектронная почта,Роли,Статус\none@test.com,user,active\ntwo@test.com,\"admin,user\",suspended\n",
+		},
+		{
+			name:           "2. JSON",
+			url:            "/users/export?format=json&columns=nickname,created_at,nickname",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockUsers:      mockUsers[:1],
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedBody:   `[{"nickname":"nick1","created_at":"2026-03-01T12:00:00Z"}]` + "\n",
+		},
+		{
+			name:           "3. Empty Selection Keeps Header",
+			url:            "/users/export?columns=id,email",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "\xef\xbb\xbfID,Email\n",
+		},
+		{
+			name:           "4. Unknown Column",
+			url:            "/users/export?columns=email,password_hash",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "5. Unknown Format",
+			url:            "/users/export?format=xml",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "6. Unknown Language",
+			url:            "/users/export?lang=de",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "7. Pagination Is Rejected",
+			url:            "/users/export?limit=10",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "8. Error Before First Row",
+			url:            "/users/export",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockErr:        errors.New("db failure"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedType:   "application/json",
+		},
+		{
+			name:           "9. Error Mid Stream Aborts",
+			url:            "/users/export?columns=email",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockUsers:      mockUsers[:1],
+			mockErr:        errors.New("connection reset"),
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			// Недописанный буфер не сбрасывается, файл оборван
+			expectedBody: "\xef\xbb\xbf",
+		},
+		{
+			name:        "10. Formula Cells Are Escaped",
+			url:         "/users/export?columns=email,nickname",
+			expectQuery: &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockUsers: []model.User{
+				{ID: uuid.New(), Email: "=cmd@test.com", Nickname: "+1+1", CreatedAt: createdAt},
+				{ID: uuid.New(), Email: "@sum@test.com", Nickname: "-2", CreatedAt: createdAt},
+				{ID: uuid.New(), Email: "ok@test.com", Nickname: "\tnick", CreatedAt: createdAt},
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "\xef\xbb\xbfEmail,Nickname\n'=cmd@test.com,'+1+1\n'@sum@test.com,'-2\nok@test.com,'\tnick\n",
+		},
+		{
+			name:           "11. JSON Keeps Formula-Like Values",
+			url:            "/users/export?format=json&columns=email,nickname",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockUsers:      []model.User{{ID: uuid.New(), Email: "=cmd@test.com", Nickname: "-2", CreatedAt: createdAt}},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json",
+			expectedBody:   `[{"email":"=cmd@test.com","nickname":"-2"}]` + "\n",
+		},
+		{
+			name:           "12. XLSX Keeps Formula-Like Values",
+			url:            "/users/export?format=xlsx&columns=email,nickname",
+			expectQuery:    &model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt},
+			mockUsers:      []model.User{{ID: uuid.New(), Email: "=cmd@test.com", Nickname: "@sum", CreatedAt: createdAt}},
+			expectedStatus: http.StatusOK,
+			expectedType:   tabular.ContentType(tabular.FormatXLSX),
+			expectedRows:   []map[string]string{{"Email": "=cmd@test.com", "Nickname": "@sum"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			if tt.expectQuery != nil {
+				mockSvc.EXPECT().
+					Export(gomock.Any(), *tt.expectQuery, gomock.Any()).
+					DoAndReturn(streamUsers(tt.mockUsers, tt.mockErr)).
+					Times(1)
+			}
+
+			h := NewUserHandler(mockSvc)
 
-				assert.Equal(t, tt.mockUsers[0].ID, resp[0].ID)
-				assert.Equal(t, tt.mockUsers[0].Email, resp[0].Email)
-				assert.Equal(t, tt.mockUsers[0].Nickname, resp[0].Nickname)
-				assert.Equal(t, tt.mockUsers[0].Role, resp[0].Role)
-				assert.NotEmpty(t, resp[0].CreatedAt)
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req = req.WithContext(middleware.ContextWithOrganization(req.Context(), orgID))
+			w := httptest.NewRecorder()
+
+			h.Export(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedType != "" {
+				assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+			}
+			if tt.expectedRows != nil {
+				table, err := tabular.ReadXLSX(w.Body)
+				if assert.NoError(t, err) && assert.Len(t, table.Rows, len(tt.expectedRows)) {
+					for i, row := range table.Rows {
+						assert.Equal(t, tt.expectedRows[i], row.Values)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUserHandler_Search(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	hit := model.UserSearchHit{
+		User: model.User{ID: uuid.New(), Email: "john@test.com", Nickname: "johnny", Roles: []string{"user"}},
+		Rank: 0.75,
+		Highlights: model.UserHighlights{
+			Nickname: []model.TextRange{{Start: 0, End: 4}},
+			Email:    []model.TextRange{{Start: 0, End: 4}},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		url            string
+		expectQuery    *model.UserSearchQuery
+		mockHits       []model.UserSearchHit
+		mockErr        error
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "1. Success",
+			url:            "/users/search?q=%20john%20&limit=5",
+			expectQuery:    &model.UserSearchQuery{OrganizationID: orgID, Text: "john", Limit: 5},
+			mockHits:       []model.UserSearchHit{hit},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"highlights":{"nickname":[[0,4]],"email":[[0,4]]}`,
+		},
+		{
+			name:           "2. Nothing Found",
+			url:            "/users/search?q=nobody",
+			expectQuery:    &model.UserSearchQuery{OrganizationID: orgID, Text: "nobody"},
+			mockHits:       []model.UserSearchHit{},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"results":[]}`,
+		},
+		{
+			name:           "3. Missing Query",
+			url:            "/users/search",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"q is required"`,
+		},
+		{
+			name:           "4. Query Too Long",
+			url:            "/users/search?q=" + strings.Repeat("я", model.MaxUserSearchLength+1),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "5. Limit Out Of Range",
+			url:            "/users/search?q=john&limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "6. Internal Server Error",
+			url:            "/users/search?q=john",
+			expectQuery:    &model.UserSearchQuery{OrganizationID: orgID, Text: "john"},
+			mockErr:        errors.New("db failure"),
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			if tt.expectQuery != nil {
+				mockSvc.EXPECT().
+					Search(gomock.Any(), *tt.expectQuery).
+					Return(tt.mockHits, tt.mockErr).
+					Times(1)
+			}
+
+			h := NewUserHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req = req.WithContext(middleware.ContextWithOrganization(req.Context(), orgID))
+			w := httptest.NewRecorder()
+
+			h.Search(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetMe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(&model.User{
+					ID:          userID,
+					Nickname:    "me",
+					Roles:       []string{"user"},
+					Preferences: model.Preferences{Theme: "dark"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"preferences":{"theme":"dark"}`,
+		},
+		{
+			name: "Deleted Account",
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.GetMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
 			}
 		})
 	}
 }
+
+func TestUserHandler_UpdateMe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"nickname":"renamed"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().UpdateProfile(gomock.Any(), userID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, update model.ProfileUpdate) (*model.User, error) {
+						assert.Equal(t, "renamed", *update.Nickname)
+						assert.Nil(t, update.Preferences)
+						return &model.User{ID: userID, Nickname: "renamed"}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Validation Error",
+			requestBody:    `{"preferences":{"theme":"neon"}}`,
+			mockBehavior:   func(_ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Nickname Taken",
+			requestBody: `{"nickname":"taken"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().UpdateProfile(gomock.Any(), userID, gomock.Any()).Return(nil, repository.ErrNicknameTaken)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPatch, "/me", strings.NewReader(tt.requestBody))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.UpdateMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserHandler_ChangeMyPassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"current_password":"old-pass","new_password":"new-pass-123"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().ChangePassword(gomock.Any(), userID, "old-pass", "new-pass-123").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing Current Password",
+			requestBody:    `{"new_password":"new-pass-123"}`,
+			mockBehavior:   func(_ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Wrong Current Password",
+			requestBody: `{"current_password":"guess","new_password":"new-pass-123"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().ChangePassword(gomock.Any(), userID, "guess", "new-pass-123").Return(service.ErrInvalidPassword)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "Too Many Wrong Passwords",
+			requestBody: `{"current_password":"guess","new_password":"new-pass-123"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().ChangePassword(gomock.Any(), userID, "guess", "new-pass-123").
+					Return(&service.LoginLockedError{RetryAfter: time.Minute})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/me/password", strings.NewReader(tt.requestBody))
+			req = req.WithContext(middleware.ContextWithUser(context.Background(), &model.User{ID: userID}))
+			w := httptest.NewRecorder()
+			h.ChangeMyPassword(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserHandler_DeleteMe(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	token := &model.TokenClaims{ID: uuid.NewString(), SessionID: uuid.New()}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name:        "Success",
+			requestBody: `{"password":"current-pass"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().CloseAccount(gomock.Any(), userID, "current-pass", *token).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Missing Password",
+			requestBody:    `{}`,
+			mockBehavior:   func(_ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "Wrong Password",
+			requestBody: `{"password":"guess"}`,
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().CloseAccount(gomock.Any(), userID, "guess", *token).Return(service.ErrInvalidPassword)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+
+			ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+			ctx = middleware.ContextWithToken(ctx, token)
+			req := httptest.NewRequest(http.MethodDelete, "/me", strings.NewReader(tt.requestBody)).WithContext(ctx)
+			w := httptest.NewRecorder()
+			h.DeleteMe(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserHandler_Unlock(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Unlock(gomock.Any(), userID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "2. Unknown User",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Unlock(gomock.Any(), userID).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Invalid ID",
+			id:             "not-a-uuid",
+			mockBehavior:   func(m *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+			w := httptest.NewRecorder()
+			h.Unlock(w, httptest.NewRequest(http.MethodPost, "/users/"+tt.id+"/unlock", nil), tt.id)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserHandler_Restore(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		id             string
+		mockBehavior   func(m *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Restore(gomock.Any(), userID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: "2. Not Deleted Or Purged",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Restore(gomock.Any(), userID).Return(service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "3. Internal Error",
+			id:   userID.String(),
+			mockBehavior: func(m *mocks.MockUserProvider) {
+				m.EXPECT().Restore(gomock.Any(), userID).Return(errors.New("db failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. Invalid ID",
+			id:             "not-a-uuid",
+			mockBehavior:   func(m *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserHandler(mockSvc)
+			w := httptest.NewRecorder()
+			h.Restore(w, httptest.NewRequest(http.MethodPost, "/users/"+tt.id+"/restore", nil), tt.id)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
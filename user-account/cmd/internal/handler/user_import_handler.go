@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/tabular"
+
+	"github.com/google/uuid"
+)
+
+type UserImportProvider interface {
+	Import(ctx context.Context, orgID uuid.UUID, rows []model.UserImportRow, dryRun bool) ([]model.UserImportResult, error)
+}
+
+// userImportColumns - обязательные колонки файла импорта, регистр не важен
+var userImportColumns = []string{"email", "nickname", "password"}
+
+// multipartOverhead - запас на заголовки multipart сверх размера файла
+const multipartOverhead = 1 << 20
+
+// importWriteTimeout - срок записи ответа импорта. Пароли всех строк
+// хэшируются до ответа, и на полном файле это дольше общего WriteTimeout
+// сервера.
+const importWriteTimeout = 10 * time.Minute
+
+// UserImportHandler - массовое создание пользователей из CSV или XLSX
+type UserImportHandler struct {
+	baseHandler
+	importService UserImportProvider
+}
+
+func NewUserImportHandler(importService UserImportProvider) *UserImportHandler {
+	return &UserImportHandler{importService: importService}
+}
+
+// Import - POST /users/import, multipart-форма с файлом в поле file.
+// Колонки email, nickname и password проверяются по тем же правилам, что
+// и при регистрации. С ?dry_run=true ничего не создаётся, отчёт показывает,
+// что было бы сделано. Созданные пользователи становятся участниками
+// активной организации вызывающего.
+func (h *UserImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			h.writeError(w, "dry_run must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, model.MaxUserImportSize+multipartOverhead)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.writeError(w, fmt.Sprintf("file must be at most %d MB", model.MaxUserImportSize>>20), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.writeError(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if header.Size > model.MaxUserImportSize {
+		h.writeError(w, fmt.Sprintf("file must be at most %d MB", model.MaxUserImportSize>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	format, err := tabular.FormatFromName(header.Filename)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	table, err := tabular.Read(format, file)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, invalid, err := parseUserImportTable(table)
+	if err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(importWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("user import: failed to extend write deadline: %v", err)
+	}
+
+	results, err := h.importService.Import(r.Context(), middleware.OrganizationFromContext(r.Context()), rows, dryRun)
+	if err != nil {
+		h.writeError(w, "failed to import users", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, model.NewUserImportReport(append(results, invalid...), dryRun))
+}
+
+// parseUserImportTable проверяет строки по правилам RegisterRequest.
+// Возвращает строки для импорта и итоги по строкам, не прошедшим проверку.
+func parseUserImportTable(table *tabular.Table) ([]model.UserImportRow, []model.UserImportResult, error) {
+	columns := make(map[string]string, len(table.Columns))
+	for _, column := range table.Columns {
+		columns[strings.ToLower(column)] = column
+	}
+	for _, name := range userImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("missing column: %s", name)
+		}
+	}
+	if len(table.Rows) == 0 {
+		return nil, nil, errors.New("file has no rows")
+	}
+	if len(table.Rows) > model.MaxUserImportRows {
+		return nil, nil, fmt.Errorf("file must have at most %d rows", model.MaxUserImportRows)
+	}
+
+	var rows []model.UserImportRow
+	var invalid []model.UserImportResult
+	for _, row := range table.Rows {
+		req := RegisterRequest{
+			Email:    row.Get(columns["email"]),
+			Nickname: row.Get(columns["nickname"]),
+			// Пробелы по краям пароля значимы
+			Password: row.Values[columns["password"]],
+		}
+		if err := req.Validate(); err != nil {
+			invalid = append(invalid, model.UserImportResult{
+				Line:   row.Line,
+				Email:  req.Email,
+				Status: model.UserImportFailed,
+				Error:  err.Error(),
+			})
+			continue
+		}
+		rows = append(rows, model.UserImportRow{
+			Line:     row.Line,
+			Email:    req.Email,
+			Nickname: req.Nickname,
+			Password: req.Password,
+		})
+	}
+	return rows, invalid, nil
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImportHandler_Import(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	validRow := model.UserImportRow{Line: 2, Email: "ann@test.com", Nickname: "ann_k", Password: "secret-pass"}
+
+	tests := []struct {
+		name           string
+		query          string
+		filename       string
+		content        string
+		mockBehavior   func(m *mocks.MockUserImportProvider)
+		expectedStatus int
+		expectedReport *model.UserImportReport
+	}{
+		{
+			name:     "1. Valid And Invalid Rows",
+			filename: "staff.csv",
+			content:  "Email;Nickname;Password\nann@test.com;ann_k;secret-pass\nnot-an-email;bob;secret\n",
+			mockBehavior: func(m *mocks.MockUserImportProvider) {
+				m.EXPECT().Import(gomock.Any(), orgID, []model.UserImportRow{validRow}, false).
+					Return([]model.UserImportResult{{Line: 2, Email: "ann@test.com", Status: model.UserImportCreated}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: &model.UserImportReport{
+				Total:   2,
+				Created: 1,
+				Failed:  1,
+				Rows: []model.UserImportResult{
+					{Line: 2, Email: "ann@test.com", Status: model.UserImportCreated},
+					{Line: 3, Email: "not-an-email", Status: model.UserImportFailed, Error: "invalid email format"},
+				},
+			},
+		},
+		{
+			name:     "2. Dry Run",
+			query:    "?dry_run=true",
+			filename: "staff.csv",
+			content:  "email,nickname,password\nann@test.com,ann_k,secret-pass\n",
+			mockBehavior: func(m *mocks.MockUserImportProvider) {
+				m.EXPECT().Import(gomock.Any(), orgID, []model.UserImportRow{validRow}, true).
+					Return([]model.UserImportResult{{Line: 2, Email: "ann@test.com", Status: model.UserImportCreated}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedReport: &model.UserImportReport{
+				DryRun:  true,
+				Total:   1,
+				Created: 1,
+				Rows:    []model.UserImportResult{{Line: 2, Email: "ann@test.com", Status: model.UserImportCreated}},
+			},
+		},
+		{
+			name:           "3. Missing Column",
+			filename:       "staff.csv",
+			content:        "email,nickname\nann@test.com,ann_k\n",
+			mockBehavior:   func(_ *mocks.MockUserImportProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Unsupported Format",
+			filename:       "staff.xls",
+			content:        "email,nickname,password\n",
+			mockBehavior:   func(_ *mocks.MockUserImportProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "5. Broken XLSX",
+			filename:       "staff.xlsx",
+			content:        "not a workbook",
+			mockBehavior:   func(_ *mocks.MockUserImportProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "6. Invalid dry_run",
+			query:          "?dry_run=maybe",
+			filename:       "staff.csv",
+			content:        "email,nickname,password\nann@test.com,ann_k,secret-pass\n",
+			mockBehavior:   func(_ *mocks.MockUserImportProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "7. Internal Error",
+			filename: "staff.csv",
+			content:  "email,nickname,password\nann@test.com,ann_k,secret-pass\n",
+			mockBehavior: func(m *mocks.MockUserImportProvider) {
+				m.EXPECT().Import(gomock.Any(), orgID, gomock.Any(), false).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserImportProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("file", tt.filename)
+			require.NoError(t, err)
+			_, _ = part.Write([]byte(tt.content))
+			require.NoError(t, form.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/users/import"+tt.query, &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			req = req.WithContext(middleware.ContextWithOrganization(req.Context(), orgID))
+			w := httptest.NewRecorder()
+
+			NewUserImportHandler(mockSvc).Import(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedReport != nil {
+				var report model.UserImportReport
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+				assert.Equal(t, *tt.expectedReport, report)
+			}
+		})
+	}
+}
+
+func TestUserImportHandler_Import_NoFile(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/users/import", nil)
+	w := httptest.NewRecorder()
+	NewUserImportHandler(mocks.NewMockUserImportProvider(ctrl)).Import(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/google/uuid"
+)
+
+type UserStatusProvider interface {
+	Suspend(ctx context.Context, actorID, userID uuid.UUID, status, reason string, expiresAt *time.Time) (*model.UserStatusChange, error)
+	Reinstate(ctx context.Context, actorID, userID uuid.UUID, reason string) (*model.UserStatusChange, error)
+	History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error)
+}
+
+// UserStatusHandler - приостановка и возврат доступа пользователям
+type UserStatusHandler struct {
+	baseHandler
+	statusService UserStatusProvider
+}
+
+func NewUserStatusHandler(statusService UserStatusProvider) *UserStatusHandler {
+	return &UserStatusHandler{statusService: statusService}
+}
+
+// Suspend - POST /users/{id}/suspend, закрывает доступ и отзывает сессии.
+// Уже выданные токены перестают приниматься.
+func (h *UserStatusHandler) Suspend(w http.ResponseWriter, r *http.Request, idStr string) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req SuspendUserRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.statusService.Suspend(r.Context(), current.ID, id, req.Status, req.Reason, req.ExpiresAt)
+	if err != nil {
+		h.writeStatusError(w, err, "failed to suspend user")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, change)
+}
+
+// Reinstate - POST /users/{id}/reinstate, возвращает доступ
+func (h *UserStatusHandler) Reinstate(w http.ResponseWriter, r *http.Request, idStr string) {
+	current := middleware.UserFromContext(r.Context())
+	if current == nil {
+		h.writeError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	var req ReinstateUserRequest
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err = req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	change, err := h.statusService.Reinstate(r.Context(), current.ID, id, req.Reason)
+	if err != nil {
+		h.writeStatusError(w, err, "failed to reinstate user")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, change)
+}
+
+// History - GET /users/{id}/status-history, смены статуса, новые первыми
+func (h *UserStatusHandler) History(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		h.writeError(w, "invalid user ID format", http.StatusBadRequest)
+		return
+	}
+
+	history, err := h.statusService.History(r.Context(), id)
+	if err != nil {
+		h.writeStatusError(w, err, "failed to fetch status history")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, history)
+}
+
+func (h *UserStatusHandler) writeStatusError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		h.writeError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidUserStatus),
+		errors.Is(err, service.ErrStatusReasonRequired),
+		errors.Is(err, service.ErrInvalidStatusExpiry):
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, service.ErrCannotSuspendSelf):
+		h.writeError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, service.ErrUserNotSuspended):
+		h.writeError(w, err.Error(), http.StatusConflict)
+	default:
+		h.writeError(w, fallback, http.StatusInternalServerError)
+	}
+}
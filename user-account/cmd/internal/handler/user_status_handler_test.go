@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatusHandler_Suspend(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		id             string
+		body           string
+		mockBehavior   func(m *mocks.MockUserStatusProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success With Default Status",
+			id:   userID.String(),
+			body: `{"reason":"left the company","expires_at":"2030-01-01T00:00:00Z"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Suspend(gomock.Any(), adminID, userID, model.UserStatusSuspended, "left the company", gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ uuid.UUID, status, reason string, at *time.Time) (*model.UserStatusChange, error) {
+						assert.True(t, expiresAt.Equal(*at))
+						return &model.UserStatusChange{UserID: userID, Status: status, Reason: reason, ExpiresAt: at}, nil
+					})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "2. Reason Required",
+			id:             userID.String(),
+			body:           `{"status":"locked"}`,
+			mockBehavior:   func(_ *mocks.MockUserStatusProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "3. Unknown Status",
+			id:             userID.String(),
+			body:           `{"status":"banned","reason":"spam"}`,
+			mockBehavior:   func(_ *mocks.MockUserStatusProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "4. Cannot Suspend Self",
+			id:   userID.String(),
+			body: `{"reason":"test"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Suspend(gomock.Any(), adminID, userID, model.UserStatusSuspended, "test", nil).
+					Return(nil, service.ErrCannotSuspendSelf)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "5. User Not Found",
+			id:   userID.String(),
+			body: `{"status":"locked","reason":"fraud"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Suspend(gomock.Any(), adminID, userID, model.UserStatusLocked, "fraud", nil).
+					Return(nil, service.ErrUserNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "6. Internal Error",
+			id:   userID.String(),
+			body: `{"reason":"test"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Suspend(gomock.Any(), adminID, userID, model.UserStatusSuspended, "test", nil).
+					Return(nil, errors.New("db failure"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "7. Invalid ID",
+			id:             "not-a-uuid",
+			body:           `{"reason":"test"}`,
+			mockBehavior:   func(_ *mocks.MockUserStatusProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserStatusProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserStatusHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodPost, "/users/"+tt.id+"/suspend", strings.NewReader(tt.body))
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: adminID}))
+			w := httptest.NewRecorder()
+			h.Suspend(w, req, tt.id)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserStatusHandler_Reinstate(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockUserStatusProvider)
+		expectedStatus int
+	}{
+		{
+			name: "1. Success",
+			body: `{"reason":"appeal accepted"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Reinstate(gomock.Any(), adminID, userID, "appeal accepted").
+					Return(&model.UserStatusChange{UserID: userID, Status: model.UserStatusActive}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "2. Reason Required",
+			body:           `{}`,
+			mockBehavior:   func(_ *mocks.MockUserStatusProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "3. User Is Not Suspended",
+			body: `{"reason":"again"}`,
+			mockBehavior: func(m *mocks.MockUserStatusProvider) {
+				m.EXPECT().Reinstate(gomock.Any(), adminID, userID, "again").Return(nil, service.ErrUserNotSuspended)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockUserStatusProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewUserStatusHandler(mockSvc)
+			req := httptest.NewRequest(http.MethodPost, "/users/"+userID.String()+"/reinstate", strings.NewReader(tt.body))
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), &model.User{ID: adminID}))
+			w := httptest.NewRecorder()
+			h.Reinstate(w, req, userID.String())
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestUserStatusHandler_History(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	mockSvc := mocks.NewMockUserStatusProvider(ctrl)
+	mockSvc.EXPECT().History(gomock.Any(), userID).Return([]model.UserStatusChange{
+		{UserID: userID, Status: model.UserStatusActive, Reason: "appeal accepted"},
+		{UserID: userID, Status: model.UserStatusSuspended, Reason: "investigation"},
+	}, nil)
+
+	h := NewUserStatusHandler(mockSvc)
+	w := httptest.NewRecorder()
+	h.History(w, httptest.NewRequest(http.MethodGet, "/users/"+userID.String()+"/status-history", nil), userID.String())
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var history []model.UserStatusChange
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&history))
+	assert.Len(t, history, 2)
+	assert.Equal(t, model.UserStatusSuspended, history[1].Status)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"user-account/cmd/internal/service"
+)
+
+type VerificationProvider interface {
+	Verify(ctx context.Context, token string) error
+	Resend(ctx context.Context, email string) error
+}
+
+type VerificationHandler struct {
+	baseHandler
+	verificationService VerificationProvider
+}
+
+func NewVerificationHandler(verificationService VerificationProvider) *VerificationHandler {
+	return &VerificationHandler{verificationService: verificationService}
+}
+
+// Verify - POST /email/verify, подтверждение почты токеном из письма
+func (h *VerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verificationService.Verify(r.Context(), req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidVerificationToken) {
+			h.writeError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.writeError(w, "failed to verify email", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{
+		"status":  "ok",
+		"message": "Email verified",
+	})
+}
+
+// Resend - POST /email/verify/resend. Отвечает одинаково для любых
+// адресов, чтобы нельзя было узнать, зарегистрирован ли адрес.
+func (h *VerificationHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	var req ResendVerificationRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		h.writeError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verificationService.Resend(r.Context(), req.Email); err != nil {
+		h.writeError(w, "failed to send verification email", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "ok",
+		"message": "If the address is registered and not yet verified, a new link has been sent",
+	})
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/service"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationHandler_Verify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		body           string
+		mockBehavior   func(m *mocks.MockVerificationProvider)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "1. Success",
+			body: `{"token":"valid"}`,
+			mockBehavior: func(m *mocks.MockVerificationProvider) {
+				m.EXPECT().Verify(gomock.Any(), "valid").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"message":"Email verified"`,
+		},
+		{
+			name: "2. Invalid Token",
+			body: `{"token":"forged"}`,
+			mockBehavior: func(m *mocks.MockVerificationProvider) {
+				m.EXPECT().Verify(gomock.Any(), "forged").Return(service.ErrInvalidVerificationToken)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"invalid or expired verification link"`,
+		},
+		{
+			name: "3. Internal Error",
+			body: `{"token":"valid"}`,
+			mockBehavior: func(m *mocks.MockVerificationProvider) {
+				m.EXPECT().Verify(gomock.Any(), "valid").Return(errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. Missing Token",
+			body:           `{}`,
+			mockBehavior:   func(m *mocks.MockVerificationProvider) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `"error":"token is required"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSvc := mocks.NewMockVerificationProvider(ctrl)
+			tt.mockBehavior(mockSvc)
+
+			h := NewVerificationHandler(mockSvc)
+
+			req := httptest.NewRequest(http.MethodPost, "/email/verify", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			h.Verify(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestVerificationHandler_Resend(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	mockSvc := mocks.NewMockVerificationProvider(ctrl)
+	mockSvc.EXPECT().Resend(gomock.Any(), "unknown@example.com").Return(nil)
+
+	h := NewVerificationHandler(mockSvc)
+
+	req := httptest.NewRequest(http.MethodPost, "/email/verify/resend", bytes.NewBufferString(`{"email":"unknown@example.com"}`))
+	w := httptest.NewRecorder()
+	h.Resend(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
@@ -0,0 +1,119 @@
+package keyring
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// JWK - публичный ключ в формате RFC 7517
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet - набор публичных ключей для /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает публичные ключи, которыми можно проверить выданные токены:
+// активный и выведенные, но ещё не покинувшие период перекрытия.
+// Для HS256 набор пуст - общий секрет публиковать нельзя.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if k.alg == AlgHS256 {
+		return set
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	var active []*key
+	for _, found := range k.keys {
+		if !k.expired(found, now) {
+			active = append(active, found)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].createdAt.After(active[j].createdAt)
+	})
+
+	for _, found := range active {
+		jwk, err := publicJWK(found.signer.Public())
+		if err != nil {
+			continue
+		}
+		jwk.Kid = found.id
+		jwk.Use = "sig"
+		jwk.Alg = k.alg
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func publicJWK(public crypto.PublicKey) (JWK, error) {
+	switch pub := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, nil
+	default:
+		return JWK{}, errors.New("unsupported public key type")
+	}
+}
+
+// thumbprint вычисляет идентификатор ключа по RFC 7638
+func thumbprint(public crypto.PublicKey) (string, error) {
+	jwk, err := publicJWK(public)
+	if err != nil {
+		return "", err
+	}
+
+	// Обязательные члены в лексикографическом порядке, без пробелов
+	var members interface{}
+	switch jwk.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	default:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	}
+
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,413 @@
+package keyring
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	rsaKeyBits            = 2048
+	rotationCheckInterval = time.Minute
+	reloadCooldown        = 10 * time.Second
+	hmacKeyID             = "hmac"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// Options - параметры связки ключей
+type Options struct {
+	// Algorithm - алгоритм подписи: HS256, RS256 или EdDSA
+	Algorithm string
+	// Secret - общий секрет для HS256
+	Secret string
+	// Dir - каталог с приватными ключами в PEM (PKCS#8). Пустой каталог
+	// означает ключи только в памяти, которые теряются при перезапуске.
+	Dir string
+	// Overlap - сколько выведенный из оборота ключ ещё принимается при
+	// проверке; должен быть не меньше времени жизни access-токена
+	Overlap time.Duration
+}
+
+// key - один ключ подписи
+type key struct {
+	id        string
+	signer    crypto.Signer
+	secret    []byte
+	createdAt time.Time
+	retiredAt *time.Time
+}
+
+func (k *key) signingKey() interface{} {
+	if k.secret != nil {
+		return k.secret
+	}
+	return k.signer
+}
+
+func (k *key) verificationKey() interface{} {
+	if k.secret != nil {
+		return k.secret
+	}
+	return k.signer.Public()
+}
+
+// Keyring - связка ключей подписи JWT. Новые токены подписываются активным
+// ключом, а проверка принимает и недавно выведенные ключи, чтобы ротация
+// не разлогинивала пользователей.
+type Keyring struct {
+	alg     string
+	method  jwt.SigningMethod
+	dir     string
+	overlap time.Duration
+
+	mu         sync.RWMutex
+	active     *key
+	keys       map[string]*key
+	lastReload time.Time
+}
+
+// New создаёт связку ключей. Для асимметричных алгоритмов ключи читаются
+// из каталога; если ключей нет, генерируется новый.
+func New(opts Options) (*Keyring, error) {
+	method := jwt.GetSigningMethod(opts.Algorithm)
+	if method == nil || !isSupported(opts.Algorithm) {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", opts.Algorithm)
+	}
+
+	k := &Keyring{
+		alg:     opts.Algorithm,
+		method:  method,
+		dir:     opts.Dir,
+		overlap: opts.Overlap,
+		keys:    make(map[string]*key),
+	}
+
+	if opts.Algorithm == AlgHS256 {
+		if opts.Secret == "" {
+			return nil, errors.New("HS256 requires a secret")
+		}
+		k.active = &key{id: hmacKeyID, secret: []byte(opts.Secret), createdAt: time.Now()}
+		k.keys[hmacKeyID] = k.active
+		return k, nil
+	}
+
+	if k.dir != "" {
+		if err := os.MkdirAll(k.dir, 0o700); err != nil {
+			return nil, err
+		}
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+	}
+
+	if k.active == nil {
+		if err := k.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+// Algorithm возвращает алгоритм подписи
+func (k *Keyring) Algorithm() string {
+	return k.alg
+}
+
+// Sign подписывает claims активным ключом и проставляет kid в заголовок
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	active := k.active
+	k.mu.RUnlock()
+
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = active.id
+	return token.SignedString(active.signingKey())
+}
+
+// Keyfunc - функция поиска ключа для jwt.Parse. Принимает только алгоритм
+// связки и ключи, которые ещё не вышли из периода перекрытия.
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.alg {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && k.alg == AlgHS256 {
+		// Токены, выданные до появления kid
+		kid = hmacKeyID
+	}
+
+	if found := k.lookup(kid); found != nil {
+		return found.verificationKey(), nil
+	}
+
+	// Ключ мог выпустить другой экземпляр сервиса - перечитываем каталог,
+	// но не чаще раза в reloadCooldown, чтобы случайные kid не нагружали диск
+	if k.dir != "" && k.reloadAllowed() {
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+		if found := k.lookup(kid); found != nil {
+			return found.verificationKey(), nil
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (k *Keyring) reloadAllowed() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if time.Since(k.lastReload) < reloadCooldown {
+		return false
+	}
+	k.lastReload = time.Now()
+	return true
+}
+
+func (k *Keyring) lookup(kid string) *key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	found, ok := k.keys[kid]
+	if !ok || k.expired(found, time.Now()) {
+		return nil
+	}
+	return found
+}
+
+func (k *Keyring) expired(found *key, now time.Time) bool {
+	return found.retiredAt != nil && now.After(found.retiredAt.Add(k.overlap))
+}
+
+// Rotate генерирует новый активный ключ. Предыдущий ключ остаётся
+// пригодным для проверки ещё на время перекрытия.
+func (k *Keyring) Rotate() error {
+	if k.alg == AlgHS256 {
+		return errors.New("HS256 keys cannot be rotated automatically")
+	}
+
+	signer, err := generate(k.alg)
+	if err != nil {
+		return err
+	}
+
+	id, err := thumbprint(signer.Public())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if k.dir != "" {
+		if err = writeKey(filepath.Join(k.dir, id+".pem"), signer); err != nil {
+			return err
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.active != nil {
+		k.active.retiredAt = &now
+	}
+	k.active = &key{id: id, signer: signer, createdAt: now}
+	k.keys[id] = k.active
+	k.prune(now)
+	return nil
+}
+
+// RunRotation ротирует ключи раз в interval, пока не отменён ctx.
+// Несколько экземпляров сервиса с общим каталогом подхватывают ключи друг
+// друга и не ротируют, если свежий ключ уже появился.
+func (k *Keyring) RunRotation(ctx context.Context, interval time.Duration) {
+	check := rotationCheckInterval
+	if interval < check {
+		check = interval
+	}
+
+	ticker := time.NewTicker(check)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.rotateIfDue(interval); err != nil {
+				log.Printf("jwt key rotation failed: %v", err)
+			}
+		}
+	}
+}
+
+func (k *Keyring) rotateIfDue(interval time.Duration) error {
+	if k.dir != "" {
+		if err := k.reload(); err != nil {
+			return err
+		}
+	}
+
+	k.mu.RLock()
+	due := time.Since(k.active.createdAt) >= interval
+	k.mu.RUnlock()
+
+	if !due {
+		return nil
+	}
+	return k.Rotate()
+}
+
+// prune забывает ключи, период перекрытия которых истёк, и удаляет их файлы
+func (k *Keyring) prune(now time.Time) {
+	for id, found := range k.keys {
+		if !k.expired(found, now) {
+			continue
+		}
+		delete(k.keys, id)
+		if k.dir != "" {
+			_ = os.Remove(filepath.Join(k.dir, id+".pem"))
+		}
+	}
+}
+
+// reload перечитывает ключи из каталога. Самый новый ключ становится
+// активным, каждый предыдущий считается выведенным в момент появления
+// следующего.
+func (k *Keyring) reload() error {
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return err
+	}
+
+	var loaded []*key
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".pem") {
+			continue
+		}
+
+		path := filepath.Join(k.dir, entry.Name())
+		signer, err := readKey(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if !matchesAlgorithm(k.alg, signer) {
+			return fmt.Errorf("%s: key type does not match %s", entry.Name(), k.alg)
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		id, err := thumbprint(signer.Public())
+		if err != nil {
+			return err
+		}
+		loaded = append(loaded, &key{id: id, signer: signer, createdAt: info.ModTime()})
+	}
+
+	if len(loaded) == 0 {
+		return nil
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].createdAt.Before(loaded[j].createdAt)
+	})
+	for i := 0; i < len(loaded)-1; i++ {
+		retiredAt := loaded[i+1].createdAt
+		loaded[i].retiredAt = &retiredAt
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = make(map[string]*key, len(loaded))
+	for _, found := range loaded {
+		k.keys[found.id] = found
+	}
+	k.active = loaded[len(loaded)-1]
+	k.prune(time.Now())
+	return nil
+}
+
+func isSupported(alg string) bool {
+	return alg == AlgHS256 || alg == AlgRS256 || alg == AlgEdDSA
+}
+
+func matchesAlgorithm(alg string, signer crypto.Signer) bool {
+	switch signer.(type) {
+	case *rsa.PrivateKey:
+		return alg == AlgRS256
+	case ed25519.PrivateKey:
+		return alg == AlgEdDSA
+	default:
+		return false
+	}
+}
+
+func generate(alg string) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	default:
+		return nil, fmt.Errorf("cannot generate keys for %s", alg)
+	}
+}
+
+func readKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
+
+func writeKey(path string, signer crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,156 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, k *Keyring, token string) error {
+	t.Helper()
+	_, err := jwt.Parse(token, k.Keyfunc)
+	return err
+}
+
+func TestKeyring_SignAndVerify(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts Options
+		kty  string
+	}{
+		{name: "HS256", opts: Options{Algorithm: AlgHS256, Secret: "secret"}},
+		{name: "RS256", opts: Options{Algorithm: AlgRS256, Overlap: time.Minute}, kty: "RSA"},
+		{name: "EdDSA", opts: Options{Algorithm: AlgEdDSA, Overlap: time.Minute}, kty: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, err := New(tt.opts)
+			require.NoError(t, err)
+
+			token, err := k.Sign(jwt.MapClaims{"sub": "user"})
+			require.NoError(t, err)
+			assert.NoError(t, parse(t, k, token))
+
+			jwks := k.JWKS()
+			if tt.kty == "" {
+				assert.Empty(t, jwks.Keys, "symmetric secret must never be published")
+				return
+			}
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tt.kty, jwks.Keys[0].Kty)
+			assert.Equal(t, tt.opts.Algorithm, jwks.Keys[0].Alg)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func TestKeyring_RejectsForeignTokens(t *testing.T) {
+	t.Parallel()
+
+	k, err := New(Options{Algorithm: AlgEdDSA, Overlap: time.Minute})
+	require.NoError(t, err)
+	other, err := New(Options{Algorithm: AlgEdDSA, Overlap: time.Minute})
+	require.NoError(t, err)
+
+	token, err := other.Sign(jwt.MapClaims{"sub": "user"})
+	require.NoError(t, err)
+	assert.Error(t, parse(t, k, token), "unknown kid")
+
+	// Подмена алгоритма на HMAC с публичным ключом в роли секрета
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user"})
+	hmacToken.Header["kid"] = k.JWKS().Keys[0].Kid
+	signed, err := hmacToken.SignedString([]byte(k.JWKS().Keys[0].X))
+	require.NoError(t, err)
+	assert.Error(t, parse(t, k, signed), "algorithm confusion")
+}
+
+func TestKeyring_RotationOverlap(t *testing.T) {
+	t.Parallel()
+
+	k, err := New(Options{Algorithm: AlgEdDSA, Overlap: time.Hour})
+	require.NoError(t, err)
+
+	oldToken, err := k.Sign(jwt.MapClaims{"sub": "user"})
+	require.NoError(t, err)
+
+	require.NoError(t, k.Rotate())
+
+	newToken, err := k.Sign(jwt.MapClaims{"sub": "user"})
+	require.NoError(t, err)
+
+	assert.NoError(t, parse(t, k, oldToken), "retired key is still accepted during overlap")
+	assert.NoError(t, parse(t, k, newToken))
+	assert.Len(t, k.JWKS().Keys, 2)
+
+	// Период перекрытия истёк - старый ключ больше не принимается
+	k.mu.Lock()
+	for _, found := range k.keys {
+		if found.retiredAt != nil {
+			expired := time.Now().Add(-2 * time.Hour)
+			found.retiredAt = &expired
+		}
+	}
+	k.mu.Unlock()
+
+	assert.Error(t, parse(t, k, oldToken))
+	assert.Len(t, k.JWKS().Keys, 1)
+}
+
+func TestKeyring_SharedDirectory(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	first, err := New(Options{Algorithm: AlgEdDSA, Dir: dir, Overlap: time.Hour})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// Второй экземпляр подхватывает тот же ключ, а не генерирует свой
+	second, err := New(Options{Algorithm: AlgEdDSA, Dir: dir, Overlap: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, first.JWKS().Keys[0].Kid, second.JWKS().Keys[0].Kid)
+
+	// Ротация на первом экземпляре видна второму при проверке нового kid
+	require.NoError(t, first.Rotate())
+	token, err := first.Sign(jwt.MapClaims{"sub": "user"})
+	require.NoError(t, err)
+	assert.NoError(t, parse(t, second, token))
+}
+
+func TestKeyring_RejectsMismatchedKeyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := New(Options{Algorithm: AlgEdDSA, Dir: dir, Overlap: time.Hour})
+	require.NoError(t, err)
+
+	_, err = New(Options{Algorithm: AlgRS256, Dir: dir, Overlap: time.Hour})
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0o600))
+	_, err = New(Options{Algorithm: AlgEdDSA, Dir: dir, Overlap: time.Hour})
+	assert.Error(t, err)
+}
+
+func TestNew_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Options{Algorithm: "none"})
+	assert.Error(t, err)
+
+	_, err = New(Options{Algorithm: AlgHS256})
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// Message - одно письмо
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogMailer пишет письма в лог вместо отправки. Нужен для локальной
+// разработки, когда SMTP-сервера нет.
+type LogMailer struct {
+	logger *log.Logger
+}
+
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogMailer{logger: logger}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig - параметры SMTP-сервера
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer отправляет письма через SMTP. Если сервер поддерживает
+// STARTTLS, соединение шифруется; авторизация включается, только когда
+// задан Username.
+type SMTPMailer struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPMailer(cfg SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(m.cfg.Host, m.cfg.Port)
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, m.build(msg))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("smtp send: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *SMTPMailer) build(msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + m.cfg.From + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
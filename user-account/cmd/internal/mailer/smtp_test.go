@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP - минимальный SMTP-сервер, который принимает одно письмо
+type fakeSMTP struct {
+	listener net.Listener
+
+	mu   sync.Mutex
+	from string
+	to   []string
+	data string
+	auth string
+}
+
+func newFakeSMTP(t *testing.T) *fakeSMTP {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	s := &fakeSMTP{listener: l}
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTP) port() string {
+	_, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	return port
+}
+
+func (s *fakeSMTP) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP fake")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case strings.HasPrefix(cmd, "AUTH"):
+			s.mu.Lock()
+			s.auth = line
+			s.mu.Unlock()
+			reply("235 OK")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from = line[len("MAIL FROM:"):]
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.mu.Lock()
+			s.to = append(s.to, line[len("RCPT TO:"):])
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 go ahead")
+			var body strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				body.WriteString(l)
+			}
+			s.mu.Lock()
+			s.data = body.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	server := newFakeSMTP(t)
+	m := NewSMTPMailer(SMTPConfig{
+		Host:     "localhost",
+		Port:     server.port(),
+		Username: "mailer",
+		Password: "secret",
+		From:     "noreply@actium.local",
+	})
+
+	err := m.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Подтвердите почту",
+		Body:    "line one\nline two",
+	})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Contains(t, server.from, "noreply@actium.local")
+	assert.Equal(t, []string{"<user@example.com>"}, server.to)
+	assert.NotEmpty(t, server.auth)
+	assert.Contains(t, server.data, "To: user@example.com\r\n")
+	assert.Contains(t, server.data, "Subject: =?utf-8?q?")
+	assert.Contains(t, server.data, "line one\r\nline two")
+}
+
+func TestSMTPMailer_RejectsHeaderInjection(t *testing.T) {
+	t.Parallel()
+
+	m := NewSMTPMailer(SMTPConfig{Host: "localhost", Port: "1", From: "noreply@actium.local"})
+	err := m.Send(context.Background(), Message{To: "user@example.com\r\nBcc: victim@example.com"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"user-account/cmd/internal/model"
+)
+
+const apiKeyCtxKey = contextKey("api_key")
+
+// APIKeyAuthenticator проверяет API-ключ. nil без ошибки - ключ
+// неизвестен, отозван или истёк.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*model.APIKey, error)
+}
+
+// APIKeyOrJWT принимает API-ключ из заголовка X-API-Key, а без него
+// передаёт запрос в jwtAuth. Владелец ключа попадает в контекст так же,
+// как пользователь из токена, а сам ключ - в APIKeyFromContext, чтобы
+// RequirePermission ограничил его правами из Scopes. Ключи владельца,
+// доступ которому закрыт, не принимаются.
+func APIKeyOrJWT(keys APIKeyAuthenticator, statuses StatusChecker, jwtAuth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		withJWT := jwtAuth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			plain := r.Header.Get(APIKeyHeader)
+			if plain == "" {
+				withJWT.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := keys.Authenticate(r.Context(), plain)
+			if err != nil {
+				jsonError(w, "Failed to verify API key", http.StatusInternalServerError)
+				return
+			}
+			if key == nil {
+				jsonError(w, "Invalid or expired API key", http.StatusUnauthorized)
+				return
+			}
+			if !checkStatus(w, r, statuses, key.UserID) {
+				return
+			}
+
+			ctx := ContextWithUser(r.Context(), &model.User{ID: key.UserID})
+			ctx = ContextWithAPIKey(ctx, key)
+			if key.OrganizationID != nil {
+				ctx = ContextWithOrganization(ctx, *key.OrganizationID)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ContextWithAPIKey кладёт в контекст API-ключ, которым подписан запрос
+func ContextWithAPIKey(ctx context.Context, key *model.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyCtxKey, key)
+}
+
+// APIKeyFromContext возвращает API-ключ запроса или nil, если запрос
+// пришёл с access-токеном
+func APIKeyFromContext(ctx context.Context) *model.APIKey {
+	k, ok := ctx.Value(apiKeyCtxKey).(*model.APIKey)
+	if !ok {
+		return nil
+	}
+	return k
+}
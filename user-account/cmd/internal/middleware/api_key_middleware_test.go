@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyOrJWT(t *testing.T) {
+	t.Parallel()
+
+	key := &model.APIKey{ID: uuid.New(), UserID: uuid.New(), Scopes: []string{"users:read"}}
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		mockBehavior   func(m *mocks.MockAPIKeyAuthenticator)
+		blocked        bool
+		expectedStatus int
+		expectJWT      bool
+		expectKey      bool
+	}{
+		{
+			name:   "1. Valid Key",
+			apiKey: "ak_valid",
+			mockBehavior: func(m *mocks.MockAPIKeyAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "ak_valid").Return(key, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectKey:      true,
+		},
+		{
+			name:   "2. Unknown Or Expired Key",
+			apiKey: "ak_unknown",
+			mockBehavior: func(m *mocks.MockAPIKeyAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "ak_unknown").Return(nil, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "3. Authenticator Error",
+			apiKey: "ak_valid",
+			mockBehavior: func(m *mocks.MockAPIKeyAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "ak_valid").Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. No Key Falls Back To JWT",
+			mockBehavior:   func(_ *mocks.MockAPIKeyAuthenticator) {},
+			expectedStatus: http.StatusOK,
+			expectJWT:      true,
+		},
+		{
+			name:   "5. Owner Suspended",
+			apiKey: "ak_valid",
+			mockBehavior: func(m *mocks.MockAPIKeyAuthenticator) {
+				m.EXPECT().Authenticate(gomock.Any(), "ak_valid").Return(key, nil)
+			},
+			blocked:        true,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			authenticator := mocks.NewMockAPIKeyAuthenticator(ctrl)
+			tt.mockBehavior(authenticator)
+			statuses := mocks.NewMockStatusChecker(ctrl)
+			statuses.EXPECT().IsBlocked(gomock.Any(), key.UserID).Return(tt.blocked, nil).AnyTimes()
+
+			var viaJWT bool
+			jwtAuth := func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					viaJWT = true
+					next.ServeHTTP(w, r)
+				})
+			}
+
+			var gotUser *model.User
+			var gotKey *model.APIKey
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser = UserFromContext(r.Context())
+				gotKey = APIKeyFromContext(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(APIKeyHeader, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+
+			APIKeyOrJWT(authenticator, statuses, jwtAuth)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectJWT, viaJWT)
+			if tt.expectKey {
+				assert.Equal(t, key, gotKey)
+				assert.Equal(t, key.UserID, gotUser.ID)
+			} else {
+				assert.Nil(t, gotKey)
+			}
+		})
+	}
+}
@@ -2,7 +2,6 @@ package middleware
 
 import (
 	"context"
-	"errors"
 	"net/http"
 	"strings"
 	"user-account/cmd/internal/model"
@@ -13,7 +12,21 @@ import (
 
 type contextKey string
 
-const userCtxKey = contextKey("user")
+const (
+	userCtxKey  = contextKey("user")
+	tokenCtxKey = contextKey("token")
+)
+
+// RevocationChecker проверяет, не отозван ли токен или его сессия
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, tokenID string, sessionID uuid.UUID) (bool, error)
+}
+
+// StatusChecker проверяет, не закрыт ли пользователю доступ: приостановлен,
+// заблокирован или удалён
+type StatusChecker interface {
+	IsBlocked(ctx context.Context, userID uuid.UUID) (bool, error)
+}
 
 // JSONError отправляет структурированную ошибку (удобно для фронтенда)
 func jsonError(w http.ResponseWriter, message string, code int) {
@@ -23,8 +36,9 @@ func jsonError(w http.ResponseWriter, message string, code int) {
 	_, _ = w.Write([]byte(`{"error": "` + message + `"}`))
 }
 
-// JWTAuth проверяет токен и добавляет пользователя в контекст
-func JWTAuth(secret string) func(http.Handler) http.Handler {
+// JWTAuth проверяет подпись токена ключом из keyfunc, сверяет его со списком
+// отозванных, проверяет статус владельца и добавляет пользователя в контекст
+func JWTAuth(keyfunc jwt.Keyfunc, revocations RevocationChecker, statuses StatusChecker) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -41,12 +55,7 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 
 			tokenStr := parts[1]
 
-			token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, errors.New("unexpected signing method")
-				}
-				return []byte(secret), nil
-			})
+			token, err := jwt.Parse(tokenStr, keyfunc, jwt.WithExpirationRequired())
 
 			if err != nil || !token.Valid {
 				jsonError(w, "Invalid or expired token", http.StatusUnauthorized)
@@ -62,7 +71,10 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 			subRaw, _ := claims["sub"].(string)
 			email, _ := claims["email"].(string)
 			nickname, _ := claims["nickname"].(string)
-			role, _ := claims["role"].(string)
+			roles := rolesFromClaims(claims)
+			tokenID, _ := claims["jti"].(string)
+			sidRaw, _ := claims["sid"].(string)
+			orgRaw, _ := claims["org"].(string)
 
 			userID, err := uuid.Parse(subRaw)
 			if err != nil {
@@ -70,19 +82,77 @@ func JWTAuth(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			sessionID, err := uuid.Parse(sidRaw)
+			if err != nil || tokenID == "" {
+				jsonError(w, "Invalid token claims", http.StatusUnauthorized)
+				return
+			}
+
+			revoked, err := revocations.IsRevoked(r.Context(), tokenID, sessionID)
+			if err != nil {
+				jsonError(w, "Failed to verify token", http.StatusInternalServerError)
+				return
+			}
+			if revoked {
+				jsonError(w, "Token has been revoked", http.StatusUnauthorized)
+				return
+			}
+			if !checkStatus(w, r, statuses, userID) {
+				return
+			}
+
+			// exp обязателен, его наличие проверено при разборе токена
+			expiresAt, _ := claims.GetExpirationTime()
+			tokenClaims := &model.TokenClaims{
+				ID:        tokenID,
+				Subject:   subRaw,
+				SessionID: sessionID,
+				ExpiresAt: expiresAt.Time,
+			}
+
 			user := &model.User{
 				ID:       userID,
 				Email:    email,
 				Nickname: nickname,
-				Role:     role,
+				Roles:    roles,
 			}
 
-			ctx := context.WithValue(r.Context(), userCtxKey, user)
+			ctx := ContextWithUser(r.Context(), user)
+			ctx = ContextWithToken(ctx, tokenClaims)
+			// Без org пользователь пока не состоит ни в одной организации
+			if orgID, err := uuid.Parse(orgRaw); err == nil {
+				ctx = ContextWithOrganization(ctx, orgID)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// checkStatus пропускает запрос дальше, только если доступ пользователю не
+// закрыт. Иначе сам отвечает ошибкой и возвращает false.
+func checkStatus(w http.ResponseWriter, r *http.Request, statuses StatusChecker, userID uuid.UUID) bool {
+	blocked, err := statuses.IsBlocked(r.Context(), userID)
+	if err != nil {
+		jsonError(w, "Failed to verify account status", http.StatusInternalServerError)
+		return false
+	}
+	if blocked {
+		jsonError(w, "Account is suspended", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// ContextWithUser кладёт пользователя в контекст
+func ContextWithUser(ctx context.Context, user *model.User) context.Context {
+	return context.WithValue(ctx, userCtxKey, user)
+}
+
+// ContextWithToken кладёт служебные поля access-токена в контекст
+func ContextWithToken(ctx context.Context, token *model.TokenClaims) context.Context {
+	return context.WithValue(ctx, tokenCtxKey, token)
+}
+
 // UserFromContext возвращает пользователя из контекста
 func UserFromContext(ctx context.Context) *model.User {
 	u, ok := ctx.Value(userCtxKey).(*model.User)
@@ -92,16 +162,31 @@ func UserFromContext(ctx context.Context) *model.User {
 	return u
 }
 
-// AdminOnly пропускает только администраторов
-func AdminOnly(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		user := UserFromContext(r.Context())
+// TokenFromContext возвращает служебные поля access-токена из контекста
+func TokenFromContext(ctx context.Context) *model.TokenClaims {
+	t, ok := ctx.Value(tokenCtxKey).(*model.TokenClaims)
+	if !ok {
+		return nil
+	}
+	return t
+}
 
-		if user == nil || user.Role != "admin" {
-			jsonError(w, "Forbidden: insufficient permissions", http.StatusForbidden)
-			return
+// rolesFromClaims читает список ролей из claims. Токены, выданные до
+// появления roles, содержат только строковый role.
+func rolesFromClaims(claims jwt.MapClaims) []string {
+	raw, ok := claims["roles"].([]interface{})
+	if !ok {
+		if role, _ := claims["role"].(string); role != "" {
+			return []string{role}
 		}
+		return nil
+	}
 
-		next.ServeHTTP(w, r)
-	})
+	roles := make([]string, 0, len(raw))
+	for _, r := range raw {
+		if role, ok := r.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
 }
@@ -1,13 +1,16 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
+	"user-account/cmd/internal/gen/mocks"
 	"user-account/cmd/internal/model"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -17,30 +20,72 @@ func TestJWTAuth(t *testing.T) {
 
 	secret := "test-secret"
 	userID := uuid.New()
+	tokenID := uuid.NewString()
+	sessionID := uuid.New()
 
-	createToken := func(id string, role string, exp time.Duration) string {
+	createTokenWithSession := func(id string, role string, exp time.Duration, sid string) string {
 		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 			"sub":  id,
 			"role": role,
+			"jti":  tokenID,
+			"sid":  sid,
 			"exp":  time.Now().Add(exp).Unix(),
 		})
 		s, _ := token.SignedString([]byte(secret))
 		return s
 	}
+	createTokenWithRoles := func(id string, roles []string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   id,
+			"role":  roles[0],
+			"roles": roles,
+			"jti":   tokenID,
+			"sid":   sessionID.String(),
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+		s, _ := token.SignedString([]byte(secret))
+		return s
+	}
+	orgID := uuid.New()
+	createTokenWithOrg := func(id string, org string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":   id,
+			"role":  "user",
+			"roles": []string{"user"},
+			"jti":   tokenID,
+			"sid":   sessionID.String(),
+			"org":   org,
+			"exp":   time.Now().Add(time.Hour).Unix(),
+		})
+		s, _ := token.SignedString([]byte(secret))
+		return s
+	}
+	createToken := func(id string, role string, exp time.Duration) string {
+		return createTokenWithSession(id, role, exp, sessionID.String())
+	}
 
 	tests := []struct {
 		name           string
 		authHeader     string
+		mockBehavior   func(m *mocks.MockRevocationChecker)
+		statusBehavior func(m *mocks.MockStatusChecker)
 		expectedStatus int
 		expectedUser   *model.User
+		expectedOrg    uuid.UUID
 	}{
 		{
-			name:           "1. Valid Token",
-			authHeader:     "Bearer " + createToken(userID.String(), "admin", time.Hour),
+			name:       "1. Valid Token",
+			authHeader: "Bearer " + createToken(userID.String(), "admin", time.Hour),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, nil)
+			},
+			statusBehavior: func(m *mocks.MockStatusChecker) {
+				m.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, nil)
+			},
 			expectedStatus: http.StatusOK,
 			expectedUser: &model.User{
-				ID:   userID,
-				Role: "admin",
+				ID:    userID,
+				Roles: []string{"admin"},
 			},
 		},
 		{
@@ -67,22 +112,121 @@ func TestJWTAuth(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized,
 			expectedUser:   nil,
 		},
+		{
+			name:           "6. Missing Session ID",
+			authHeader:     "Bearer " + createTokenWithSession(userID.String(), "user", time.Hour, ""),
+			expectedStatus: http.StatusUnauthorized,
+			expectedUser:   nil,
+		},
+		{
+			name:       "7. Revoked Token",
+			authHeader: "Bearer " + createToken(userID.String(), "user", time.Hour),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(true, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedUser:   nil,
+		},
+		{
+			name:       "8. Revocation Store Unavailable",
+			authHeader: "Bearer " + createToken(userID.String(), "user", time.Hour),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedUser:   nil,
+		},
+		{
+			name:       "9. Multiple Roles",
+			authHeader: "Bearer " + createTokenWithRoles(userID.String(), []string{"user", "admin"}),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, nil)
+			},
+			statusBehavior: func(m *mocks.MockStatusChecker) {
+				m.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedUser: &model.User{
+				ID:    userID,
+				Roles: []string{"user", "admin"},
+			},
+		},
+		{
+			name:       "10. Active Organization",
+			authHeader: "Bearer " + createTokenWithOrg(userID.String(), orgID.String()),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, nil)
+			},
+			statusBehavior: func(m *mocks.MockStatusChecker) {
+				m.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedUser:   &model.User{ID: userID, Roles: []string{"user"}},
+			expectedOrg:    orgID,
+		},
+		{
+			name:       "11. Suspended Account",
+			authHeader: "Bearer " + createToken(userID.String(), "user", time.Hour),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, nil)
+			},
+			statusBehavior: func(m *mocks.MockStatusChecker) {
+				m.EXPECT().IsBlocked(gomock.Any(), userID).Return(true, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedUser:   nil,
+		},
+		{
+			name:       "12. Status Check Failed",
+			authHeader: "Bearer " + createToken(userID.String(), "user", time.Hour),
+			mockBehavior: func(m *mocks.MockRevocationChecker) {
+				m.EXPECT().IsRevoked(gomock.Any(), tokenID, sessionID).Return(false, nil)
+			},
+			statusBehavior: func(m *mocks.MockStatusChecker) {
+				m.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedUser:   nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
+			ctrl := gomock.NewController(t)
+			revocations := mocks.NewMockRevocationChecker(ctrl)
+			if tt.mockBehavior != nil {
+				tt.mockBehavior(revocations)
+			}
+			statuses := mocks.NewMockStatusChecker(ctrl)
+			if tt.statusBehavior != nil {
+				tt.statusBehavior(statuses)
+			}
+
 			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				user := UserFromContext(r.Context())
 				if tt.expectedUser != nil {
 					assert.NotNil(t, user)
 					assert.Equal(t, tt.expectedUser.ID, user.ID)
-					assert.Equal(t, tt.expectedUser.Role, user.Role)
+					assert.Equal(t, tt.expectedUser.Roles, user.Roles)
+
+					token := TokenFromContext(r.Context())
+					assert.NotNil(t, token)
+					assert.Equal(t, tokenID, token.ID)
+					assert.Equal(t, tt.expectedUser.ID.String(), token.Subject)
+					assert.Equal(t, sessionID, token.SessionID)
+					assert.Equal(t, tt.expectedOrg, OrganizationFromContext(r.Context()))
 				}
 				w.WriteHeader(http.StatusOK)
 			})
 
-			mdw := JWTAuth(secret)(nextHandler)
+			keyfunc := func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, errors.New("unexpected signing method")
+				}
+				return []byte(secret), nil
+			}
+			mdw := JWTAuth(keyfunc, revocations, statuses)(nextHandler)
 
 			req := httptest.NewRequest(http.MethodGet, "/protected", nil)
 			if tt.authHeader != "" {
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"user-account/cmd/internal/model"
+)
+
+const clientCtxKey = contextKey("client")
+
+// Client кладёт в контекст адрес и браузер клиента. По ним журнал аудита
+// отмечает, откуда выполнено действие, не требуя передавать их в каждый
+// метод сервисов.
+func Client(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ContextWithClient(r.Context(), model.ClientInfo{
+			UserAgent: r.UserAgent(),
+			IPAddress: remoteIP(r),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ContextWithClient кладёт сведения о клиенте в контекст
+func ContextWithClient(ctx context.Context, client model.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientCtxKey, client)
+}
+
+// ClientFromContext возвращает сведения о клиенте из контекста, пустые -
+// если запрос пришёл не по HTTP
+func ClientFromContext(ctx context.Context) model.ClientInfo {
+	client, _ := ctx.Value(clientCtxKey).(model.ClientInfo)
+	return client
+}
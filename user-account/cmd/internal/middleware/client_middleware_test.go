@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	var got model.ClientInfo
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = ClientFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "curl/8.0")
+	Client(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, model.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "curl/8.0"}, got)
+	assert.Equal(t, model.ClientInfo{}, ClientFromContext(req.Context()), "request without the middleware has no client")
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+)
+
+const organizationCtxKey = contextKey("organization")
+
+// MembershipChecker возвращает роль пользователя в организации, пустую -
+// если он в ней не состоит
+type MembershipChecker interface {
+	MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error)
+}
+
+// SameOrganization пропускает к чужому пользователю, только если он
+// состоит в активной организации вызывающего, а роль вызывающего в ней
+// даёт право permission над ним (model.OrgRoleAllows). Чужим отвечает 404,
+// чтобы не раскрывать пользователей других организаций, без права - 403.
+// Глобальное право проверяется отдельно. userID извлекает из запроса
+// идентификатор пользователя. Должен стоять после JWTAuth или APIKeyOrJWT.
+func SameOrganization(members MembershipChecker, permission string, userID func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				jsonError(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			id, err := uuid.Parse(userID(r))
+			// Некорректный ID отклонит хендлер, свой профиль доступен всегда
+			if err != nil || id == user.ID {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			orgID := OrganizationFromContext(r.Context())
+			if orgID == uuid.Nil {
+				jsonError(w, "user not found", http.StatusNotFound)
+				return
+			}
+
+			targetRole, err := members.MemberRole(r.Context(), orgID, id)
+			if err != nil {
+				jsonError(w, "Failed to check organization membership", http.StatusInternalServerError)
+				return
+			}
+			if targetRole == "" {
+				jsonError(w, "user not found", http.StatusNotFound)
+				return
+			}
+
+			actorRole, err := members.MemberRole(r.Context(), orgID, user.ID)
+			if err != nil {
+				jsonError(w, "Failed to check organization membership", http.StatusInternalServerError)
+				return
+			}
+			if !model.OrgRoleAllows(actorRole, targetRole, permission) {
+				jsonError(w, "Insufficient organization role", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContextWithOrganization кладёт активную организацию в контекст
+func ContextWithOrganization(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, organizationCtxKey, orgID)
+}
+
+// OrganizationFromContext возвращает активную организацию запроса или
+// uuid.Nil, если пользователь не состоит ни в одной
+func OrganizationFromContext(ctx context.Context) uuid.UUID {
+	id, ok := ctx.Value(organizationCtxKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+	return id
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameOrganization(t *testing.T) {
+	t.Parallel()
+
+	callerID := uuid.New()
+	targetID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name           string
+		target         string
+		permission     string
+		withOrg        bool
+		mockBehavior   func(m *mocks.MockMembershipChecker)
+		expectedStatus int
+	}{
+		{
+			name:       "1. Member Reads Member Of Same Organization",
+			target:     targetID.String(),
+			permission: model.PermUsersRead,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return(model.OrgRoleMember, nil)
+				m.EXPECT().MemberRole(gomock.Any(), orgID, callerID).Return(model.OrgRoleMember, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "2. Target In Another Organization",
+			target:     targetID.String(),
+			permission: model.PermUsersRead,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return("", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "3. Own Profile Without Organization",
+			target:         callerID.String(),
+			permission:     model.PermUsersManage,
+			mockBehavior:   func(_ *mocks.MockMembershipChecker) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "4. Caller Without Organization",
+			target:         targetID.String(),
+			permission:     model.PermUsersRead,
+			mockBehavior:   func(_ *mocks.MockMembershipChecker) {},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:       "5. Checker Error",
+			target:     targetID.String(),
+			permission: model.PermUsersRead,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return("", errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "6. Plain Member Cannot Manage",
+			target:     targetID.String(),
+			permission: model.PermUsersManage,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return(model.OrgRoleMember, nil)
+				m.EXPECT().MemberRole(gomock.Any(), orgID, callerID).Return(model.OrgRoleMember, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "7. Admin Manages Member",
+			target:     targetID.String(),
+			permission: model.PermUsersManage,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return(model.OrgRoleMember, nil)
+				m.EXPECT().MemberRole(gomock.Any(), orgID, callerID).Return(model.OrgRoleAdmin, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "8. Admin Cannot Manage Owner",
+			target:     targetID.String(),
+			permission: model.PermUsersManage,
+			withOrg:    true,
+			mockBehavior: func(m *mocks.MockMembershipChecker) {
+				m.EXPECT().MemberRole(gomock.Any(), orgID, targetID).Return(model.OrgRoleOwner, nil)
+				m.EXPECT().MemberRole(gomock.Any(), orgID, callerID).Return(model.OrgRoleAdmin, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			members := mocks.NewMockMembershipChecker(ctrl)
+			tt.mockBehavior(members)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			mdw := SameOrganization(members, tt.permission, func(_ *http.Request) string { return tt.target })(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/"+tt.target, nil)
+			ctx := ContextWithUser(context.Background(), &model.User{ID: callerID})
+			if tt.withOrg {
+				ctx = ContextWithOrganization(ctx, orgID)
+			}
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			mdw.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// PermissionChecker проверяет, есть ли у пользователя право
+type PermissionChecker interface {
+	HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
+}
+
+// RequirePermission пропускает только пользователей с правом permission.
+// Запросу с API-ключом право должно быть выдано ещё и в scopes ключа.
+// Должен стоять после JWTAuth или APIKeyOrJWT.
+func RequirePermission(checker PermissionChecker, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				jsonError(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			if key := APIKeyFromContext(r.Context()); key != nil && !key.HasScope(permission) {
+				jsonError(w, "Forbidden: API key scope does not include "+permission, http.StatusForbidden)
+				return
+			}
+
+			allowed, err := checker.HasPermission(r.Context(), user.ID, permission)
+			if err != nil {
+				jsonError(w, "Failed to check permissions", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				jsonError(w, "Forbidden: insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// OwnerOrPermission пропускает пользователя к его собственному ресурсу,
+// а к чужим - только при наличии права permission. ownerID извлекает из
+// запроса идентификатор владельца ресурса. API-ключу право нужно и для
+// ресурсов владельца. Должен стоять после JWTAuth или APIKeyOrJWT.
+func OwnerOrPermission(checker PermissionChecker, permission string, ownerID func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		requirePermission := RequirePermission(checker, permission)(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := UserFromContext(r.Context())
+			if user == nil {
+				jsonError(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			id, err := uuid.Parse(ownerID(r))
+			if err == nil && id == user.ID && APIKeyFromContext(r.Context()) == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requirePermission.ServeHTTP(w, r)
+		})
+	}
+}
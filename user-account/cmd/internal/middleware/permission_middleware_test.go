@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name           string
+		user           *model.User
+		apiKey         *model.APIKey
+		mockBehavior   func(m *mocks.MockPermissionChecker)
+		expectedStatus int
+	}{
+		{
+			name: "1. Permission Granted",
+			user: &model.User{ID: userID},
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "2. Permission Missing",
+			user: &model.User{ID: userID},
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name: "3. Checker Error",
+			user: &model.User{ID: userID},
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "4. No User In Context",
+			user:           nil,
+			mockBehavior:   func(_ *mocks.MockPermissionChecker) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "5. API Key With Scope",
+			user:   &model.User{ID: userID},
+			apiKey: &model.APIKey{Scopes: []string{"users:read"}},
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "6. API Key Without Scope",
+			user:           &model.User{ID: userID},
+			apiKey:         &model.APIKey{Scopes: []string{"users:manage"}},
+			mockBehavior:   func(_ *mocks.MockPermissionChecker) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "7. API Key Scope Outlives Owner Permission",
+			user:   &model.User{ID: userID},
+			apiKey: &model.APIKey{Scopes: []string{"users:read"}},
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			checker := mocks.NewMockPermissionChecker(ctrl)
+			tt.mockBehavior(checker)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			mdw := RequirePermission(checker, "users:read")(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			ctx := context.Background()
+			if tt.user != nil {
+				ctx = ContextWithUser(ctx, tt.user)
+			}
+			if tt.apiKey != nil {
+				ctx = ContextWithAPIKey(ctx, tt.apiKey)
+			}
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			mdw.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestOwnerOrPermission(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name           string
+		ownerID        string
+		apiKey         *model.APIKey
+		mockBehavior   func(m *mocks.MockPermissionChecker)
+		expectedStatus int
+	}{
+		{
+			name:           "1. Own Resource",
+			ownerID:        userID.String(),
+			mockBehavior:   func(_ *mocks.MockPermissionChecker) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "2. Foreign Resource With Permission",
+			ownerID: otherID.String(),
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "3. Foreign Resource Without Permission",
+			ownerID: otherID.String(),
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:    "4. Malformed Owner ID Falls Back To Permission",
+			ownerID: "not-a-uuid",
+			mockBehavior: func(m *mocks.MockPermissionChecker) {
+				m.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "5. Own Resource With API Key Requires Scope",
+			ownerID:        userID.String(),
+			apiKey:         &model.APIKey{Scopes: []string{"users:read"}},
+			mockBehavior:   func(_ *mocks.MockPermissionChecker) {},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			checker := mocks.NewMockPermissionChecker(ctrl)
+			tt.mockBehavior(checker)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+			ownerID := func(_ *http.Request) string { return tt.ownerID }
+			mdw := OwnerOrPermission(checker, "users:manage", ownerID)(next)
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+tt.ownerID, nil)
+			ctx := ContextWithUser(context.Background(), &model.User{ID: userID})
+			if tt.apiKey != nil {
+				ctx = ContextWithAPIKey(ctx, tt.apiKey)
+			}
+			req = req.WithContext(ctx)
+			rec := httptest.NewRecorder()
+
+			mdw.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
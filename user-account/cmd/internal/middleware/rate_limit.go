@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+	"user-account/cmd/internal/ratelimit"
+)
+
+// APIKeyHeader - заголовок, в котором клиенты присылают API-ключ
+const APIKeyHeader = "X-API-Key"
+
+// RateLimiter списывает запрос из корзины key
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, rate ratelimit.Rate) (ratelimit.Decision, error)
+}
+
+// RateLimitKey определяет, из чьей корзины списывается запрос
+type RateLimitKey func(r *http.Request) string
+
+// RateLimitPolicy - лимит для группы маршрутов. Name разделяет корзины:
+// у политик с разными именами счёт независимый.
+type RateLimitPolicy struct {
+	Name string
+	Rate ratelimit.Rate
+	Key  RateLimitKey
+}
+
+// RateLimit ограничивает частоту запросов по политике и отдаёт заголовки
+// RateLimit-* (draft-ietf-httpapi-ratelimit-headers). Сверх лимита
+// отвечает 429 с Retry-After. Если хранилище недоступно, запрос
+// пропускается: лучше временно остаться без лимита, чем без сервиса.
+func RateLimit(limiter RateLimiter, policy RateLimitPolicy) func(http.Handler) http.Handler {
+	policyHeader := strconv.Itoa(policy.Rate.Limit) + ";w=" + strconv.Itoa(ceilSeconds(policy.Rate.Period))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := "rl:" + policy.Name + ":" + policy.Key(r)
+
+			decision, err := limiter.Allow(r.Context(), key, policy.Rate)
+			if err != nil {
+				log.Printf("rate limit %s: %v", policy.Name, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Policy", policyHeader)
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.ResetAfter)))
+
+			if !decision.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(decision.RetryAfter), 1)))
+				jsonError(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ByIP - корзина на IP-адрес клиента
+func ByIP(r *http.Request) string {
+	return "ip:" + remoteIP(r)
+}
+
+// ByUser - корзина на пользователя из UserFromContext, анонимные запросы
+// делят корзину по IP. Должен стоять после JWTAuth.
+func ByUser(r *http.Request) string {
+	if user := UserFromContext(r.Context()); user != nil {
+		return "user:" + user.ID.String()
+	}
+	return ByIP(r)
+}
+
+// ByAPIKey - корзина на API-ключ: после APIKeyOrJWT - по ID ключа, до
+// проверки - по хэшу заголовка X-API-Key, чтобы сам ключ не оседал в
+// хранилище. Запросы без ключа считаются как в ByUser.
+func ByAPIKey(r *http.Request) string {
+	if key := APIKeyFromContext(r.Context()); key != nil {
+		return "key:" + key.ID.String()
+	}
+	apiKey := r.Header.Get(APIKeyHeader)
+	if apiKey == "" {
+		return ByUser(r)
+	}
+	sum := sha256.Sum256([]byte(apiKey))
+	return "key:" + hex.EncodeToString(sum[:16])
+}
+
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/ratelimit"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(ratelimit.NewMemoryLimiter(), RateLimitPolicy{
+		Name: "login",
+		Rate: ratelimit.Rate{Limit: 2, Period: time.Minute},
+		Key:  ByIP,
+	})(next)
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("10.0.0.1:5000")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+	w = request("10.0.0.1:5001")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = request("10.0.0.1:5002")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	w = request("10.0.0.2:5000")
+	assert.Equal(t, http.StatusOK, w.Code, "other clients keep their own bucket")
+}
+
+func TestRateLimit_LimiterFailureFailsOpen(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	limiter := mocks.NewMockRateLimiter(ctrl)
+	limiter.EXPECT().Allow(gomock.Any(), "rl:api:ip:192.0.2.1", gomock.Any()).Return(ratelimit.Decision{}, errors.New("redis down"))
+
+	handler := RateLimit(limiter, RateLimitPolicy{Name: "api", Rate: ratelimit.Rate{Limit: 1, Period: time.Second}, Key: ByIP})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }),
+	)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	anonymous := httptest.NewRequest(http.MethodGet, "/", nil)
+	anonymous.RemoteAddr = "198.51.100.7:443"
+
+	authenticated := anonymous.WithContext(ContextWithUser(anonymous.Context(), &model.User{ID: userID}))
+
+	withKey := httptest.NewRequest(http.MethodGet, "/", nil)
+	withKey.Header.Set(APIKeyHeader, "secret-api-key")
+
+	keyID := uuid.New()
+	verifiedKey := withKey.WithContext(ContextWithAPIKey(withKey.Context(), &model.APIKey{ID: keyID}))
+
+	assert.Equal(t, "ip:198.51.100.7", ByIP(anonymous))
+	assert.Equal(t, "ip:198.51.100.7", ByUser(anonymous))
+	assert.Equal(t, "user:"+userID.String(), ByUser(authenticated))
+	assert.Equal(t, "ip:198.51.100.7", ByAPIKey(anonymous))
+	assert.Equal(t, "user:"+userID.String(), ByAPIKey(authenticated))
+	assert.Regexp(t, `^key:[0-9a-f]{32}$`, ByAPIKey(withKey))
+	assert.NotContains(t, ByAPIKey(withKey), "secret-api-key")
+	assert.Equal(t, "key:"+keyID.String(), ByAPIKey(verifiedKey))
+}
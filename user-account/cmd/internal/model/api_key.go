@@ -0,0 +1,63 @@
+package model
+
+import (
+	"strings"
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// APIKey - ключ для скриптов и интеграций. Действует от имени владельца,
+// но только в пределах Scopes. Сам ключ не хранится, только его хэш.
+type APIKey struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// OrganizationID - организация, в которой действует ключ
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	Name           string     `json:"name"`
+	// Prefix - начало ключа, чтобы владелец узнал его в списке
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// HasScope сообщает, разрешено ли ключу право permission
+func (k *APIKey) HasScope(permission string) bool {
+	for _, scope := range k.Scopes {
+		if scope == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Active сообщает, можно ли пользоваться ключом в момент now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// APIKeyToDomain - из модельки базы в доменную модель
+func APIKeyToDomain(k jet_model.APIKeys) APIKey {
+	var createdAt time.Time
+	if k.CreatedAt != nil {
+		createdAt = *k.CreatedAt
+	}
+	return APIKey{
+		ID:             k.ID,
+		UserID:         k.UserID,
+		OrganizationID: k.OrganizationID,
+		Name:           k.Name,
+		Prefix:         k.Prefix,
+		KeyHash:        k.KeyHash,
+		Scopes:         strings.Fields(k.Scopes),
+		ExpiresAt:      k.ExpiresAt,
+		LastUsedAt:     k.LastUsedAt,
+		RevokedAt:      k.RevokedAt,
+		CreatedAt:      createdAt,
+	}
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// PermAuditRead - просмотр и проверка журнала аудита
+const PermAuditRead = "audit:read"
+
+// Действия журнала аудита
+const (
+	AuditLogin          = "auth.login"
+	AuditLogout         = "auth.logout"
+	AuditLogoutAll      = "auth.logout_all"
+	AuditRegister       = "user.register"
+	AuditPasswordChange = "user.password_change"
+	AuditPasswordReset  = "user.password_reset"
+	AuditUserDelete     = "user.delete"
+	AuditUserRestore    = "user.restore"
+	AuditUserUnlock     = "user.unlock"
+	AuditUserErase      = "user.erase"
+	AuditUserSuspend    = "user.suspend"
+	AuditUserReinstate  = "user.reinstate"
+	AuditRoleAssign     = "role.assign"
+	AuditRoleRevoke     = "role.revoke"
+)
+
+// Итог действия
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// Размер страницы журнала аудита
+const (
+	DefaultAuditPageSize = 50
+	MaxAuditPageSize     = 200
+)
+
+// AuditEvent - запись журнала аудита. ActorID - кто выполнил действие,
+// TargetID - над кем; при неудачном входе в неизвестный аккаунт оба пусты.
+// Hash - sha256 от записи вместе с PrevHash, хэшем предыдущей записи,
+// так что записи образуют цепочку.
+type AuditEvent struct {
+	ID        int64             `json:"id"`
+	ActorID   *uuid.UUID        `json:"actor_id"`
+	TargetID  *uuid.UUID        `json:"target_id"`
+	Action    string            `json:"action"`
+	IPAddress string            `json:"ip_address"`
+	UserAgent string            `json:"user_agent"`
+	Outcome   string            `json:"outcome"`
+	Metadata  map[string]string `json:"metadata"`
+	CreatedAt time.Time         `json:"created_at"`
+	PrevHash  string            `json:"prev_hash"`
+	Hash      string            `json:"hash"`
+}
+
+// AuditEventQuery - выборка журнала, новые записи первыми. Пустые фильтры
+// не применяются.
+type AuditEventQuery struct {
+	ActorID     *uuid.UUID
+	TargetID    *uuid.UUID
+	Action      string
+	Outcome     string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	// Before - ID последней записи предыдущей страницы, 0 для первой
+	Before int64
+}
+
+// AuditEventPage - страница журнала. NextCursor - ID последней записи,
+// 0 на последней странице.
+type AuditEventPage struct {
+	Events     []AuditEvent
+	NextCursor int64
+}
+
+// AuditChainCheck - итог проверки цепочки хэшей. BrokenAt - ID первой
+// записи, которая не сходится с цепочкой.
+type AuditChainCheck struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+}
+
+// ComputeHash считает хэш записи поверх prevHash. В хэш входят все поля,
+// кроме самого Hash; метаданные сериализуются с ключами по алфавиту, а
+// время - в UTC с микросекундами, как его хранит база.
+func (e AuditEvent) ComputeHash(prevHash string) string {
+	metadata := e.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	payload, _ := json.Marshal(struct {
+		PrevHash  string            `json:"prev_hash"`
+		ID        int64             `json:"id"`
+		ActorID   *uuid.UUID        `json:"actor_id"`
+		TargetID  *uuid.UUID        `json:"target_id"`
+		Action    string            `json:"action"`
+		IPAddress string            `json:"ip_address"`
+		UserAgent string            `json:"user_agent"`
+		Outcome   string            `json:"outcome"`
+		Metadata  map[string]string `json:"metadata"`
+		CreatedAt string            `json:"created_at"`
+	}{
+		PrevHash:  prevHash,
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		TargetID:  e.TargetID,
+		Action:    e.Action,
+		IPAddress: e.IPAddress,
+		UserAgent: e.UserAgent,
+		Outcome:   e.Outcome,
+		Metadata:  metadata,
+		CreatedAt: e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditEventToDomain - из модельки базы в доменную модель
+func AuditEventToDomain(e jet_model.AuditEvents) AuditEvent {
+	metadata := map[string]string{}
+	_ = json.Unmarshal([]byte(e.Metadata), &metadata)
+	return AuditEvent{
+		ID:        e.ID,
+		ActorID:   e.ActorID,
+		TargetID:  e.TargetID,
+		Action:    e.Action,
+		IPAddress: e.IPAddress,
+		UserAgent: e.UserAgent,
+		Outcome:   e.Outcome,
+		Metadata:  metadata,
+		CreatedAt: e.CreatedAt,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	}
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification - выданная ссылка подтверждения почты. Email
+// запоминается, чтобы ссылка не подтверждала адрес, сменённый после отправки.
+type EmailVerification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Email     string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// EmailVerificationToDomain - из модельки базы в доменную модель
+func EmailVerificationToDomain(v jet_model.EmailVerifications) EmailVerification {
+	return EmailVerification{
+		ID:        v.ID,
+		UserID:    v.UserID,
+		Email:     v.Email,
+		ExpiresAt: v.ExpiresAt,
+		UsedAt:    v.UsedAt,
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity - учётная запись внешнего провайдера OpenID Connect,
+// привязанная к пользователю
+type UserIdentity struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"user_id"`
+	// Provider - имя провайдера из настроек, например google
+	Provider string `json:"provider"`
+	// Subject - claim sub, постоянный идентификатор пользователя у провайдера
+	Subject     string     `json:"-"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// OIDCLoginState - незавершённый вход через провайдера. Сам state не
+// хранится, только его хэш.
+type OIDCLoginState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	// BindingHash - хэш значения из cookie браузера, начавшего вход
+	BindingHash string
+	ExpiresAt   time.Time
+}
+
+// UserIdentityToDomain - из модельки базы в доменную модель
+func UserIdentityToDomain(i jet_model.UserIdentities) UserIdentity {
+	createdAt := time.Now()
+	if i.CreatedAt != nil {
+		createdAt = *i.CreatedAt
+	}
+	return UserIdentity{
+		ID:          i.ID,
+		UserID:      i.UserID,
+		Provider:    i.Provider,
+		Subject:     i.Subject,
+		Email:       i.Email,
+		LastLoginAt: i.LastLoginAt,
+		CreatedAt:   createdAt,
+	}
+}
+
+// OIDCLoginStateToDomain - из модельки базы в доменную модель
+func OIDCLoginStateToDomain(s jet_model.OidcLoginStates) OIDCLoginState {
+	return OIDCLoginState{
+		StateHash:    s.StateHash,
+		Provider:     s.Provider,
+		Nonce:        s.Nonce,
+		CodeVerifier: s.CodeVerifier,
+		BindingHash:  s.BindingHash,
+		ExpiresAt:    s.ExpiresAt,
+	}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Invitation - приглашение в организацию на почту с заранее назначенной
+// ролью. Ожидающим считается приглашение, которое ещё не приняли, не
+// отклонили, не отозвали и срок которого не истёк.
+type Invitation struct {
+	ID             uuid.UUID  `json:"id"`
+	OrganizationID uuid.UUID  `json:"organization_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	InvitedBy      *uuid.UUID `json:"invited_by,omitempty"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at,omitempty"`
+	DeclinedAt     *time.Time `json:"declined_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// InvitationAcceptance - итог принятия приглашения. AccountCreated
+// сообщает, что аккаунт был создан по приглашению.
+type InvitationAcceptance struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	Role           string    `json:"role"`
+	UserID         uuid.UUID `json:"user_id"`
+	AccountCreated bool      `json:"account_created"`
+}
+
+// Pending сообщает, можно ли ещё принять или отклонить приглашение
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.DeclinedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
+
+// InvitationToDomain - из модельки базы в доменную модель
+func InvitationToDomain(i jet_model.OrganizationInvitations) Invitation {
+	var createdAt time.Time
+	if i.CreatedAt != nil {
+		createdAt = *i.CreatedAt
+	}
+	return Invitation{
+		ID:             i.ID,
+		OrganizationID: i.OrganizationID,
+		Email:          i.Email,
+		Role:           i.Role,
+		InvitedBy:      i.InvitedBy,
+		ExpiresAt:      i.ExpiresAt,
+		AcceptedAt:     i.AcceptedAt,
+		DeclinedAt:     i.DeclinedAt,
+		RevokedAt:      i.RevokedAt,
+		CreatedAt:      createdAt,
+	}
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+)
+
+// LoginAttempt - счётчик неудачных попыток входа по одному ключу
+// (аккаунту или IP-адресу)
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// LoginAttemptToDomain - из модельки базы в доменную модель
+func LoginAttemptToDomain(a jet_model.LoginAttempts) LoginAttempt {
+	return LoginAttempt{
+		Key:           a.Key,
+		Failures:      int(a.Failures),
+		LastFailureAt: a.LastFailureAt,
+		LockedUntil:   a.LockedUntil,
+	}
+}
@@ -0,0 +1,200 @@
+package model
+
+import (
+	"strings"
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Виды грантов OAuth2, которые поддерживает сервер авторизации
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// Scopes OpenID Connect: с openid к токену доступа выдаётся ID-токен,
+// profile и email добавляют в него соответствующие claims
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// OAuthClient - клиент сервера авторизации: соседний сервис или фронтенд.
+// Сам секрет не хранится, только его хэш; у публичного клиента секрета нет.
+type OAuthClient struct {
+	ID           uuid.UUID  `json:"client_id"`
+	Name         string     `json:"name"`
+	SecretHash   string     `json:"-"`
+	RedirectURIs []string   `json:"redirect_uris"`
+	GrantTypes   []string   `json:"grant_types"`
+	Scopes       []string   `json:"scopes"`
+	CreatedBy    *uuid.UUID `json:"created_by"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Public сообщает, что у клиента нет секрета и он не может его сохранить
+func (c *OAuthClient) Public() bool {
+	return c.SecretHash == ""
+}
+
+// AllowsGrant сообщает, разрешён ли клиенту грант grantType
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	return containsString(c.GrantTypes, grantType)
+}
+
+// AllowsRedirect сообщает, зарегистрирован ли адрес возврата. Адрес
+// сравнивается целиком, без нормализации (RFC 6749, 3.1.2.3).
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+	return containsString(c.RedirectURIs, uri)
+}
+
+// AllowsScopes сообщает, входят ли все scopes в разрешённые клиенту
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsString(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// OAuthClientRegistration - параметры регистрации клиента
+type OAuthClientRegistration struct {
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string
+	Scopes       []string
+	// Public - клиент без секрета, только authorization_code с PKCE
+	Public bool
+}
+
+// AuthorizationRequest - запрос кода авторизации (RFC 6749, 4.1.1) с PKCE
+// (RFC 7636) и nonce для ID-токена
+type AuthorizationRequest struct {
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	Nonce               string `json:"nonce"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+}
+
+// AuthorizationPreview - что показать пользователю на странице согласия
+type AuthorizationPreview struct {
+	ClientID    uuid.UUID `json:"client_id"`
+	ClientName  string    `json:"client_name"`
+	RedirectURI string    `json:"redirect_uri"`
+	Scopes      []string  `json:"scopes"`
+}
+
+// OAuthAuthorizationCode - выданный, но ещё не обменянный код авторизации.
+// Сам код не хранится, только его хэш.
+type OAuthAuthorizationCode struct {
+	CodeHash      string
+	ClientID      uuid.UUID
+	UserID        uuid.UUID
+	RedirectURI   string
+	Scopes        []string
+	Nonce         string
+	CodeChallenge string
+	ExpiresAt     time.Time
+}
+
+// TokenRequest - запрос к /oauth/token (RFC 6749, 4.1.3 и 4.4.2)
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	Scope        string
+}
+
+// OAuthTokens - токены, выданные клиенту. IDToken есть только при scope openid.
+type OAuthTokens struct {
+	AccessToken string
+	IDToken     string
+	ExpiresIn   time.Duration
+	Scopes      []string
+}
+
+// TokenIntrospection - ответ интроспекции (RFC 7662, 2.2). Для
+// недействительного токена заполнено только Active.
+type TokenIntrospection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	TokenID   string `json:"jti,omitempty"`
+}
+
+// OAuthServerMetadata - документ /.well-known/openid-configuration
+type OAuthServerMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// OAuthClientToDomain - из модельки базы в доменную модель
+func OAuthClientToDomain(c jet_model.OauthClients) OAuthClient {
+	var createdAt time.Time
+	if c.CreatedAt != nil {
+		createdAt = *c.CreatedAt
+	}
+	return OAuthClient{
+		ID:           c.ID,
+		Name:         c.Name,
+		SecretHash:   c.SecretHash,
+		RedirectURIs: strings.Fields(c.RedirectUris),
+		GrantTypes:   strings.Fields(c.GrantTypes),
+		Scopes:       strings.Fields(c.Scopes),
+		CreatedBy:    c.CreatedBy,
+		RevokedAt:    c.RevokedAt,
+		CreatedAt:    createdAt,
+	}
+}
+
+// OAuthAuthorizationCodeToDomain - из модельки базы в доменную модель
+func OAuthAuthorizationCodeToDomain(c jet_model.OauthAuthorizationCodes) OAuthAuthorizationCode {
+	return OAuthAuthorizationCode{
+		CodeHash:      c.CodeHash,
+		ClientID:      c.ClientID,
+		UserID:        c.UserID,
+		RedirectURI:   c.RedirectURI,
+		Scopes:        strings.Fields(c.Scopes),
+		Nonce:         c.Nonce,
+		CodeChallenge: c.CodeChallenge,
+		ExpiresAt:     c.ExpiresAt,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
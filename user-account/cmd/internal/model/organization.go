@@ -0,0 +1,85 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Роли в организации. Они не связаны с глобальными ролями из RBAC:
+// администратор организации управляет только её составом.
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleAdmin  = "admin"
+	OrgRoleMember = "member"
+)
+
+// Organization - рабочее пространство одного клиента сервиса. Пользователи
+// видят только тех, кто состоит с ними в одной организации.
+type Organization struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrganizationMembership - организация, в которой состоит пользователь, и
+// его роль в ней
+type OrganizationMembership struct {
+	Organization
+	Role string `json:"role"`
+}
+
+// OrganizationMember - участник организации
+type OrganizationMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Email    string    `json:"email"`
+	Nickname string    `json:"nickname"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// ValidOrgRole сообщает, существует ли роль в организации
+func ValidOrgRole(role string) bool {
+	switch role {
+	case OrgRoleOwner, OrgRoleAdmin, OrgRoleMember:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanManageOrganization сообщает, может ли роль менять организацию и её состав
+func CanManageOrganization(role string) bool {
+	return role == OrgRoleOwner || role == OrgRoleAdmin
+}
+
+// OrgRoleAllows сообщает, даёт ли роль actorRole право permission над
+// участником организации с ролью targetRole. Глобального права для этого
+// мало: смотреть участников может любой участник, менять - владелец и
+// администратор, а владельца - только владелец.
+func OrgRoleAllows(actorRole, targetRole, permission string) bool {
+	if actorRole == "" {
+		return false
+	}
+	if permission == PermUsersRead {
+		return true
+	}
+	if !CanManageOrganization(actorRole) {
+		return false
+	}
+	return targetRole != OrgRoleOwner || actorRole == OrgRoleOwner
+}
+
+// OrganizationToDomain - из модельки базы в доменную модель
+func OrganizationToDomain(o jet_model.Organizations) Organization {
+	var createdAt time.Time
+	if o.CreatedAt != nil {
+		createdAt = *o.CreatedAt
+	}
+	return Organization{
+		ID:        o.ID,
+		Name:      o.Name,
+		CreatedAt: createdAt,
+	}
+}
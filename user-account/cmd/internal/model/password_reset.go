@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// PasswordReset - выданный токен сброса пароля. Сам токен не хранится,
+// только его хэш.
+type PasswordReset struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// PasswordResetToDomain - из модельки базы в доменную модель
+func PasswordResetToDomain(r jet_model.PasswordResets) PasswordReset {
+	return PasswordReset{
+		ID:        r.ID,
+		UserID:    r.UserID,
+		TokenHash: r.TokenHash,
+		ExpiresAt: r.ExpiresAt,
+		UsedAt:    r.UsedAt,
+	}
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Статусы выгрузки персональных данных
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+)
+
+// erasedEmailDomain - зарезервированный домен .invalid: письмо на такой
+// адрес никуда не уйдёт
+const erasedEmailDomain = "erased.invalid"
+
+// DataExport - задача выгрузки персональных данных пользователя. Архив
+// собирается фоновой задачей и хранится отдельно, до ExpiresAt. Error -
+// причина сбоя для эксплуатации, пользователю она не показывается.
+type DataExport struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"-"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+// Downloadable сообщает, можно ли скачать архив в момент now
+func (e *DataExport) Downloadable(now time.Time) bool {
+	return e.Status == DataExportReady && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+// InProgress сообщает, что архив ещё собирается
+func (e *DataExport) InProgress() bool {
+	return e.Status == DataExportPending || e.Status == DataExportProcessing
+}
+
+// PersonalData - всё, что сервис хранит о пользователе, в том виде, в
+// каком оно попадает в архив выгрузки. Хэши паролей, ключей и секретов
+// не выгружаются: это не данные о человеке, а их раскрытие вредно.
+type PersonalData struct {
+	Profile       User
+	Organizations []OrganizationMembership
+	Sessions      []Session
+	APIKeys       []APIKey
+	Identities    []UserIdentity
+	StatusHistory []UserStatusChange
+	// OAuthClients - клиенты, зарегистрированные пользователем
+	OAuthClients []OAuthClient
+	// Invitations - приглашения, отправленные пользователем или на его адрес
+	Invitations []Invitation
+	// AuditEvents - записи журнала безопасности, где пользователь - автор
+	// или цель действия
+	AuditEvents []AuditEvent
+}
+
+// ErasedEmail - адрес стёртого пользователя. Он уникален, как того требует
+// таблица, и не совпадает ни с одним настоящим.
+func ErasedEmail(id uuid.UUID) string {
+	return "erased-" + id.String() + "@" + erasedEmailDomain
+}
+
+// ErasedNickname - ник стёртого пользователя
+func ErasedNickname(id uuid.UUID) string {
+	return "erased-" + id.String()
+}
+
+// DataExportToDomain - из модельки базы в доменную модель
+func DataExportToDomain(e jet_model.DataExports) DataExport {
+	var createdAt time.Time
+	if e.CreatedAt != nil {
+		createdAt = *e.CreatedAt
+	}
+	return DataExport{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Status:      e.Status,
+		Error:       e.Error,
+		CreatedAt:   createdAt,
+		StartedAt:   e.StartedAt,
+		CompletedAt: e.CompletedAt,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
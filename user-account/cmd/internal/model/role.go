@@ -0,0 +1,39 @@
+package model
+
+import (
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+)
+
+// Встроенные роли
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Права, на которые ссылается код. Остальные права живут только в базе.
+const (
+	PermUsersRead     = "users:read"
+	PermUsersManage   = "users:manage"
+	PermRolesManage   = "roles:manage"
+	PermClientsManage = "oauth_clients:manage"
+)
+
+// Role - роль с набором прав
+type Role struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+// RoleToDomain - из модельки базы в доменную модель
+func RoleToDomain(r jet_model.Roles, permissions []jet_model.RolePermissions) Role {
+	perms := make([]string, len(permissions))
+	for i, p := range permissions {
+		perms[i] = p.Permission
+	}
+	return Role{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: perms,
+	}
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Session - серверная сессия, привязанная к одному refresh-токену.
+// Все сессии, полученные ротацией от одного логина, делят FamilyID.
+type Session struct {
+	ID       uuid.UUID `json:"id"`
+	FamilyID uuid.UUID `json:"family_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	// OrganizationID - активная организация, переходит к следующей сессии при ротации
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	TokenHash      string     `json:"-"`
+	UserAgent      string     `json:"user_agent"`
+	IPAddress      string     `json:"ip_address"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RotatedAt      *time.Time `json:"rotated_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+}
+
+// ClientInfo - сведения о клиенте, от имени которого открывается сессия
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+// TokenPair - пара токенов, выдаваемая при логине и ротации
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
+}
+
+// LoginResult - итог проверки пароля: либо пара токенов, либо, если у
+// пользователя включён второй фактор, токен для шага POST /login/2fa
+type LoginResult struct {
+	Tokens   *TokenPair
+	MFAToken string
+}
+
+// TokenClaims - служебные поля предъявленного access-токена
+type TokenClaims struct {
+	ID string
+	// Subject - claim sub, ID владельца токена
+	Subject   string
+	SessionID uuid.UUID
+	ExpiresAt time.Time
+}
+
+// SessionToDomain - из модельки базы в доменную модель
+func SessionToDomain(s jet_model.Sessions) Session {
+	createdAt := time.Now()
+	if s.CreatedAt != nil {
+		createdAt = *s.CreatedAt
+	}
+	return Session{
+		ID:             s.ID,
+		FamilyID:       s.FamilyID,
+		UserID:         s.UserID,
+		OrganizationID: s.OrganizationID,
+		TokenHash:      s.TokenHash,
+		UserAgent:      s.UserAgent,
+		IPAddress:      s.IPAddress,
+		ExpiresAt:      s.ExpiresAt,
+		CreatedAt:      createdAt,
+		RotatedAt:      s.RotatedAt,
+		RevokedAt:      s.RevokedAt,
+	}
+}
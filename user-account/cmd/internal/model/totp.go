@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// TOTP - секрет второго фактора пользователя. Пока ConfirmedAt пуст,
+// фактор только выпущен и при входе не спрашивается.
+type TOTP struct {
+	UserID       uuid.UUID
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+}
+
+// Enabled сообщает, подтверждён ли фактор
+func (t *TOTP) Enabled() bool {
+	return t != nil && t.ConfirmedAt != nil
+}
+
+// TOTPEnrollment - данные для добавления аккаунта в приложение-аутентификатор
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	// QRCode - PNG с URI в base64
+	QRCode string `json:"qr_png"`
+}
+
+// TOTPToDomain - из модельки базы в доменную модель
+func TOTPToDomain(t jet_model.UserTotp) TOTP {
+	return TOTP{
+		UserID:       t.UserID,
+		Secret:       t.Secret,
+		ConfirmedAt:  t.ConfirmedAt,
+		LastUsedStep: t.LastUsedStep,
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 	jet_model "user-account/cmd/internal/gen/docflow/public/model"
 
@@ -8,12 +9,61 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Email        string    `json:"email"`
-	Nickname     string    `json:"nickname"`
-	PasswordHash string    `json:"-"`
-	Role         string    `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID              uuid.UUID   `json:"id"`
+	Email           string      `json:"email"`
+	Nickname        string      `json:"nickname"`
+	PasswordHash    string      `json:"-"`
+	Roles           []string    `json:"roles"`
+	Preferences     Preferences `json:"preferences"`
+	EmailVerifiedAt *time.Time  `json:"email_verified_at"`
+	Status          string      `json:"status"`
+	StatusReason    string      `json:"status_reason,omitempty"`
+	StatusExpiresAt *time.Time  `json:"status_expires_at,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+// Preferences - настройки интерфейса, которые пользователь меняет сам
+type Preferences struct {
+	Language string `json:"language,omitempty"`
+	Theme    string `json:"theme,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// ProfileUpdate - изменения профиля; nil означает "оставить как есть"
+type ProfileUpdate struct {
+	Nickname    *string
+	Preferences *Preferences
+}
+
+// Blocked сообщает, закрыт ли пользователю доступ на момент now.
+// Приостановка со сроком перестаёт действовать сама, без записи в базу.
+func (u User) Blocked(now time.Time) bool {
+	if u.Status == "" || u.Status == UserStatusActive {
+		return false
+	}
+	return u.StatusExpiresAt == nil || now.Before(*u.StatusExpiresAt)
+}
+
+// HasRole проверяет, назначена ли пользователю роль
+func (u User) HasRole(role string) bool {
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// PrimaryRole - роль для отображения в интерфейсе: admin, если она назначена,
+// иначе первая из назначенных
+func (u User) PrimaryRole() string {
+	if u.HasRole(RoleAdmin) {
+		return RoleAdmin
+	}
+	if len(u.Roles) > 0 {
+		return u.Roles[0]
+	}
+	return RoleUser
 }
 
 // ToDomain - из модельки базы в доменную модель
@@ -22,12 +72,21 @@ func ToDomain(u jet_model.Users) User {
 	if u.CreatedAt != nil {
 		createdAt = *u.CreatedAt
 	}
+	var prefs Preferences
+	if u.Preferences != "" {
+		// Битые настройки не должны мешать входу - считаем их пустыми
+		_ = json.Unmarshal([]byte(u.Preferences), &prefs)
+	}
 	return User{
-		ID:           u.ID,
-		Email:        u.Email,
-		Nickname:     u.Nickname,
-		PasswordHash: u.PasswordHash,
-		Role:         u.Role,
-		CreatedAt:    createdAt,
+		ID:              u.ID,
+		Email:           u.Email,
+		Nickname:        u.Nickname,
+		PasswordHash:    u.PasswordHash,
+		Preferences:     prefs,
+		EmailVerifiedAt: u.EmailVerifiedAt,
+		Status:          u.Status,
+		StatusReason:    u.StatusReason,
+		StatusExpiresAt: u.StatusExpiresAt,
+		CreatedAt:       createdAt,
 	}
 }
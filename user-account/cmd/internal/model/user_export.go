@@ -0,0 +1,112 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Колонки выгрузки пользователей
+const (
+	UserExportID              = "id"
+	UserExportEmail           = "email"
+	UserExportNickname        = "nickname"
+	UserExportRoles           = "roles"
+	UserExportStatus          = "status"
+	UserExportEmailVerifiedAt = "email_verified_at"
+	UserExportCreatedAt       = "created_at"
+)
+
+// Языки заголовков выгрузки
+const (
+	ExportLanguageEN = "en"
+	ExportLanguageRU = "ru"
+)
+
+// UserExportColumns - все колонки выгрузки в порядке по умолчанию
+var UserExportColumns = []string{
+	UserExportID,
+	UserExportEmail,
+	UserExportNickname,
+	UserExportRoles,
+	UserExportStatus,
+	UserExportEmailVerifiedAt,
+	UserExportCreatedAt,
+}
+
+var userExportHeaders = map[string]map[string]string{
+	ExportLanguageEN: {
+		UserExportID:              "ID",
+		UserExportEmail:           "Email",
+		UserExportNickname:        "Nickname",
+		UserExportRoles:           "Roles",
+		UserExportStatus:          "Status",
+		UserExportEmailVerifiedAt: "Email verified at",
+		UserExportCreatedAt:       "Created at",
+	},
+	ExportLanguageRU: {
+		UserExportID:              "ID",
+		UserExportEmail:           "Электронная почта",
+		UserExportNickname:        "Никнейм",
+		UserExportRoles:           "Роли",
+		UserExportStatus:          "Статус",
+		UserExportEmailVerifiedAt: "Почта подтверждена",
+		UserExportCreatedAt:       "Дата регистрации",
+	},
+}
+
+// IsUserExportColumn сообщает, есть ли такая колонка в выгрузке
+func IsUserExportColumn(column string) bool {
+	_, ok := userExportHeaders[ExportLanguageEN][column]
+	return ok
+}
+
+// IsExportLanguage сообщает, есть ли заголовки на языке
+func IsExportLanguage(lang string) bool {
+	_, ok := userExportHeaders[lang]
+	return ok
+}
+
+// UserExportHeader - заголовки колонок на языке lang, по умолчанию
+// английские
+func UserExportHeader(columns []string, lang string) []string {
+	headers, ok := userExportHeaders[lang]
+	if !ok {
+		headers = userExportHeaders[ExportLanguageEN]
+	}
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = headers[column]
+	}
+	return header
+}
+
+// UserExportRow - значения колонок пользователя. Время - RFC 3339 в UTC,
+// роли - через запятую; значения не переводятся, чтобы выгрузку можно было
+// разбирать программно на любом языке заголовков.
+func UserExportRow(u User, columns []string) []string {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		switch column {
+		case UserExportID:
+			row[i] = u.ID.String()
+		case UserExportEmail:
+			row[i] = u.Email
+		case UserExportNickname:
+			row[i] = u.Nickname
+		case UserExportRoles:
+			row[i] = strings.Join(u.Roles, ",")
+		case UserExportStatus:
+			row[i] = u.Status
+			if row[i] == "" {
+				row[i] = UserStatusActive
+			}
+		case UserExportEmailVerifiedAt:
+			if u.EmailVerifiedAt != nil {
+				row[i] = u.EmailVerifiedAt.UTC().Format(time.RFC3339)
+			}
+		case UserExportCreatedAt:
+			row[i] = u.CreatedAt.UTC().Format(time.RFC3339)
+		}
+	}
+	return row
+}
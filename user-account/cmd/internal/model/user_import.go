@@ -0,0 +1,71 @@
+package model
+
+import "sort"
+
+// Ограничения импорта пользователей
+const (
+	// MaxUserImportRows - сколько строк принимается в одном файле
+	MaxUserImportRows = 1000
+	// MaxUserImportSize - предел размера файла в байтах
+	MaxUserImportSize = 5 << 20
+	// UserImportChunkSize - сколько пользователей создаётся в одной
+	// транзакции. Ошибка откатывает только свою пачку.
+	UserImportChunkSize = 100
+)
+
+// Итог обработки строки импорта
+const (
+	UserImportCreated = "created"
+	UserImportSkipped = "skipped"
+	UserImportFailed  = "failed"
+)
+
+// UserImportRow - строка файла импорта, уже прошедшая проверку полей
+type UserImportRow struct {
+	Line     int
+	Email    string
+	Nickname string
+	Password string
+}
+
+// UserImportResult - итог по одной строке файла. Skipped - аккаунт с такой
+// почтой уже есть или почта повторяется в файле, Failed - строку нужно
+// исправить.
+type UserImportResult struct {
+	Line   int    `json:"line"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// UserImportReport - отчёт об импорте. При DryRun ничего не создаётся:
+// Created - сколько пользователей было бы создано.
+type UserImportReport struct {
+	DryRun  bool               `json:"dry_run"`
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Failed  int                `json:"failed"`
+	Rows    []UserImportResult `json:"rows"`
+}
+
+// NewUserImportReport собирает отчёт из итогов по строкам в порядке файла
+func NewUserImportReport(results []UserImportResult, dryRun bool) UserImportReport {
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+
+	report := UserImportReport{DryRun: dryRun, Total: len(results), Rows: results}
+	if report.Rows == nil {
+		report.Rows = []UserImportResult{}
+	}
+	for _, r := range results {
+		switch r.Status {
+		case UserImportCreated:
+			report.Created++
+		case UserImportSkipped:
+			report.Skipped++
+		case UserImportFailed:
+			report.Failed++
+		}
+	}
+	return report
+}
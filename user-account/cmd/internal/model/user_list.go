@@ -0,0 +1,122 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Поля, по которым сортируется список пользователей
+const (
+	UserSortCreatedAt = "created_at"
+	UserSortEmail     = "email"
+	UserSortNickname  = "nickname"
+)
+
+// Размер страницы списка пользователей
+const (
+	DefaultUserPageSize = 50
+	MaxUserPageSize     = 200
+)
+
+var ErrInvalidUserCursor = errors.New("invalid cursor")
+
+// UserListQuery - выборка пользователей организации. Пустые фильтры не
+// применяются, Email и Nickname ищутся подстрокой без учёта регистра.
+type UserListQuery struct {
+	OrganizationID uuid.UUID
+	Role           string
+	Email          string
+	Nickname       string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	SortBy         string
+	Descending     bool
+	Limit          int
+	// After - курсор из предыдущей страницы, nil для первой
+	After *UserCursor
+	// WithTotal - посчитать всех подходящих под фильтры, это отдельный запрос
+	WithTotal bool
+}
+
+// UserCursor - позиция в списке: значение поля сортировки и ID последнего
+// пользователя страницы. Курсор помнит сортировку и не подходит к другой.
+type UserCursor struct {
+	SortBy     string    `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	Value      string    `json:"v"`
+	ID         uuid.UUID `json:"id"`
+}
+
+// UserPage - страница списка. NextCursor пуст на последней странице, Total
+// заполнен, только если его запрашивали.
+type UserPage struct {
+	Users      []User
+	NextCursor string
+	Total      *int
+}
+
+// ValidUserSort сообщает, можно ли сортировать по полю
+func ValidUserSort(field string) bool {
+	switch field {
+	case UserSortCreatedAt, UserSortEmail, UserSortNickname:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewUserCursor строит курсор, указывающий на пользователя u
+func NewUserCursor(u User, sortBy string, descending bool) UserCursor {
+	c := UserCursor{SortBy: sortBy, Descending: descending, ID: u.ID}
+	switch sortBy {
+	case UserSortEmail:
+		c.Value = u.Email
+	case UserSortNickname:
+		c.Value = u.Nickname
+	default:
+		c.Value = u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return c
+}
+
+// Encode - непрозрачная строка для параметра cursor
+func (c UserCursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// CreatedAt разбирает значение курсора при сортировке по дате создания
+func (c UserCursor) CreatedAt() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return time.Time{}, ErrInvalidUserCursor
+	}
+	return t, nil
+}
+
+// DecodeUserCursor разбирает курсор и проверяет, что он выдан для той же
+// сортировки
+func DecodeUserCursor(s, sortBy string, descending bool) (*UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidUserCursor
+	}
+
+	var c UserCursor
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return nil, ErrInvalidUserCursor
+	}
+	if c.SortBy != sortBy || c.Descending != descending || c.ID == uuid.Nil {
+		return nil, ErrInvalidUserCursor
+	}
+	if c.SortBy == UserSortCreatedAt {
+		if _, err = c.CreatedAt(); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
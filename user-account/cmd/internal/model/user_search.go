@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Размер выдачи поиска пользователей
+const (
+	DefaultUserSearchLimit = 20
+	MaxUserSearchLimit     = 50
+	// MaxUserSearchLength - предел длины строки поиска в символах
+	MaxUserSearchLength = 100
+)
+
+// UserSearchQuery - поиск среди участников организации по части ника или
+// адреса почты, в том числе с опечатками
+type UserSearchQuery struct {
+	OrganizationID uuid.UUID
+	Text           string
+	Limit          int
+}
+
+// TextRange - совпавший фрагмент строки [Start, End), позиции в символах
+type TextRange struct {
+	Start int
+	End   int
+}
+
+// MarshalJSON записывает фрагмент парой [start, end]
+func (r TextRange) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]int{r.Start, r.End})
+}
+
+// UserHighlights - фрагменты ника и адреса, совпавшие со словами поиска
+type UserHighlights struct {
+	Nickname []TextRange `json:"nickname"`
+	Email    []TextRange `json:"email"`
+}
+
+// UserSearchHit - найденный пользователь. Чем больше Rank, тем выше он в
+// выдаче.
+type UserSearchHit struct {
+	User       User
+	Rank       float64
+	Highlights UserHighlights
+}
+
+// SearchTerms разбивает строку поиска на слова в нижнем регистре. Всё,
+// кроме букв и цифр, считается разделителем.
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.Map(unicode.ToLower, text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Highlight находит в text вхождения слов terms без учёта регистра.
+// Пересекающиеся и соседние фрагменты склеиваются. Опечатки не
+// подсвечиваются: у таких совпадений фрагментов нет.
+func Highlight(text string, terms []string) []TextRange {
+	runes := []rune(strings.Map(unicode.ToLower, text))
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		needle := []rune(term)
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(runes); i++ {
+			if string(runes[i:i+len(needle)]) == term {
+				for j := i; j < i+len(needle); j++ {
+					marked[j] = true
+				}
+			}
+		}
+	}
+
+	ranges := []TextRange{}
+	for i := 0; i < len(marked); i++ {
+		if !marked[i] {
+			continue
+		}
+		start := i
+		for i < len(marked) && marked[i] {
+			i++
+		}
+		ranges = append(ranges, TextRange{Start: start, End: i})
+	}
+	return ranges
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"time"
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	"github.com/google/uuid"
+)
+
+// Статусы аккаунта
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusLocked    = "locked"
+)
+
+// MaxStatusReasonLength - предел длины причины смены статуса в символах
+const MaxStatusReasonLength = 500
+
+// UserStatusChange - запись истории статусов: кто, когда и почему сменил
+// статус пользователя. ChangedBy пуст, если автора уже удалили.
+type UserStatusChange struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Status    string     `json:"status"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	ChangedBy *uuid.UUID `json:"changed_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsUserStatus проверяет, что статус из известного набора
+func IsUserStatus(status string) bool {
+	switch status {
+	case UserStatusActive, UserStatusSuspended, UserStatusLocked:
+		return true
+	}
+	return false
+}
+
+// UserStatusChangeToDomain - из модельки базы в доменную модель
+func UserStatusChangeToDomain(h jet_model.UserStatusHistory) UserStatusChange {
+	var createdAt time.Time
+	if h.CreatedAt != nil {
+		createdAt = *h.CreatedAt
+	}
+	return UserStatusChange{
+		ID:        h.ID,
+		UserID:    h.UserID,
+		Status:    h.Status,
+		Reason:    h.Reason,
+		ExpiresAt: h.ExpiresAt,
+		ChangedBy: h.ChangedBy,
+		CreatedAt: createdAt,
+	}
+}
@@ -0,0 +1,173 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// refreshCooldown - не чаще этого ключи провайдера перезапрашиваются из-за
+// незнакомого kid, чтобы поддельные токены не превращались в лавину запросов
+const refreshCooldown = 10 * time.Second
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// jwk - ключ из JWKS провайдера (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet - кэш публичных ключей провайдера. Провайдеры ротируют ключи,
+// поэтому незнакомый kid - повод перечитать jwks_uri.
+type keySet struct {
+	client   *http.Client
+	uri      string
+	cooldown time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func newKeySet(client *http.Client, uri string, cooldown time.Duration) *keySet {
+	return &keySet{client: client, uri: uri, cooldown: cooldown}
+}
+
+// key ищет ключ по kid. Токен без kid принимается, только если у провайдера
+// ровно один ключ.
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if found, ok := s.lookup(kid); ok {
+		return found, nil
+	}
+	if s.keys != nil && time.Since(s.lastRefresh) < s.cooldown {
+		return nil, errUnknownKey
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if found, ok := s.lookup(kid); ok {
+		return found, nil
+	}
+	return nil, errUnknownKey
+}
+
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" {
+		if len(s.keys) != 1 {
+			return nil, false
+		}
+		for _, found := range s.keys {
+			return found, true
+		}
+	}
+	found, ok := s.keys[kid]
+	return found, ok
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	s.lastRefresh = time.Now()
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.uri, &set); err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		public, err := k.publicKey()
+		if err != nil {
+			// Ключ неизвестного типа не должен ломать проверку остальных
+			continue
+		}
+		keys[k.Kid] = public
+	}
+	s.keys = keys
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("ec point is not on the curve")
+		}
+		return public, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("invalid key component")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
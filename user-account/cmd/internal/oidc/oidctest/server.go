@@ -0,0 +1,223 @@
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "test-client"
+	ClientSecret = "test-secret"
+)
+
+// Identity - пользователь, от имени которого провайдер выдаёт ID-токен
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// grant - выданный, но ещё не обменянный код авторизации
+type grant struct {
+	identity      Identity
+	nonce         string
+	codeChallenge string
+	redirectURI   string
+}
+
+// Server - поддельный провайдер OpenID Connect в памяти процесса для
+// тестов: discovery, JWKS, выдача кода и обмен его на ID-токен с проверкой
+// PKCE. Вход пользователя у провайдера заменяет Authorize.
+type Server struct {
+	*httptest.Server
+
+	// TamperClaims, если задана, правит claims перед подписью ID-токена
+	TamperClaims func(claims jwt.MapClaims)
+
+	mu     sync.Mutex
+	key    *rsa.PrivateKey
+	kid    string
+	codes  map[string]grant
+	serial int
+}
+
+// NewServer запускает провайдер и останавливает его по окончании теста
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{codes: make(map[string]grant)}
+	s.RotateKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/token", s.token)
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Issuer - идентификатор провайдера
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// RotateKey заменяет ключ подписи; прежний пропадает из JWKS
+func (s *Server) RotateKey(t testing.TB) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serial++
+	s.key = key
+	s.kid = "key-" + strconv.Itoa(s.serial)
+}
+
+// Authorize изображает вход пользователя у провайдера по адресу из
+// AuthCodeURL и возвращает код и state, с которыми провайдер перенаправил
+// бы пользователя обратно
+func (s *Server) Authorize(t testing.TB, authURL string, identity Identity) (code, state string) {
+	t.Helper()
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	query := parsed.Query()
+	if query.Get("client_id") != ClientID {
+		t.Fatalf("unexpected client_id %q", query.Get("client_id"))
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("expected authorization code flow with PKCE S256, got %s", parsed.RawQuery)
+	}
+
+	code = rand.Text()
+	s.mu.Lock()
+	s.codes[code] = grant{
+		identity:      identity,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		redirectURI:   query.Get("redirect_uri"),
+	}
+	s.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	public := s.key.PublicKey
+	kid := s.kid
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if ok {
+		id, _ = url.QueryUnescape(id)
+		secret, _ = url.QueryUnescape(secret)
+	}
+	if !ok || id != ClientID || secret != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	s.mu.Lock()
+	g, found := s.codes[code]
+	// Код одноразовый
+	delete(s.codes, code)
+	key, kid := s.key, s.kid
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !found ||
+		g.redirectURI != r.PostForm.Get("redirect_uri") ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                s.URL,
+		"aud":                ClientID,
+		"sub":                g.identity.Subject,
+		"nonce":              g.nonce,
+		"email":              g.identity.Email,
+		"email_verified":     g.identity.EmailVerified,
+		"name":               g.identity.Name,
+		"preferred_username": g.identity.PreferredUsername,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+	}
+	if s.TamperClaims != nil {
+		s.TamperClaims(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	idToken, err := token.SignedString(key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(payload)
+}
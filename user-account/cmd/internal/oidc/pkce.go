@@ -0,0 +1,26 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// verifierBytes - 32 случайных байта дают code_verifier из 43 символов,
+// минимально допустимой по RFC 7636 длины
+const verifierBytes = 32
+
+// NewCodeVerifier генерирует PKCE code_verifier (RFC 7636, 4.1)
+func NewCodeVerifier() (string, error) {
+	buf := make([]byte, verifierBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CodeChallenge - code_challenge для метода S256
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
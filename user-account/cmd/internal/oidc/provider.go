@@ -0,0 +1,326 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	discoveryPath   = "/.well-known/openid-configuration"
+	maxResponseSize = 1 << 20
+	clockSkew       = time.Minute
+	defaultTimeout  = 10 * time.Second
+)
+
+var (
+	ErrDiscovery      = errors.New("oidc discovery failed")
+	ErrExchange       = errors.New("authorization code exchange failed")
+	ErrInvalidIDToken = errors.New("invalid id token")
+)
+
+// DefaultScopes - scopes, которые запрашиваются, если в Config не заданы свои
+var DefaultScopes = []string{"openid", "email", "profile"}
+
+// idTokenAlgorithms - допустимые алгоритмы подписи ID-токена. none и HMAC
+// не принимаются: проверка идёт только по опубликованным ключам провайдера.
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Config - настройки клиента у одного провайдера
+type Config struct {
+	// Issuer - идентификатор провайдера, из него строится адрес discovery
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// Scopes - запрашиваемые scopes; openid добавляется всегда
+	Scopes []string
+}
+
+// AuthRequest - параметры перенаправления пользователя к провайдеру
+type AuthRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string
+	RedirectURI   string
+}
+
+// ExchangeRequest - параметры обмена кода авторизации на токены. Nonce и
+// CodeVerifier - те же, что были у AuthRequest.
+type ExchangeRequest struct {
+	Code         string
+	CodeVerifier string
+	RedirectURI  string
+	Nonce        string
+}
+
+// Claims - сведения о пользователе из проверенного ID-токена
+type Claims struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// metadata - нужная часть документа discovery
+type metadata struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// Provider - клиент (relying party) одного провайдера OpenID Connect:
+// код авторизации с PKCE, discovery и проверка ID-токена по JWKS провайдера.
+// Discovery выполняется при первом обращении, а не при старте: недоступный
+// провайдер не мешает запуску сервиса и входу по паролю.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+	// keysCooldown - пауза между перечитываниями JWKS из-за незнакомого kid
+	keysCooldown time.Duration
+
+	mu   sync.Mutex
+	meta *metadata
+	keys *keySet
+}
+
+// NewProvider создаёт клиента провайдера. client может быть nil.
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+	return &Provider{cfg: cfg, client: client, keysCooldown: refreshCooldown}
+}
+
+// AuthCodeURL возвращает адрес, на который нужно перенаправить пользователя
+func (p *Provider) AuthCodeURL(ctx context.Context, req AuthRequest) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint, err := url.Parse(meta.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: authorization endpoint: %v", ErrDiscovery, err)
+	}
+	query := endpoint.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", req.RedirectURI)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", req.State)
+	query.Set("nonce", req.Nonce)
+	query.Set("code_challenge", req.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	endpoint.RawQuery = query.Encode()
+	return endpoint.String(), nil
+}
+
+// Exchange обменивает код авторизации на токены и возвращает сведения
+// из проверенного ID-токена
+func (p *Provider) Exchange(ctx context.Context, req ExchangeRequest) (*Claims, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {req.Code},
+		"redirect_uri":  {req.RedirectURI},
+		"code_verifier": {req.CodeVerifier},
+	}
+	useBasic := supportsBasicAuth(meta.TokenAuthMethods)
+	if !useBasic {
+		form.Set("client_id", p.cfg.ClientID)
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	if useBasic {
+		// RFC 6749, 2.3.1: перед Basic-авторизацией id и секрет кодируются
+		httpReq.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: status %d", ErrExchange, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrExchange, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id_token in response", ErrExchange)
+	}
+
+	return p.verifyIDToken(ctx, meta, body.IDToken, req.Nonce)
+}
+
+// idTokenClaims - claims ID-токена. email_verified некоторые провайдеры
+// отдают строкой.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string   `json:"nonce"`
+	AuthorizedParty   string   `json:"azp"`
+	Email             string   `json:"email"`
+	EmailVerified     flexBool `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+}
+
+// verifyIDToken проверяет подпись, издателя, аудиторию, срок действия и nonce
+// (OpenID Connect Core, 3.1.3.7)
+func (p *Provider) verifyIDToken(ctx context.Context, meta *metadata, raw, nonce string) (*Claims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+
+	var claims idTokenClaims
+	if _, err := parser.ParseWithClaims(raw, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.key(ctx, kid)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	if nonce == "" || claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: azp does not match client id", ErrInvalidIDToken)
+	}
+
+	return &Claims{
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		Name:              claims.Name,
+		PreferredUsername: claims.PreferredUsername,
+	}, nil
+}
+
+// discover загружает документ discovery. Удачный результат кэшируется,
+// неудачный - нет, следующий вход попробует снова.
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.meta != nil {
+		return p.meta, nil
+	}
+
+	var meta metadata
+	if err := getJSON(ctx, p.client, strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, &meta); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	// Издатель в документе должен совпадать с настроенным, иначе документ
+	// может подсунуть чужие ключи (OpenID Connect Discovery, 4.3)
+	if meta.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscovery, meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete provider metadata", ErrDiscovery)
+	}
+
+	p.meta = &meta
+	p.keys = newKeySet(p.client, meta.JWKSURI, p.keysCooldown)
+	return p.meta, nil
+}
+
+func (p *Provider) scopes() []string {
+	scopes := p.cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	for _, scope := range scopes {
+		if scope == "openid" {
+			return scopes
+		}
+	}
+	return append([]string{"openid"}, scopes...)
+}
+
+// supportsBasicAuth - client_secret_basic используется по умолчанию, если
+// провайдер не объявил способы аутентификации или объявил этот
+func supportsBasicAuth(methods []string) bool {
+	if len(methods) == 0 {
+		return true
+	}
+	for _, method := range methods {
+		if method == "client_secret_basic" {
+			return true
+		}
+	}
+	return false
+}
+
+func getJSON(ctx context.Context, client *http.Client, endpoint string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(dest)
+}
+
+// flexBool принимает как true, так и "true"
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = flexBool(v)
+	case string:
+		*b = flexBool(strings.EqualFold(v, "true"))
+	default:
+		*b = false
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"user-account/cmd/internal/oidc/oidctest"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURI = "https://app.example.com/oauth/callback"
+
+var alice = oidctest.Identity{
+	Subject:           "alice-123",
+	Email:             "alice@example.com",
+	EmailVerified:     true,
+	Name:              "Alice",
+	PreferredUsername: "alice",
+}
+
+func newProvider(server *oidctest.Server) *Provider {
+	return NewProvider(Config{
+		Issuer:       server.Issuer(),
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+	}, server.Client())
+}
+
+// login проходит весь поток: адрес авторизации, вход у провайдера, обмен кода
+func login(t *testing.T, server *oidctest.Server, provider *Provider, identity oidctest.Identity) (*Claims, error) {
+	t.Helper()
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(context.Background(), AuthRequest{
+		State:         "state-1",
+		Nonce:         "nonce-1",
+		CodeChallenge: CodeChallenge(verifier),
+		RedirectURI:   redirectURI,
+	})
+	require.NoError(t, err)
+
+	code, state := server.Authorize(t, authURL, identity)
+	assert.Equal(t, "state-1", state)
+
+	return provider.Exchange(context.Background(), ExchangeRequest{
+		Code:         code,
+		CodeVerifier: verifier,
+		RedirectURI:  redirectURI,
+		Nonce:        "nonce-1",
+	})
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	t.Parallel()
+	server := oidctest.NewServer(t)
+
+	authURL, err := newProvider(server).AuthCodeURL(context.Background(), AuthRequest{
+		State:         "s",
+		Nonce:         "n",
+		CodeChallenge: "c",
+		RedirectURI:   redirectURI,
+	})
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, oidctest.ClientID, query.Get("client_id"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, redirectURI, query.Get("redirect_uri"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	t.Parallel()
+	server := oidctest.NewServer(t)
+
+	claims, err := login(t, server, newProvider(server), alice)
+	require.NoError(t, err)
+	assert.Equal(t, &Claims{
+		Subject:           "alice-123",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		Name:              "Alice",
+		PreferredUsername: "alice",
+	}, claims)
+}
+
+func TestProvider_Exchange_RejectsInvalidTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		tamper func(claims jwt.MapClaims)
+	}{
+		{name: "Wrong Issuer", tamper: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "Wrong Audience", tamper: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "Expired", tamper: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "Nonce Mismatch", tamper: func(c jwt.MapClaims) { c["nonce"] = "replayed" }},
+		{name: "Missing Subject", tamper: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "Foreign Azp", tamper: func(c jwt.MapClaims) {
+			c["aud"] = []string{oidctest.ClientID, "other-client"}
+			c["azp"] = "other-client"
+		}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := oidctest.NewServer(t)
+			server.TamperClaims = tt.tamper
+
+			_, err := login(t, server, newProvider(server), alice)
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	t.Parallel()
+	server := oidctest.NewServer(t)
+	provider := newProvider(server)
+
+	verifier, err := NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := provider.AuthCodeURL(context.Background(), AuthRequest{
+		State: "s", Nonce: "n", CodeChallenge: CodeChallenge(verifier), RedirectURI: redirectURI,
+	})
+	require.NoError(t, err)
+	code, _ := server.Authorize(t, authURL, alice)
+
+	// Перехваченный код без code_verifier бесполезен
+	_, err = provider.Exchange(context.Background(), ExchangeRequest{
+		Code: code, CodeVerifier: "stolen-code-without-verifier", RedirectURI: redirectURI, Nonce: "n",
+	})
+	assert.ErrorIs(t, err, ErrExchange)
+}
+
+func TestProvider_Exchange_FollowsKeyRotation(t *testing.T) {
+	t.Parallel()
+	server := oidctest.NewServer(t)
+	provider := newProvider(server)
+
+	// Без паузы между перечитываниями ключей, чтобы не ждать её в тесте
+	provider.keysCooldown = 0
+
+	_, err := login(t, server, provider, alice)
+	require.NoError(t, err)
+
+	// Новый kid незнаком кэшу: ключи должны перечитаться
+	server.RotateKey(t)
+	_, err = login(t, server, provider, alice)
+	assert.NoError(t, err)
+}
+
+func TestProvider_DiscoveryFailure(t *testing.T) {
+	t.Parallel()
+	server := oidctest.NewServer(t)
+
+	provider := NewProvider(Config{
+		Issuer:   server.Issuer() + "/other-realm",
+		ClientID: oidctest.ClientID,
+	}, server.Client())
+
+	_, err := provider.AuthCodeURL(context.Background(), AuthRequest{})
+	assert.True(t, errors.Is(err, ErrDiscovery))
+}
+
+func TestCodeVerifier(t *testing.T) {
+	t.Parallel()
+
+	first, err := NewCodeVerifier()
+	assert.NoError(t, err)
+	second, err := NewCodeVerifier()
+	assert.NoError(t, err)
+
+	// RFC 7636: от 43 до 128 символов из [A-Za-z0-9-._~]
+	assert.Len(t, first, 43)
+	assert.Regexp(t, `^[A-Za-z0-9\-._~]+$`, first)
+	assert.NotEqual(t, first, second)
+	assert.Len(t, CodeChallenge(first), 43)
+	assert.Equal(t, CodeChallenge(first), CodeChallenge(first))
+	assert.NotEqual(t, CodeChallenge(first), CodeChallenge(second))
+}
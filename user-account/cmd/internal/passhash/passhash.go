@@ -0,0 +1,188 @@
+package passhash
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrMalformedHash   = errors.New("malformed password hash")
+	ErrUnsupportedHash = errors.New("unsupported password hash algorithm")
+	ErrPepperRequired  = errors.New("password hash requires a pepper")
+)
+
+// Params - параметры argon2id
+type Params struct {
+	// Memory - память на один хэш, КиБ
+	Memory uint32
+	// Iterations - число проходов по памяти
+	Iterations uint32
+	// Parallelism - число потоков
+	Parallelism uint8
+	// SaltLength - длина соли, байт
+	SaltLength uint32
+	// KeyLength - длина хэша, байт
+	KeyLength uint32
+}
+
+// DefaultParams - параметры по умолчанию, второй вариант из RFC 9106
+// для окружений с ограниченной памятью
+var DefaultParams = Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Hasher хэширует пароли argon2id в формате PHC:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<соль>$<хэш>
+//	$argon2id$v=19$m=65536,t=3,p=2,pepper=1$<соль>$<хэш>
+//
+// Параметры записаны в самом хэше, поэтому их можно менять без миграции:
+// старые хэши проверяются со своими параметрами, а Verify сообщает, что
+// хэш пора пересчитать. Так же проверяются хэши bcrypt, которыми сервис
+// пользовался раньше. pepper=1 отмечает хэш, посчитанный с pepper.
+type Hasher struct {
+	params Params
+	pepper []byte
+}
+
+// New создаёт Hasher. pepper - необязательный секрет сервера: пароль
+// перед хэшированием подписывается им через HMAC-SHA256, и утёкшая без
+// него база не поддаётся перебору. Включить pepper можно в любой момент:
+// хэши без него проверяются как раньше и просят пересчёта. Сменить или
+// убрать pepper нельзя - хэши, посчитанные с прежним, перестанут сходиться.
+func New(params Params, pepper []byte) *Hasher {
+	return &Hasher{params: params, pepper: pepper}
+}
+
+// Hash возвращает хэш пароля в формате PHC
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey(h.secret(password, h.peppered()), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	pepperParam := ""
+	if h.peppered() {
+		pepperParam = ",pepper=1"
+	}
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d%s$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Iterations, h.params.Parallelism, pepperParam,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify сверяет пароль с хэшем. needsRehash - пароль верный, но хэш
+// посчитан устаревшим алгоритмом, с другими параметрами или не так, как
+// сейчас, использует pepper; его стоит пересчитать через Hash, пока
+// открытый пароль под рукой. Хэш с pepper без настроенного pepper не
+// проверить - это ErrPepperRequired.
+func (h *Hasher) Verify(password, encoded string) (ok, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return h.verifyArgon2id(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return verifyBcrypt(password, encoded)
+	default:
+		return false, false, ErrUnsupportedHash
+	}
+}
+
+func (h *Hasher) verifyArgon2id(password, encoded string) (bool, bool, error) {
+	params, peppered, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, false, err
+	}
+	if peppered && !h.peppered() {
+		return false, false, ErrPepperRequired
+	}
+
+	actual := argon2.IDKey(h.secret(password, peppered), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(actual, key) != 1 {
+		return false, false, nil
+	}
+	return true, params != h.params || peppered != h.peppered(), nil
+}
+
+// verifyBcrypt проверяет хэш bcrypt. Такие хэши посчитаны без pepper и
+// всегда требуют пересчёта.
+func verifyBcrypt(password, encoded string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, fmt.Errorf("%w: %v", ErrMalformedHash, err)
+	}
+	return true, true, nil
+}
+
+// peppered сообщает, считаются ли новые хэши с pepper
+func (h *Hasher) peppered() bool {
+	return len(h.pepper) > 0
+}
+
+// secret - то, что хэшируется: сам пароль или его HMAC с pepper
+func (h *Hasher) secret(password string, peppered bool) []byte {
+	if !peppered {
+		return []byte(password)
+	}
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+// decodeArgon2id разбирает $argon2id$v=19$m=...,t=...,p=...[,pepper=1]$salt$key.
+// peppered - хэш посчитан с pepper.
+func decodeArgon2id(encoded string) (params Params, peppered bool, salt, key []byte, err error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+	if version != argon2.Version {
+		return Params{}, false, nil, nil, fmt.Errorf("%w: argon2 version %d", ErrUnsupportedHash, version)
+	}
+
+	paramsPart, peppered := strings.CutSuffix(parts[3], ",pepper=1")
+	var rest string
+	n, _ := fmt.Sscanf(paramsPart, "m=%d,t=%d,p=%d%s", &params.Memory, &params.Iterations, &params.Parallelism, &rest)
+	if n != 3 {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return Params{}, false, nil, nil, ErrMalformedHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, peppered, salt, key, nil
+}
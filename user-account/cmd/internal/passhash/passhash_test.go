@@ -0,0 +1,164 @@
+package passhash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams - дешёвые параметры, чтобы тесты не тратили по 64 МиБ на хэш
+var testParams = Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func TestHasher_HashAndVerify(t *testing.T) {
+	t.Parallel()
+	h := New(testParams, nil)
+
+	hash, err := h.Hash("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	other, err := h.Hash("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "every hash gets its own salt")
+
+	ok, rehash, err := h.Verify("correct horse battery staple", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, _, err = h.Verify("wrong password", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_LongPasswords(t *testing.T) {
+	t.Parallel()
+	h := New(testParams, nil)
+
+	// bcrypt отбрасывает всё после 72 байт, argon2id - нет
+	prefix := strings.Repeat("a", 72)
+	hash, err := h.Hash(prefix + "1")
+	assert.NoError(t, err)
+
+	ok, _, err := h.Verify(prefix+"2", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_Pepper(t *testing.T) {
+	t.Parallel()
+	peppered := New(testParams, []byte("server-secret"))
+
+	hash, err := peppered.Hash("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1,pepper=1$"))
+
+	ok, rehash, err := peppered.Verify("password", hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, _, err = New(testParams, nil).Verify("password", hash)
+	assert.ErrorIs(t, err, ErrPepperRequired, "hash must not verify without the pepper")
+	assert.False(t, ok)
+
+	ok, _, err = New(testParams, []byte("other-secret")).Verify("password", hash)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_NeedsRehash(t *testing.T) {
+	t.Parallel()
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	weak, err := New(testParams, nil).Hash("password")
+	assert.NoError(t, err)
+
+	stronger := testParams
+	stronger.Iterations = 2
+	h := New(stronger, []byte("server-secret"))
+
+	tests := []struct {
+		name       string
+		password   string
+		hash       string
+		wantOK     bool
+		wantRehash bool
+	}{
+		{name: "Legacy Bcrypt", password: "password", hash: string(legacy), wantOK: true, wantRehash: true},
+		{name: "Legacy Bcrypt Wrong Password", password: "wrong", hash: string(legacy), wantOK: false, wantRehash: false},
+		{name: "Outdated Argon2 Parameters Without Pepper", password: "password", hash: weak, wantOK: true, wantRehash: true},
+		{name: "Hash Without Pepper Wrong Password", password: "wrong", hash: weak, wantOK: false, wantRehash: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ok, rehash, err := h.Verify(tt.password, tt.hash)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRehash, rehash)
+		})
+	}
+
+	// Без смены pepper хэш со старыми параметрами сходится, но просит пересчёта
+	ok, rehash, err := New(stronger, nil).Verify("password", weak)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+}
+
+func TestHasher_Verify_MalformedHash(t *testing.T) {
+	t.Parallel()
+	h := New(testParams, nil)
+
+	tests := []struct {
+		name    string
+		hash    string
+		wantErr error
+	}{
+		{name: "Empty", hash: "", wantErr: ErrUnsupportedHash},
+		{name: "Unknown Algorithm", hash: "$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA", wantErr: ErrUnsupportedHash},
+		{name: "Missing Parts", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA", wantErr: ErrMalformedHash},
+		{name: "Wrong Version", hash: "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$aGFzaA", wantErr: ErrUnsupportedHash},
+		{name: "Bad Parameters", hash: "$argon2id$v=19$m=64,t=0,p=1$c2FsdA$aGFzaA", wantErr: ErrMalformedHash},
+		{name: "Unknown Parameter", hash: "$argon2id$v=19$m=64,t=1,p=1,x=1$c2FsdA$aGFzaA", wantErr: ErrMalformedHash},
+		{name: "Bad Salt Encoding", hash: "$argon2id$v=19$m=64,t=1,p=1$!!!$aGFzaA", wantErr: ErrMalformedHash},
+		{name: "Truncated Bcrypt", hash: "$2a$10$short", wantErr: ErrMalformedHash},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ok, _, err := h.Verify("password", tt.hash)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestHasher_PepperEnabledLater(t *testing.T) {
+	t.Parallel()
+
+	plain, err := New(testParams, nil).Hash("password")
+	assert.NoError(t, err)
+
+	// Хэш без pepper проверяется как раньше и просит пересчёта с pepper
+	h := New(testParams, []byte("server-secret"))
+	ok, rehash, err := h.Verify("password", plain)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	rehashed, err := h.Hash("password")
+	assert.NoError(t, err)
+	ok, rehash, err = h.Verify("password", rehashed)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+}
@@ -0,0 +1,65 @@
+package passpolicy
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// corpusPrefixLen - длина префикса SHA-1, по которому хэши разложены по файлам
+const corpusPrefixLen = 5
+
+// DirCorpus - локальная база утёкших паролей в раскладке Pwned Passwords:
+// хэши SHA-1 разложены по файлам <PREFIX>.txt по первым пяти hex-символам,
+// в файле - строки SUFFIX:COUNT. Такую раскладку выгружает
+// haveibeenpwned-downloader. На проверку читается один файл, а сам пароль
+// и даже его полный хэш никуда не уходят.
+type DirCorpus struct {
+	dir string
+}
+
+// NewDirCorpus открывает базу в каталоге dir
+func NewDirCorpus(dir string) (*DirCorpus, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("breached password corpus %s is not a directory", dir)
+	}
+	return &DirCorpus{dir: dir}, nil
+}
+
+// Breached ищет SHA-1 пароля в файле его префикса. Нет файла - нет и
+// утёкших паролей с таким префиксом.
+func (c *DirCorpus) Breached(_ context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:corpusPrefixLen], digest[corpusPrefixLen:]
+
+	f, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		hash, _, _ := strings.Cut(line, ":")
+		if strings.EqualFold(hash, suffix) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,203 @@
+package passpolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Правила политики. Идентификаторы стабильны: по ним фронтенд выбирает
+// перевод сообщения.
+const (
+	RuleMinLength    = "min_length"
+	RuleMaxLength    = "max_length"
+	RuleCharClasses  = "char_classes"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+)
+
+// minPersonalFragment - фрагменты ника и почты короче этого не проверяются,
+// иначе под запрет попадали бы обычные слоги
+const minPersonalFragment = 4
+
+var ErrPolicyViolated = errors.New("password does not meet the policy")
+
+// Violation - нарушенное правило. Message - сообщение по-английски, Params -
+// числа для подстановки в перевод.
+type Violation struct {
+	Rule    string         `json:"rule"`
+	Message string         `json:"message"`
+	Params  map[string]int `json:"params,omitempty"`
+}
+
+// Error - пароль не прошёл политику. errors.Is(err, ErrPolicyViolated)
+// срабатывает и для неё.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrPolicyViolated
+}
+
+// Config - требования к паролю
+type Config struct {
+	// MinLength и MaxLength - границы длины в символах
+	MinLength int
+	MaxLength int
+	// MinCharClasses - сколько разных классов символов из четырёх (строчные,
+	// заглавные, цифры, прочие) должно быть в пароле
+	MinCharClasses int
+	// ForbidPersonal запрещает пароли, содержащие ник или части почты
+	ForbidPersonal bool
+}
+
+// BreachChecker сообщает, встречался ли пароль в утечках
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// Policy проверяет пароли на соответствие Config и, если задан breaches,
+// по базе утёкших паролей
+type Policy struct {
+	cfg      Config
+	breaches BreachChecker
+}
+
+func New(cfg Config, breaches BreachChecker) *Policy {
+	return &Policy{cfg: cfg, breaches: breaches}
+}
+
+// Check возвращает *Error со всеми нарушенными правилами или nil. personal -
+// ник, почта и прочие сведения о пользователе, которых не должно быть в
+// пароле.
+func (p *Policy) Check(ctx context.Context, password string, personal ...string) error {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		violations = append(violations, Violation{
+			Rule:    RuleMinLength,
+			Message: fmt.Sprintf("password must be at least %d characters long", p.cfg.MinLength),
+			Params:  map[string]int{"min": p.cfg.MinLength},
+		})
+	}
+	if p.cfg.MaxLength > 0 && length > p.cfg.MaxLength {
+		violations = append(violations, Violation{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("password must be at most %d characters long", p.cfg.MaxLength),
+			Params:  map[string]int{"max": p.cfg.MaxLength},
+		})
+	}
+	if classes := charClasses(password); classes < p.cfg.MinCharClasses {
+		violations = append(violations, Violation{
+			Rule: RuleCharClasses,
+			Message: fmt.Sprintf(
+				"password must mix at least %d of: lowercase letters, uppercase letters, digits, symbols",
+				p.cfg.MinCharClasses,
+			),
+			Params: map[string]int{"min": p.cfg.MinCharClasses},
+		})
+	}
+	if p.cfg.ForbidPersonal && containsPersonal(password, personal) {
+		violations = append(violations, Violation{
+			Rule:    RulePersonalInfo,
+			Message: "password must not contain your nickname or email",
+		})
+	}
+	if p.breached(ctx, password) {
+		violations = append(violations, Violation{
+			Rule:    RuleBreached,
+			Message: "password has appeared in a data breach, choose another one",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &Error{Violations: violations}
+	}
+	return nil
+}
+
+// breached проверяет пароль по базе утечек. Если база недоступна, пароль
+// пропускается: лучше временно не проверять утечки, чем не дать сменить пароль.
+func (p *Policy) breached(ctx context.Context, password string) bool {
+	if p.breaches == nil {
+		return false
+	}
+	found, err := p.breaches.Breached(ctx, password)
+	if err != nil {
+		log.Printf("breached password check failed: %v", err)
+		return false
+	}
+	return found
+}
+
+func charClasses(password string) int {
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
+
+// containsPersonal ищет в пароле ник, локальную часть почты и её слова,
+// без учёта регистра
+func containsPersonal(password string, personal []string) bool {
+	lowered := strings.ToLower(password)
+	for _, value := range personal {
+		for _, fragment := range personalFragments(value) {
+			if strings.Contains(lowered, fragment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// personalFragments - "John.Smith@example.com" -> john.smith, john, smith
+func personalFragments(value string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if at := strings.LastIndex(value, "@"); at >= 0 {
+		value = value[:at]
+	}
+
+	candidates := append([]string{value}, strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})...)
+
+	fragments := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		if utf8.RuneCountInString(c) >= minPersonalFragment {
+			fragments = append(fragments, c)
+		}
+	}
+	return fragments
+}
@@ -0,0 +1,103 @@
+package passpolicy
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubBreaches struct {
+	breached map[string]bool
+	err      error
+}
+
+func (s stubBreaches) Breached(_ context.Context, password string) (bool, error) {
+	return s.breached[password], s.err
+}
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+
+	policy := New(Config{MinLength: 8, MaxLength: 20, MinCharClasses: 3, ForbidPersonal: true}, stubBreaches{
+		breached: map[string]bool{"Password1!": true},
+	})
+
+	tests := []struct {
+		name      string
+		password  string
+		personal  []string
+		wantRules []string
+	}{
+		{name: "Valid", password: "Tr0ub4dor&3", personal: []string{"wanderer", "john.smith@example.com"}},
+		{name: "Unicode Counts Characters Not Bytes", password: "Пароль-ёж1", wantRules: nil},
+		{name: "Too Short", password: "Ab1!", wantRules: []string{RuleMinLength}},
+		{name: "Too Long", password: "Abcdefghij1234567890!", wantRules: []string{RuleMaxLength}},
+		{name: "Too Few Classes", password: "alllowercase1", wantRules: []string{RuleCharClasses}},
+		{name: "Contains Nickname", password: "WANDERER-2024", personal: []string{"wanderer"}, wantRules: []string{RulePersonalInfo}},
+		{name: "Contains Email Word", password: "Smith#2024x", personal: []string{"john.smith@example.com"}, wantRules: []string{RulePersonalInfo}},
+		{name: "Short Fragments Are Ignored", password: "Jo#2024xyz", personal: []string{"jo.li@example.com"}},
+		{name: "Breached", password: "Password1!", wantRules: []string{RuleBreached}},
+		{name: "Several Rules At Once", password: "abc", wantRules: []string{RuleMinLength, RuleCharClasses}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := policy.Check(context.Background(), tt.password, tt.personal...)
+
+			if len(tt.wantRules) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrPolicyViolated)
+			var policyErr *Error
+			if assert.ErrorAs(t, err, &policyErr) {
+				rules := make([]string, len(policyErr.Violations))
+				for i, v := range policyErr.Violations {
+					rules[i] = v.Rule
+					assert.NotEmpty(t, v.Message)
+				}
+				assert.Equal(t, tt.wantRules, rules)
+			}
+		})
+	}
+}
+
+func TestPolicy_Check_BreachCheckerFailureIsIgnored(t *testing.T) {
+	t.Parallel()
+	policy := New(Config{MinLength: 8}, stubBreaches{err: errors.New("disk error")})
+
+	assert.NoError(t, policy.Check(context.Background(), "long enough"))
+}
+
+func TestDirCorpus(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "5BAA6.txt"), []byte(
+		"003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:10434004\r\n",
+	), 0o600))
+
+	corpus, err := NewDirCorpus(dir)
+	assert.NoError(t, err)
+
+	found, err := corpus.Breached(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = corpus.Breached(context.Background(), "passw0rd-not-in-bucket")
+	assert.NoError(t, err)
+	assert.False(t, found, "missing prefix file means no breached passwords with that prefix")
+
+	_, err = NewDirCorpus(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+	_, err = NewDirCorpus(filepath.Join(dir, "5BAA6.txt"))
+	assert.Error(t, err)
+}
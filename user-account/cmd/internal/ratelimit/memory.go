@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepEvery - через сколько запросов убирать полные корзины
+const memorySweepEvery = 1024
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	// fullAt - когда корзина наполнится, после этого её можно забыть
+	fullAt time.Time
+}
+
+// MemoryLimiter держит корзины в памяти процесса. У каждой реплики сервиса
+// свои корзины, так что общий лимит умножается на число реплик.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]bucket
+	calls   int
+	now     func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]bucket), now: time.Now}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, rate Rate) (Decision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.calls++
+	if l.calls%memorySweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = bucket{tokens: float64(rate.Limit), at: now}
+	}
+
+	tokens, decision := take(b.tokens, now.Sub(b.at), rate)
+	l.buckets[key] = bucket{tokens: tokens, at: now, fullAt: now.Add(decision.ResetAfter)}
+	return decision, nil
+}
+
+// sweep удаляет корзины, которые уже наполнились: новая корзина ничем от
+// них не отличается. Вызывается под mu.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !b.fullAt.After(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate - ёмкость корзины Limit запросов, которая целиком восстанавливается
+// за Period. Запросы можно тратить пачкой, но в среднем не чаще Limit/Period.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate разбирает запись вида "10/1m": 10 запросов в минуту
+func ParseRate(s string) (Rate, error) {
+	rawLimit, rawPeriod, found := strings.Cut(strings.TrimSpace(s), "/")
+	if !found {
+		return Rate{}, fmt.Errorf("rate %q must look like 10/1m", s)
+	}
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must start with a positive number of requests", s)
+	}
+	period, err := time.ParseDuration(rawPeriod)
+	if err != nil || period <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must end with a positive duration", s)
+	}
+	return Rate{Limit: limit, Period: period}, nil
+}
+
+func (r Rate) String() string {
+	return strconv.Itoa(r.Limit) + "/" + r.Period.String()
+}
+
+// Decision - результат попытки взять токен из корзины
+type Decision struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter - через сколько корзина снова будет полной
+	ResetAfter time.Duration
+	// RetryAfter - через сколько появится следующий токен, если запрос отклонён
+	RetryAfter time.Duration
+}
+
+// Limiter - хранилище корзин. key уже включает имя политики и клиента.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rate Rate) (Decision, error)
+}
+
+// take списывает токен из корзины, в которой было tokens на момент elapsed
+// назад, и возвращает новый остаток
+func take(tokens float64, elapsed time.Duration, rate Rate) (float64, Decision) {
+	perSecond := float64(rate.Limit) / rate.Period.Seconds()
+	tokens = math.Min(float64(rate.Limit), tokens+math.Max(elapsed.Seconds(), 0)*perSecond)
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+	return tokens, decide(allowed, tokens, rate)
+}
+
+// decide строит решение по остатку после попытки
+func decide(allowed bool, tokens float64, rate Rate) Decision {
+	perSecond := float64(rate.Limit) / rate.Period.Seconds()
+
+	decision := Decision{
+		Allowed:    allowed,
+		Limit:      rate.Limit,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: secondsToDuration((float64(rate.Limit) - tokens) / perSecond),
+	}
+	if !allowed {
+		decision.RetryAfter = secondsToDuration((1 - tokens) / perSecond)
+	}
+	return decision
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in       string
+		expected Rate
+		wantErr  bool
+	}{
+		{in: "10/1m", expected: Rate{Limit: 10, Period: time.Minute}},
+		{in: " 300/1h ", expected: Rate{Limit: 300, Period: time.Hour}},
+		{in: "10", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "ten/1m", wantErr: true},
+		{in: "10/minute", wantErr: true},
+		{in: "10/-1s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.in, func(t *testing.T) {
+			t.Parallel()
+			rate, err := ParseRate(tt.in)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.expected, rate)
+		})
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	t.Parallel()
+
+	limiters := map[string]func(t *testing.T, now func() time.Time) Limiter{
+		"memory": func(_ *testing.T, now func() time.Time) Limiter {
+			l := NewMemoryLimiter()
+			l.now = now
+			return l
+		},
+		"redis": func(t *testing.T, now func() time.Time) Limiter {
+			server := miniredis.RunT(t)
+			l := NewRedisLimiter(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+			l.now = now
+			return l
+		},
+	}
+
+	for name, newLimiter := range limiters {
+		newLimiter := newLimiter
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			rate := Rate{Limit: 3, Period: 3 * time.Second}
+
+			clock := time.Unix(1_700_000_000, 0)
+			limiter := newLimiter(t, func() time.Time { return clock })
+
+			for i := 2; i >= 0; i-- {
+				decision, err := limiter.Allow(ctx, "burst", rate)
+				assert.NoError(t, err)
+				assert.True(t, decision.Allowed)
+				assert.Equal(t, 3, decision.Limit)
+				assert.Equal(t, i, decision.Remaining)
+			}
+
+			decision, err := limiter.Allow(ctx, "burst", rate)
+			assert.NoError(t, err)
+			assert.False(t, decision.Allowed)
+			assert.Equal(t, time.Second, decision.RetryAfter.Round(time.Millisecond))
+			assert.Equal(t, 3*time.Second, decision.ResetAfter.Round(time.Millisecond))
+
+			other, err := limiter.Allow(ctx, "other", rate)
+			assert.NoError(t, err)
+			assert.True(t, other.Allowed, "buckets are independent")
+
+			clock = clock.Add(time.Second)
+			decision, err = limiter.Allow(ctx, "burst", rate)
+			assert.NoError(t, err)
+			assert.True(t, decision.Allowed, "one token refills per second")
+			assert.Equal(t, 0, decision.Remaining)
+
+			clock = clock.Add(time.Hour)
+			decision, err = limiter.Allow(ctx, "burst", rate)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, decision.Remaining, "refill is capped by the limit")
+		})
+	}
+}
+
+func TestMemoryLimiter_Sweep(t *testing.T) {
+	t.Parallel()
+
+	clock := time.Unix(1_700_000_000, 0)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return clock }
+	rate := Rate{Limit: 2, Period: time.Minute}
+
+	_, _ = limiter.Allow(context.Background(), "idle", rate)
+	clock = clock.Add(time.Minute)
+	_, _ = limiter.Allow(context.Background(), "active", rate)
+
+	limiter.sweep(clock)
+	assert.NotContains(t, limiter.buckets, "idle")
+	assert.Contains(t, limiter.buckets, "active")
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript - та же корзина, что и в take, но атомарно на стороне сервера,
+// чтобы реплики сервиса делили общий лимит. Время передаётся из приложения:
+// не все Redis-совместимые серверы разрешают TIME в скриптах.
+var takeScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1])
+local at = tonumber(state[2])
+if tokens == nil or at == nil then
+	tokens = capacity
+	at = now
+end
+
+tokens = math.min(capacity, tokens + math.max(now - at, 0) * capacity / period)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', tostring(now))
+redis.call('PEXPIRE', KEYS[1], period)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisLimiter хранит корзины в Redis или совместимом сервере (Valkey,
+// KeyDB, Dragonfly). Корзина - хэш с остатком и временем последнего запроса,
+// ключ живёт не дольше Period: за это время корзина наполняется сама.
+type RedisLimiter struct {
+	client redis.Scripter
+	now    func() time.Time
+}
+
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client, now: time.Now}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, rate Rate) (Decision, error) {
+	res, err := takeScript.Run(ctx, l.client, []string{key},
+		rate.Limit,
+		rate.Period.Milliseconds(),
+		l.now().UnixMilli(),
+	).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(res) != 2 {
+		return Decision{}, fmt.Errorf("unexpected rate limit script reply: %v", res)
+	}
+
+	allowed, _ := res[0].(int64)
+	raw, _ := res[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("unexpected rate limit script reply: %v", res)
+	}
+	return decide(allowed == 1, tokens, rate), nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *model.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error)
+	Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, every time.Duration) error
+}
+
+type apiKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAPIKeyRepository(db *pgxpool.Pool) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *model.APIKey) error {
+	stmt := table.APIKeys.INSERT(
+		table.APIKeys.ID,
+		table.APIKeys.UserID,
+		table.APIKeys.OrganizationID,
+		table.APIKeys.Name,
+		table.APIKeys.Prefix,
+		table.APIKeys.KeyHash,
+		table.APIKeys.Scopes,
+		table.APIKeys.ExpiresAt,
+	).MODEL(jet_model.APIKeys{
+		ID:             key.ID,
+		UserID:         key.UserID,
+		OrganizationID: key.OrganizationID,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		KeyHash:        key.KeyHash,
+		Scopes:         strings.Join(key.Scopes, " "),
+		ExpiresAt:      key.ExpiresAt,
+	}).RETURNING(table.APIKeys.CreatedAt)
+
+	var dest jet_model.APIKeys
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return err
+	}
+	if dest.CreatedAt != nil {
+		key.CreatedAt = *dest.CreatedAt
+	}
+	return nil
+}
+
+// GetByHash ищет ключ по хэшу, в том числе отозванный или истёкший:
+// годность проверяет сервис. Ключи удалённых пользователей не находятся.
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	var dest jet_model.APIKeys
+
+	stmt := SELECT(table.APIKeys.AllColumns).
+		FROM(table.APIKeys.INNER_JOIN(table.Users, table.Users.ID.EQ(table.APIKeys.UserID))).
+		WHERE(table.APIKeys.KeyHash.EQ(String(keyHash)).AND(notDeleted())).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.APIKeyToDomain(dest)
+	return &res, nil
+}
+
+// ListByUser возвращает все ключи пользователя, новые первыми
+func (r *apiKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error) {
+	var dest []jet_model.APIKeys
+
+	stmt := SELECT(table.APIKeys.AllColumns).
+		FROM(table.APIKeys).
+		WHERE(table.APIKeys.UserID.EQ(UUID(userID))).
+		ORDER_BY(table.APIKeys.CreatedAt.DESC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	keys := make([]model.APIKey, len(dest))
+	for i, k := range dest {
+		keys[i] = model.APIKeyToDomain(k)
+	}
+	return keys, nil
+}
+
+// Revoke отзывает ключ пользователя userID. Возвращает false, если такого
+// действующего ключа у него нет.
+func (r *apiKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	stmt := table.APIKeys.UPDATE(table.APIKeys.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.APIKeys.ID.EQ(UUID(id)).
+				AND(table.APIKeys.UserID.EQ(UUID(userID))).
+				AND(table.APIKeys.RevokedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// TouchLastUsed обновляет время последнего использования не чаще раза в
+// every, чтобы активный скрипт не писал в базу на каждый запрос
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time, every time.Duration) error {
+	stmt := table.APIKeys.UPDATE(table.APIKeys.LastUsedAt).
+		SET(TimestampzT(at)).
+		WHERE(
+			table.APIKeys.ID.EQ(UUID(id)).
+				AND(
+					table.APIKeys.LastUsedAt.IS_NULL().
+						OR(table.APIKeys.LastUsedAt.LT(TimestampzT(at.Add(-every)))),
+				),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
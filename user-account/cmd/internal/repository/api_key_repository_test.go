@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresAPIKeyRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "apikey@test.com",
+		Nickname:     "apikey_nick",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	key := &model.APIKey{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Name:      "ci",
+		Prefix:    "ak_abcdefgh",
+		KeyHash:   "api-key-hash",
+		Scopes:    []string{model.PermUsersRead, model.PermUsersManage},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("Create And Get By Hash", func(t *testing.T) {
+		assert.NoError(t, repo.Create(ctx, key))
+		assert.False(t, key.CreatedAt.IsZero())
+
+		found, err := repo.GetByHash(ctx, "api-key-hash")
+		assert.NoError(t, err)
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, key.Scopes, found.Scopes)
+		assert.Nil(t, found.LastUsedAt)
+
+		missing, err := repo.GetByHash(ctx, "unknown-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("Touch Last Used Is Throttled", func(t *testing.T) {
+		first := time.Now()
+		assert.NoError(t, repo.TouchLastUsed(ctx, key.ID, first, time.Minute))
+		assert.NoError(t, repo.TouchLastUsed(ctx, key.ID, first.Add(time.Second), time.Minute))
+
+		found, err := repo.GetByHash(ctx, "api-key-hash")
+		assert.NoError(t, err)
+		assert.WithinDuration(t, first, *found.LastUsedAt, time.Millisecond)
+	})
+
+	t.Run("List And Revoke", func(t *testing.T) {
+		keys, err := repo.ListByUser(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+
+		// Чужой ключ отозвать нельзя
+		revoked, err := repo.Revoke(ctx, uuid.New(), key.ID)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		revoked, err = repo.Revoke(ctx, user.ID, key.ID)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.Revoke(ctx, user.ID, key.ID)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		found, err := repo.GetByHash(ctx, "api-key-hash")
+		assert.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+	})
+
+	t.Run("Keys Of Deleted Users Are Not Found", func(t *testing.T) {
+		assert.NoError(t, users.Delete(ctx, user.ID))
+
+		found, err := repo.GetByHash(ctx, "api-key-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
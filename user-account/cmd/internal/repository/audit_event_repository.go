@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type AuditEventRepository interface {
+	Append(ctx context.Context, event *model.AuditEvent) error
+	List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error)
+	ForEach(ctx context.Context, fn func(model.AuditEvent) error) error
+}
+
+// auditChainLockKey - ключ транзакционной advisory-блокировки, которой
+// упорядочиваются записи в журнал
+const auditChainLockKey = 0x61756469745f6c67
+
+type auditEventRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresAuditEventRepository(db *pgxpool.Pool) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+// Append дописывает событие в конец журнала: присваивает ему следующий ID,
+// время и хэш поверх хэша последней записи. Записи упорядочиваются
+// advisory-блокировкой до конца транзакции, иначе две транзакции сцепились
+// бы с одной и той же предыдущей записью. Саму таблицу она не блокирует:
+// ждут друг друга только конкурирующие Append.
+func (r *auditEventRepository) Append(ctx context.Context, event *model.AuditEvent) error {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = SELECT(Func("pg_advisory_xact_lock", Int(auditChainLockKey))).ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	var last jet_model.AuditEvents
+	lastStmt := SELECT(table.AuditEvents.ID, table.AuditEvents.Hash).
+		FROM(table.AuditEvents).
+		ORDER_BY(table.AuditEvents.ID.DESC()).
+		LIMIT(1)
+	if err = lastStmt.QueryContext(ctx, tx, &last); err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return err
+	}
+
+	if event.Metadata == nil {
+		event.Metadata = map[string]string{}
+	}
+	event.ID = last.ID + 1
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = last.Hash
+	event.Hash = event.ComputeHash(last.Hash)
+
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+	insertStmt := table.AuditEvents.INSERT(table.AuditEvents.AllColumns).
+		MODEL(jet_model.AuditEvents{
+			ID:        event.ID,
+			ActorID:   event.ActorID,
+			TargetID:  event.TargetID,
+			Action:    event.Action,
+			IPAddress: event.IPAddress,
+			UserAgent: event.UserAgent,
+			Outcome:   event.Outcome,
+			Metadata:  string(metadata),
+			CreatedAt: event.CreatedAt,
+			PrevHash:  event.PrevHash,
+			Hash:      event.Hash,
+		})
+	if _, err = insertStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// List возвращает страницу журнала, новые записи первыми
+func (r *auditEventRepository) List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error) {
+	condition := Bool(true)
+	if query.ActorID != nil {
+		condition = condition.AND(table.AuditEvents.ActorID.EQ(UUID(*query.ActorID)))
+	}
+	if query.TargetID != nil {
+		condition = condition.AND(table.AuditEvents.TargetID.EQ(UUID(*query.TargetID)))
+	}
+	if query.Action != "" {
+		condition = condition.AND(table.AuditEvents.Action.EQ(String(query.Action)))
+	}
+	if query.Outcome != "" {
+		condition = condition.AND(table.AuditEvents.Outcome.EQ(String(query.Outcome)))
+	}
+	if query.CreatedFrom != nil {
+		condition = condition.AND(table.AuditEvents.CreatedAt.GT_EQ(TimestampzT(*query.CreatedFrom)))
+	}
+	if query.CreatedTo != nil {
+		condition = condition.AND(table.AuditEvents.CreatedAt.LT(TimestampzT(*query.CreatedTo)))
+	}
+	if query.Before > 0 {
+		condition = condition.AND(table.AuditEvents.ID.LT(Int(query.Before)))
+	}
+
+	stmt := SELECT(table.AuditEvents.AllColumns).
+		FROM(table.AuditEvents).
+		WHERE(condition).
+		ORDER_BY(table.AuditEvents.ID.DESC()).
+		LIMIT(int64(query.Limit) + 1)
+
+	var dest []jet_model.AuditEvents
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	page := &model.AuditEventPage{Events: make([]model.AuditEvent, 0, len(dest))}
+	if len(dest) > query.Limit {
+		dest = dest[:query.Limit]
+		page.NextCursor = dest[len(dest)-1].ID
+	}
+	for _, e := range dest {
+		page.Events = append(page.Events, model.AuditEventToDomain(e))
+	}
+	return page, nil
+}
+
+// ForEach передаёт fn весь журнал по порядку записи, не загружая его в
+// память целиком
+func (r *auditEventRepository) ForEach(ctx context.Context, fn func(model.AuditEvent) error) error {
+	stmt := SELECT(table.AuditEvents.AllColumns).
+		FROM(table.AuditEvents).
+		ORDER_BY(table.AuditEvents.ID.ASC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	rows, err := stmt.Rows(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var dest jet_model.AuditEvents
+		if err = rows.Scan(&dest); err != nil {
+			return err
+		}
+		if err = fn(model.AuditEventToDomain(dest)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEventRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresAuditEventRepository(testPool)
+	ctx := context.Background()
+
+	actorID := uuid.New()
+	targetID := uuid.New()
+
+	t.Run("Append Chains Events", func(t *testing.T) {
+		first := &model.AuditEvent{ActorID: &actorID, TargetID: &targetID, Action: "test.first", Outcome: model.AuditSuccess, IPAddress: "10.0.0.1"}
+		second := &model.AuditEvent{ActorID: &actorID, Action: "test.second", Outcome: model.AuditFailure, Metadata: map[string]string{"error": "boom"}}
+		assert.NoError(t, repo.Append(ctx, first))
+		assert.NoError(t, repo.Append(ctx, second))
+
+		assert.Equal(t, first.ID+1, second.ID)
+		assert.Equal(t, first.Hash, second.PrevHash)
+		assert.Equal(t, second.ComputeHash(first.Hash), second.Hash)
+		assert.False(t, second.CreatedAt.IsZero())
+	})
+
+	t.Run("List Filters And Pages", func(t *testing.T) {
+		page, err := repo.List(ctx, model.AuditEventQuery{ActorID: &actorID, Limit: 1})
+		assert.NoError(t, err)
+		if assert.Len(t, page.Events, 1) {
+			assert.Equal(t, "test.second", page.Events[0].Action)
+			assert.Equal(t, "boom", page.Events[0].Metadata["error"])
+			assert.Equal(t, page.Events[0].ID, page.NextCursor)
+		}
+
+		next, err := repo.List(ctx, model.AuditEventQuery{ActorID: &actorID, Limit: 1, Before: page.NextCursor})
+		assert.NoError(t, err)
+		if assert.Len(t, next.Events, 1) {
+			assert.Equal(t, "test.first", next.Events[0].Action)
+			assert.Equal(t, targetID, *next.Events[0].TargetID)
+		}
+		assert.Zero(t, next.NextCursor)
+
+		failures, err := repo.List(ctx, model.AuditEventQuery{ActorID: &actorID, Outcome: model.AuditFailure, Limit: 10})
+		assert.NoError(t, err)
+		assert.Len(t, failures.Events, 1)
+	})
+
+	t.Run("ForEach Walks In Order", func(t *testing.T) {
+		var ids []int64
+		assert.NoError(t, repo.ForEach(ctx, func(e model.AuditEvent) error {
+			ids = append(ids, e.ID)
+			return nil
+		}))
+		for i := 1; i < len(ids); i++ {
+			assert.Equal(t, ids[i-1]+1, ids[i])
+		}
+	})
+
+	t.Run("Events Are Append Only", func(t *testing.T) {
+		_, err := testPool.Exec(ctx, "UPDATE audit_events SET action = 'forged' WHERE actor_id = $1", actorID)
+		assert.Error(t, err)
+
+		_, err = testPool.Exec(ctx, "DELETE FROM audit_events WHERE actor_id = $1", actorID)
+		assert.Error(t, err)
+	})
+}
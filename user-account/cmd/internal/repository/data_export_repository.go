@@ -0,0 +1,216 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *model.DataExport) error
+	Get(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error)
+	Active(ctx context.Context, userID uuid.UUID) (*model.DataExport, error)
+	Archive(ctx context.Context, userID, id uuid.UUID, now time.Time) ([]byte, error)
+	Claim(ctx context.Context, staleBefore time.Time) (*model.DataExport, error)
+	Complete(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) error
+	Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type dataExportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresDataExportRepository(db *pgxpool.Pool) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// dataExportColumns - всё, кроме самого архива: он нужен только при
+// скачивании и может весить мегабайты
+var dataExportColumns = ColumnList{
+	table.DataExports.ID,
+	table.DataExports.UserID,
+	table.DataExports.Status,
+	table.DataExports.Error,
+	table.DataExports.CreatedAt,
+	table.DataExports.StartedAt,
+	table.DataExports.CompletedAt,
+	table.DataExports.ExpiresAt,
+}
+
+// Create ставит выгрузку в очередь
+func (r *dataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	var dest jet_model.DataExports
+	stmt := table.DataExports.INSERT(table.DataExports.ID, table.DataExports.UserID, table.DataExports.Status).
+		MODEL(jet_model.DataExports{ID: export.ID, UserID: export.UserID, Status: model.DataExportPending}).
+		RETURNING(dataExportColumns)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return err
+	}
+	*export = model.DataExportToDomain(dest)
+	return nil
+}
+
+// Get возвращает выгрузку пользователя userID, nil - если её нет или она
+// чужая
+func (r *dataExportRepository) Get(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	return r.first(ctx, table.DataExports.ID.EQ(UUID(id)).AND(table.DataExports.UserID.EQ(UUID(userID))))
+}
+
+// Active возвращает ещё не собранную выгрузку пользователя, nil - если
+// такой нет
+func (r *dataExportRepository) Active(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	return r.first(ctx, table.DataExports.UserID.EQ(UUID(userID)).AND(inProgress()))
+}
+
+func (r *dataExportRepository) first(ctx context.Context, condition BoolExpression) (*model.DataExport, error) {
+	var dest jet_model.DataExports
+	stmt := SELECT(dataExportColumns).
+		FROM(table.DataExports).
+		WHERE(condition).
+		ORDER_BY(table.DataExports.CreatedAt.DESC()).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := model.DataExportToDomain(dest)
+	return &res, nil
+}
+
+// Archive возвращает собранный архив, nil - если выгрузка не готова,
+// истекла или чужая
+func (r *dataExportRepository) Archive(ctx context.Context, userID, id uuid.UUID, now time.Time) ([]byte, error) {
+	var dest jet_model.DataExports
+	stmt := SELECT(table.DataExports.ID, table.DataExports.Archive).
+		FROM(table.DataExports).
+		WHERE(
+			table.DataExports.ID.EQ(UUID(id)).
+				AND(table.DataExports.UserID.EQ(UUID(userID))).
+				AND(table.DataExports.Status.EQ(String(model.DataExportReady))).
+				AND(table.DataExports.ExpiresAt.GT(TimestampzT(now))),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return dest.Archive, nil
+}
+
+// Claim забирает из очереди самую старую выгрузку и отмечает, что она
+// собирается. Выгрузка, которую начали раньше staleBefore и не закончили,
+// считается брошенной упавшим экземпляром и забирается снова. Строки
+// блокируются с SKIP LOCKED, поэтому несколько реплик не возьмут одну и
+// ту же выгрузку. Пустая очередь - nil.
+func (r *dataExportRepository) Claim(ctx context.Context, staleBefore time.Time) (*model.DataExport, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var dest jet_model.DataExports
+	selectStmt := SELECT(dataExportColumns).
+		FROM(table.DataExports).
+		WHERE(
+			table.DataExports.Status.EQ(String(model.DataExportPending)).
+				OR(
+					table.DataExports.Status.EQ(String(model.DataExportProcessing)).
+						AND(table.DataExports.StartedAt.LT(TimestampzT(staleBefore))),
+				),
+		).
+		ORDER_BY(table.DataExports.CreatedAt).
+		LIMIT(1).
+		FOR(UPDATE().SKIP_LOCKED())
+	if err = selectStmt.QueryContext(ctx, tx, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	updateStmt := table.DataExports.UPDATE(table.DataExports.Status, table.DataExports.StartedAt).
+		SET(String(model.DataExportProcessing), NOW()).
+		WHERE(table.DataExports.ID.EQ(UUID(dest.ID))).
+		RETURNING(table.DataExports.Status, table.DataExports.StartedAt)
+	if err = updateStmt.QueryContext(ctx, tx, &dest); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	res := model.DataExportToDomain(dest)
+	return &res, nil
+}
+
+// Complete сохраняет собранный архив
+func (r *dataExportRepository) Complete(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) error {
+	stmt := table.DataExports.UPDATE(
+		table.DataExports.Status,
+		table.DataExports.Archive,
+		table.DataExports.CompletedAt,
+		table.DataExports.ExpiresAt,
+	).
+		SET(String(model.DataExportReady), Bytea(archive), NOW(), TimestampzT(expiresAt)).
+		WHERE(table.DataExports.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Fail отмечает, что выгрузку собрать не удалось. Запись о сбое хранится
+// до expiresAt, чтобы пользователь увидел её и запросил выгрузку снова.
+func (r *dataExportRepository) Fail(ctx context.Context, id uuid.UUID, reason string, expiresAt time.Time) error {
+	stmt := table.DataExports.UPDATE(
+		table.DataExports.Status,
+		table.DataExports.Error,
+		table.DataExports.CompletedAt,
+		table.DataExports.ExpiresAt,
+	).
+		SET(String(model.DataExportFailed), String(reason), NOW(), TimestampzT(expiresAt)).
+		WHERE(table.DataExports.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// DeleteExpired удаляет выгрузки, истёкшие к now, вместе с архивами
+func (r *dataExportRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	stmt := table.DataExports.DELETE().
+		WHERE(table.DataExports.ExpiresAt.LT(TimestampzT(now)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func inProgress() BoolExpression {
+	return table.DataExports.Status.IN(String(model.DataExportPending), String(model.DataExportProcessing))
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExportRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresDataExportRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "export@dataexport.test",
+		Nickname:     "export_owner",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	export := &model.DataExport{ID: uuid.New(), UserID: user.ID}
+
+	t.Run("Create, Get And Active", func(t *testing.T) {
+		assert.NoError(t, repo.Create(ctx, export))
+		assert.Equal(t, model.DataExportPending, export.Status)
+		assert.False(t, export.CreatedAt.IsZero())
+
+		found, err := repo.Get(ctx, user.ID, export.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, export.ID, found.ID)
+
+		foreign, err := repo.Get(ctx, uuid.New(), export.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, foreign, "exports are visible to their owner only")
+
+		active, err := repo.Active(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, export.ID, active.ID)
+	})
+
+	t.Run("Claim, Complete And Archive", func(t *testing.T) {
+		claimed, err := repo.Claim(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		if assert.NotNil(t, claimed) {
+			assert.Equal(t, export.ID, claimed.ID)
+			assert.Equal(t, model.DataExportProcessing, claimed.Status)
+		}
+
+		again, err := repo.Claim(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Nil(t, again, "export being processed is not claimed twice")
+
+		expiresAt := time.Now().Add(time.Hour)
+		assert.NoError(t, repo.Complete(ctx, export.ID, []byte("zip"), expiresAt))
+
+		found, err := repo.Get(ctx, user.ID, export.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.DataExportReady, found.Status)
+		assert.True(t, found.Downloadable(time.Now()))
+
+		archive, err := repo.Archive(ctx, user.ID, export.ID, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("zip"), archive)
+
+		expired, err := repo.Archive(ctx, user.ID, export.ID, expiresAt.Add(time.Second))
+		assert.NoError(t, err)
+		assert.Nil(t, expired)
+
+		active, err := repo.Active(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+	})
+
+	t.Run("Stale Processing Is Reclaimed And Failed", func(t *testing.T) {
+		stale := &model.DataExport{ID: uuid.New(), UserID: user.ID}
+		assert.NoError(t, repo.Create(ctx, stale))
+
+		claimed, err := repo.Claim(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		if assert.NotNil(t, claimed) {
+			assert.Equal(t, stale.ID, claimed.ID)
+		}
+
+		reclaimed, err := repo.Claim(ctx, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		if assert.NotNil(t, reclaimed) {
+			assert.Equal(t, stale.ID, reclaimed.ID)
+		}
+
+		assert.NoError(t, repo.Fail(ctx, stale.ID, "db down", time.Now().Add(time.Hour)))
+		found, err := repo.Get(ctx, user.ID, stale.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.DataExportFailed, found.Status)
+		assert.Equal(t, "db down", found.Error)
+		assert.False(t, found.Downloadable(time.Now()))
+	})
+
+	t.Run("Delete Expired", func(t *testing.T) {
+		deleted, err := repo.DeleteExpired(ctx, time.Now().Add(2*time.Hour))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(2))
+
+		found, err := repo.Get(ctx, user.ID, export.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+}
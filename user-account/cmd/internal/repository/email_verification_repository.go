@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, verification *model.EmailVerification) error
+	Consume(ctx context.Context, id uuid.UUID) (*model.EmailVerification, error)
+}
+
+type emailVerificationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresEmailVerificationRepository(db *pgxpool.Pool) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(ctx context.Context, verification *model.EmailVerification) error {
+	stmt := table.EmailVerifications.INSERT(
+		table.EmailVerifications.ID,
+		table.EmailVerifications.UserID,
+		table.EmailVerifications.Email,
+		table.EmailVerifications.ExpiresAt,
+	).MODEL(jet_model.EmailVerifications{
+		ID:        verification.ID,
+		UserID:    verification.UserID,
+		Email:     verification.Email,
+		ExpiresAt: verification.ExpiresAt,
+	})
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Consume атомарно помечает ссылку использованной и возвращает её.
+// Возвращает nil, если ссылка не найдена, уже использована или истекла.
+func (r *emailVerificationRepository) Consume(ctx context.Context, id uuid.UUID) (*model.EmailVerification, error) {
+	var dest jet_model.EmailVerifications
+
+	stmt := table.EmailVerifications.UPDATE(table.EmailVerifications.UsedAt).
+		SET(NOW()).
+		WHERE(
+			table.EmailVerifications.ID.EQ(UUID(id)).
+				AND(table.EmailVerifications.UsedAt.IS_NULL()).
+				AND(table.EmailVerifications.ExpiresAt.GT(NOW())),
+		).
+		RETURNING(table.EmailVerifications.AllColumns)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.EmailVerificationToDomain(dest)
+	return &res, nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerificationRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresEmailVerificationRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "verify@test.com",
+		Nickname:     "verify_nick",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	t.Run("Consume Is Single Use", func(t *testing.T) {
+		v := &model.EmailVerification{ID: uuid.New(), UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(time.Hour)}
+		assert.NoError(t, repo.Create(ctx, v))
+
+		consumed, err := repo.Consume(ctx, v.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, consumed) {
+			assert.Equal(t, user.ID, consumed.UserID)
+			assert.NotNil(t, consumed.UsedAt)
+		}
+
+		consumed, err = repo.Consume(ctx, v.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, consumed)
+	})
+
+	t.Run("Expired Link Is Not Consumed", func(t *testing.T) {
+		v := &model.EmailVerification{ID: uuid.New(), UserID: user.ID, Email: user.Email, ExpiresAt: time.Now().Add(-time.Minute)}
+		assert.NoError(t, repo.Create(ctx, v))
+
+		consumed, err := repo.Consume(ctx, v.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, consumed)
+	})
+
+	t.Run("Mark Verified Only For Current Email", func(t *testing.T) {
+		verified, err := users.MarkEmailVerified(ctx, user.ID, "old@test.com")
+		assert.NoError(t, err)
+		assert.False(t, verified)
+
+		verified, err = users.MarkEmailVerified(ctx, user.ID, user.Email)
+		assert.NoError(t, err)
+		assert.True(t, verified)
+
+		got, err := users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, got.EmailVerifiedAt)
+	})
+}
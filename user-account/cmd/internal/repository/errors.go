@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolationCode = "23505"
+
+var (
+	ErrEmailTaken    = errors.New("email is already taken")
+	ErrNicknameTaken = errors.New("nickname is already taken")
+
+	ErrIdentityTaken         = errors.New("identity is already linked to another user")
+	ErrProviderAlreadyLinked = errors.New("user already has an identity at this provider")
+
+	ErrAlreadyMember = errors.New("user is already a member of the organization")
+	ErrLastOwner     = errors.New("organization must keep at least one owner")
+)
+
+// isUniqueViolation проверяет, что запрос упал на уникальном ограничении constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) &&
+		pgErr.Code == uniqueViolationCode &&
+		pgErr.ConstraintName == constraint
+}
+
+// jsonb сериализует значение для записи в колонку JSONB
+func jsonb(value interface{}) (StringExpression, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return StringExp(CAST(String(string(raw))).AS("jsonb")), nil
+}
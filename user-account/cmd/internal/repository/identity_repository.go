@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type IdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error)
+	TouchLogin(ctx context.Context, id uuid.UUID) error
+}
+
+type identityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresIdentityRepository(db *pgxpool.Pool) IdentityRepository {
+	return &identityRepository{db: db}
+}
+
+// Create привязывает учётную запись провайдера. Одна учётная запись
+// привязывается только к одному пользователю, а у пользователя - не больше
+// одной записи на провайдера.
+func (r *identityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	stmt := table.UserIdentities.INSERT(
+		table.UserIdentities.ID,
+		table.UserIdentities.UserID,
+		table.UserIdentities.Provider,
+		table.UserIdentities.Subject,
+		table.UserIdentities.Email,
+		table.UserIdentities.LastLoginAt,
+	).MODEL(jet_model.UserIdentities{
+		ID:          identity.ID,
+		UserID:      identity.UserID,
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       identity.Email,
+		LastLoginAt: identity.LastLoginAt,
+	}).RETURNING(table.UserIdentities.CreatedAt)
+
+	var dest jet_model.UserIdentities
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		switch {
+		case isUniqueViolation(err, "user_identities_provider_subject_key"):
+			return ErrIdentityTaken
+		case isUniqueViolation(err, "user_identities_user_id_provider_key"):
+			return ErrProviderAlreadyLinked
+		default:
+			return err
+		}
+	}
+	if dest.CreatedAt != nil {
+		identity.CreatedAt = *dest.CreatedAt
+	}
+	return nil
+}
+
+func (r *identityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	var dest jet_model.UserIdentities
+
+	stmt := SELECT(table.UserIdentities.AllColumns).
+		FROM(table.UserIdentities).
+		WHERE(
+			table.UserIdentities.Provider.EQ(String(provider)).
+				AND(table.UserIdentities.Subject.EQ(String(subject))),
+		).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.UserIdentityToDomain(dest)
+	return &res, nil
+}
+
+// ListByUser возвращает привязанные учётные записи пользователя по имени провайдера
+func (r *identityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	var dest []jet_model.UserIdentities
+
+	stmt := SELECT(table.UserIdentities.AllColumns).
+		FROM(table.UserIdentities).
+		WHERE(table.UserIdentities.UserID.EQ(UUID(userID))).
+		ORDER_BY(table.UserIdentities.Provider.ASC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	identities := make([]model.UserIdentity, len(dest))
+	for i, identity := range dest {
+		identities[i] = model.UserIdentityToDomain(identity)
+	}
+	return identities, nil
+}
+
+// TouchLogin запоминает время входа через учётную запись
+func (r *identityRepository) TouchLogin(ctx context.Context, id uuid.UUID) error {
+	stmt := table.UserIdentities.UPDATE(table.UserIdentities.LastLoginAt).
+		SET(NOW()).
+		WHERE(table.UserIdentities.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
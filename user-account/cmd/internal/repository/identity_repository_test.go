@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresIdentityRepository(testPool)
+	ctx := context.Background()
+
+	verifiedAt := time.Now()
+	user := &model.User{
+		ID:              uuid.New(),
+		Email:           "identity@test.com",
+		Nickname:        "identity_nick",
+		PasswordHash:    "h",
+		Roles:           []string{"user"},
+		EmailVerifiedAt: &verifiedAt,
+	}
+	other := &model.User{
+		ID:           uuid.New(),
+		Email:        "identity-other@test.com",
+		Nickname:     "identity_other",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+	assert.NoError(t, users.Create(ctx, other))
+
+	t.Run("Create Keeps Email Verification", func(t *testing.T) {
+		found, err := users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, found.EmailVerifiedAt) {
+			assert.WithinDuration(t, verifiedAt, *found.EmailVerifiedAt, time.Millisecond)
+		}
+	})
+
+	identity := &model.UserIdentity{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		Provider: "google",
+		Subject:  "google-sub-1",
+		Email:    user.Email,
+	}
+
+	t.Run("Create And Get", func(t *testing.T) {
+		assert.NoError(t, repo.Create(ctx, identity))
+		assert.False(t, identity.CreatedAt.IsZero())
+
+		found, err := repo.GetByProviderSubject(ctx, "google", "google-sub-1")
+		assert.NoError(t, err)
+		assert.Equal(t, user.ID, found.UserID)
+		assert.Nil(t, found.LastLoginAt)
+
+		// Subject уникален только в пределах провайдера
+		missing, err := repo.GetByProviderSubject(ctx, "keycloak", "google-sub-1")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("Uniqueness", func(t *testing.T) {
+		err := repo.Create(ctx, &model.UserIdentity{ID: uuid.New(), UserID: other.ID, Provider: "google", Subject: "google-sub-1"})
+		assert.ErrorIs(t, err, ErrIdentityTaken)
+
+		err = repo.Create(ctx, &model.UserIdentity{ID: uuid.New(), UserID: user.ID, Provider: "google", Subject: "google-sub-2"})
+		assert.ErrorIs(t, err, ErrProviderAlreadyLinked)
+	})
+
+	t.Run("List And Touch", func(t *testing.T) {
+		assert.NoError(t, repo.Create(ctx, &model.UserIdentity{ID: uuid.New(), UserID: user.ID, Provider: "yandex", Subject: "ya-1"}))
+		assert.NoError(t, repo.TouchLogin(ctx, identity.ID))
+
+		list, err := repo.ListByUser(ctx, user.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, list, 2) {
+			assert.Equal(t, "google", list[0].Provider)
+			assert.NotNil(t, list[0].LastLoginAt)
+			assert.Equal(t, "yandex", list[1].Provider)
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *model.Invitation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error)
+	ListPending(ctx context.Context, orgID uuid.UUID) ([]model.Invitation, error)
+	Revoke(ctx context.Context, orgID, id uuid.UUID) (bool, error)
+	Accept(ctx context.Context, id, userID uuid.UUID) (*model.Invitation, error)
+	Decline(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type invitationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresInvitationRepository(db *pgxpool.Pool) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+// Create сохраняет приглашение. Прежнее приглашение на тот же адрес в эту
+// организацию, если оно ещё не принято и не отклонено, отзывается в той же
+// транзакции.
+func (r *invitationRepository) Create(ctx context.Context, invitation *model.Invitation) error {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	revokeStmt := table.OrganizationInvitations.UPDATE(table.OrganizationInvitations.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.OrganizationInvitations.OrganizationID.EQ(UUID(invitation.OrganizationID)).
+				AND(table.OrganizationInvitations.Email.EQ(String(invitation.Email))).
+				AND(unresolvedInvitation()),
+		)
+	if _, err = revokeStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	var dest jet_model.OrganizationInvitations
+	stmt := table.OrganizationInvitations.INSERT(
+		table.OrganizationInvitations.ID,
+		table.OrganizationInvitations.OrganizationID,
+		table.OrganizationInvitations.Email,
+		table.OrganizationInvitations.Role,
+		table.OrganizationInvitations.InvitedBy,
+		table.OrganizationInvitations.ExpiresAt,
+	).MODEL(jet_model.OrganizationInvitations{
+		ID:             invitation.ID,
+		OrganizationID: invitation.OrganizationID,
+		Email:          invitation.Email,
+		Role:           invitation.Role,
+		InvitedBy:      invitation.InvitedBy,
+		ExpiresAt:      invitation.ExpiresAt,
+	}).RETURNING(table.OrganizationInvitations.CreatedAt)
+	if err = stmt.QueryContext(ctx, tx, &dest); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	if dest.CreatedAt != nil {
+		invitation.CreatedAt = *dest.CreatedAt
+	}
+	return nil
+}
+
+func (r *invitationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Invitation, error) {
+	var dest jet_model.OrganizationInvitations
+
+	stmt := SELECT(table.OrganizationInvitations.AllColumns).
+		FROM(table.OrganizationInvitations).
+		WHERE(table.OrganizationInvitations.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.InvitationToDomain(dest)
+	return &res, nil
+}
+
+// ListPending возвращает ожидающие приглашения организации, новые первыми
+func (r *invitationRepository) ListPending(ctx context.Context, orgID uuid.UUID) ([]model.Invitation, error) {
+	var dest []jet_model.OrganizationInvitations
+
+	stmt := SELECT(table.OrganizationInvitations.AllColumns).
+		FROM(table.OrganizationInvitations).
+		WHERE(
+			table.OrganizationInvitations.OrganizationID.EQ(UUID(orgID)).
+				AND(pendingInvitation()),
+		).
+		ORDER_BY(table.OrganizationInvitations.CreatedAt.DESC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	invitations := make([]model.Invitation, len(dest))
+	for i, inv := range dest {
+		invitations[i] = model.InvitationToDomain(inv)
+	}
+	return invitations, nil
+}
+
+// Revoke отзывает ожидающее приглашение организации. Возвращает false, если
+// такого приглашения нет.
+func (r *invitationRepository) Revoke(ctx context.Context, orgID, id uuid.UUID) (bool, error) {
+	stmt := table.OrganizationInvitations.UPDATE(table.OrganizationInvitations.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.OrganizationInvitations.ID.EQ(UUID(id)).
+				AND(table.OrganizationInvitations.OrganizationID.EQ(UUID(orgID))).
+				AND(pendingInvitation()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// Accept атомарно помечает приглашение принятым и добавляет пользователя в
+// организацию с ролью из приглашения. Если пользователь уже состоит в
+// организации, его роль не меняется. Возвращает nil, если приглашение не
+// ожидает ответа.
+func (r *invitationRepository) Accept(ctx context.Context, id, userID uuid.UUID) (*model.Invitation, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var dest jet_model.OrganizationInvitations
+	stmt := table.OrganizationInvitations.UPDATE(table.OrganizationInvitations.AcceptedAt).
+		SET(NOW()).
+		WHERE(
+			table.OrganizationInvitations.ID.EQ(UUID(id)).
+				AND(pendingInvitation()),
+		).
+		RETURNING(table.OrganizationInvitations.AllColumns)
+	if err = stmt.QueryContext(ctx, tx, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	memberStmt := table.OrganizationMembers.INSERT(
+		table.OrganizationMembers.OrganizationID,
+		table.OrganizationMembers.UserID,
+		table.OrganizationMembers.Role,
+	).MODEL(jet_model.OrganizationMembers{OrganizationID: dest.OrganizationID, UserID: userID, Role: dest.Role}).
+		ON_CONFLICT(table.OrganizationMembers.OrganizationID, table.OrganizationMembers.UserID).
+		DO_NOTHING()
+	if _, err = memberStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	res := model.InvitationToDomain(dest)
+	return &res, nil
+}
+
+// Decline помечает ожидающее приглашение отклонённым
+func (r *invitationRepository) Decline(ctx context.Context, id uuid.UUID) (bool, error) {
+	stmt := table.OrganizationInvitations.UPDATE(table.OrganizationInvitations.DeclinedAt).
+		SET(NOW()).
+		WHERE(
+			table.OrganizationInvitations.ID.EQ(UUID(id)).
+				AND(pendingInvitation()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// unresolvedInvitation - приглашение ещё не принято, не отклонено и не отозвано
+func unresolvedInvitation() BoolExpression {
+	return table.OrganizationInvitations.AcceptedAt.IS_NULL().
+		AND(table.OrganizationInvitations.DeclinedAt.IS_NULL()).
+		AND(table.OrganizationInvitations.RevokedAt.IS_NULL())
+}
+
+// pendingInvitation - приглашение ещё можно принять
+func pendingInvitation() BoolExpression {
+	return unresolvedInvitation().AND(table.OrganizationInvitations.ExpiresAt.GT(NOW()))
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	orgs := NewPostgresOrganizationRepository(testPool)
+	repo := NewPostgresInvitationRepository(testPool)
+	ctx := context.Background()
+
+	owner := &model.User{ID: uuid.New(), Email: "inv-owner@test.com", Nickname: "inv_owner", PasswordHash: "h", Roles: []string{"user"}}
+	guest := &model.User{ID: uuid.New(), Email: "inv-guest@test.com", Nickname: "inv_guest", PasswordHash: "h", Roles: []string{"user"}}
+	assert.NoError(t, users.Create(ctx, owner))
+	assert.NoError(t, users.Create(ctx, guest))
+
+	org := &model.Organization{ID: uuid.New(), Name: "Invite Corp"}
+	assert.NoError(t, orgs.Create(ctx, org, owner.ID))
+
+	newInvitation := func(email, role string, expiresAt time.Time) *model.Invitation {
+		return &model.Invitation{
+			ID:             uuid.New(),
+			OrganizationID: org.ID,
+			Email:          email,
+			Role:           role,
+			InvitedBy:      &owner.ID,
+			ExpiresAt:      expiresAt,
+		}
+	}
+
+	t.Run("Reinvite Revokes Previous", func(t *testing.T) {
+		first := newInvitation("reinvite@test.com", model.OrgRoleMember, time.Now().Add(time.Hour))
+		assert.NoError(t, repo.Create(ctx, first))
+		assert.False(t, first.CreatedAt.IsZero())
+
+		second := newInvitation("reinvite@test.com", model.OrgRoleAdmin, time.Now().Add(time.Hour))
+		assert.NoError(t, repo.Create(ctx, second))
+
+		got, err := repo.GetByID(ctx, first.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, got) {
+			assert.NotNil(t, got.RevokedAt)
+		}
+
+		pending, err := repo.ListPending(ctx, org.ID)
+		assert.NoError(t, err)
+		ids := make([]uuid.UUID, 0, len(pending))
+		for _, inv := range pending {
+			ids = append(ids, inv.ID)
+		}
+		assert.Contains(t, ids, second.ID)
+		assert.NotContains(t, ids, first.ID)
+	})
+
+	t.Run("Accept Adds Member Once", func(t *testing.T) {
+		inv := newInvitation(guest.Email, model.OrgRoleAdmin, time.Now().Add(time.Hour))
+		assert.NoError(t, repo.Create(ctx, inv))
+
+		accepted, err := repo.Accept(ctx, inv.ID, guest.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, accepted) {
+			assert.NotNil(t, accepted.AcceptedAt)
+		}
+
+		role, err := orgs.MemberRole(ctx, org.ID, guest.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrgRoleAdmin, role)
+
+		accepted, err = repo.Accept(ctx, inv.ID, guest.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, accepted)
+	})
+
+	t.Run("Expired Invitation Is Not Accepted", func(t *testing.T) {
+		inv := newInvitation("late@test.com", model.OrgRoleMember, time.Now().Add(-time.Minute))
+		assert.NoError(t, repo.Create(ctx, inv))
+
+		accepted, err := repo.Accept(ctx, inv.ID, guest.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, accepted)
+
+		declined, err := repo.Decline(ctx, inv.ID)
+		assert.NoError(t, err)
+		assert.False(t, declined)
+	})
+
+	t.Run("Decline And Revoke", func(t *testing.T) {
+		inv := newInvitation("maybe@test.com", model.OrgRoleMember, time.Now().Add(time.Hour))
+		assert.NoError(t, repo.Create(ctx, inv))
+
+		revoked, err := repo.Revoke(ctx, uuid.New(), inv.ID)
+		assert.NoError(t, err)
+		assert.False(t, revoked, "invitation of another organization must not be revoked")
+
+		declined, err := repo.Decline(ctx, inv.ID)
+		assert.NoError(t, err)
+		assert.True(t, declined)
+
+		revoked, err = repo.Revoke(ctx, org.ID, inv.ID)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
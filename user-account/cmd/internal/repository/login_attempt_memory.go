@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-account/cmd/internal/model"
+)
+
+// memorySweepEvery - через сколько записей чистить устаревшие счётчики
+const memorySweepEvery = 1024
+
+// memoryLoginAttemptRepository держит счётчики в памяти процесса. Подходит
+// для одного экземпляра сервиса; при нескольких репликах у каждой свой счёт.
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]model.LoginAttempt
+	writes   int
+}
+
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: make(map[string]model.LoginAttempt)}
+}
+
+func (r *memoryLoginAttemptRepository) Get(_ context.Context, key string) (*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) RecordFailure(_ context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes++
+	if r.writes%memorySweepEvery == 0 {
+		r.sweep(at.Add(-window), at)
+	}
+
+	attempt, ok := r.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(at.Add(-window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = at
+	r.attempts[key] = attempt
+
+	return attempt.Failures, nil
+}
+
+func (r *memoryLoginAttemptRepository) Lock(_ context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil
+	}
+	attempt.LockedUntil = &until
+	r.attempts[key] = attempt
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) Reset(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) DeleteStale(_ context.Context, staleBefore, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.sweep(staleBefore, now), nil
+}
+
+// sweep удаляет счётчики, которые уже не влияют на вход: последняя неудача
+// старше cutoff и блокировки нет или она истекла. Возвращает, сколько
+// удалено. Вызывается под mu.
+func (r *memoryLoginAttemptRepository) sweep(cutoff, now time.Time) int64 {
+	var deleted int64
+	for key, attempt := range r.attempts {
+		if attempt.LastFailureAt.Before(cutoff) && (attempt.LockedUntil == nil || attempt.LockedUntil.Before(now)) {
+			delete(r.attempts, key)
+			deleted++
+		}
+	}
+	return deleted
+}
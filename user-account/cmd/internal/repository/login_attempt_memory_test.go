@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewMemoryLoginAttemptRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("Failures Accumulate Within Window", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			failures, err := repo.RecordFailure(ctx, "account:window", now, time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, i, failures)
+		}
+
+		failures, err := repo.RecordFailure(ctx, "account:window", now.Add(2*time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failures, "count restarts after a quiet window")
+	})
+
+	t.Run("Lock And Reset", func(t *testing.T) {
+		_, err := repo.RecordFailure(ctx, "account:lock", now, time.Hour)
+		assert.NoError(t, err)
+		until := now.Add(time.Minute)
+		assert.NoError(t, repo.Lock(ctx, "account:lock", until))
+
+		attempt, err := repo.Get(ctx, "account:lock")
+		assert.NoError(t, err)
+		if assert.NotNil(t, attempt) && assert.NotNil(t, attempt.LockedUntil) {
+			assert.Equal(t, until, *attempt.LockedUntil)
+		}
+
+		assert.NoError(t, repo.Reset(ctx, "account:lock"))
+		attempt, err = repo.Get(ctx, "account:lock")
+		assert.NoError(t, err)
+		assert.Nil(t, attempt)
+	})
+
+	t.Run("Sweep Keeps Active Locks", func(t *testing.T) {
+		mem := NewMemoryLoginAttemptRepository().(*memoryLoginAttemptRepository)
+		_, _ = mem.RecordFailure(ctx, "ip:stale", now.Add(-2*time.Hour), time.Hour)
+		_, _ = mem.RecordFailure(ctx, "ip:locked", now.Add(-2*time.Hour), time.Hour)
+		assert.NoError(t, mem.Lock(ctx, "ip:locked", now.Add(time.Hour)))
+
+		deleted, err := mem.DeleteStale(ctx, now.Add(-time.Hour), now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		stale, _ := mem.Get(ctx, "ip:stale")
+		locked, _ := mem.Get(ctx, "ip:locked")
+		assert.Nil(t, stale)
+		assert.NotNil(t, locked)
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// LoginAttemptRepository хранит счётчики неудачных входов. Ключ - строка
+// вида "account:<email>" или "ip:<адрес>", смысл ключей знает только сервис.
+type LoginAttemptRepository interface {
+	Get(ctx context.Context, key string) (*model.LoginAttempt, error)
+	// RecordFailure увеличивает счётчик и возвращает новое значение. Если
+	// предыдущая неудача была раньше at-window, счёт начинается заново.
+	RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+	// DeleteStale удаляет счётчики, которые уже не влияют на вход: последняя
+	// неудача раньше staleBefore, блокировки нет или она кончилась до now.
+	// Возвращает, сколько удалено.
+	DeleteStale(ctx context.Context, staleBefore, now time.Time) (int64, error)
+}
+
+type loginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresLoginAttemptRepository(db *pgxpool.Pool) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Get(ctx context.Context, key string) (*model.LoginAttempt, error) {
+	var dest jet_model.LoginAttempts
+
+	stmt := SELECT(table.LoginAttempts.AllColumns).
+		FROM(table.LoginAttempts).
+		WHERE(table.LoginAttempts.Key.EQ(String(key))).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.LoginAttemptToDomain(dest)
+	return &res, nil
+}
+
+// RecordFailure - один upsert, чтобы параллельные попытки не теряли инкременты
+func (r *loginAttemptRepository) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (int, error) {
+	var dest jet_model.LoginAttempts
+
+	stmt := table.LoginAttempts.INSERT(table.LoginAttempts.Key, table.LoginAttempts.Failures, table.LoginAttempts.LastFailureAt).
+		MODEL(jet_model.LoginAttempts{Key: key, Failures: 1, LastFailureAt: at}).
+		ON_CONFLICT(table.LoginAttempts.Key).
+		DO_UPDATE(
+			SET(
+				table.LoginAttempts.Failures.SET(IntExp(
+					CASE().
+						WHEN(table.LoginAttempts.LastFailureAt.LT(TimestampzT(at.Add(-window)))).
+						THEN(Int(1)).
+						ELSE(table.LoginAttempts.Failures.ADD(Int(1))),
+				)),
+				table.LoginAttempts.LastFailureAt.SET(table.LoginAttempts.EXCLUDED.LastFailureAt),
+			),
+		).
+		RETURNING(table.LoginAttempts.Failures)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return 0, err
+	}
+	return int(dest.Failures), nil
+}
+
+func (r *loginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	stmt := table.LoginAttempts.UPDATE(table.LoginAttempts.LockedUntil).
+		SET(TimestampzT(until)).
+		WHERE(table.LoginAttempts.Key.EQ(String(key)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Reset удаляет счётчик вместе с блокировкой
+func (r *loginAttemptRepository) Reset(ctx context.Context, key string) error {
+	stmt := table.LoginAttempts.DELETE().
+		WHERE(table.LoginAttempts.Key.EQ(String(key)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+func (r *loginAttemptRepository) DeleteStale(ctx context.Context, staleBefore, now time.Time) (int64, error) {
+	stmt := table.LoginAttempts.DELETE().
+		WHERE(
+			table.LoginAttempts.LastFailureAt.LT(TimestampzT(staleBefore)).
+				AND(table.LoginAttempts.LockedUntil.IS_NULL().OR(table.LoginAttempts.LockedUntil.LT(TimestampzT(now)))),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	res, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginAttemptRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresLoginAttemptRepository(testPool)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	t.Run("Failures Accumulate Within Window", func(t *testing.T) {
+		for i := 1; i <= 3; i++ {
+			failures, err := repo.RecordFailure(ctx, "account:pg-window", now, time.Hour)
+			assert.NoError(t, err)
+			assert.Equal(t, i, failures)
+		}
+
+		failures, err := repo.RecordFailure(ctx, "account:pg-window", now.Add(2*time.Hour), time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failures, "count restarts after a quiet window")
+	})
+
+	t.Run("Lock And Reset", func(t *testing.T) {
+		_, err := repo.RecordFailure(ctx, "account:pg-lock", now, time.Hour)
+		assert.NoError(t, err)
+		until := now.Add(time.Minute)
+		assert.NoError(t, repo.Lock(ctx, "account:pg-lock", until))
+
+		attempt, err := repo.Get(ctx, "account:pg-lock")
+		assert.NoError(t, err)
+		if assert.NotNil(t, attempt) && assert.NotNil(t, attempt.LockedUntil) {
+			assert.True(t, until.Equal(*attempt.LockedUntil))
+			assert.Equal(t, 1, attempt.Failures)
+		}
+
+		assert.NoError(t, repo.Reset(ctx, "account:pg-lock"))
+		attempt, err = repo.Get(ctx, "account:pg-lock")
+		assert.NoError(t, err)
+		assert.Nil(t, attempt)
+	})
+
+	t.Run("Delete Stale Keeps Active Locks", func(t *testing.T) {
+		old := now.Add(-48 * time.Hour)
+		_, err := repo.RecordFailure(ctx, "ip:pg-stale", old, time.Hour)
+		assert.NoError(t, err)
+		_, err = repo.RecordFailure(ctx, "ip:pg-locked", old, time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Lock(ctx, "ip:pg-locked", now.Add(time.Hour)))
+		_, err = repo.RecordFailure(ctx, "ip:pg-recent", now, time.Hour)
+		assert.NoError(t, err)
+
+		deleted, err := repo.DeleteStale(ctx, now.Add(-24*time.Hour), now)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		stale, err := repo.Get(ctx, "ip:pg-stale")
+		assert.NoError(t, err)
+		assert.Nil(t, stale)
+		locked, err := repo.Get(ctx, "ip:pg-locked")
+		assert.NoError(t, err)
+		assert.NotNil(t, locked)
+		recent, err := repo.Get(ctx, "ip:pg-recent")
+		assert.NoError(t, err)
+		assert.NotNil(t, recent)
+	})
+}
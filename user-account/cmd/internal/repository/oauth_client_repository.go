@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type OAuthClientRepository interface {
+	Create(ctx context.Context, client *model.OAuthClient) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.OAuthClient, error)
+	List(ctx context.Context) ([]model.OAuthClient, error)
+	Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+}
+
+type oauthClientRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOAuthClientRepository(db *pgxpool.Pool) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(ctx context.Context, client *model.OAuthClient) error {
+	stmt := table.OauthClients.INSERT(
+		table.OauthClients.ID,
+		table.OauthClients.Name,
+		table.OauthClients.SecretHash,
+		table.OauthClients.RedirectUris,
+		table.OauthClients.GrantTypes,
+		table.OauthClients.Scopes,
+		table.OauthClients.CreatedBy,
+	).MODEL(jet_model.OauthClients{
+		ID:           client.ID,
+		Name:         client.Name,
+		SecretHash:   client.SecretHash,
+		RedirectUris: strings.Join(client.RedirectURIs, " "),
+		GrantTypes:   strings.Join(client.GrantTypes, " "),
+		Scopes:       strings.Join(client.Scopes, " "),
+		CreatedBy:    client.CreatedBy,
+	}).RETURNING(table.OauthClients.CreatedAt)
+
+	var dest jet_model.OauthClients
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return err
+	}
+	if dest.CreatedAt != nil {
+		client.CreatedAt = *dest.CreatedAt
+	}
+	return nil
+}
+
+// GetByID ищет клиента, в том числе отозванного: годность проверяет сервис
+func (r *oauthClientRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.OAuthClient, error) {
+	var dest jet_model.OauthClients
+
+	stmt := SELECT(table.OauthClients.AllColumns).
+		FROM(table.OauthClients).
+		WHERE(table.OauthClients.ID.EQ(UUID(id))).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.OAuthClientToDomain(dest)
+	return &res, nil
+}
+
+// List возвращает всех клиентов, новые первыми
+func (r *oauthClientRepository) List(ctx context.Context) ([]model.OAuthClient, error) {
+	var dest []jet_model.OauthClients
+
+	stmt := SELECT(table.OauthClients.AllColumns).
+		FROM(table.OauthClients).
+		ORDER_BY(table.OauthClients.CreatedAt.DESC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	clients := make([]model.OAuthClient, len(dest))
+	for i, c := range dest {
+		clients[i] = model.OAuthClientToDomain(c)
+	}
+	return clients, nil
+}
+
+// Revoke отзывает клиента. Возвращает false, если такого действующего
+// клиента нет.
+func (r *oauthClientRepository) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	stmt := table.OauthClients.UPDATE(table.OauthClients.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.OauthClients.ID.EQ(UUID(id)).
+				AND(table.OauthClients.RevokedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
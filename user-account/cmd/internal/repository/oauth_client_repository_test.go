@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthClientRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresOAuthClientRepository(testPool)
+	ctx := context.Background()
+
+	admin := &model.User{ID: uuid.New(), Email: "oauth-admin@test.com", Nickname: "oauth_admin", PasswordHash: "h", Roles: []string{"admin"}}
+	assert.NoError(t, users.Create(ctx, admin))
+
+	client := &model.OAuthClient{
+		ID:           uuid.New(),
+		Name:         "templater",
+		SecretHash:   "secret-hash",
+		RedirectURIs: []string{"https://templater.example.com/callback", "http://localhost:5173/callback"},
+		GrantTypes:   []string{model.GrantAuthorizationCode},
+		Scopes:       []string{model.ScopeOpenID, model.ScopeEmail},
+		CreatedBy:    &admin.ID,
+	}
+	assert.NoError(t, repo.Create(ctx, client))
+	assert.False(t, client.CreatedAt.IsZero())
+
+	t.Run("GetByID", func(t *testing.T) {
+		found, err := repo.GetByID(ctx, client.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) {
+			assert.Equal(t, "templater", found.Name)
+			assert.Equal(t, client.RedirectURIs, found.RedirectURIs)
+			assert.Equal(t, client.GrantTypes, found.GrantTypes)
+			assert.Equal(t, client.Scopes, found.Scopes)
+			assert.Equal(t, &admin.ID, found.CreatedBy)
+			assert.False(t, found.Public())
+		}
+
+		missing, err := repo.GetByID(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		clients, err := repo.List(ctx)
+		assert.NoError(t, err)
+		ids := make([]uuid.UUID, len(clients))
+		for i, c := range clients {
+			ids[i] = c.ID
+		}
+		assert.Contains(t, ids, client.ID)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		revoked, err := repo.Revoke(ctx, client.ID)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		found, err := repo.GetByID(ctx, client.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+
+		again, err := repo.Revoke(ctx, client.ID)
+		assert.NoError(t, err)
+		assert.False(t, again)
+	})
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type OAuthCodeRepository interface {
+	Create(ctx context.Context, code *model.OAuthAuthorizationCode) error
+	Consume(ctx context.Context, codeHash string, clientID uuid.UUID) (*model.OAuthAuthorizationCode, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type oauthCodeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOAuthCodeRepository(db *pgxpool.Pool) OAuthCodeRepository {
+	return &oauthCodeRepository{db: db}
+}
+
+func (r *oauthCodeRepository) Create(ctx context.Context, code *model.OAuthAuthorizationCode) error {
+	stmt := table.OauthAuthorizationCodes.INSERT(
+		table.OauthAuthorizationCodes.CodeHash,
+		table.OauthAuthorizationCodes.ClientID,
+		table.OauthAuthorizationCodes.UserID,
+		table.OauthAuthorizationCodes.RedirectURI,
+		table.OauthAuthorizationCodes.Scopes,
+		table.OauthAuthorizationCodes.Nonce,
+		table.OauthAuthorizationCodes.CodeChallenge,
+		table.OauthAuthorizationCodes.ExpiresAt,
+	).MODEL(jet_model.OauthAuthorizationCodes{
+		CodeHash:      code.CodeHash,
+		ClientID:      code.ClientID,
+		UserID:        code.UserID,
+		RedirectURI:   code.RedirectURI,
+		Scopes:        strings.Join(code.Scopes, " "),
+		Nonce:         code.Nonce,
+		CodeChallenge: code.CodeChallenge,
+		ExpiresAt:     code.ExpiresAt,
+	})
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Consume атомарно удаляет код клиента clientID и возвращает его. Возвращает
+// nil, если код не найден, уже обменян, истёк или выдан другому клиенту -
+// чужой код при этом не удаляется.
+func (r *oauthCodeRepository) Consume(ctx context.Context, codeHash string, clientID uuid.UUID) (*model.OAuthAuthorizationCode, error) {
+	var dest jet_model.OauthAuthorizationCodes
+
+	stmt := table.OauthAuthorizationCodes.DELETE().
+		WHERE(
+			table.OauthAuthorizationCodes.CodeHash.EQ(String(codeHash)).
+				AND(table.OauthAuthorizationCodes.ClientID.EQ(UUID(clientID))).
+				AND(table.OauthAuthorizationCodes.ExpiresAt.GT(NOW())),
+		).
+		RETURNING(table.OauthAuthorizationCodes.AllColumns)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.OAuthAuthorizationCodeToDomain(dest)
+	return &res, nil
+}
+
+// DeleteExpired удаляет коды, которые так и не обменяли
+func (r *oauthCodeRepository) DeleteExpired(ctx context.Context) error {
+	stmt := table.OauthAuthorizationCodes.DELETE().
+		WHERE(table.OauthAuthorizationCodes.ExpiresAt.LT_EQ(NOW()))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
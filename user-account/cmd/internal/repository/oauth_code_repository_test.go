@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthCodeRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	clients := NewPostgresOAuthClientRepository(testPool)
+	repo := NewPostgresOAuthCodeRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{ID: uuid.New(), Email: "oauth-code@test.com", Nickname: "oauth_code", PasswordHash: "h", Roles: []string{"user"}}
+	assert.NoError(t, users.Create(ctx, user))
+	client := &model.OAuthClient{ID: uuid.New(), Name: "spa", GrantTypes: []string{model.GrantAuthorizationCode}}
+	assert.NoError(t, clients.Create(ctx, client))
+
+	code := &model.OAuthAuthorizationCode{
+		CodeHash:      "code-hash",
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   "http://localhost:5173/callback",
+		Scopes:        []string{model.ScopeOpenID, model.ScopeProfile},
+		Nonce:         "nonce",
+		CodeChallenge: "challenge",
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+	expired := &model.OAuthAuthorizationCode{
+		CodeHash:    "expired-code-hash",
+		ClientID:    client.ID,
+		UserID:      user.ID,
+		RedirectURI: "http://localhost:5173/callback",
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+	assert.NoError(t, repo.Create(ctx, code))
+	assert.NoError(t, repo.Create(ctx, expired))
+
+	t.Run("Code Of Another Client Is Kept", func(t *testing.T) {
+		found, err := repo.Consume(ctx, "code-hash", uuid.New())
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+	})
+
+	t.Run("Consume Is Single Use", func(t *testing.T) {
+		found, err := repo.Consume(ctx, "code-hash", client.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) {
+			assert.Equal(t, client.ID, found.ClientID)
+			assert.Equal(t, user.ID, found.UserID)
+			assert.Equal(t, code.Scopes, found.Scopes)
+			assert.Equal(t, "nonce", found.Nonce)
+			assert.Equal(t, "challenge", found.CodeChallenge)
+		}
+
+		again, err := repo.Consume(ctx, "code-hash", client.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, again)
+	})
+
+	t.Run("Expired Code Is Rejected And Purged", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteExpired(ctx))
+
+		found, err := repo.Consume(ctx, "expired-code-hash", client.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+
+		var count int
+		assert.NoError(t, testPool.QueryRow(ctx, `SELECT count(*) FROM oauth_authorization_codes WHERE code_hash = $1`, "expired-code-hash").Scan(&count))
+		assert.Zero(t, count)
+	})
+}
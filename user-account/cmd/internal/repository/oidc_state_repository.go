@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type OIDCStateRepository interface {
+	Create(ctx context.Context, state *model.OIDCLoginState) error
+	Consume(ctx context.Context, stateHash, bindingHash string) (*model.OIDCLoginState, error)
+	DeleteExpired(ctx context.Context) error
+}
+
+type oidcStateRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOIDCStateRepository(db *pgxpool.Pool) OIDCStateRepository {
+	return &oidcStateRepository{db: db}
+}
+
+func (r *oidcStateRepository) Create(ctx context.Context, state *model.OIDCLoginState) error {
+	stmt := table.OidcLoginStates.INSERT(
+		table.OidcLoginStates.StateHash,
+		table.OidcLoginStates.Provider,
+		table.OidcLoginStates.Nonce,
+		table.OidcLoginStates.CodeVerifier,
+		table.OidcLoginStates.BindingHash,
+		table.OidcLoginStates.ExpiresAt,
+	).MODEL(jet_model.OidcLoginStates{
+		StateHash:    state.StateHash,
+		Provider:     state.Provider,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+		BindingHash:  state.BindingHash,
+		ExpiresAt:    state.ExpiresAt,
+	})
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Consume атомарно удаляет state и возвращает его. Возвращает nil, если
+// state не найден, уже использован, истёк или начат в другом браузере.
+func (r *oidcStateRepository) Consume(ctx context.Context, stateHash, bindingHash string) (*model.OIDCLoginState, error) {
+	var dest jet_model.OidcLoginStates
+
+	stmt := table.OidcLoginStates.DELETE().
+		WHERE(
+			table.OidcLoginStates.StateHash.EQ(String(stateHash)).
+				AND(table.OidcLoginStates.BindingHash.EQ(String(bindingHash))).
+				AND(table.OidcLoginStates.ExpiresAt.GT(NOW())),
+		).
+		RETURNING(table.OidcLoginStates.AllColumns)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.OIDCLoginStateToDomain(dest)
+	return &res, nil
+}
+
+// DeleteExpired удаляет брошенные входы, до возврата из которых дело не дошло
+func (r *oidcStateRepository) DeleteExpired(ctx context.Context) error {
+	stmt := table.OidcLoginStates.DELETE().
+		WHERE(table.OidcLoginStates.ExpiresAt.LT_EQ(NOW()))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOIDCStateRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresOIDCStateRepository(testPool)
+	ctx := context.Background()
+
+	state := &model.OIDCLoginState{
+		StateHash:    "state-hash",
+		Provider:     "google",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		BindingHash:  "binding-hash",
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+	}
+	expired := &model.OIDCLoginState{
+		StateHash:    "expired-state-hash",
+		Provider:     "google",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		BindingHash:  "binding-hash",
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	}
+	assert.NoError(t, repo.Create(ctx, state))
+	assert.NoError(t, repo.Create(ctx, expired))
+
+	t.Run("Consume Is Single Use", func(t *testing.T) {
+		// Чужая привязка не подходит и state не расходует
+		other, err := repo.Consume(ctx, "state-hash", "other-binding-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, other)
+
+		found, err := repo.Consume(ctx, "state-hash", "binding-hash")
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) {
+			assert.Equal(t, "google", found.Provider)
+			assert.Equal(t, "nonce", found.Nonce)
+			assert.Equal(t, "verifier", found.CodeVerifier)
+		}
+
+		again, err := repo.Consume(ctx, "state-hash", "binding-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, again)
+	})
+
+	t.Run("Expired State Is Rejected And Purged", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteExpired(ctx))
+
+		found, err := repo.Consume(ctx, "expired-state-hash", "binding-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, found)
+
+		var count int
+		assert.NoError(t, testPool.QueryRow(ctx, `SELECT count(*) FROM oidc_login_states WHERE state_hash = $1`, "expired-state-hash").Scan(&count))
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *model.Organization, ownerID uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Organization, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) (bool, error)
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error)
+	MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error)
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]model.OrganizationMember, error)
+	AddMember(ctx context.Context, orgID, userID uuid.UUID, role string) error
+	UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) (bool, error)
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error)
+}
+
+type organizationRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresOrganizationRepository(db *pgxpool.Pool) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create сохраняет организацию вместе с её владельцем в одной транзакции
+func (r *organizationRepository) Create(ctx context.Context, org *model.Organization, ownerID uuid.UUID) error {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var dest jet_model.Organizations
+	stmt := table.Organizations.INSERT(table.Organizations.ID, table.Organizations.Name).
+		MODEL(jet_model.Organizations{ID: org.ID, Name: org.Name}).
+		RETURNING(table.Organizations.CreatedAt)
+	if err = stmt.QueryContext(ctx, tx, &dest); err != nil {
+		return err
+	}
+
+	memberStmt := table.OrganizationMembers.INSERT(
+		table.OrganizationMembers.OrganizationID,
+		table.OrganizationMembers.UserID,
+		table.OrganizationMembers.Role,
+	).MODEL(jet_model.OrganizationMembers{OrganizationID: org.ID, UserID: ownerID, Role: model.OrgRoleOwner})
+	if _, err = memberStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+	if dest.CreatedAt != nil {
+		org.CreatedAt = *dest.CreatedAt
+	}
+	return nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Organization, error) {
+	var dest jet_model.Organizations
+
+	stmt := SELECT(table.Organizations.AllColumns).
+		FROM(table.Organizations).
+		WHERE(table.Organizations.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.OrganizationToDomain(dest)
+	return &res, nil
+}
+
+func (r *organizationRepository) Rename(ctx context.Context, id uuid.UUID, name string) (bool, error) {
+	stmt := table.Organizations.UPDATE(table.Organizations.Name).
+		SET(String(name)).
+		WHERE(table.Organizations.ID.EQ(UUID(id)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// ListForUser возвращает организации пользователя в порядке вступления
+func (r *organizationRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error) {
+	var dest []struct {
+		jet_model.OrganizationMembers
+		Organization jet_model.Organizations
+	}
+
+	stmt := SELECT(table.OrganizationMembers.AllColumns, table.Organizations.AllColumns).
+		FROM(table.OrganizationMembers.INNER_JOIN(
+			table.Organizations, table.Organizations.ID.EQ(table.OrganizationMembers.OrganizationID),
+		)).
+		WHERE(table.OrganizationMembers.UserID.EQ(UUID(userID))).
+		ORDER_BY(table.OrganizationMembers.CreatedAt, table.Organizations.ID)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	memberships := make([]model.OrganizationMembership, len(dest))
+	for i, m := range dest {
+		memberships[i] = model.OrganizationMembership{
+			Organization: model.OrganizationToDomain(m.Organization),
+			Role:         m.Role,
+		}
+	}
+	return memberships, nil
+}
+
+// MemberRole возвращает роль пользователя в организации, пустую строку -
+// если он в ней не состоит
+func (r *organizationRepository) MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	var dest jet_model.OrganizationMembers
+
+	stmt := SELECT(table.OrganizationMembers.AllColumns).
+		FROM(table.OrganizationMembers).
+		WHERE(
+			table.OrganizationMembers.OrganizationID.EQ(UUID(orgID)).
+				AND(table.OrganizationMembers.UserID.EQ(UUID(userID))),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return dest.Role, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID uuid.UUID) ([]model.OrganizationMember, error) {
+	var dest []struct {
+		jet_model.OrganizationMembers
+		User jet_model.Users
+	}
+
+	stmt := SELECT(table.OrganizationMembers.AllColumns, table.Users.ID, table.Users.Email, table.Users.Nickname).
+		FROM(table.OrganizationMembers.INNER_JOIN(table.Users, table.Users.ID.EQ(table.OrganizationMembers.UserID))).
+		WHERE(table.OrganizationMembers.OrganizationID.EQ(UUID(orgID)).AND(notDeleted())).
+		ORDER_BY(table.OrganizationMembers.CreatedAt, table.Users.ID)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	members := make([]model.OrganizationMember, len(dest))
+	for i, m := range dest {
+		members[i] = model.OrganizationMember{
+			UserID:   m.UserID,
+			Email:    m.User.Email,
+			Nickname: m.User.Nickname,
+			Role:     m.Role,
+		}
+		if m.CreatedAt != nil {
+			members[i].JoinedAt = *m.CreatedAt
+		}
+	}
+	return members, nil
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	stmt := table.OrganizationMembers.INSERT(
+		table.OrganizationMembers.OrganizationID,
+		table.OrganizationMembers.UserID,
+		table.OrganizationMembers.Role,
+	).MODEL(jet_model.OrganizationMembers{OrganizationID: orgID, UserID: userID, Role: role})
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if _, err := stmt.ExecContext(ctx, db); err != nil {
+		if isUniqueViolation(err, "organization_members_pkey") {
+			return ErrAlreadyMember
+		}
+		return err
+	}
+	return nil
+}
+
+// UpdateMemberRole меняет роль участника. Понизить последнего владельца
+// нельзя: ErrLastOwner.
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, orgID, userID uuid.UUID, role string) (bool, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if role != model.OrgRoleOwner {
+		if err = keepAnotherOwner(ctx, tx, orgID, userID); err != nil {
+			return false, err
+		}
+	}
+
+	stmt := table.OrganizationMembers.UPDATE(table.OrganizationMembers.Role).
+		SET(String(role)).
+		WHERE(
+			table.OrganizationMembers.OrganizationID.EQ(UUID(orgID)).
+				AND(table.OrganizationMembers.UserID.EQ(UUID(userID))),
+		)
+
+	result, err := stmt.ExecContext(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 1, tx.Commit()
+}
+
+// RemoveMember исключает участника. Исключить последнего владельца нельзя:
+// ErrLastOwner.
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID uuid.UUID) (bool, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = keepAnotherOwner(ctx, tx, orgID, userID); err != nil {
+		return false, err
+	}
+
+	stmt := table.OrganizationMembers.DELETE().
+		WHERE(
+			table.OrganizationMembers.OrganizationID.EQ(UUID(orgID)).
+				AND(table.OrganizationMembers.UserID.EQ(UUID(userID))),
+		)
+
+	result, err := stmt.ExecContext(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 1, tx.Commit()
+}
+
+// keepAnotherOwner возвращает ErrLastOwner, если userID - единственный
+// владелец организации. Строки владельцев блокируются до конца транзакции:
+// иначе два владельца, понижающие друг друга одновременно, оба увидели бы
+// второго и оставили бы организацию без владельца. Удалённые пользователи
+// не в счёт: организация не должна остаться только с ними.
+func keepAnotherOwner(ctx context.Context, tx qrm.Queryable, orgID, userID uuid.UUID) error {
+	var owners []jet_model.OrganizationMembers
+
+	stmt := SELECT(table.OrganizationMembers.OrganizationID, table.OrganizationMembers.UserID).
+		FROM(table.OrganizationMembers.INNER_JOIN(table.Users, table.Users.ID.EQ(table.OrganizationMembers.UserID))).
+		WHERE(
+			table.OrganizationMembers.OrganizationID.EQ(UUID(orgID)).
+				AND(table.OrganizationMembers.Role.EQ(String(model.OrgRoleOwner))).
+				AND(notDeleted()),
+		).
+		FOR(UPDATE().OF(table.OrganizationMembers))
+
+	if err := stmt.QueryContext(ctx, tx, &owners); err != nil && !errors.Is(err, qrm.ErrNoRows) {
+		return err
+	}
+	if len(owners) == 1 && owners[0].UserID == userID {
+		return ErrLastOwner
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresOrganizationRepository(testPool)
+	ctx := context.Background()
+
+	owner := &model.User{ID: uuid.New(), Email: "org-owner@test.com", Nickname: "org_owner", PasswordHash: "h", Roles: []string{"user"}}
+	member := &model.User{ID: uuid.New(), Email: "org-member@test.com", Nickname: "org_member", PasswordHash: "h", Roles: []string{"user"}}
+	assert.NoError(t, users.Create(ctx, owner))
+	assert.NoError(t, users.Create(ctx, member))
+
+	org := &model.Organization{ID: uuid.New(), Name: "Acme Legal"}
+	assert.NoError(t, repo.Create(ctx, org, owner.ID))
+	assert.False(t, org.CreatedAt.IsZero())
+
+	t.Run("Owner Membership", func(t *testing.T) {
+		role, err := repo.MemberRole(ctx, org.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrgRoleOwner, role)
+
+		role, err = repo.MemberRole(ctx, org.ID, member.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, role)
+	})
+
+	t.Run("Members", func(t *testing.T) {
+		assert.NoError(t, repo.AddMember(ctx, org.ID, member.ID, model.OrgRoleMember))
+		assert.ErrorIs(t, repo.AddMember(ctx, org.ID, member.ID, model.OrgRoleAdmin), ErrAlreadyMember)
+
+		members, err := repo.ListMembers(ctx, org.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, members, 2) {
+			assert.Equal(t, owner.ID, members[0].UserID)
+			assert.Equal(t, "org-owner@test.com", members[0].Email)
+			assert.Equal(t, model.OrgRoleMember, members[1].Role)
+		}
+
+		updated, err := repo.UpdateMemberRole(ctx, org.ID, member.ID, model.OrgRoleOwner)
+		assert.NoError(t, err)
+		assert.True(t, updated)
+
+		role, err := repo.MemberRole(ctx, org.ID, member.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrgRoleOwner, role)
+
+		memberships, err := repo.ListForUser(ctx, member.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, memberships, 1) {
+			assert.Equal(t, org.ID, memberships[0].ID)
+			assert.Equal(t, "Acme Legal", memberships[0].Name)
+			assert.Equal(t, model.OrgRoleOwner, memberships[0].Role)
+		}
+
+		removed, err := repo.RemoveMember(ctx, org.ID, member.ID)
+		assert.NoError(t, err)
+		assert.True(t, removed)
+
+		removed, err = repo.RemoveMember(ctx, org.ID, member.ID)
+		assert.NoError(t, err)
+		assert.False(t, removed)
+
+		// Оставшегося владельца не понизить и не исключить
+		_, err = repo.UpdateMemberRole(ctx, org.ID, owner.ID, model.OrgRoleAdmin)
+		assert.ErrorIs(t, err, ErrLastOwner)
+		_, err = repo.RemoveMember(ctx, org.ID, owner.ID)
+		assert.ErrorIs(t, err, ErrLastOwner)
+
+		role, err = repo.MemberRole(ctx, org.ID, owner.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrgRoleOwner, role)
+	})
+
+	t.Run("Rename", func(t *testing.T) {
+		renamed, err := repo.Rename(ctx, org.ID, "Acme Legal LLP")
+		assert.NoError(t, err)
+		assert.True(t, renamed)
+
+		found, err := repo.GetByID(ctx, org.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) {
+			assert.Equal(t, "Acme Legal LLP", found.Name)
+		}
+
+		missing, err := repo.GetByID(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, reset *model.PasswordReset) error
+	GetActive(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	Consume(ctx context.Context, tokenHash string) (*model.PasswordReset, error)
+	InvalidateForUser(ctx context.Context, userID uuid.UUID) error
+}
+
+type passwordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPasswordResetRepository(db *pgxpool.Pool) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, reset *model.PasswordReset) error {
+	stmt := table.PasswordResets.INSERT(
+		table.PasswordResets.ID,
+		table.PasswordResets.UserID,
+		table.PasswordResets.TokenHash,
+		table.PasswordResets.ExpiresAt,
+	).MODEL(jet_model.PasswordResets{
+		ID:        reset.ID,
+		UserID:    reset.UserID,
+		TokenHash: reset.TokenHash,
+		ExpiresAt: reset.ExpiresAt,
+	})
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// GetActive возвращает токен, не расходуя его. Возвращает nil, если токен
+// не найден, уже использован или истёк.
+func (r *passwordResetRepository) GetActive(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	var dest jet_model.PasswordResets
+
+	stmt := SELECT(table.PasswordResets.AllColumns).
+		FROM(table.PasswordResets).
+		WHERE(
+			table.PasswordResets.TokenHash.EQ(String(tokenHash)).
+				AND(table.PasswordResets.UsedAt.IS_NULL()).
+				AND(table.PasswordResets.ExpiresAt.GT(NOW())),
+		).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.PasswordResetToDomain(dest)
+	return &res, nil
+}
+
+// Consume атомарно помечает токен использованным и возвращает его.
+// Возвращает nil, если токен не найден, уже использован или истёк.
+func (r *passwordResetRepository) Consume(ctx context.Context, tokenHash string) (*model.PasswordReset, error) {
+	var dest jet_model.PasswordResets
+
+	stmt := table.PasswordResets.UPDATE(table.PasswordResets.UsedAt).
+		SET(NOW()).
+		WHERE(
+			table.PasswordResets.TokenHash.EQ(String(tokenHash)).
+				AND(table.PasswordResets.UsedAt.IS_NULL()).
+				AND(table.PasswordResets.ExpiresAt.GT(NOW())),
+		).
+		RETURNING(table.PasswordResets.AllColumns)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.PasswordResetToDomain(dest)
+	return &res, nil
+}
+
+// InvalidateForUser гасит все ещё не использованные токены пользователя
+func (r *passwordResetRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID) error {
+	stmt := table.PasswordResets.UPDATE(table.PasswordResets.UsedAt).
+		SET(NOW()).
+		WHERE(
+			table.PasswordResets.UserID.EQ(UUID(userID)).
+				AND(table.PasswordResets.UsedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresPasswordResetRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "reset@test.com",
+		Nickname:     "reset_nick",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	newReset := func(hash string, expiresAt time.Time) *model.PasswordReset {
+		reset := &model.PasswordReset{ID: uuid.New(), UserID: user.ID, TokenHash: hash, ExpiresAt: expiresAt}
+		assert.NoError(t, repo.Create(ctx, reset))
+		return reset
+	}
+
+	t.Run("Consume Is Single Use", func(t *testing.T) {
+		newReset("hash-single", time.Now().Add(time.Hour))
+
+		// Просмотр токен не расходует
+		active, err := repo.GetActive(ctx, "hash-single")
+		assert.NoError(t, err)
+		assert.NotNil(t, active)
+
+		consumed, err := repo.Consume(ctx, "hash-single")
+		assert.NoError(t, err)
+		if assert.NotNil(t, consumed) {
+			assert.Equal(t, user.ID, consumed.UserID)
+		}
+
+		consumed, err = repo.Consume(ctx, "hash-single")
+		assert.NoError(t, err)
+		assert.Nil(t, consumed)
+
+		active, err = repo.GetActive(ctx, "hash-single")
+		assert.NoError(t, err)
+		assert.Nil(t, active)
+	})
+
+	t.Run("Expired Token Is Not Consumed", func(t *testing.T) {
+		newReset("hash-expired", time.Now().Add(-time.Minute))
+
+		consumed, err := repo.Consume(ctx, "hash-expired")
+		assert.NoError(t, err)
+		assert.Nil(t, consumed)
+	})
+
+	t.Run("Invalidate For User", func(t *testing.T) {
+		newReset("hash-pending", time.Now().Add(time.Hour))
+		assert.NoError(t, repo.InvalidateForUser(ctx, user.ID))
+
+		consumed, err := repo.Consume(ctx, "hash-pending")
+		assert.NoError(t, err)
+		assert.Nil(t, consumed)
+	})
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// PersonalDataRepository - данные пользователя целиком, для запросов
+// субъекта персональных данных
+type PersonalDataRepository interface {
+	Collect(ctx context.Context, userID uuid.UUID) (*model.PersonalData, error)
+	Erase(ctx context.Context, userID uuid.UUID) (*model.User, error)
+}
+
+type personalDataRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresPersonalDataRepository(db *pgxpool.Pool) PersonalDataRepository {
+	return &personalDataRepository{db: db}
+}
+
+// Collect собирает всё, что связано с пользователем, из одного снимка базы,
+// чтобы части выгрузки не противоречили друг другу. Удалённый, но ещё не
+// стёртый пользователь выгружается; nil - если пользователя нет или он
+// стёрт.
+func (r *personalDataRepository) Collect(ctx context.Context, userID uuid.UUID) (*model.PersonalData, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var user jet_model.Users
+	userStmt := SELECT(table.Users.AllColumns).
+		FROM(table.Users).
+		WHERE(table.Users.ID.EQ(UUID(userID)).AND(table.Users.ErasedAt.IS_NULL()))
+	if err = userStmt.QueryContext(ctx, tx, &user); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	data := &model.PersonalData{Profile: model.ToDomain(user)}
+	id := UUID(userID)
+
+	var roles []jet_model.UserRoles
+	rolesStmt := SELECT(table.UserRoles.UserID, table.UserRoles.Role).
+		FROM(table.UserRoles).
+		WHERE(table.UserRoles.UserID.EQ(id)).
+		ORDER_BY(table.UserRoles.Role)
+	if err = rolesStmt.QueryContext(ctx, tx, &roles); err != nil {
+		return nil, err
+	}
+	data.Profile.Roles = make([]string, len(roles))
+	for i, role := range roles {
+		data.Profile.Roles[i] = role.Role
+	}
+
+	var memberships []struct {
+		jet_model.OrganizationMembers
+		Organization jet_model.Organizations
+	}
+	membershipsStmt := SELECT(table.OrganizationMembers.AllColumns, table.Organizations.AllColumns).
+		FROM(table.OrganizationMembers.INNER_JOIN(
+			table.Organizations, table.Organizations.ID.EQ(table.OrganizationMembers.OrganizationID),
+		)).
+		WHERE(table.OrganizationMembers.UserID.EQ(id)).
+		ORDER_BY(table.OrganizationMembers.CreatedAt, table.Organizations.ID)
+	if err = membershipsStmt.QueryContext(ctx, tx, &memberships); err != nil {
+		return nil, err
+	}
+	data.Organizations = make([]model.OrganizationMembership, len(memberships))
+	for i, m := range memberships {
+		data.Organizations[i] = model.OrganizationMembership{
+			Organization: model.OrganizationToDomain(m.Organization),
+			Role:         m.Role,
+		}
+	}
+
+	var sessions []jet_model.Sessions
+	sessionsStmt := SELECT(table.Sessions.AllColumns).
+		FROM(table.Sessions).
+		WHERE(table.Sessions.UserID.EQ(id)).
+		ORDER_BY(table.Sessions.CreatedAt, table.Sessions.ID)
+	if err = sessionsStmt.QueryContext(ctx, tx, &sessions); err != nil {
+		return nil, err
+	}
+	data.Sessions = make([]model.Session, len(sessions))
+	for i, s := range sessions {
+		data.Sessions[i] = model.SessionToDomain(s)
+	}
+
+	var keys []jet_model.APIKeys
+	keysStmt := SELECT(table.APIKeys.AllColumns).
+		FROM(table.APIKeys).
+		WHERE(table.APIKeys.UserID.EQ(id)).
+		ORDER_BY(table.APIKeys.CreatedAt, table.APIKeys.ID)
+	if err = keysStmt.QueryContext(ctx, tx, &keys); err != nil {
+		return nil, err
+	}
+	data.APIKeys = make([]model.APIKey, len(keys))
+	for i, k := range keys {
+		data.APIKeys[i] = model.APIKeyToDomain(k)
+	}
+
+	var identities []jet_model.UserIdentities
+	identitiesStmt := SELECT(table.UserIdentities.AllColumns).
+		FROM(table.UserIdentities).
+		WHERE(table.UserIdentities.UserID.EQ(id)).
+		ORDER_BY(table.UserIdentities.CreatedAt, table.UserIdentities.ID)
+	if err = identitiesStmt.QueryContext(ctx, tx, &identities); err != nil {
+		return nil, err
+	}
+	data.Identities = make([]model.UserIdentity, len(identities))
+	for i, identity := range identities {
+		data.Identities[i] = model.UserIdentityToDomain(identity)
+	}
+
+	var history []jet_model.UserStatusHistory
+	historyStmt := SELECT(table.UserStatusHistory.AllColumns).
+		FROM(table.UserStatusHistory).
+		WHERE(table.UserStatusHistory.UserID.EQ(id)).
+		ORDER_BY(table.UserStatusHistory.CreatedAt, table.UserStatusHistory.ID)
+	if err = historyStmt.QueryContext(ctx, tx, &history); err != nil {
+		return nil, err
+	}
+	data.StatusHistory = make([]model.UserStatusChange, len(history))
+	for i, h := range history {
+		data.StatusHistory[i] = model.UserStatusChangeToDomain(h)
+	}
+
+	var clients []jet_model.OauthClients
+	clientsStmt := SELECT(table.OauthClients.AllColumns).
+		FROM(table.OauthClients).
+		WHERE(table.OauthClients.CreatedBy.EQ(id)).
+		ORDER_BY(table.OauthClients.CreatedAt, table.OauthClients.ID)
+	if err = clientsStmt.QueryContext(ctx, tx, &clients); err != nil {
+		return nil, err
+	}
+	data.OAuthClients = make([]model.OAuthClient, len(clients))
+	for i, c := range clients {
+		data.OAuthClients[i] = model.OAuthClientToDomain(c)
+	}
+
+	var invitations []jet_model.OrganizationInvitations
+	invitationsStmt := SELECT(table.OrganizationInvitations.AllColumns).
+		FROM(table.OrganizationInvitations).
+		WHERE(
+			table.OrganizationInvitations.InvitedBy.EQ(id).
+				OR(LOWER(table.OrganizationInvitations.Email).EQ(LOWER(String(user.Email)))),
+		).
+		ORDER_BY(table.OrganizationInvitations.CreatedAt, table.OrganizationInvitations.ID)
+	if err = invitationsStmt.QueryContext(ctx, tx, &invitations); err != nil {
+		return nil, err
+	}
+	data.Invitations = make([]model.Invitation, len(invitations))
+	for i, inv := range invitations {
+		data.Invitations[i] = model.InvitationToDomain(inv)
+	}
+
+	var events []jet_model.AuditEvents
+	eventsStmt := SELECT(table.AuditEvents.AllColumns).
+		FROM(table.AuditEvents).
+		WHERE(table.AuditEvents.ActorID.EQ(id).OR(table.AuditEvents.TargetID.EQ(id))).
+		ORDER_BY(table.AuditEvents.ID)
+	if err = eventsStmt.QueryContext(ctx, tx, &events); err != nil {
+		return nil, err
+	}
+	data.AuditEvents = make([]model.AuditEvent, len(events))
+	for i, e := range events {
+		data.AuditEvents[i] = model.AuditEventToDomain(e)
+	}
+
+	return data, nil
+}
+
+// Erase обезличивает пользователя в одной транзакции. Строка в users
+// остаётся, чтобы ссылки на неё из истории статусов, выданных ролей,
+// отправленных приглашений и клиентов OAuth не потеряли смысл, но адрес,
+// ник, пароль и настройки заменяются, а пользователь помечается удалённым и
+// стёртым - восстановить или окончательно удалить его уже нельзя.
+//
+// Записи, которые сами по себе описывают человека, удаляются: ключи API,
+// привязки внешних аккаунтов, второй фактор, ссылки подтверждения и сброса,
+// коды авторизации, выгрузки и приглашения на его адрес. Сессии остаются
+// отозванными без адреса и браузера: по ним проверяется отзыв уже выданных
+// токенов. Из истории статусов стираются причины.
+//
+// Возвращает пользователя до обезличивания, nil - если его нет или он уже
+// стёрт.
+func (r *personalDataRepository) Erase(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	id := UUID(userID)
+
+	var before jet_model.Users
+	selectStmt := SELECT(table.Users.AllColumns).
+		FROM(table.Users).
+		WHERE(table.Users.ID.EQ(id).AND(table.Users.ErasedAt.IS_NULL())).
+		FOR(UPDATE())
+	if err = selectStmt.QueryContext(ctx, tx, &before); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	eraseStmt := table.Users.UPDATE().
+		SET(
+			table.Users.Email.SET(String(model.ErasedEmail(userID))),
+			table.Users.Nickname.SET(String(model.ErasedNickname(userID))),
+			table.Users.PasswordHash.SET(String("")),
+			table.Users.Preferences.SET(Json("{}")),
+			table.Users.EmailVerifiedAt.SET(TimestampzExp(NULL)),
+			table.Users.StatusReason.SET(String("")),
+			table.Users.DeletedAt.SET(TimestampzExp(COALESCE(table.Users.DeletedAt, NOW()))),
+			table.Users.ErasedAt.SET(NOW()),
+		).
+		WHERE(table.Users.ID.EQ(id))
+	if _, err = eraseStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	statements := []Statement{
+		table.Sessions.UPDATE().
+			SET(
+				table.Sessions.UserAgent.SET(String("")),
+				table.Sessions.IPAddress.SET(String("")),
+				table.Sessions.RevokedAt.SET(TimestampzExp(COALESCE(table.Sessions.RevokedAt, NOW()))),
+			).
+			WHERE(table.Sessions.UserID.EQ(id)),
+		table.UserStatusHistory.UPDATE(table.UserStatusHistory.Reason).
+			SET(String("")).
+			WHERE(table.UserStatusHistory.UserID.EQ(id)),
+		table.APIKeys.DELETE().WHERE(table.APIKeys.UserID.EQ(id)),
+		table.UserIdentities.DELETE().WHERE(table.UserIdentities.UserID.EQ(id)),
+		table.UserTotp.DELETE().WHERE(table.UserTotp.UserID.EQ(id)),
+		table.RecoveryCodes.DELETE().WHERE(table.RecoveryCodes.UserID.EQ(id)),
+		table.EmailVerifications.DELETE().WHERE(table.EmailVerifications.UserID.EQ(id)),
+		table.PasswordResets.DELETE().WHERE(table.PasswordResets.UserID.EQ(id)),
+		table.OauthAuthorizationCodes.DELETE().WHERE(table.OauthAuthorizationCodes.UserID.EQ(id)),
+		table.DataExports.DELETE().WHERE(table.DataExports.UserID.EQ(id)),
+		table.OrganizationInvitations.DELETE().
+			WHERE(LOWER(table.OrganizationInvitations.Email).EQ(LOWER(String(before.Email)))),
+	}
+	for _, stmt := range statements {
+		if _, err = stmt.ExecContext(ctx, tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+	user := model.ToDomain(before)
+	return &user, nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalDataRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	sessions := NewPostgresSessionRepository(testPool)
+	keys := NewPostgresAPIKeyRepository(testPool)
+	statuses := NewPostgresUserStatusRepository(testPool)
+	repo := NewPostgresPersonalDataRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "subject@personal.test",
+		Nickname:     "data_subject",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	session := &model.Session{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    user.ID,
+		TokenHash: "hash-personal-" + uuid.NewString(),
+		UserAgent: "agent",
+		IPAddress: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, sessions.Create(ctx, session))
+	assert.NoError(t, keys.Create(ctx, &model.APIKey{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Name:      "ci",
+		Prefix:    "ak_personal",
+		KeyHash:   "personal-key-hash",
+		Scopes:    []string{model.PermUsersRead},
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+	_, err := statuses.Change(ctx, &model.UserStatusChange{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Status: model.UserStatusSuspended,
+		Reason: "personal reason",
+	})
+	assert.NoError(t, err)
+
+	t.Run("Collect", func(t *testing.T) {
+		data, err := repo.Collect(ctx, user.ID)
+		assert.NoError(t, err)
+		if !assert.NotNil(t, data) {
+			return
+		}
+		assert.Equal(t, user.Email, data.Profile.Email)
+		assert.Equal(t, []string{"user"}, data.Profile.Roles)
+		assert.Len(t, data.Sessions, 1)
+		assert.Len(t, data.APIKeys, 1)
+		assert.Len(t, data.StatusHistory, 1)
+		assert.Empty(t, data.Organizations)
+
+		missing, err := repo.Collect(ctx, uuid.New())
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("Erase", func(t *testing.T) {
+		before, err := repo.Erase(ctx, user.ID)
+		assert.NoError(t, err)
+		if assert.NotNil(t, before) {
+			assert.Equal(t, user.Email, before.Email)
+		}
+
+		byEmail, err := users.GetByEmail(ctx, user.Email)
+		assert.NoError(t, err)
+		assert.Nil(t, byEmail, "the address is released")
+
+		found, err := sessions.GetByTokenHash(ctx, session.TokenHash)
+		assert.NoError(t, err)
+		if assert.NotNil(t, found) {
+			assert.NotNil(t, found.RevokedAt)
+			assert.Empty(t, found.IPAddress)
+			assert.Empty(t, found.UserAgent)
+		}
+
+		key, err := keys.GetByHash(ctx, "personal-key-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, key)
+
+		history, err := statuses.History(ctx, user.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, history, 1) {
+			assert.Empty(t, history[0].Reason)
+		}
+
+		data, err := repo.Collect(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, data)
+
+		restored, err := users.Restore(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.False(t, restored, "erased users cannot be restored")
+
+		again, err := repo.Erase(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, again)
+	})
+}
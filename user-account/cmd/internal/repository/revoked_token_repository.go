@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type RevokedTokenRepository interface {
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type revokedTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRevokedTokenRepository(db *pgxpool.Pool) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+func (r *revokedTokenRepository) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	stmt := table.RevokedTokens.INSERT(table.RevokedTokens.Jti, table.RevokedTokens.ExpiresAt).
+		MODEL(jet_model.RevokedTokens{Jti: jti, ExpiresAt: expiresAt}).
+		ON_CONFLICT(table.RevokedTokens.Jti).DO_NOTHING()
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+func (r *revokedTokenRepository) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	var dest jet_model.RevokedTokens
+
+	stmt := SELECT(table.RevokedTokens.Jti).
+		FROM(table.RevokedTokens).
+		WHERE(table.RevokedTokens.Jti.EQ(String(jti))).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteExpired удаляет отзывы токенов, истёкших к now: такие токены
+// отклоняются по сроку и без записи в таблице
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	stmt := table.RevokedTokens.DELETE().
+		WHERE(table.RevokedTokens.ExpiresAt.LT(TimestampzT(now)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevokedTokenRepository(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresRevokedTokenRepository(testPool)
+	ctx := context.Background()
+
+	jti := uuid.NewString()
+
+	revoked, err := repo.IsRevoked(ctx, jti)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	assert.NoError(t, repo.Revoke(ctx, jti, time.Now().Add(time.Minute)))
+	// Повторный отзыв не должен падать на уникальности
+	assert.NoError(t, repo.Revoke(ctx, jti, time.Now().Add(time.Minute)))
+
+	revoked, err = repo.IsRevoked(ctx, jti)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	expired := uuid.NewString()
+	assert.NoError(t, repo.Revoke(ctx, expired, time.Now().Add(-time.Minute)))
+
+	deleted, err := repo.DeleteExpired(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, deleted, int64(1))
+
+	revoked, err = repo.IsRevoked(ctx, expired)
+	assert.NoError(t, err)
+	assert.False(t, revoked, "expired revocation is purged")
+
+	revoked, err = repo.IsRevoked(ctx, jti)
+	assert.NoError(t, err)
+	assert.True(t, revoked, "unexpired revocation is kept")
+}
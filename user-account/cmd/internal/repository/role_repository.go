@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type RoleRepository interface {
+	List(ctx context.Context) ([]model.Role, error)
+	Exists(ctx context.Context, name string) (bool, error)
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error)
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+	AssignToUser(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error
+	RevokeFromUser(ctx context.Context, userID uuid.UUID, role string) (bool, error)
+}
+
+type roleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresRoleRepository(db *pgxpool.Pool) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) List(ctx context.Context) ([]model.Role, error) {
+	var dest []struct {
+		jet_model.Roles
+		RolePermissions []jet_model.RolePermissions
+	}
+
+	stmt := SELECT(table.Roles.AllColumns, table.RolePermissions.AllColumns).
+		FROM(table.Roles.LEFT_JOIN(table.RolePermissions, table.RolePermissions.Role.EQ(table.Roles.Name))).
+		ORDER_BY(table.Roles.Name, table.RolePermissions.Permission)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]model.Role, len(dest))
+	for i, role := range dest {
+		roles[i] = model.RoleToDomain(role.Roles, role.RolePermissions)
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) Exists(ctx context.Context, name string) (bool, error) {
+	var dest jet_model.Roles
+
+	stmt := SELECT(table.Roles.Name).
+		FROM(table.Roles).
+		WHERE(table.Roles.Name.EQ(String(name))).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *roleRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var dest []jet_model.UserRoles
+
+	stmt := SELECT(table.UserRoles.AllColumns).
+		FROM(table.UserRoles).
+		WHERE(table.UserRoles.UserID.EQ(UUID(userID))).
+		ORDER_BY(table.UserRoles.Role)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make([]string, len(dest))
+	for i, role := range dest {
+		roles[i] = role.Role
+	}
+	return roles, nil
+}
+
+// GetUserPermissions возвращает объединение прав всех ролей пользователя
+func (r *roleRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var dest []jet_model.RolePermissions
+
+	stmt := SELECT(table.RolePermissions.AllColumns).
+		FROM(table.UserRoles.INNER_JOIN(table.RolePermissions, table.RolePermissions.Role.EQ(table.UserRoles.Role))).
+		WHERE(table.UserRoles.UserID.EQ(UUID(userID)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{}, len(dest))
+	permissions := make([]string, 0, len(dest))
+	for _, p := range dest {
+		if _, ok := seen[p.Permission]; ok {
+			continue
+		}
+		seen[p.Permission] = struct{}{}
+		permissions = append(permissions, p.Permission)
+	}
+	return permissions, nil
+}
+
+// AssignToUser назначает роль; повторное назначение ничего не меняет
+func (r *roleRepository) AssignToUser(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error {
+	stmt := table.UserRoles.INSERT(table.UserRoles.UserID, table.UserRoles.Role, table.UserRoles.GrantedBy).
+		MODEL(jet_model.UserRoles{UserID: userID, Role: role, GrantedBy: &grantedBy}).
+		ON_CONFLICT(table.UserRoles.UserID, table.UserRoles.Role).
+		DO_NOTHING()
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// RevokeFromUser снимает роль. Возвращает false, если роли у пользователя не было.
+func (r *roleRepository) RevokeFromUser(ctx context.Context, userID uuid.UUID, role string) (bool, error) {
+	stmt := table.UserRoles.DELETE().
+		WHERE(
+			table.UserRoles.UserID.EQ(UUID(userID)).
+				AND(table.UserRoles.Role.EQ(String(role))),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresRoleRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "rbac@test.com",
+		Nickname:     "rbac_nick",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	t.Run("List Roles With Permissions", func(t *testing.T) {
+		roles, err := repo.List(ctx)
+		assert.NoError(t, err)
+
+		byName := make(map[string]model.Role, len(roles))
+		for _, r := range roles {
+			byName[r.Name] = r
+		}
+		assert.ElementsMatch(t, []string{"users:read", "users:manage", "roles:manage"}, byName["admin"].Permissions)
+		assert.Empty(t, byName["user"].Permissions)
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		exists, err := repo.Exists(ctx, "admin")
+		assert.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = repo.Exists(ctx, "superuser")
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Assign And Revoke", func(t *testing.T) {
+		perms, err := repo.GetUserPermissions(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Empty(t, perms)
+
+		assert.NoError(t, repo.AssignToUser(ctx, user.ID, "admin", user.ID))
+		// Повторное назначение не должно падать на уникальности
+		assert.NoError(t, repo.AssignToUser(ctx, user.ID, "admin", user.ID))
+
+		roles, err := repo.GetUserRoles(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"admin", "user"}, roles)
+
+		perms, err = repo.GetUserPermissions(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"users:read", "users:manage", "roles:manage"}, perms)
+
+		revoked, err := repo.RevokeFromUser(ctx, user.ID, "admin")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		revoked, err = repo.RevokeFromUser(ctx, user.ID, "admin")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	})
+}
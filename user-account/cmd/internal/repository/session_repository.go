@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *model.Session) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error)
+	MarkRotated(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	IsFamilyRevoked(ctx context.Context, familyID uuid.UUID) (bool, error)
+}
+
+type sessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresSessionRepository(db *pgxpool.Pool) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *model.Session) error {
+	jetSession := jet_model.Sessions{
+		ID:             session.ID,
+		FamilyID:       session.FamilyID,
+		UserID:         session.UserID,
+		OrganizationID: session.OrganizationID,
+		TokenHash:      session.TokenHash,
+		UserAgent:      session.UserAgent,
+		IPAddress:      session.IPAddress,
+		ExpiresAt:      session.ExpiresAt,
+	}
+
+	stmt := table.Sessions.INSERT(
+		table.Sessions.ID,
+		table.Sessions.FamilyID,
+		table.Sessions.UserID,
+		table.Sessions.OrganizationID,
+		table.Sessions.TokenHash,
+		table.Sessions.UserAgent,
+		table.Sessions.IPAddress,
+		table.Sessions.ExpiresAt,
+	).MODEL(jetSession)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+func (r *sessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	var dest jet_model.Sessions
+
+	stmt := SELECT(table.Sessions.AllColumns).
+		FROM(table.Sessions).
+		WHERE(table.Sessions.TokenHash.EQ(String(tokenHash)))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.SessionToDomain(dest)
+	return &res, nil
+}
+
+// MarkRotated помечает сессию использованной. Возвращает false, если сессия
+// уже была ротирована или отозвана (например, параллельным запросом).
+func (r *sessionRepository) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	stmt := table.Sessions.UPDATE(table.Sessions.RotatedAt).
+		SET(NOW()).
+		WHERE(
+			table.Sessions.ID.EQ(UUID(id)).
+				AND(table.Sessions.RotatedAt.IS_NULL()).
+				AND(table.Sessions.RevokedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+func (r *sessionRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	stmt := table.Sessions.UPDATE(table.Sessions.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.Sessions.FamilyID.EQ(UUID(familyID)).
+				AND(table.Sessions.RevokedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+func (r *sessionRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	stmt := table.Sessions.UPDATE(table.Sessions.RevokedAt).
+		SET(NOW()).
+		WHERE(
+			table.Sessions.UserID.EQ(UUID(userID)).
+				AND(table.Sessions.RevokedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+func (r *sessionRepository) IsFamilyRevoked(ctx context.Context, familyID uuid.UUID) (bool, error) {
+	var dest jet_model.Sessions
+
+	stmt := SELECT(table.Sessions.ID).
+		FROM(table.Sessions).
+		WHERE(
+			table.Sessions.FamilyID.EQ(UUID(familyID)).
+				AND(table.Sessions.RevokedAt.IS_NOT_NULL()),
+		).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
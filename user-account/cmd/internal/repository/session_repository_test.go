@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionRepository_Rotation(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresSessionRepository(testPool)
+	ctx := context.Background()
+
+	owner := &model.User{
+		ID:           uuid.New(),
+		Email:        "session_owner@test.com",
+		Nickname:     "session_owner",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, owner))
+
+	familyID := uuid.New()
+	first := &model.Session{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    owner.ID,
+		TokenHash: "hash-first-" + uuid.NewString(),
+		UserAgent: "agent",
+		IPAddress: "127.0.0.1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	second := &model.Session{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		UserID:    owner.ID,
+		TokenHash: "hash-second-" + uuid.NewString(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, repo.Create(ctx, first))
+	assert.NoError(t, repo.Create(ctx, second))
+
+	t.Run("GetByTokenHash", func(t *testing.T) {
+		found, err := repo.GetByTokenHash(ctx, first.TokenHash)
+		assert.NoError(t, err)
+		assert.Equal(t, first.ID, found.ID)
+		assert.Equal(t, "agent", found.UserAgent)
+		assert.Nil(t, found.RotatedAt)
+
+		missing, err := repo.GetByTokenHash(ctx, "unknown-hash")
+		assert.NoError(t, err)
+		assert.Nil(t, missing)
+	})
+
+	t.Run("MarkRotated Only Once", func(t *testing.T) {
+		ok, err := repo.MarkRotated(ctx, first.ID)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = repo.MarkRotated(ctx, first.ID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("RevokeFamily", func(t *testing.T) {
+		revoked, err := repo.IsFamilyRevoked(ctx, familyID)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+
+		assert.NoError(t, repo.RevokeFamily(ctx, familyID))
+
+		revoked, err = repo.IsFamilyRevoked(ctx, familyID)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+
+		found, err := repo.GetByTokenHash(ctx, second.TokenHash)
+		assert.NoError(t, err)
+		assert.NotNil(t, found.RevokedAt)
+
+		ok, err := repo.MarkRotated(ctx, second.ID)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestSessionRepository_RevokeAllForUser(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresSessionRepository(testPool)
+	ctx := context.Background()
+
+	owner := &model.User{
+		ID:           uuid.New(),
+		Email:        "many_devices@test.com",
+		Nickname:     "many_devices",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, owner))
+
+	families := []uuid.UUID{uuid.New(), uuid.New()}
+	for _, familyID := range families {
+		assert.NoError(t, repo.Create(ctx, &model.Session{
+			ID:        uuid.New(),
+			FamilyID:  familyID,
+			UserID:    owner.ID,
+			TokenHash: "hash-" + uuid.NewString(),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}))
+	}
+
+	assert.NoError(t, repo.RevokeAllForUser(ctx, owner.ID))
+
+	for _, familyID := range families {
+		revoked, err := repo.IsFamilyRevoked(ctx, familyID)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type TOTPRepository interface {
+	Get(ctx context.Context, userID uuid.UUID) (*model.TOTP, error)
+	SavePending(ctx context.Context, userID uuid.UUID, secret string) error
+	Confirm(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) (bool, error)
+	MarkStepUsed(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+type totpRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresTOTPRepository(db *pgxpool.Pool) TOTPRepository {
+	return &totpRepository{db: db}
+}
+
+func (r *totpRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TOTP, error) {
+	var dest jet_model.UserTotp
+
+	stmt := SELECT(table.UserTotp.AllColumns).
+		FROM(table.UserTotp).
+		WHERE(table.UserTotp.UserID.EQ(UUID(userID))).
+		LIMIT(1)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	err := stmt.QueryContext(ctx, db, &dest)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	res := model.TOTPToDomain(dest)
+	return &res, nil
+}
+
+// SavePending сохраняет новый неподтверждённый секрет, заменяя прежний
+// неподтверждённый. Подтверждённый секрет не трогает.
+func (r *totpRepository) SavePending(ctx context.Context, userID uuid.UUID, secret string) error {
+	stmt := table.UserTotp.INSERT(table.UserTotp.UserID, table.UserTotp.Secret).
+		MODEL(jet_model.UserTotp{UserID: userID, Secret: secret}).
+		ON_CONFLICT(table.UserTotp.UserID).
+		DO_UPDATE(
+			SET(
+				table.UserTotp.Secret.SET(table.UserTotp.EXCLUDED.Secret),
+				table.UserTotp.LastUsedStep.SET(Int(0)),
+				table.UserTotp.CreatedAt.SET(TimestampzExp(NOW())),
+			).WHERE(table.UserTotp.ConfirmedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	_, err := stmt.ExecContext(ctx, db)
+	return err
+}
+
+// Confirm включает фактор и заменяет коды восстановления. step - окно кода,
+// которым фактор подтвердили, чтобы этот же код не прошёл при входе.
+// Возвращает false, если неподтверждённого секрета нет.
+func (r *totpRepository) Confirm(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) (bool, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	confirmStmt := table.UserTotp.UPDATE(table.UserTotp.ConfirmedAt, table.UserTotp.LastUsedStep).
+		SET(NOW(), Int(step)).
+		WHERE(
+			table.UserTotp.UserID.EQ(UUID(userID)).
+				AND(table.UserTotp.ConfirmedAt.IS_NULL()),
+		)
+	result, err := confirmStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := result.RowsAffected(); rows != 1 {
+		return false, nil
+	}
+
+	if err = replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// MarkStepUsed запоминает принятое окно. Возвращает false, если это или
+// более позднее окно уже использовано.
+func (r *totpRepository) MarkStepUsed(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	stmt := table.UserTotp.UPDATE(table.UserTotp.LastUsedStep).
+		SET(Int(step)).
+		WHERE(
+			table.UserTotp.UserID.EQ(UUID(userID)).
+				AND(table.UserTotp.LastUsedStep.LT(Int(step))),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// ConsumeRecoveryCode гасит код восстановления. Возвращает false, если
+// такого неиспользованного кода нет.
+func (r *totpRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	stmt := table.RecoveryCodes.UPDATE(table.RecoveryCodes.UsedAt).
+		SET(NOW()).
+		WHERE(
+			table.RecoveryCodes.UserID.EQ(UUID(userID)).
+				AND(table.RecoveryCodes.CodeHash.EQ(String(codeHash))).
+				AND(table.RecoveryCodes.UsedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// Delete выключает фактор вместе с кодами восстановления
+func (r *totpRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = table.UserTotp.DELETE().WHERE(table.UserTotp.UserID.EQ(UUID(userID))).ExecContext(ctx, tx); err != nil {
+		return err
+	}
+	if err = replaceRecoveryCodes(ctx, tx, userID, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx qrm.Executable, userID uuid.UUID, hashes []string) error {
+	deleteStmt := table.RecoveryCodes.DELETE().WHERE(table.RecoveryCodes.UserID.EQ(UUID(userID)))
+	if _, err := deleteStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	codes := make([]jet_model.RecoveryCodes, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = jet_model.RecoveryCodes{ID: uuid.New(), UserID: userID, CodeHash: hash}
+	}
+	insertStmt := table.RecoveryCodes.INSERT(table.RecoveryCodes.ID, table.RecoveryCodes.UserID, table.RecoveryCodes.CodeHash).
+		MODELS(codes)
+	_, err := insertStmt.ExecContext(ctx, tx)
+	return err
+}
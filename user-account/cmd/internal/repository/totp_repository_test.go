@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresTOTPRepository(testPool)
+	ctx := context.Background()
+
+	user := &model.User{
+		ID:           uuid.New(),
+		Email:        "totp@test.com",
+		Nickname:     "totp_nick",
+		PasswordHash: "h",
+		Roles:        []string{"user"},
+	}
+	assert.NoError(t, users.Create(ctx, user))
+
+	t.Run("Enroll And Confirm", func(t *testing.T) {
+		assert.NoError(t, repo.SavePending(ctx, user.ID, "FIRST"))
+		// Повторный выпуск до подтверждения заменяет секрет
+		assert.NoError(t, repo.SavePending(ctx, user.ID, "SECOND"))
+
+		current, err := repo.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "SECOND", current.Secret)
+		assert.False(t, current.Enabled())
+
+		confirmed, err := repo.Confirm(ctx, user.ID, 100, []string{"hash-1", "hash-2"})
+		assert.NoError(t, err)
+		assert.True(t, confirmed)
+
+		// Подтверждённый секрет новым выпуском не перезаписывается
+		assert.NoError(t, repo.SavePending(ctx, user.ID, "THIRD"))
+		current, err = repo.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "SECOND", current.Secret)
+		assert.True(t, current.Enabled())
+		assert.Equal(t, int64(100), current.LastUsedStep)
+	})
+
+	t.Run("Step Is Single Use", func(t *testing.T) {
+		fresh, err := repo.MarkStepUsed(ctx, user.ID, 100)
+		assert.NoError(t, err)
+		assert.False(t, fresh)
+
+		fresh, err = repo.MarkStepUsed(ctx, user.ID, 101)
+		assert.NoError(t, err)
+		assert.True(t, fresh)
+	})
+
+	t.Run("Recovery Code Is Single Use", func(t *testing.T) {
+		consumed, err := repo.ConsumeRecoveryCode(ctx, user.ID, "hash-1")
+		assert.NoError(t, err)
+		assert.True(t, consumed)
+
+		consumed, err = repo.ConsumeRecoveryCode(ctx, user.ID, "hash-1")
+		assert.NoError(t, err)
+		assert.False(t, consumed)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(ctx, user.ID))
+
+		current, err := repo.Get(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, current)
+
+		consumed, err := repo.ConsumeRecoveryCode(ctx, user.ID, "hash-2")
+		assert.NoError(t, err)
+		assert.False(t, consumed)
+	})
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 	"user-account/cmd/internal/gen/docflow/public/table"
 
 	jet_model "user-account/cmd/internal/gen/docflow/public/model"
 	"user-account/cmd/internal/model"
 
 	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
@@ -17,11 +20,20 @@ import (
 
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
+	CreateBatch(ctx context.Context, users []*model.User, orgID *uuid.UUID) error
+	ExistingAccounts(ctx context.Context, emails, nicknames []string) ([]model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID) (bool, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 	UpdatePassword(ctx context.Context, id uuid.UUID, hash string) error
-	List(ctx context.Context) ([]model.User, error)
+	RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) (bool, error)
+	UpdateProfile(ctx context.Context, id uuid.UUID, nickname string, preferences model.Preferences) error
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error)
+	List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error)
+	Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error)
+	ForEach(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error
 }
 
 type userRepository struct {
@@ -32,64 +44,191 @@ func NewPostgresUserRepository(db *pgxpool.Pool) UserRepository {
 	return &userRepository{db: db}
 }
 
+// userWithRoles - пользователь вместе с назначенными ролями
+type userWithRoles struct {
+	jet_model.Users
+	UserRoles []jet_model.UserRoles
+}
+
+func (u userWithRoles) toDomain() model.User {
+	res := model.ToDomain(u.Users)
+	res.Roles = make([]string, len(u.UserRoles))
+	for i, role := range u.UserRoles {
+		res.Roles[i] = role.Role
+	}
+	return res
+}
+
+// Create сохраняет пользователя вместе с его ролями в одной транзакции
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	jetUser := jet_model.Users{
-		ID:           user.ID,
-		Email:        user.Email,
-		Nickname:     user.Nickname,
-		PasswordHash: user.PasswordHash,
-		Role:         user.Role,
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = insertUsers(ctx, tx, []*model.User{user}); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
-	stmt := table.Users.INSERT(table.Users.ID, table.Users.Email, table.Users.Nickname, table.Users.PasswordHash, table.Users.Role).
-		MODEL(jetUser)
+// CreateBatch сохраняет пользователей с ролями в одной транзакции: либо
+// все, либо никого. С orgID пользователи сразу становятся участниками
+// организации.
+func (r *userRepository) CreateBatch(ctx context.Context, users []*model.User, orgID *uuid.UUID) error {
+	if len(users) == 0 {
+		return nil
+	}
 
 	db := stdlib.OpenDBFromPool(r.db)
-	_, err := stmt.ExecContext(ctx, db)
-	return err
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = insertUsers(ctx, tx, users); err != nil {
+		return err
+	}
+
+	if orgID != nil {
+		members := make([]jet_model.OrganizationMembers, len(users))
+		for i, user := range users {
+			members[i] = jet_model.OrganizationMembers{OrganizationID: *orgID, UserID: user.ID, Role: model.OrgRoleMember}
+		}
+		stmt := table.OrganizationMembers.INSERT(
+			table.OrganizationMembers.OrganizationID,
+			table.OrganizationMembers.UserID,
+			table.OrganizationMembers.Role,
+		).MODELS(members)
+		if _, err = stmt.ExecContext(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
-	var dest jet_model.Users
+func insertUsers(ctx context.Context, tx *sql.Tx, users []*model.User) error {
+	jetUsers := make([]jet_model.Users, len(users))
+	var roles []jet_model.UserRoles
+	for i, user := range users {
+		jetUsers[i] = jet_model.Users{
+			ID:              user.ID,
+			Email:           user.Email,
+			Nickname:        user.Nickname,
+			PasswordHash:    user.PasswordHash,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+		}
+		for _, role := range user.Roles {
+			roles = append(roles, jet_model.UserRoles{UserID: user.ID, Role: role})
+		}
+	}
+
+	stmt := table.Users.INSERT(table.Users.ID, table.Users.Email, table.Users.Nickname, table.Users.PasswordHash, table.Users.EmailVerifiedAt).
+		MODELS(jetUsers)
+	if _, err := stmt.ExecContext(ctx, tx); err != nil {
+		return uniqueUserError(err)
+	}
+
+	if len(roles) > 0 {
+		rolesStmt := table.UserRoles.INSERT(table.UserRoles.UserID, table.UserRoles.Role).
+			MODELS(roles)
+		if _, err := rolesStmt.ExecContext(ctx, tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExistingAccounts находит пользователей, у которых почта из emails или ник
+// из nicknames. Заполнены только ID, Email и Nickname. Удалённые тоже
+// попадают в выдачу: до окончательной очистки их почта и ник заняты.
+func (r *userRepository) ExistingAccounts(ctx context.Context, emails, nicknames []string) ([]model.User, error) {
+	if len(emails) == 0 && len(nicknames) == 0 {
+		return nil, nil
+	}
+
+	condition := Bool(false)
+	if len(emails) > 0 {
+		condition = condition.OR(table.Users.Email.IN(stringList(emails)...))
+	}
+	if len(nicknames) > 0 {
+		condition = condition.OR(table.Users.Nickname.IN(stringList(nicknames)...))
+	}
 
-	stmt := SELECT(table.Users.AllColumns).
+	var dest []jet_model.Users
+	stmt := SELECT(table.Users.ID, table.Users.Email, table.Users.Nickname).
 		FROM(table.Users).
-		WHERE(table.Users.Email.EQ(String(email)))
+		WHERE(condition)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	res := make([]model.User, len(dest))
+	for i, u := range dest {
+		res[i] = model.User{ID: u.ID, Email: u.Email, Nickname: u.Nickname}
+	}
+	return res, nil
+}
+
+func stringList(values []string) []Expression {
+	res := make([]Expression, len(values))
+	for i, v := range values {
+		res[i] = String(v)
+	}
+	return res
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	var dest userWithRoles
+
+	stmt := SELECT(table.Users.AllColumns, table.UserRoles.UserID, table.UserRoles.Role).
+		FROM(table.Users.LEFT_JOIN(table.UserRoles, table.UserRoles.UserID.EQ(table.Users.ID))).
+		WHERE(table.Users.Email.EQ(String(email)).AND(notDeleted()))
 
 	db := stdlib.OpenDBFromPool(r.db)
 	err := stmt.QueryContext(ctx, db, &dest)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, qrm.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	res := model.ToDomain(dest)
+	res := dest.toDomain()
 	return &res, nil
 }
 func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
-	var dest jet_model.Users
+	var dest userWithRoles
 
-	stmt := SELECT(table.Users.AllColumns).
-		FROM(table.Users).
-		WHERE(table.Users.ID.EQ(UUID(id)))
+	stmt := SELECT(table.Users.AllColumns, table.UserRoles.UserID, table.UserRoles.Role).
+		FROM(table.Users.LEFT_JOIN(table.UserRoles, table.UserRoles.UserID.EQ(table.Users.ID))).
+		WHERE(table.Users.ID.EQ(UUID(id)).AND(notDeleted()))
 
 	db := stdlib.OpenDBFromPool(r.db)
 	err := stmt.QueryContext(ctx, db, &dest)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, qrm.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	res := model.ToDomain(dest)
+	res := dest.toDomain()
 	return &res, nil
 }
 
+// Delete помечает пользователя удалённым. Строка и всё, что на неё
+// ссылается, остаются до Purge, поэтому удаление можно отменить.
 func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	stmt := table.Users.DELETE().WHERE(table.Users.ID.EQ(UUID(id)))
+	stmt := table.Users.UPDATE(table.Users.DeletedAt).
+		SET(NOW()).
+		WHERE(table.Users.ID.EQ(UUID(id)).AND(notDeleted()))
 
 	db := stdlib.OpenDBFromPool(r.db)
 	result, err := stmt.ExecContext(ctx, db)
@@ -104,10 +243,47 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// Restore отменяет удаление. Возвращает false, если пользователь не
+// удалён, уже удалён окончательно или стёрт.
+func (r *userRepository) Restore(ctx context.Context, id uuid.UUID) (bool, error) {
+	stmt := table.Users.UPDATE(table.Users.DeletedAt).
+		SET(NULL).
+		WHERE(
+			table.Users.ID.EQ(UUID(id)).
+				AND(table.Users.DeletedAt.IS_NOT_NULL()).
+				AND(table.Users.ErasedAt.IS_NULL()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// Purge окончательно удаляет пользователей, удалённых раньше deletedBefore.
+// Сессии, ключи и прочие связанные записи удаляются каскадом. Стёртые
+// пользователи остаются: личных данных в них уже нет, а на них ссылаются
+// история и журналы.
+func (r *userRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	stmt := table.Users.DELETE().
+		WHERE(table.Users.DeletedAt.LT(TimestampzT(deletedBefore)).AND(table.Users.ErasedAt.IS_NULL()))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hash string) error {
 	stmt := table.Users.UPDATE(table.Users.PasswordHash).
 		SET(String(hash)).
-		WHERE(table.Users.ID.EQ(UUID(id)))
+		WHERE(table.Users.ID.EQ(UUID(id)).AND(notDeleted()))
 
 	db := stdlib.OpenDBFromPool(r.db)
 	result, err := stmt.ExecContext(ctx, db)
@@ -121,22 +297,388 @@ func (r *userRepository) UpdatePassword(ctx context.Context, id uuid.UUID, hash
 	}
 	return nil
 }
-func (r *userRepository) List(ctx context.Context) ([]model.User, error) {
+
+// UpdateProfile меняет поля, которые пользователь редактирует сам
+func (r *userRepository) UpdateProfile(ctx context.Context, id uuid.UUID, nickname string, preferences model.Preferences) error {
+	prefs, err := jsonb(preferences)
+	if err != nil {
+		return err
+	}
+
+	stmt := table.Users.UPDATE(table.Users.Nickname, table.Users.Preferences).
+		SET(String(nickname), prefs).
+		WHERE(table.Users.ID.EQ(UUID(id)).AND(notDeleted()))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return uniqueUserError(err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// RehashPassword заменяет хэш того же пароля пересчитанным. Возвращает
+// false, если пароль успели сменить и хэш уже не oldHash.
+func (r *userRepository) RehashPassword(ctx context.Context, id uuid.UUID, oldHash, newHash string) (bool, error) {
+	stmt := table.Users.UPDATE(table.Users.PasswordHash).
+		SET(String(newHash)).
+		WHERE(
+			table.Users.ID.EQ(UUID(id)).
+				AND(table.Users.PasswordHash.EQ(String(oldHash))).
+				AND(notDeleted()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// MarkEmailVerified подтверждает почту, если у пользователя всё ещё тот же
+// адрес. Возвращает false, если адрес успел смениться.
+func (r *userRepository) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) (bool, error) {
+	stmt := table.Users.UPDATE(table.Users.EmailVerifiedAt).
+		SET(NOW()).
+		WHERE(
+			table.Users.ID.EQ(UUID(id)).
+				AND(table.Users.Email.EQ(String(email))).
+				AND(notDeleted()),
+		)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	result, err := stmt.ExecContext(ctx, db)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+// notDeleted отсекает удалённых пользователей. Удалённые не видны ни в
+// одной выборке, кроме восстановления и окончательной очистки.
+func notDeleted() BoolExpression {
+	return table.Users.DeletedAt.IS_NULL()
+}
+
+// uniqueUserError переводит нарушение уникальности email/nickname в понятную ошибку
+func uniqueUserError(err error) error {
+	switch {
+	case isUniqueViolation(err, "users_email_key"):
+		return ErrEmailTaken
+	case isUniqueViolation(err, "users_nickname_key"):
+		return ErrNicknameTaken
+	default:
+		return err
+	}
+}
+
+// List возвращает страницу участников организации query.OrganizationID.
+// Страницы листаются курсором по полю сортировки и ID, поэтому вставки и
+// удаления между запросами не сдвигают выдачу. Пользователи выбираются
+// отдельно от ролей: соединение с ролями размножило бы строки под LIMIT.
+func (r *userRepository) List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error) {
+	condition := userListCondition(query)
+
+	where := condition
+	if query.After != nil {
+		after, err := userListAfter(query)
+		if err != nil {
+			return nil, err
+		}
+		where = where.AND(after)
+	}
+
+	sortColumn, orderBy := userListOrder(query)
+	stmt := SELECT(table.Users.ID, table.Users.Email, table.Users.Nickname, table.Users.CreatedAt).
+		FROM(table.Users.INNER_JOIN(table.OrganizationMembers, table.OrganizationMembers.UserID.EQ(table.Users.ID))).
+		WHERE(where).
+		ORDER_BY(orderBy(sortColumn), orderBy(table.Users.ID)).
+		LIMIT(int64(query.Limit) + 1)
+
 	var dest []jet_model.Users
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
 
-	stmt := SELECT(table.Users.ID, table.Users.Email, table.Users.Nickname, table.Users.Role, table.Users.CreatedAt).
-		FROM(table.Users)
+	page := &model.UserPage{Users: make([]model.User, 0, len(dest))}
+	if len(dest) > query.Limit {
+		dest = dest[:query.Limit]
+		last := model.ToDomain(dest[len(dest)-1])
+		page.NextCursor = model.NewUserCursor(last, query.SortBy, query.Descending).Encode()
+	}
 
+	ids := make([]Expression, len(dest))
+	for i, u := range dest {
+		ids[i] = UUID(u.ID)
+	}
+	roles, err := r.rolesOf(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range dest {
+		user := model.ToDomain(u)
+		user.Roles = roles[u.ID]
+		if user.Roles == nil {
+			user.Roles = []string{}
+		}
+		page.Users = append(page.Users, user)
+	}
+
+	if query.WithTotal {
+		total, err := r.countUsers(ctx, condition)
+		if err != nil {
+			return nil, err
+		}
+		page.Total = &total
+	}
+	return page, nil
+}
+
+// userSearchDocument - документ полнотекстового поиска. То же выражение
+// стоит в индексе users_search_document_idx, иначе индекс не применится.
+const userSearchDocument = `to_tsvector('simple', users.nickname || ' ' || translate(users.email, '@.-_+', '     '))`
+
+// Search ищет участников организации query.OrganizationID. Пользователь
+// находится, если строка поиска похожа на часть его ника или адреса
+// (триграммы pg_trgm, это ловит опечатки) или все её слова начинают слова
+// ника и адреса (полнотекстовый поиск). Выдача отсортирована по убыванию
+// релевантности: сходства триграмм и ранга полнотекстового совпадения.
+func (r *userRepository) Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error) {
+	text := RawArgs{"#text": strings.ToLower(query.Text)}
+	match := RawBool("(#text <% LOWER(users.nickname) OR #text <% LOWER(users.email))", text)
+	rank := RawFloat("GREATEST(word_similarity(#text, LOWER(users.nickname)), word_similarity(#text, LOWER(users.email)))", text)
+
+	if tsQuery := userSearchTSQuery(query.Text); tsQuery != "" {
+		words := RawArgs{"#query": tsQuery}
+		match = match.OR(RawBool(userSearchDocument+" @@ to_tsquery('simple', #query)", words))
+		rank = rank.ADD(RawFloat("ts_rank("+userSearchDocument+", to_tsquery('simple', #query))", words))
+	}
+
+	stmt := SELECT(table.Users.ID, table.Users.Email, table.Users.Nickname, table.Users.CreatedAt, rank.AS("rank")).
+		FROM(table.Users.INNER_JOIN(table.OrganizationMembers, table.OrganizationMembers.UserID.EQ(table.Users.ID))).
+		WHERE(table.OrganizationMembers.OrganizationID.EQ(UUID(query.OrganizationID)).AND(notDeleted()).AND(match)).
+		ORDER_BY(FloatColumn("rank").DESC(), table.Users.ID.ASC()).
+		LIMIT(int64(query.Limit))
+
+	var dest []struct {
+		jet_model.Users
+		Rank float64 `alias:"rank"`
+	}
 	db := stdlib.OpenDBFromPool(r.db)
-	err := stmt.QueryContext(ctx, db, &dest)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	ids := make([]Expression, len(dest))
+	for i, u := range dest {
+		ids[i] = UUID(u.ID)
+	}
+	roles, err := r.rolesOf(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
 
-	users := make([]model.User, len(dest))
+	hits := make([]model.UserSearchHit, len(dest))
 	for i, u := range dest {
-		users[i] = model.ToDomain(u)
+		user := model.ToDomain(u.Users)
+		user.Roles = roles[u.ID]
+		if user.Roles == nil {
+			user.Roles = []string{}
+		}
+		hits[i] = model.UserSearchHit{User: user, Rank: u.Rank}
+	}
+	return hits, nil
+}
+
+// userSearchTSQuery - запрос to_tsquery: все слова строки поиска по
+// префиксу. В словах только буквы и цифры, экранировать нечего.
+func userSearchTSQuery(text string) string {
+	terms := model.SearchTerms(text)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// userRolesList - роли пользователя через запятую, по алфавиту
+const userRolesList = `array_to_string(ARRAY(SELECT user_roles.role FROM user_roles WHERE user_roles.user_id = users.id ORDER BY user_roles.role), ',')`
+
+// ForEach передаёт fn всех участников организации, подходящих под фильтры
+// query, в порядке его сортировки. Строки читаются курсором базы по одной,
+// поэтому выборка любого размера не собирается в памяти. Limit и After не
+// применяются. Ошибка fn прерывает обход и возвращается как есть.
+func (r *userRepository) ForEach(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error {
+	sortColumn, orderBy := userListOrder(query)
+	stmt := SELECT(
+		table.Users.ID,
+		table.Users.Email,
+		table.Users.Nickname,
+		table.Users.EmailVerifiedAt,
+		table.Users.Status,
+		table.Users.CreatedAt,
+		RawString(userRolesList).AS("roles"),
+	).
+		FROM(table.Users.INNER_JOIN(table.OrganizationMembers, table.OrganizationMembers.UserID.EQ(table.Users.ID))).
+		WHERE(userListCondition(query)).
+		ORDER_BY(orderBy(sortColumn), orderBy(table.Users.ID))
+
+	db := stdlib.OpenDBFromPool(r.db)
+	rows, err := stmt.Rows(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var dest struct {
+			jet_model.Users
+			Roles string `alias:"roles"`
+		}
+		if err = rows.Scan(&dest); err != nil {
+			return err
+		}
+
+		user := model.ToDomain(dest.Users)
+		user.Roles = []string{}
+		if dest.Roles != "" {
+			user.Roles = strings.Split(dest.Roles, ",")
+		}
+		if err = fn(user); err != nil {
+			return err
+		}
 	}
+	return rows.Err()
+}
+
+// rolesOf возвращает роли пользователей ids
+func (r *userRepository) rolesOf(ctx context.Context, ids []Expression) (map[uuid.UUID][]string, error) {
+	roles := make(map[uuid.UUID][]string, len(ids))
+	if len(ids) == 0 {
+		return roles, nil
+	}
+
+	var dest []jet_model.UserRoles
+	stmt := SELECT(table.UserRoles.UserID, table.UserRoles.Role).
+		FROM(table.UserRoles).
+		WHERE(table.UserRoles.UserID.IN(ids...)).
+		ORDER_BY(table.UserRoles.UserID, table.UserRoles.Role)
 
-	return users, nil
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	for _, role := range dest {
+		roles[role.UserID] = append(roles[role.UserID], role.Role)
+	}
+	return roles, nil
 }
+
+func (r *userRepository) countUsers(ctx context.Context, condition BoolExpression) (int, error) {
+	var dest struct {
+		Count int64 `alias:"count"`
+	}
+
+	stmt := SELECT(COUNT(STAR).AS("count")).
+		FROM(table.Users.INNER_JOIN(table.OrganizationMembers, table.OrganizationMembers.UserID.EQ(table.Users.ID))).
+		WHERE(condition)
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return 0, err
+	}
+	return int(dest.Count), nil
+}
+
+// userListCondition - фильтры списка без учёта курсора. Диапазон дат
+// создания полуоткрытый: [CreatedFrom, CreatedTo).
+func userListCondition(query model.UserListQuery) BoolExpression {
+	condition := table.OrganizationMembers.OrganizationID.EQ(UUID(query.OrganizationID)).
+		AND(notDeleted())
+
+	if query.Role != "" {
+		condition = condition.AND(EXISTS(
+			SELECT(Int(1)).
+				FROM(table.UserRoles).
+				WHERE(
+					table.UserRoles.UserID.EQ(table.Users.ID).
+						AND(table.UserRoles.Role.EQ(String(query.Role))),
+				),
+		))
+	}
+	if query.Email != "" {
+		condition = condition.AND(LOWER(table.Users.Email).LIKE(String(containsPattern(query.Email))))
+	}
+	if query.Nickname != "" {
+		condition = condition.AND(LOWER(table.Users.Nickname).LIKE(String(containsPattern(query.Nickname))))
+	}
+	if query.CreatedFrom != nil {
+		condition = condition.AND(table.Users.CreatedAt.GT_EQ(TimestampzT(*query.CreatedFrom)))
+	}
+	if query.CreatedTo != nil {
+		condition = condition.AND(table.Users.CreatedAt.LT(TimestampzT(*query.CreatedTo)))
+	}
+	return condition
+}
+
+// userListAfter - условие "после курсора" в порядке сортировки. При равных
+// значениях поля порядок определяет ID.
+func userListAfter(query model.UserListQuery) (BoolExpression, error) {
+	var greater, less, equal BoolExpression
+	switch query.SortBy {
+	case model.UserSortEmail:
+		value := String(query.After.Value)
+		greater, less, equal = table.Users.Email.GT(value), table.Users.Email.LT(value), table.Users.Email.EQ(value)
+	case model.UserSortNickname:
+		value := String(query.After.Value)
+		greater, less, equal = table.Users.Nickname.GT(value), table.Users.Nickname.LT(value), table.Users.Nickname.EQ(value)
+	default:
+		createdAt, err := query.After.CreatedAt()
+		if err != nil {
+			return nil, err
+		}
+		value := TimestampzT(createdAt)
+		greater, less, equal = table.Users.CreatedAt.GT(value), table.Users.CreatedAt.LT(value), table.Users.CreatedAt.EQ(value)
+	}
+
+	id := UUID(query.After.ID)
+	if query.Descending {
+		return less.OR(equal.AND(table.Users.ID.LT(id))), nil
+	}
+	return greater.OR(equal.AND(table.Users.ID.GT(id))), nil
+}
+
+// userListOrder возвращает колонку сортировки и направление
+func userListOrder(query model.UserListQuery) (Column, func(Column) OrderByClause) {
+	var column Column = table.Users.CreatedAt
+	switch query.SortBy {
+	case model.UserSortEmail:
+		column = table.Users.Email
+	case model.UserSortNickname:
+		column = table.Users.Nickname
+	}
+
+	if query.Descending {
+		return column, func(c Column) OrderByClause { return c.DESC() }
+	}
+	return column, func(c Column) OrderByClause { return c.ASC() }
+}
+
+// containsPattern - шаблон LIKE для поиска подстроки без учёта регистра.
+// Спецсимволы LIKE в подстроке экранируются.
+func containsPattern(substring string) string {
+	return "%" + likeEscaper.Replace(strings.ToLower(substring)) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"testing"
 	"time"
 	"user-account/cmd/internal/model"
@@ -53,9 +55,255 @@ func TestMain(m *testing.M) {
        email         TEXT UNIQUE NOT NULL,
        nickname      TEXT UNIQUE NOT NULL,
        password_hash TEXT NOT NULL,
-       role          TEXT NOT NULL,
+       preferences   JSONB NOT NULL DEFAULT '{}',
+       email_verified_at TIMESTAMPTZ,
+       created_at    TIMESTAMPTZ DEFAULT NOW(),
+       deleted_at    TIMESTAMPTZ,
+       status        TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'locked')),
+       status_reason TEXT NOT NULL DEFAULT '',
+       status_expires_at TIMESTAMPTZ,
+       erased_at     TIMESTAMPTZ
+    );
+
+    CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+    CREATE TABLE IF NOT EXISTS organizations (
+       id         UUID PRIMARY KEY,
+       name       TEXT NOT NULL,
+       created_at TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS organization_members (
+       organization_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+       user_id         UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       role            TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+       created_at      TIMESTAMPTZ DEFAULT NOW(),
+       PRIMARY KEY (organization_id, user_id)
+    );
+
+    CREATE TABLE IF NOT EXISTS sessions (
+       id         UUID PRIMARY KEY,
+       family_id  UUID NOT NULL,
+       user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       token_hash TEXT UNIQUE NOT NULL,
+       user_agent TEXT NOT NULL DEFAULT '',
+       ip_address TEXT NOT NULL DEFAULT '',
+       expires_at TIMESTAMPTZ NOT NULL,
+       created_at TIMESTAMPTZ DEFAULT NOW(),
+       rotated_at TIMESTAMPTZ,
+       revoked_at TIMESTAMPTZ,
+       organization_id UUID REFERENCES organizations (id) ON DELETE SET NULL
+    );
+
+    CREATE TABLE IF NOT EXISTS revoked_tokens (
+       jti        TEXT PRIMARY KEY,
+       expires_at TIMESTAMPTZ NOT NULL,
+       revoked_at TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS roles (
+       name        TEXT PRIMARY KEY,
+       description TEXT NOT NULL DEFAULT '',
+       created_at  TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS permissions (
+       name        TEXT PRIMARY KEY,
+       description TEXT NOT NULL DEFAULT ''
+    );
+
+    CREATE TABLE IF NOT EXISTS role_permissions (
+       role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+       permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+       PRIMARY KEY (role, permission)
+    );
+
+    CREATE TABLE IF NOT EXISTS user_roles (
+       user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+       granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+       granted_at TIMESTAMPTZ DEFAULT NOW(),
+       PRIMARY KEY (user_id, role)
+    );
+
+    CREATE TABLE IF NOT EXISTS email_verifications (
+       id         UUID PRIMARY KEY,
+       user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       email      TEXT NOT NULL,
+       expires_at TIMESTAMPTZ NOT NULL,
+       used_at    TIMESTAMPTZ,
+       created_at TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS password_resets (
+       id         UUID PRIMARY KEY,
+       user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       token_hash TEXT NOT NULL UNIQUE,
+       expires_at TIMESTAMPTZ NOT NULL,
+       used_at    TIMESTAMPTZ,
+       created_at TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS user_totp (
+       user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+       secret         TEXT NOT NULL,
+       confirmed_at   TIMESTAMPTZ,
+       last_used_step BIGINT NOT NULL DEFAULT 0,
+       created_at     TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS recovery_codes (
+       id         UUID PRIMARY KEY,
+       user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       code_hash  TEXT NOT NULL,
+       used_at    TIMESTAMPTZ,
+       created_at TIMESTAMPTZ DEFAULT NOW(),
+       UNIQUE (user_id, code_hash)
+    );
+
+    CREATE TABLE IF NOT EXISTS login_attempts (
+       key             TEXT PRIMARY KEY,
+       failures        INTEGER NOT NULL,
+       last_failure_at TIMESTAMPTZ NOT NULL,
+       locked_until    TIMESTAMPTZ
+    );
+
+    CREATE TABLE IF NOT EXISTS api_keys (
+       id           UUID PRIMARY KEY,
+       user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       name         TEXT NOT NULL,
+       prefix       TEXT NOT NULL,
+       key_hash     TEXT NOT NULL UNIQUE,
+       scopes       TEXT NOT NULL DEFAULT '',
+       expires_at   TIMESTAMPTZ NOT NULL,
+       last_used_at TIMESTAMPTZ,
+       revoked_at   TIMESTAMPTZ,
+       created_at   TIMESTAMPTZ DEFAULT NOW(),
+       organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE
+    );
+
+    CREATE TABLE IF NOT EXISTS user_identities (
+       id            UUID PRIMARY KEY,
+       user_id       UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+       provider      TEXT NOT NULL,
+       subject       TEXT NOT NULL,
+       email         TEXT NOT NULL DEFAULT '',
+       last_login_at TIMESTAMPTZ,
+       created_at    TIMESTAMPTZ DEFAULT NOW(),
+       CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject),
+       CONSTRAINT user_identities_user_id_provider_key UNIQUE (user_id, provider)
+    );
+
+    CREATE TABLE IF NOT EXISTS oidc_login_states (
+       state_hash    TEXT PRIMARY KEY,
+       provider      TEXT NOT NULL,
+       nonce         TEXT NOT NULL,
+       code_verifier TEXT NOT NULL,
+       binding_hash  TEXT NOT NULL,
+       expires_at    TIMESTAMPTZ NOT NULL,
        created_at    TIMESTAMPTZ DEFAULT NOW()
-    );`
+    );
+
+    CREATE TABLE IF NOT EXISTS oauth_clients (
+       id            UUID PRIMARY KEY,
+       name          TEXT NOT NULL,
+       secret_hash   TEXT NOT NULL DEFAULT '',
+       redirect_uris TEXT NOT NULL DEFAULT '',
+       grant_types   TEXT NOT NULL DEFAULT '',
+       scopes        TEXT NOT NULL DEFAULT '',
+       created_by    UUID REFERENCES users(id) ON DELETE SET NULL,
+       revoked_at    TIMESTAMPTZ,
+       created_at    TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+       code_hash      TEXT PRIMARY KEY,
+       client_id      UUID NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+       user_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+       redirect_uri   TEXT NOT NULL,
+       scopes         TEXT NOT NULL DEFAULT '',
+       nonce          TEXT NOT NULL DEFAULT '',
+       code_challenge TEXT NOT NULL DEFAULT '',
+       expires_at     TIMESTAMPTZ NOT NULL,
+       created_at     TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE TABLE IF NOT EXISTS organization_invitations (
+       id              UUID PRIMARY KEY,
+       organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+       email           TEXT NOT NULL,
+       role            TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+       invited_by      UUID REFERENCES users(id) ON DELETE SET NULL,
+       expires_at      TIMESTAMPTZ NOT NULL,
+       accepted_at     TIMESTAMPTZ,
+       declined_at     TIMESTAMPTZ,
+       revoked_at      TIMESTAMPTZ,
+       created_at      TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE UNIQUE INDEX IF NOT EXISTS organization_invitations_pending_email_key
+       ON organization_invitations (organization_id, email)
+       WHERE accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL;
+
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    CREATE INDEX IF NOT EXISTS users_nickname_trgm_idx ON users USING GIN (LOWER(nickname) gin_trgm_ops);
+    CREATE INDEX IF NOT EXISTS users_email_trgm_idx ON users USING GIN (LOWER(email) gin_trgm_ops);
+    CREATE INDEX IF NOT EXISTS users_search_document_idx ON users
+       USING GIN (to_tsvector('simple', nickname || ' ' || translate(email, '@.-_+', '     ')));
+
+    CREATE TABLE IF NOT EXISTS user_status_history (
+       id         UUID PRIMARY KEY,
+       user_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+       status     TEXT NOT NULL CHECK (status IN ('active', 'suspended', 'locked')),
+       reason     TEXT NOT NULL DEFAULT '',
+       expires_at TIMESTAMPTZ,
+       changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+       created_at TIMESTAMPTZ DEFAULT NOW()
+    );
+
+    CREATE INDEX IF NOT EXISTS user_status_history_user_idx ON user_status_history (user_id, created_at);
+
+    CREATE TABLE IF NOT EXISTS data_exports (
+       id           UUID PRIMARY KEY,
+       user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+       status       TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+       archive      BYTEA,
+       error        TEXT NOT NULL DEFAULT '',
+       created_at   TIMESTAMPTZ DEFAULT NOW(),
+       started_at   TIMESTAMPTZ,
+       completed_at TIMESTAMPTZ,
+       expires_at   TIMESTAMPTZ
+    );
+
+    CREATE INDEX IF NOT EXISTS data_exports_queue_idx ON data_exports (created_at) WHERE status IN ('pending', 'processing');
+
+    CREATE TABLE IF NOT EXISTS audit_events (
+       id         BIGINT PRIMARY KEY,
+       actor_id   UUID,
+       target_id  UUID,
+       action     TEXT NOT NULL,
+       ip_address TEXT NOT NULL DEFAULT '',
+       user_agent TEXT NOT NULL DEFAULT '',
+       outcome    TEXT NOT NULL CHECK (outcome IN ('success', 'failure')),
+       metadata   JSONB NOT NULL DEFAULT '{}',
+       created_at TIMESTAMPTZ NOT NULL,
+       prev_hash  TEXT NOT NULL,
+       hash       TEXT NOT NULL
+    );
+
+    CREATE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+    BEGIN
+       RAISE EXCEPTION 'audit_events is append-only';
+    END;
+    $$ LANGUAGE plpgsql;
+
+    CREATE TRIGGER audit_events_no_change BEFORE UPDATE OR DELETE ON audit_events
+       FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+    INSERT INTO roles (name) VALUES ('user'), ('admin');
+    INSERT INTO permissions (name) VALUES ('users:read'), ('users:manage'), ('roles:manage'), ('oauth_clients:manage'), ('audit:read');
+    INSERT INTO role_permissions (role, permission)
+    VALUES ('admin', 'users:read'), ('admin', 'users:manage'), ('admin', 'roles:manage'), ('admin', 'oauth_clients:manage'), ('admin', 'audit:read');`
 	if _, err = testPool.Exec(ctx, setupSQL); err != nil {
 		log.Fatalf("failed to setup schema: %s", err)
 	}
@@ -79,7 +327,7 @@ func TestUserRepository_Create(t *testing.T) {
 		Email:        existingEmail,
 		Nickname:     existingNick,
 		PasswordHash: "h",
-		Role:         "user",
+		Roles:        []string{"user"},
 	})
 
 	tests := []struct {
@@ -94,7 +342,7 @@ func TestUserRepository_Create(t *testing.T) {
 				Email:        "new_user@test.com",
 				Nickname:     "new_nick",
 				PasswordHash: "hash",
-				Role:         "user",
+				Roles:        []string{"user"},
 			},
 			wantErr: false,
 		},
@@ -105,7 +353,7 @@ func TestUserRepository_Create(t *testing.T) {
 				Email:        existingEmail,
 				Nickname:     "random_nick",
 				PasswordHash: "hash",
-				Role:         "user",
+				Roles:        []string{"user"},
 			},
 			wantErr: true,
 		},
@@ -116,7 +364,7 @@ func TestUserRepository_Create(t *testing.T) {
 				Email:        "another@test.com",
 				Nickname:     existingNick,
 				PasswordHash: "hash",
-				Role:         "user",
+				Roles:        []string{"user"},
 			},
 			wantErr: true,
 		},
@@ -131,6 +379,41 @@ func TestUserRepository_Create(t *testing.T) {
 	}
 }
 
+func TestUserRepository_CreateBatch(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresUserRepository(testPool)
+	ctx := context.Background()
+
+	batch := []*model.User{
+		{ID: uuid.New(), Email: "batch_one@test.com", Nickname: "batch_one", PasswordHash: "h", Roles: []string{"user"}},
+		{ID: uuid.New(), Email: "batch_two@test.com", Nickname: "batch_two", PasswordHash: "h", Roles: []string{"user"}},
+	}
+	assert.NoError(t, repo.CreateBatch(ctx, batch, nil))
+
+	created, err := repo.GetByEmail(ctx, "batch_two@test.com")
+	if assert.NoError(t, err) && assert.NotNil(t, created) {
+		assert.Equal(t, []string{"user"}, created.Roles)
+	}
+
+	// Пакет с занятым адресом не создаёт никого
+	conflict := []*model.User{
+		{ID: uuid.New(), Email: "batch_three@test.com", Nickname: "batch_three", PasswordHash: "h", Roles: []string{"user"}},
+		{ID: uuid.New(), Email: "batch_one@test.com", Nickname: "batch_four", PasswordHash: "h", Roles: []string{"user"}},
+	}
+	assert.ErrorIs(t, repo.CreateBatch(ctx, conflict, nil), ErrEmailTaken)
+	missing, err := repo.GetByEmail(ctx, "batch_three@test.com")
+	assert.NoError(t, err)
+	assert.Nil(t, missing)
+
+	existing, err := repo.ExistingAccounts(ctx, []string{"batch_one@test.com", "nobody@test.com"}, []string{"batch_two"})
+	assert.NoError(t, err)
+	emails := make([]string, 0, len(existing))
+	for _, user := range existing {
+		emails = append(emails, user.Email)
+	}
+	assert.ElementsMatch(t, []string{"batch_one@test.com", "batch_two@test.com"}, emails)
+}
+
 func TestUserRepository_GetMethods(t *testing.T) {
 	t.Parallel()
 	repo := NewPostgresUserRepository(testPool)
@@ -141,7 +424,7 @@ func TestUserRepository_GetMethods(t *testing.T) {
 		Email:        "find_me@test.com",
 		Nickname:     "find_me_nick",
 		PasswordHash: "secret_hash",
-		Role:         "admin",
+		Roles:        []string{"admin"},
 	}
 	assert.NoError(t, repo.Create(ctx, refUser))
 
@@ -150,11 +433,12 @@ func TestUserRepository_GetMethods(t *testing.T) {
 		queryType string
 		queryVal  interface{}
 		wantErr   bool
+		wantNil   bool
 	}{
 		{name: "GetByID Success", queryType: "id", queryVal: refUser.ID, wantErr: false},
-		{name: "GetByID Not Found", queryType: "id", queryVal: uuid.New(), wantErr: true},
+		{name: "GetByID Not Found", queryType: "id", queryVal: uuid.New(), wantNil: true},
 		{name: "GetByEmail Success", queryType: "email", queryVal: refUser.Email, wantErr: false},
-		{name: "GetByEmail Not Found", queryType: "email", queryVal: "none@test.com", wantErr: true},
+		{name: "GetByEmail Not Found", queryType: "email", queryVal: "none@test.com", wantNil: true},
 	}
 
 	for _, tt := range tests {
@@ -170,12 +454,17 @@ func TestUserRepository_GetMethods(t *testing.T) {
 				res, err = repo.GetByEmail(ctx, tt.queryVal.(string))
 			}
 
-			if tt.wantErr {
+			switch {
+			case tt.wantErr:
 				assert.Error(t, err)
-			} else {
+			case tt.wantNil:
+				assert.NoError(t, err)
+				assert.Nil(t, res)
+			default:
 				assert.NoError(t, err)
 				assert.Equal(t, refUser.Email, res.Email)
 				assert.Equal(t, refUser.Nickname, res.Nickname)
+				assert.Equal(t, refUser.Roles, res.Roles)
 			}
 		})
 	}
@@ -190,7 +479,7 @@ func TestUserRepository_UpdateAndDelete(t *testing.T) {
 		Email:        "mutable@test.com",
 		Nickname:     "mutable_nick",
 		PasswordHash: "old_hash",
-		Role:         "user",
+		Roles:        []string{"user"},
 	}
 	assert.NoError(t, repo.Create(ctx, u))
 
@@ -203,45 +492,276 @@ func TestUserRepository_UpdateAndDelete(t *testing.T) {
 		assert.Equal(t, newHash, updated.PasswordHash)
 	})
 
-	t.Run("Delete User Success", func(t *testing.T) {
+	t.Run("Rehash Password Only Replaces Expected Hash", func(t *testing.T) {
+		replaced, err := repo.RehashPassword(ctx, u.ID, "stale_hash", "rehashed")
+		assert.NoError(t, err)
+		assert.False(t, replaced)
+
+		replaced, err = repo.RehashPassword(ctx, u.ID, "brand_new_hash", "rehashed")
+		assert.NoError(t, err)
+		assert.True(t, replaced)
+
+		updated, _ := repo.GetByID(ctx, u.ID)
+		assert.Equal(t, "rehashed", updated.PasswordHash)
+	})
+
+	t.Run("Update Profile Success", func(t *testing.T) {
+		prefs := model.Preferences{Language: "en", Theme: "dark"}
+		err := repo.UpdateProfile(ctx, u.ID, "mutable_renamed", prefs)
+		assert.NoError(t, err)
+
+		updated, _ := repo.GetByID(ctx, u.ID)
+		assert.Equal(t, "mutable_renamed", updated.Nickname)
+		assert.Equal(t, prefs, updated.Preferences)
+	})
+
+	t.Run("Update Profile Nickname Taken", func(t *testing.T) {
+		other := &model.User{
+			ID:           uuid.New(),
+			Email:        "mutable_other@test.com",
+			Nickname:     "mutable_other",
+			PasswordHash: "h",
+			Roles:        []string{"user"},
+		}
+		assert.NoError(t, repo.Create(ctx, other))
+
+		err := repo.UpdateProfile(ctx, u.ID, other.Nickname, model.Preferences{})
+		assert.ErrorIs(t, err, ErrNicknameTaken)
+	})
+
+	t.Run("Soft Delete, Restore And Purge", func(t *testing.T) {
 		t.Parallel()
 		err := repo.Delete(ctx, u.ID)
 		assert.NoError(t, err)
 
-		_, err = repo.GetByID(ctx, u.ID)
-		assert.Error(t, err, "User should not be found after deletion")
+		deleted, err := repo.GetByID(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.Nil(t, deleted, "User should not be found after deletion")
+		deleted, err = repo.GetByEmail(ctx, u.Email)
+		assert.NoError(t, err)
+		assert.Nil(t, deleted)
+		assert.Error(t, repo.Delete(ctx, u.ID), "deleted user cannot be deleted twice")
+		assert.Error(t, repo.UpdatePassword(ctx, u.ID, "h"), "deleted user cannot be changed")
+
+		// Срок хранения ещё не истёк
+		_, err = repo.Purge(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+
+		restored, err := repo.Restore(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.True(t, restored)
+		restored, err = repo.Restore(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.False(t, restored, "active user cannot be restored")
+
+		found, err := repo.GetByID(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.NotNil(t, found)
+
+		assert.NoError(t, repo.Delete(ctx, u.ID))
+		purged, err := repo.Purge(ctx, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, purged, int64(1))
+
+		restored, err = repo.Restore(ctx, u.ID)
+		assert.NoError(t, err)
+		assert.False(t, restored, "purged user is gone for good")
 	})
 }
 
 func TestUserRepository_List(t *testing.T) {
 	t.Parallel()
 	repo := NewPostgresUserRepository(testPool)
+	orgs := NewPostgresOrganizationRepository(testPool)
 	ctx := context.Background()
 
-	initialList, _ := repo.List(ctx)
-	initialCount := len(initialList)
-
-	for i := 0; i < 3; i++ {
-		_ = repo.Create(ctx, &model.User{
+	newUser := func(i int) *model.User {
+		u := &model.User{
 			ID:           uuid.New(),
 			Email:        fmt.Sprintf("list_%d_%d@test.com", i, time.Now().UnixNano()),
 			Nickname:     fmt.Sprintf("nick_%d_%d", i, time.Now().UnixNano()),
 			PasswordHash: "h",
-			Role:         "u",
-		})
+			Roles:        []string{"user"},
+		}
+		assert.NoError(t, repo.Create(ctx, u))
+		return u
+	}
+
+	owner := newUser(0)
+	org := &model.Organization{ID: uuid.New(), Name: "List Org"}
+	assert.NoError(t, orgs.Create(ctx, org, owner.ID))
+	for i := 1; i < 3; i++ {
+		assert.NoError(t, orgs.AddMember(ctx, org.ID, newUser(i).ID, model.OrgRoleMember))
+	}
+	outsider := newUser(3)
+
+	listQuery := func(limit int) model.UserListQuery {
+		return model.UserListQuery{OrganizationID: org.ID, SortBy: model.UserSortCreatedAt, Limit: limit}
 	}
 
 	t.Run("Verify List Count and Content", func(t *testing.T) {
 		t.Parallel()
-		list, err := repo.List(ctx)
+		page, err := repo.List(ctx, listQuery(50))
 		assert.NoError(t, err)
-		assert.GreaterOrEqual(t, len(list), initialCount+3)
+		assert.Len(t, page.Users, 3)
+		assert.Empty(t, page.NextCursor)
+		assert.Nil(t, page.Total)
 
-		for _, user := range list {
+		for _, user := range page.Users {
+			assert.NotEqual(t, outsider.ID, user.ID, "users of other organizations must not be listed")
 			assert.NotEmpty(t, user.ID)
 			assert.NotEmpty(t, user.Email)
 			assert.NotEmpty(t, user.Nickname)
+			assert.NotEmpty(t, user.Roles)
 			assert.False(t, user.CreatedAt.IsZero())
 		}
 	})
+
+	t.Run("ForEach Streams Whole Selection", func(t *testing.T) {
+		t.Parallel()
+		query := listQuery(1)
+		query.SortBy = model.UserSortEmail
+
+		var emails []string
+		err := repo.ForEach(ctx, query, func(user model.User) error {
+			assert.NotEqual(t, outsider.ID, user.ID)
+			assert.Equal(t, []string{"user"}, user.Roles)
+			emails = append(emails, user.Email)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, emails, 3, "limit must not apply to export")
+		assert.IsIncreasing(t, emails)
+
+		stop := errors.New("stop")
+		calls := 0
+		err = repo.ForEach(ctx, query, func(model.User) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Cursor Pages Do Not Overlap", func(t *testing.T) {
+		t.Parallel()
+		query := listQuery(2)
+		query.SortBy = model.UserSortEmail
+		query.Descending = true
+		query.WithTotal = true
+
+		first, err := repo.List(ctx, query)
+		assert.NoError(t, err)
+		assert.Len(t, first.Users, 2)
+		assert.NotEmpty(t, first.NextCursor)
+		if assert.NotNil(t, first.Total) {
+			assert.Equal(t, 3, *first.Total)
+		}
+		assert.Greater(t, first.Users[0].Email, first.Users[1].Email)
+
+		query.After, err = model.DecodeUserCursor(first.NextCursor, model.UserSortEmail, true)
+		assert.NoError(t, err)
+		second, err := repo.List(ctx, query)
+		assert.NoError(t, err)
+		if assert.Len(t, second.Users, 1) {
+			assert.Less(t, second.Users[0].Email, first.Users[1].Email)
+		}
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		t.Parallel()
+		query := listQuery(50)
+		query.Nickname = strings.ToUpper(owner.Nickname[:7])
+		page, err := repo.List(ctx, query)
+		assert.NoError(t, err)
+		if assert.Len(t, page.Users, 1) {
+			assert.Equal(t, owner.ID, page.Users[0].ID)
+		}
+
+		query = listQuery(50)
+		query.Email = "%"
+		page, err = repo.List(ctx, query)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Users, "LIKE wildcards must be matched literally")
+
+		query = listQuery(50)
+		query.Role = model.RoleAdmin
+		page, err = repo.List(ctx, query)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Users)
+
+		future := time.Now().Add(time.Hour)
+		query = listQuery(50)
+		query.CreatedFrom = &future
+		page, err = repo.List(ctx, query)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Users)
+	})
+
+	t.Run("Unknown Organization Is Empty", func(t *testing.T) {
+		t.Parallel()
+		page, err := repo.List(ctx, model.UserListQuery{OrganizationID: uuid.New(), Limit: 50})
+		assert.NoError(t, err)
+		assert.Empty(t, page.Users)
+	})
+}
+
+func TestUserRepository_Search(t *testing.T) {
+	t.Parallel()
+	repo := NewPostgresUserRepository(testPool)
+	orgs := NewPostgresOrganizationRepository(testPool)
+	ctx := context.Background()
+
+	suffix := time.Now().UnixNano()
+	newUser := func(email, nickname string) *model.User {
+		u := &model.User{
+			ID:           uuid.New(),
+			Email:        fmt.Sprintf("%s_%d@searchmail.test", email, suffix),
+			Nickname:     fmt.Sprintf("%s_%d", nickname, suffix),
+			PasswordHash: "h",
+			Roles:        []string{"user"},
+		}
+		assert.NoError(t, repo.Create(ctx, u))
+		return u
+	}
+
+	owner := newUser("gwendolyn.price", "gwendolyn")
+	org := &model.Organization{ID: uuid.New(), Name: "Search Org"}
+	assert.NoError(t, orgs.Create(ctx, org, owner.ID))
+	member := newUser("theodore.hughes", "teddy")
+	assert.NoError(t, orgs.AddMember(ctx, org.ID, member.ID, model.OrgRoleMember))
+	outsider := newUser("gwendolyn.stone", "gwen_stone")
+
+	search := func(text string) []model.UserSearchHit {
+		hits, err := repo.Search(ctx, model.UserSearchQuery{OrganizationID: org.ID, Text: text, Limit: 10})
+		assert.NoError(t, err)
+		return hits
+	}
+
+	t.Run("Partial Nickname", func(t *testing.T) {
+		t.Parallel()
+		hits := search("GWEND")
+		if assert.Len(t, hits, 1, "members of other organizations must not be found") {
+			assert.Equal(t, owner.ID, hits[0].User.ID)
+			assert.Equal(t, []string{"user"}, hits[0].User.Roles)
+			assert.Greater(t, hits[0].Rank, 0.0)
+		}
+		for _, hit := range hits {
+			assert.NotEqual(t, outsider.ID, hit.User.ID)
+		}
+	})
+
+	t.Run("Misspelled Email", func(t *testing.T) {
+		t.Parallel()
+		hits := search(fmt.Sprintf("theodroe.hughes_%d@searchmail.test", suffix))
+		if assert.NotEmpty(t, hits) {
+			assert.Equal(t, member.ID, hits[0].User.ID)
+		}
+	})
+
+	t.Run("Nothing Found", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, search("zzzzqqqq"))
+	})
 }
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"user-account/cmd/internal/gen/docflow/public/table"
+
+	jet_model "user-account/cmd/internal/gen/docflow/public/model"
+	"user-account/cmd/internal/model"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+type UserStatusRepository interface {
+	Change(ctx context.Context, change *model.UserStatusChange) (bool, error)
+	History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error)
+}
+
+type userStatusRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPostgresUserStatusRepository(db *pgxpool.Pool) UserStatusRepository {
+	return &userStatusRepository{db: db}
+}
+
+// Change меняет статус пользователя и пишет запись в историю в одной
+// транзакции. Возвращает false, если пользователя нет или он удалён.
+func (r *userStatusRepository) Change(ctx context.Context, change *model.UserStatusChange) (bool, error) {
+	db := stdlib.OpenDBFromPool(r.db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	updateStmt := table.Users.UPDATE(table.Users.Status, table.Users.StatusReason, table.Users.StatusExpiresAt).
+		MODEL(jet_model.Users{
+			Status:          change.Status,
+			StatusReason:    change.Reason,
+			StatusExpiresAt: change.ExpiresAt,
+		}).
+		WHERE(table.Users.ID.EQ(UUID(change.UserID)).AND(notDeleted()))
+	result, err := updateStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return false, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return false, nil
+	}
+
+	var dest jet_model.UserStatusHistory
+	insertStmt := table.UserStatusHistory.INSERT(
+		table.UserStatusHistory.ID,
+		table.UserStatusHistory.UserID,
+		table.UserStatusHistory.Status,
+		table.UserStatusHistory.Reason,
+		table.UserStatusHistory.ExpiresAt,
+		table.UserStatusHistory.ChangedBy,
+	).MODEL(jet_model.UserStatusHistory{
+		ID:        change.ID,
+		UserID:    change.UserID,
+		Status:    change.Status,
+		Reason:    change.Reason,
+		ExpiresAt: change.ExpiresAt,
+		ChangedBy: change.ChangedBy,
+	}).RETURNING(table.UserStatusHistory.CreatedAt)
+	if err = insertStmt.QueryContext(ctx, tx, &dest); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	if dest.CreatedAt != nil {
+		change.CreatedAt = *dest.CreatedAt
+	}
+	return true, nil
+}
+
+// History - все смены статуса пользователя, новые первыми
+func (r *userStatusRepository) History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error) {
+	var dest []jet_model.UserStatusHistory
+
+	stmt := SELECT(table.UserStatusHistory.AllColumns).
+		FROM(table.UserStatusHistory).
+		WHERE(table.UserStatusHistory.UserID.EQ(UUID(userID))).
+		ORDER_BY(table.UserStatusHistory.CreatedAt.DESC(), table.UserStatusHistory.ID.DESC())
+
+	db := stdlib.OpenDBFromPool(r.db)
+	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	res := make([]model.UserStatusChange, len(dest))
+	for i, h := range dest {
+		res[i] = model.UserStatusChangeToDomain(h)
+	}
+	return res, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatusRepository(t *testing.T) {
+	t.Parallel()
+	users := NewPostgresUserRepository(testPool)
+	repo := NewPostgresUserStatusRepository(testPool)
+	ctx := context.Background()
+
+	newUser := func(name string) *model.User {
+		u := &model.User{
+			ID:           uuid.New(),
+			Email:        name + "@status.test",
+			Nickname:     name + "_status",
+			PasswordHash: "h",
+			Roles:        []string{"user"},
+		}
+		assert.NoError(t, users.Create(ctx, u))
+		return u
+	}
+	admin := newUser("admin")
+	user := newUser("employee")
+
+	t.Run("New Users Are Active", func(t *testing.T) {
+		found, err := users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.UserStatusActive, found.Status)
+		assert.False(t, found.Blocked(time.Now()))
+	})
+
+	t.Run("Suspend, Reinstate And History", func(t *testing.T) {
+		until := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+		suspend := &model.UserStatusChange{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			Status:    model.UserStatusSuspended,
+			Reason:    "left the company",
+			ExpiresAt: &until,
+			ChangedBy: &admin.ID,
+		}
+		changed, err := repo.Change(ctx, suspend)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		assert.False(t, suspend.CreatedAt.IsZero())
+
+		found, err := users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.UserStatusSuspended, found.Status)
+		assert.Equal(t, "left the company", found.StatusReason)
+		assert.True(t, until.Equal(*found.StatusExpiresAt))
+		assert.True(t, found.Blocked(time.Now()))
+		assert.False(t, found.Blocked(until.Add(time.Second)), "suspension ends by itself")
+
+		changed, err = repo.Change(ctx, &model.UserStatusChange{
+			ID:        uuid.New(),
+			UserID:    user.ID,
+			Status:    model.UserStatusActive,
+			Reason:    "came back",
+			ChangedBy: &admin.ID,
+		})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		found, err = users.GetByID(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.UserStatusActive, found.Status)
+		assert.Nil(t, found.StatusExpiresAt)
+
+		history, err := repo.History(ctx, user.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, history, 2) {
+			assert.Equal(t, model.UserStatusActive, history[0].Status)
+			assert.Equal(t, model.UserStatusSuspended, history[1].Status)
+			assert.Equal(t, admin.ID, *history[1].ChangedBy)
+		}
+	})
+
+	t.Run("Unknown User", func(t *testing.T) {
+		changed, err := repo.Change(ctx, &model.UserStatusChange{
+			ID:     uuid.New(),
+			UserID: uuid.New(),
+			Status: model.UserStatusLocked,
+			Reason: "fraud",
+		})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+}
@@ -1,40 +1,267 @@
 package router
 
 import (
+	"expvar"
 	"net/http"
 	"user-account/cmd/internal/handler"
 	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/ratelimit"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
+// Handlers - хендлеры, которые монтирует роутер
+type Handlers struct {
+	Health       *handler.HealthHandler
+	Auth         *handler.AuthHandler
+	Verification *handler.VerificationHandler
+	Password     *handler.PasswordResetHandler
+	User         *handler.UserHandler
+	TwoFactor    *handler.TwoFactorHandler
+	Role         *handler.RoleHandler
+	APIKey       *handler.APIKeyHandler
+	JWKS         *handler.JWKSHandler
+	OIDC         *handler.OIDCHandler
+	OAuth        *handler.OAuthHandler
+	Organization *handler.OrganizationHandler
+	Invitation   *handler.InvitationHandler
+	UserStatus   *handler.UserStatusHandler
+	UserImport   *handler.UserImportHandler
+	PersonalData *handler.PersonalDataHandler
+	Audit        *handler.AuditHandler
+}
+
+// Security - зависимости для аутентификации и авторизации запросов
+type Security struct {
+	Keyfunc     jwt.Keyfunc
+	Revocations middleware.RevocationChecker
+	Permissions middleware.PermissionChecker
+	APIKeys     middleware.APIKeyAuthenticator
+	// Members - проверка, что пользователь из /users/{id} состоит в
+	// активной организации вызывающего
+	Members middleware.MembershipChecker
+	// Statuses - проверка, что доступ владельцу токена или ключа не закрыт
+	Statuses middleware.StatusChecker
+}
+
+// RateLimits - ограничение частоты запросов. Без Limiter лимитов нет.
+type RateLimits struct {
+	Limiter middleware.RateLimiter
+	// Auth - анонимные маршруты входа, регистрации и писем, по IP. У каждого
+	// маршрута своя корзина.
+	Auth ratelimit.Rate
+	// API - запросы с access-токеном, по пользователю, и с API-ключом, по ключу
+	API ratelimit.Rate
+}
+
 // NewRouter возвращает настроенный роутер с хендлерами
-func NewRouter(healthHandler *handler.HealthHandler, authHandler *handler.AuthHandler, userHandler *handler.UserHandler, jwtSecret string, allowedOrigins []string) http.Handler {
+func NewRouter(h Handlers, sec Security, limits RateLimits, allowedOrigins []string) http.Handler {
 	r := mux.NewRouter()
 
-	jwtMiddleware := middleware.JWTAuth(jwtSecret)
+	limit := func(name string, rate ratelimit.Rate, key middleware.RateLimitKey) func(http.Handler) http.Handler {
+		if limits.Limiter == nil {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		return middleware.RateLimit(limits.Limiter, middleware.RateLimitPolicy{Name: name, Rate: rate, Key: key})
+	}
+	authLimit := func(name string, next http.HandlerFunc) http.Handler {
+		return limit(name, limits.Auth, middleware.ByIP)(next)
+	}
+	apiLimit := limit("api", limits.API, middleware.ByUser)
+
+	jwtAuth := middleware.JWTAuth(sec.Keyfunc, sec.Revocations, sec.Statuses)
+	jwtMiddleware := func(next http.Handler) http.Handler {
+		return jwtAuth(apiLimit(next))
+	}
+	// API-ключи принимаются только на маршрутах с проверкой прав: права
+	// ключа ограничены его scopes. Маршруты /me и управление самими ключами
+	// доступны только с access-токеном.
+	apiKeyAuth := jwtAuth
+	if sec.APIKeys != nil {
+		apiKeyAuth = middleware.APIKeyOrJWT(sec.APIKeys, sec.Statuses, jwtAuth)
+	}
+	keyLimit := limit("api", limits.API, middleware.ByAPIKey)
+	automationMiddleware := func(next http.Handler) http.Handler {
+		return apiKeyAuth(keyLimit(next))
+	}
+	requirePermission := func(permission string, next http.HandlerFunc) http.Handler {
+		return automationMiddleware(middleware.RequirePermission(sec.Permissions, permission)(next))
+	}
+	userIDVar := func(r *http.Request) string { return mux.Vars(r)["id"] }
+	// Маршруты /users/{id} видят только пользователей своей организации, а
+	// менять их можно, только если это позволяет и роль в ней
+	sameOrganization := func(permission string, next http.Handler) http.Handler {
+		return middleware.SameOrganization(sec.Members, permission, userIDVar)(next)
+	}
+	ownerOrPermission := func(permission string, next http.HandlerFunc) http.Handler {
+		return automationMiddleware(middleware.OwnerOrPermission(sec.Permissions, permission, userIDVar)(sameOrganization(permission, next)))
+	}
+	requireUserPermission := func(permission string, next http.HandlerFunc) http.Handler {
+		return requirePermission(permission, sameOrganization(permission, next).ServeHTTP)
+	}
+
+	r.HandleFunc("/health", h.Health.Health).Methods(http.MethodGet)
+	r.HandleFunc("/.well-known/jwks.json", h.JWKS.JWKS).Methods(http.MethodGet)
+	r.HandleFunc("/.well-known/openid-configuration", h.OAuth.Discovery).Methods(http.MethodGet)
+
+	r.Handle("/register", authLimit("register", h.Auth.Register)).Methods(http.MethodPost)
+	r.Handle("/login", authLimit("login", h.Auth.Login)).Methods(http.MethodPost)
+	r.Handle("/login/2fa", authLimit("login_2fa", h.Auth.LoginTwoFactor)).Methods(http.MethodPost)
+	r.Handle("/token/refresh", authLimit("token_refresh", h.Auth.Refresh)).Methods(http.MethodPost)
+	r.Handle("/email/verify", authLimit("email_verify", h.Verification.Verify)).Methods(http.MethodPost)
+	r.Handle("/email/verify/resend", authLimit("email_resend", h.Verification.Resend)).Methods(http.MethodPost)
+	r.Handle("/password/forgot", authLimit("password_forgot", h.Password.Forgot)).Methods(http.MethodPost)
+	r.Handle("/password/reset", authLimit("password_reset", h.Password.Reset)).Methods(http.MethodPost)
+	r.HandleFunc("/oauth/providers", h.OIDC.Providers).Methods(http.MethodGet)
+	r.Handle("/oauth/{provider}/authorize", authLimit("oauth_authorize", func(w http.ResponseWriter, r *http.Request) {
+		h.OIDC.Authorize(w, r, mux.Vars(r)["provider"])
+	})).Methods(http.MethodPost)
+	r.Handle("/oauth/callback", authLimit("oauth_callback", h.OIDC.Callback)).Methods(http.MethodPost)
+	r.Handle("/invitations/accept", authLimit("invitation_accept", h.Invitation.Accept)).Methods(http.MethodPost)
+	r.Handle("/invitations/decline", authLimit("invitation_decline", h.Invitation.Decline)).Methods(http.MethodPost)
+	// Токены и интроспекцию запрашивают сервисы, а не люди: перебор
+	// секретов клиентов бессмыслен, а лимит по IP бил бы по соседним
+	// сервисам за общим NAT
+	r.HandleFunc("/oauth/token", h.OAuth.Token).Methods(http.MethodPost)
+	r.HandleFunc("/oauth/introspect", h.OAuth.Introspect).Methods(http.MethodPost)
+	r.Handle("/oauth/authorize", jwtMiddleware(http.HandlerFunc(h.OAuth.PreviewAuthorization))).Methods(http.MethodGet)
+	r.Handle("/oauth/authorize", jwtMiddleware(http.HandlerFunc(h.OAuth.Authorize))).Methods(http.MethodPost)
+	r.Handle("/logout", jwtMiddleware(http.HandlerFunc(h.Auth.Logout))).Methods(http.MethodPost)
+	r.Handle("/logout/all", jwtMiddleware(http.HandlerFunc(h.Auth.LogoutAll))).Methods(http.MethodPost)
 
-	r.HandleFunc("/health", healthHandler.Health).Methods(http.MethodGet)
+	r.Handle("/me", jwtMiddleware(http.HandlerFunc(h.User.GetMe))).Methods(http.MethodGet)
+	r.Handle("/me", jwtMiddleware(http.HandlerFunc(h.User.UpdateMe))).Methods(http.MethodPatch)
+	// Действия с проверкой пароля или кода второго фактора ограничены, как и
+	// вход: их подбирают и с украденным access-токеном
+	r.Handle("/me", jwtMiddleware(authLimit("close_account", h.User.DeleteMe))).Methods(http.MethodDelete)
+	r.Handle("/me/password", jwtMiddleware(authLimit("change_password", h.User.ChangeMyPassword))).Methods(http.MethodPost)
+	r.Handle("/me/2fa/totp", jwtMiddleware(http.HandlerFunc(h.TwoFactor.Enroll))).Methods(http.MethodPost)
+	r.Handle("/me/2fa/totp", jwtMiddleware(authLimit("totp_disable", h.TwoFactor.Disable))).Methods(http.MethodDelete)
+	r.Handle("/me/2fa/totp/confirm", jwtMiddleware(http.HandlerFunc(h.TwoFactor.Confirm))).Methods(http.MethodPost)
+	r.Handle("/me/identities", jwtMiddleware(http.HandlerFunc(h.OIDC.Identities))).Methods(http.MethodGet)
+	r.Handle("/me/organizations", jwtMiddleware(http.HandlerFunc(h.Organization.ListMine))).Methods(http.MethodGet)
+	r.Handle("/me/organization", jwtMiddleware(http.HandlerFunc(h.Auth.SwitchOrganization))).Methods(http.MethodPost)
+	r.Handle("/me/api-keys", jwtMiddleware(http.HandlerFunc(h.APIKey.ListMine))).Methods(http.MethodGet)
+	r.Handle("/me/api-keys", jwtMiddleware(http.HandlerFunc(h.APIKey.CreateMine))).Methods(http.MethodPost)
+	r.Handle("/me/api-keys/{keyID}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.APIKey.RevokeMine(w, r, mux.Vars(r)["keyID"])
+	}))).Methods(http.MethodDelete)
+	r.Handle("/me/data-export", jwtMiddleware(http.HandlerFunc(h.PersonalData.RequestExport))).Methods(http.MethodPost)
+	r.Handle("/me/data-export/{id}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.PersonalData.GetExport(w, r, mux.Vars(r)["id"])
+	}))).Methods(http.MethodGet)
+	r.Handle("/me/data-export/{id}/download", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.PersonalData.DownloadExport(w, r, mux.Vars(r)["id"])
+	}))).Methods(http.MethodGet)
+	r.Handle("/me/erase", jwtMiddleware(authLimit("erase_account", h.PersonalData.EraseMe))).Methods(http.MethodPost)
 
-	r.HandleFunc("/register", authHandler.Register).Methods(http.MethodPost)
-	r.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
-	r.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
+	// Права на организацию проверяются по роли в ней, а не по RBAC
+	r.Handle("/organizations", jwtMiddleware(http.HandlerFunc(h.Organization.Create))).Methods(http.MethodPost)
+	r.Handle("/organization", jwtMiddleware(http.HandlerFunc(h.Organization.Get))).Methods(http.MethodGet)
+	r.Handle("/organization", jwtMiddleware(http.HandlerFunc(h.Organization.Rename))).Methods(http.MethodPatch)
+	r.Handle("/organization/members", jwtMiddleware(http.HandlerFunc(h.Organization.ListMembers))).Methods(http.MethodGet)
+	r.Handle("/organization/members", jwtMiddleware(http.HandlerFunc(h.Organization.AddMember))).Methods(http.MethodPost)
+	r.Handle("/organization/members/{userID}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Organization.ChangeMemberRole(w, r, mux.Vars(r)["userID"])
+	}))).Methods(http.MethodPut)
+	r.Handle("/organization/members/{userID}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Organization.RemoveMember(w, r, mux.Vars(r)["userID"])
+	}))).Methods(http.MethodDelete)
+	r.Handle("/orgs/{id}/invitations", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Invitation.List(w, r, mux.Vars(r)["id"])
+	}))).Methods(http.MethodGet)
+	r.Handle("/orgs/{id}/invitations", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.Invitation.Create(w, r, mux.Vars(r)["id"])
+	}))).Methods(http.MethodPost)
+	r.Handle("/orgs/{id}/invitations/{invitationID}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		h.Invitation.Revoke(w, r, vars["id"], vars["invitationID"])
+	}))).Methods(http.MethodDelete)
 
-	r.Handle("/users", jwtMiddleware(http.HandlerFunc(userHandler.List))).Methods(http.MethodGet)
+	// Список, поиск и выгрузка ограничены активной организацией вызывающего.
+	// Они объявлены раньше /users/{id}, иначе "search" разберётся как ID.
+	r.Handle("/users", requirePermission(model.PermUsersRead, h.User.List)).Methods(http.MethodGet)
+	r.Handle("/users/search", requirePermission(model.PermUsersRead, h.User.Search)).Methods(http.MethodGet)
+	r.Handle("/users/export", requirePermission(model.PermUsersRead, h.User.Export)).Methods(http.MethodGet)
+	// Импортированные пользователи попадают в активную организацию вызывающего
+	r.Handle("/users/import", requirePermission(model.PermUsersManage, h.UserImport.Import)).Methods(http.MethodPost)
 
-	r.Handle("/users/{id}", jwtMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	r.Handle("/users/{id}", ownerOrPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		idStr := vars["id"]
-		userHandler.ServeUserByID(w, r, idStr)
-	}))).Methods(http.MethodDelete, http.MethodPatch)
+		h.User.ServeUserByID(w, r, idStr)
+	})).Methods(http.MethodDelete)
+	// Сброс пароля без текущего - только для администраторов,
+	// свой пароль меняется через POST /me/password
+	r.Handle("/users/{id}", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.User.ServeUserByID(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPatch)
+
+	r.Handle("/users/{id}/unlock", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.User.Unlock(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPost)
+	// Удалённый пользователь остаётся участником организации, поэтому
+	// проверка своей организации пропускает его до окончательной очистки
+	r.Handle("/users/{id}/restore", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.User.Restore(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPost)
+	r.Handle("/users/{id}/erase", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.PersonalData.Erase(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPost)
+	r.Handle("/users/{id}/suspend", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.UserStatus.Suspend(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPost)
+	r.Handle("/users/{id}/reinstate", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		h.UserStatus.Reinstate(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodPost)
+	r.Handle("/users/{id}/status-history", requireUserPermission(model.PermUsersRead, func(w http.ResponseWriter, r *http.Request) {
+		h.UserStatus.History(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodGet)
+
+	r.Handle("/users/{id}/api-keys", requireUserPermission(model.PermUsersRead, func(w http.ResponseWriter, r *http.Request) {
+		h.APIKey.ListUserKeys(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodGet)
+	r.Handle("/users/{id}/api-keys/{keyID}", requireUserPermission(model.PermUsersManage, func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		h.APIKey.RevokeUserKey(w, r, vars["id"], vars["keyID"])
+	})).Methods(http.MethodDelete)
+
+	r.Handle("/roles", requirePermission(model.PermRolesManage, h.Role.ListRoles)).Methods(http.MethodGet)
+	r.Handle("/users/{id}/roles", requireUserPermission(model.PermRolesManage, func(w http.ResponseWriter, r *http.Request) {
+		h.Role.ListUserRoles(w, r, mux.Vars(r)["id"])
+	})).Methods(http.MethodGet)
+	r.Handle("/users/{id}/roles/{role}", requireUserPermission(model.PermRolesManage, func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		h.Role.AssignRole(w, r, vars["id"], vars["role"])
+	})).Methods(http.MethodPut)
+	r.Handle("/users/{id}/roles/{role}", requireUserPermission(model.PermRolesManage, func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		h.Role.RevokeRole(w, r, vars["id"], vars["role"])
+	})).Methods(http.MethodDelete)
+
+	r.Handle("/oauth/clients", requirePermission(model.PermClientsManage, h.OAuth.ListClients)).Methods(http.MethodGet)
+	r.Handle("/oauth/clients", requirePermission(model.PermClientsManage, h.OAuth.RegisterClient)).Methods(http.MethodPost)
+	r.Handle("/oauth/clients/{clientID}", requirePermission(model.PermClientsManage, func(w http.ResponseWriter, r *http.Request) {
+		h.OAuth.RevokeClient(w, r, mux.Vars(r)["clientID"])
+	})).Methods(http.MethodDelete)
+
+	r.Handle("/audit", requirePermission(model.PermAuditRead, h.Audit.List)).Methods(http.MethodGet)
+	r.Handle("/audit/verify", requirePermission(model.PermAuditRead, h.Audit.Verify)).Methods(http.MethodGet)
+	// Счётчики expvar, среди них audit_write_failures - по нему оповещают
+	// о пропусках в журнале
+	r.Handle("/audit/metrics", requirePermission(model.PermAuditRead, expvar.Handler().ServeHTTP)).Methods(http.MethodGet)
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   allowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", middleware.APIKeyHeader},
+		ExposedHeaders:   []string{"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 	})
 
-	return c.Handler(r)
+	// Client кладёт IP и User-Agent в контекст для журнала аудита
+	return c.Handler(middleware.Client(r))
 }
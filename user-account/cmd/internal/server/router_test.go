@@ -4,17 +4,26 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 	"user-account/cmd/internal/gen/mocks" // Убедись, что путь к GoMock правильный
 	"user-account/cmd/internal/handler"
+	"user-account/cmd/internal/keyring"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/ratelimit"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewRouter(t *testing.T) {
 	t.Parallel()
 
-	jwtSecret := "test-secret"
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgEdDSA, Overlap: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
 
 	tests := []struct {
 		name           string
@@ -31,26 +40,194 @@ func TestNewRouter(t *testing.T) {
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:           "2. Route POST /register (Empty Body)",
+			name:           "2. Route GET /.well-known/jwks.json",
+			method:         http.MethodGet,
+			url:            "/.well-known/jwks.json",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "3. Route POST /login/2fa (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/login/2fa",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "4. Route POST /register (Empty Body)",
 			method:         http.MethodPost,
 			url:            "/register",
 			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name:           "3. Route GET /users - Unauthorized (Missing JWT)",
+			name:           "5. Route POST /token/refresh (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/token/refresh",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "6. Route POST /email/verify (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/email/verify",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "7. Route POST /email/verify/resend (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/email/verify/resend",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "8. Route POST /password/forgot (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/password/forgot",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "9. Route POST /password/reset (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/password/reset",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "10. Route GET /users - Unauthorized (Missing JWT)",
 			method:         http.MethodGet,
 			url:            "/users",
 			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
 			expectedStatus: http.StatusUnauthorized,
 		},
 		{
-			name:           "4. Route DELETE /users/{id} - Unauthorized",
+			name:           "11. Route DELETE /users/{id} - Unauthorized",
 			method:         http.MethodDelete,
 			url:            "/users/550e8400-e29b-41d4-a716-446655440000",
 			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
 			expectedStatus: http.StatusUnauthorized,
 		},
+		{
+			name:           "12. Route POST /logout - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/logout",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "13. Route POST /logout/all - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/logout/all",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "14. Route GET /me - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/me",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "15. Route POST /me/2fa/totp - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/me/2fa/totp",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "16. Route GET /roles - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/roles",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "17. Route PUT /users/{id}/roles/{role} - Unauthorized",
+			method:         http.MethodPut,
+			url:            "/users/550e8400-e29b-41d4-a716-446655440000/roles/admin",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "18. Route GET /me/api-keys - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/me/api-keys",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "19. Route POST /oauth/callback (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/oauth/callback",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "20. Route GET /me/identities - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/me/identities",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "21. Route GET /oauth/authorize - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/oauth/authorize?client_id=x",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "22. Route POST /oauth/introspect (Missing Token)",
+			method:         http.MethodPost,
+			url:            "/oauth/introspect",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "23. Route GET /organization/members - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/organization/members",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "24. Route POST /me/organization - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/me/organization",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "25. Route POST /orgs/{id}/invitations - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/orgs/550e8400-e29b-41d4-a716-446655440000/invitations",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "26. Route POST /invitations/accept (Empty Body)",
+			method:         http.MethodPost,
+			url:            "/invitations/accept",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "27. Route POST /me/data-export - Unauthorized",
+			method:         http.MethodPost,
+			url:            "/me/data-export",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "28. Route GET /audit - Unauthorized",
+			method:         http.MethodGet,
+			url:            "/audit",
+			setupMock:      func(_ *mocks.MockAuthProvider, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
 	}
 
 	for _, tt := range tests {
@@ -67,9 +244,578 @@ func TestNewRouter(t *testing.T) {
 			userHandler := handler.NewUserHandler(mockUserSvc)
 			healthHandler := handler.NewHealthHandler()
 
-			r := NewRouter(healthHandler, authHandler, userHandler, jwtSecret, []string{"http://localhost:5173"})
+			roleHandler := handler.NewRoleHandler(mocks.NewMockRoleProvider(ctrl))
+			jwksHandler := handler.NewJWKSHandler(keys)
+			verificationHandler := handler.NewVerificationHandler(mocks.NewMockVerificationProvider(ctrl))
+			passwordHandler := handler.NewPasswordResetHandler(mocks.NewMockPasswordResetProvider(ctrl))
+			twoFactorHandler := handler.NewTwoFactorHandler(mocks.NewMockTwoFactorProvider(ctrl))
+			apiKeyHandler := handler.NewAPIKeyHandler(mocks.NewMockAPIKeyProvider(ctrl))
+			oidcHandler := handler.NewOIDCHandler(mocks.NewMockOIDCProvider(ctrl))
+			oauthHandler := handler.NewOAuthHandler(mocks.NewMockOAuthProvider(ctrl))
+			organizationHandler := handler.NewOrganizationHandler(mocks.NewMockOrganizationProvider(ctrl))
+			invitationHandler := handler.NewInvitationHandler(mocks.NewMockInvitationProvider(ctrl))
+			userStatusHandler := handler.NewUserStatusHandler(mocks.NewMockUserStatusProvider(ctrl))
+			userImportHandler := handler.NewUserImportHandler(mocks.NewMockUserImportProvider(ctrl))
+			personalDataHandler := handler.NewPersonalDataHandler(mocks.NewMockPersonalDataProvider(ctrl))
+			auditHandler := handler.NewAuditHandler(mocks.NewMockAuditProvider(ctrl))
+
+			r := NewRouter(
+				Handlers{Health: healthHandler, Auth: authHandler, Verification: verificationHandler, Password: passwordHandler, User: userHandler, TwoFactor: twoFactorHandler, Role: roleHandler, APIKey: apiKeyHandler, JWKS: jwksHandler, OIDC: oidcHandler, OAuth: oauthHandler, Organization: organizationHandler, Invitation: invitationHandler, UserStatus: userStatusHandler, UserImport: userImportHandler, PersonalData: personalDataHandler, Audit: auditHandler},
+				Security{Keyfunc: keys.Keyfunc, Revocations: mocks.NewMockRevocationChecker(ctrl), Permissions: mocks.NewMockPermissionChecker(ctrl), Statuses: mocks.NewMockStatusChecker(ctrl)},
+				RateLimits{},
+				[]string{"http://localhost:5173"},
+			)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestNewRouter_Permissions(t *testing.T) {
+	t.Parallel()
+
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgEdDSA, Overlap: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	userID := uuid.New()
+	otherID := uuid.New()
+	// ownerID - владелец организации, вызывающий в ней только администратор
+	ownerID := uuid.New()
+	foreignID := uuid.New()
+	orgID := uuid.New()
+	token, err := keys.Sign(jwt.MapClaims{
+		"sub":   userID.String(),
+		"roles": []string{"user"},
+		"jti":   uuid.NewString(),
+		"sid":   uuid.NewString(),
+		"org":   orgID.String(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		setupMock      func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name:   "1. GET /users Without users:read",
+			method: http.MethodGet,
+			url:    "/users",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "2. GET /users With users:read",
+			method: http.MethodGet,
+			url:    "/users",
+			setupMock: func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+				mu.EXPECT().List(gomock.Any(), model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt}).Return(&model.UserPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "3. DELETE Own Account",
+			method: http.MethodDelete,
+			url:    "/users/" + userID.String(),
+			setupMock: func(_ *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mu.EXPECT().Delete(gomock.Any(), userID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "4. DELETE Foreign Account Without users:manage",
+			method: http.MethodDelete,
+			url:    "/users/" + otherID.String(),
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "5. PUT Role Without roles:manage",
+			method: http.MethodPut,
+			url:    "/users/" + otherID.String() + "/roles/admin",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "roles:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "6. PATCH Own Account Requires users:manage",
+			method: http.MethodPatch,
+			url:    "/users/" + userID.String(),
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "7. POST Unlock Requires users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/unlock",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "8. POST Unlock With users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/unlock",
+			setupMock: func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+				mu.EXPECT().Unlock(gomock.Any(), otherID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "9. GET /oauth/clients Requires oauth_clients:manage",
+			method: http.MethodGet,
+			url:    "/oauth/clients",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "oauth_clients:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "10. GET /me",
+			method: http.MethodGet,
+			url:    "/me",
+			setupMock: func(_ *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mu.EXPECT().Get(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "11. POST Unlock User Of Another Organization",
+			method: http.MethodPost,
+			url:    "/users/" + foreignID.String() + "/unlock",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "12. DELETE Account Of Another Organization",
+			method: http.MethodDelete,
+			url:    "/users/" + foreignID.String(),
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "13. GET /users/search Is Not Routed As A User ID",
+			method: http.MethodGet,
+			url:    "/users/search?q=john",
+			setupMock: func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+				mu.EXPECT().Search(gomock.Any(), model.UserSearchQuery{OrganizationID: orgID, Text: "john"}).Return([]model.UserSearchHit{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "14. POST /users/{id}/restore Without users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/restore",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "15. POST /users/{id}/restore With users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/restore",
+			setupMock: func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+				mu.EXPECT().Restore(gomock.Any(), otherID).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "16. POST /users/{id}/suspend Without users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/suspend",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "17. POST /users/{id}/suspend With users:manage (Empty Body)",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/suspend",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "18. POST /users/{id}/reinstate User Of Another Organization",
+			method: http.MethodPost,
+			url:    "/users/" + foreignID.String() + "/reinstate",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "19. GET /users/{id}/status-history Without users:read",
+			method: http.MethodGet,
+			url:    "/users/" + otherID.String() + "/status-history",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "20. POST /users/import Without users:manage",
+			method: http.MethodPost,
+			url:    "/users/import",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "21. POST /users/import With users:manage (No File)",
+			method: http.MethodPost,
+			url:    "/users/import",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "22. GET /users/export Without users:read",
+			method: http.MethodGet,
+			url:    "/users/export",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "23. GET /users/export With users:read",
+			method: http.MethodGet,
+			url:    "/users/export?format=json",
+			setupMock: func(mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+				mu.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "24. POST /users/{id}/erase Without users:manage",
+			method: http.MethodPost,
+			url:    "/users/" + otherID.String() + "/erase",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "25. GET /audit Without audit:read",
+			method: http.MethodGet,
+			url:    "/audit",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "audit:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "26. GET /audit/metrics Without audit:read",
+			method: http.MethodGet,
+			url:    "/audit/metrics",
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "audit:read").Return(false, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "27. PATCH Org Owner By Org Admin With users:manage",
+			method: http.MethodPatch,
+			url:    "/users/" + ownerID.String(),
+			setupMock: func(mp *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:manage").Return(true, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			revocations := mocks.NewMockRevocationChecker(ctrl)
+			revocations.EXPECT().IsRevoked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+			permissions := mocks.NewMockPermissionChecker(ctrl)
+			mockUserSvc := mocks.NewMockUserProvider(ctrl)
+			tt.setupMock(permissions, mockUserSvc)
+			members := mocks.NewMockMembershipChecker(ctrl)
+			members.EXPECT().MemberRole(gomock.Any(), orgID, userID).Return(model.OrgRoleAdmin, nil).AnyTimes()
+			members.EXPECT().MemberRole(gomock.Any(), orgID, otherID).Return(model.OrgRoleMember, nil).AnyTimes()
+			members.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil).AnyTimes()
+			members.EXPECT().MemberRole(gomock.Any(), orgID, foreignID).Return("", nil).AnyTimes()
+			statuses := mocks.NewMockStatusChecker(ctrl)
+			statuses.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, nil)
+
+			r := NewRouter(
+				Handlers{
+					Health:       handler.NewHealthHandler(),
+					Auth:         handler.NewAuthHandler(mocks.NewMockAuthProvider(ctrl)),
+					Verification: handler.NewVerificationHandler(mocks.NewMockVerificationProvider(ctrl)),
+					Password:     handler.NewPasswordResetHandler(mocks.NewMockPasswordResetProvider(ctrl)),
+					User:         handler.NewUserHandler(mockUserSvc),
+					TwoFactor:    handler.NewTwoFactorHandler(mocks.NewMockTwoFactorProvider(ctrl)),
+					Role:         handler.NewRoleHandler(mocks.NewMockRoleProvider(ctrl)),
+					APIKey:       handler.NewAPIKeyHandler(mocks.NewMockAPIKeyProvider(ctrl)),
+					JWKS:         handler.NewJWKSHandler(keys),
+					OIDC:         handler.NewOIDCHandler(mocks.NewMockOIDCProvider(ctrl)),
+					OAuth:        handler.NewOAuthHandler(mocks.NewMockOAuthProvider(ctrl)),
+					Organization: handler.NewOrganizationHandler(mocks.NewMockOrganizationProvider(ctrl)),
+					Invitation:   handler.NewInvitationHandler(mocks.NewMockInvitationProvider(ctrl)),
+					UserStatus:   handler.NewUserStatusHandler(mocks.NewMockUserStatusProvider(ctrl)),
+					UserImport:   handler.NewUserImportHandler(mocks.NewMockUserImportProvider(ctrl)),
+					PersonalData: handler.NewPersonalDataHandler(mocks.NewMockPersonalDataProvider(ctrl)),
+					Audit:        handler.NewAuditHandler(mocks.NewMockAuditProvider(ctrl)),
+				},
+				Security{Keyfunc: keys.Keyfunc, Revocations: revocations, Permissions: permissions, Members: members, Statuses: statuses},
+				RateLimits{},
+				[]string{"http://localhost:5173"},
+			)
+
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestNewRouter_RateLimits(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgEdDSA, Overlap: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	userID := uuid.New()
+	token, err := keys.Sign(jwt.MapClaims{
+		"sub": userID.String(),
+		"jti": uuid.NewString(),
+		"sid": uuid.NewString(),
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+
+	revocations := mocks.NewMockRevocationChecker(ctrl)
+	revocations.EXPECT().IsRevoked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+	users := mocks.NewMockUserProvider(ctrl)
+	users.EXPECT().Get(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+	statuses := mocks.NewMockStatusChecker(ctrl)
+	statuses.EXPECT().IsBlocked(gomock.Any(), userID).Return(false, nil).AnyTimes()
+
+	r := NewRouter(
+		Handlers{
+			Health:       handler.NewHealthHandler(),
+			Auth:         handler.NewAuthHandler(mocks.NewMockAuthProvider(ctrl)),
+			Verification: handler.NewVerificationHandler(mocks.NewMockVerificationProvider(ctrl)),
+			Password:     handler.NewPasswordResetHandler(mocks.NewMockPasswordResetProvider(ctrl)),
+			User:         handler.NewUserHandler(users),
+			TwoFactor:    handler.NewTwoFactorHandler(mocks.NewMockTwoFactorProvider(ctrl)),
+			Role:         handler.NewRoleHandler(mocks.NewMockRoleProvider(ctrl)),
+			APIKey:       handler.NewAPIKeyHandler(mocks.NewMockAPIKeyProvider(ctrl)),
+			JWKS:         handler.NewJWKSHandler(keys),
+			OIDC:         handler.NewOIDCHandler(mocks.NewMockOIDCProvider(ctrl)),
+			OAuth:        handler.NewOAuthHandler(mocks.NewMockOAuthProvider(ctrl)),
+			Organization: handler.NewOrganizationHandler(mocks.NewMockOrganizationProvider(ctrl)),
+			Invitation:   handler.NewInvitationHandler(mocks.NewMockInvitationProvider(ctrl)),
+			UserStatus:   handler.NewUserStatusHandler(mocks.NewMockUserStatusProvider(ctrl)),
+			UserImport:   handler.NewUserImportHandler(mocks.NewMockUserImportProvider(ctrl)),
+			PersonalData: handler.NewPersonalDataHandler(mocks.NewMockPersonalDataProvider(ctrl)),
+			Audit:        handler.NewAuditHandler(mocks.NewMockAuditProvider(ctrl)),
+		},
+		Security{Keyfunc: keys.Keyfunc, Revocations: revocations, Permissions: mocks.NewMockPermissionChecker(ctrl), Statuses: statuses},
+		RateLimits{
+			Limiter: ratelimit.NewMemoryLimiter(),
+			Auth:    ratelimit.Rate{Limit: 1, Period: time.Minute},
+			API:     ratelimit.Rate{Limit: 1, Period: time.Minute},
+		},
+		[]string{"http://localhost:5173"},
+	)
+
+	serve := func(method, url, bearer string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/login", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "1;w=60", w.Header().Get("RateLimit-Policy"))
+
+	w = serve(http.MethodPost, "/login", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = serve(http.MethodPost, "/register", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "each auth route has its own bucket")
+
+	w = serve(http.MethodGet, "/health", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"), "health checks are not limited")
+
+	w = serve(http.MethodGet, "/me", token)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(http.MethodGet, "/me", token)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "authenticated requests are limited per user")
+}
+
+func TestNewRouter_APIKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgEdDSA, Overlap: time.Minute})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	key := &model.APIKey{ID: uuid.New(), UserID: userID, OrganizationID: &orgID, Scopes: []string{"users:read"}}
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		apiKey         string
+		setupMock      func(ma *mocks.MockAPIKeyAuthenticator, mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider)
+		expectedStatus int
+	}{
+		{
+			name:   "1. GET /users With Scoped Key",
+			method: http.MethodGet,
+			url:    "/users",
+			apiKey: "ak_valid",
+			setupMock: func(ma *mocks.MockAPIKeyAuthenticator, mp *mocks.MockPermissionChecker, mu *mocks.MockUserProvider) {
+				ma.EXPECT().Authenticate(gomock.Any(), "ak_valid").Return(key, nil)
+				mp.EXPECT().HasPermission(gomock.Any(), userID, "users:read").Return(true, nil)
+				mu.EXPECT().List(gomock.Any(), model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt}).Return(&model.UserPage{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "2. GET /users With Unknown Key",
+			method: http.MethodGet,
+			url:    "/users",
+			apiKey: "ak_unknown",
+			setupMock: func(ma *mocks.MockAPIKeyAuthenticator, _ *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				ma.EXPECT().Authenticate(gomock.Any(), "ak_unknown").Return(nil, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:   "3. DELETE Own Account Outside Key Scope",
+			method: http.MethodDelete,
+			url:    "/users/" + userID.String(),
+			apiKey: "ak_valid",
+			setupMock: func(ma *mocks.MockAPIKeyAuthenticator, _ *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {
+				ma.EXPECT().Authenticate(gomock.Any(), "ak_valid").Return(key, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "4. GET /me Does Not Accept Keys",
+			method:         http.MethodGet,
+			url:            "/me",
+			apiKey:         "ak_valid",
+			setupMock:      func(_ *mocks.MockAPIKeyAuthenticator, _ *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "5. POST /me/api-keys Does Not Accept Keys",
+			method:         http.MethodPost,
+			url:            "/me/api-keys",
+			apiKey:         "ak_valid",
+			setupMock:      func(_ *mocks.MockAPIKeyAuthenticator, _ *mocks.MockPermissionChecker, _ *mocks.MockUserProvider) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			authenticator := mocks.NewMockAPIKeyAuthenticator(ctrl)
+			permissions := mocks.NewMockPermissionChecker(ctrl)
+			mockUserSvc := mocks.NewMockUserProvider(ctrl)
+			tt.setupMock(authenticator, permissions, mockUserSvc)
+			statuses := mocks.NewMockStatusChecker(ctrl)
+			statuses.EXPECT().IsBlocked(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+			r := NewRouter(
+				Handlers{
+					Health:       handler.NewHealthHandler(),
+					Auth:         handler.NewAuthHandler(mocks.NewMockAuthProvider(ctrl)),
+					Verification: handler.NewVerificationHandler(mocks.NewMockVerificationProvider(ctrl)),
+					Password:     handler.NewPasswordResetHandler(mocks.NewMockPasswordResetProvider(ctrl)),
+					User:         handler.NewUserHandler(mockUserSvc),
+					TwoFactor:    handler.NewTwoFactorHandler(mocks.NewMockTwoFactorProvider(ctrl)),
+					Role:         handler.NewRoleHandler(mocks.NewMockRoleProvider(ctrl)),
+					APIKey:       handler.NewAPIKeyHandler(mocks.NewMockAPIKeyProvider(ctrl)),
+					JWKS:         handler.NewJWKSHandler(keys),
+					OIDC:         handler.NewOIDCHandler(mocks.NewMockOIDCProvider(ctrl)),
+					OAuth:        handler.NewOAuthHandler(mocks.NewMockOAuthProvider(ctrl)),
+					Organization: handler.NewOrganizationHandler(mocks.NewMockOrganizationProvider(ctrl)),
+					Invitation:   handler.NewInvitationHandler(mocks.NewMockInvitationProvider(ctrl)),
+					UserStatus:   handler.NewUserStatusHandler(mocks.NewMockUserStatusProvider(ctrl)),
+					UserImport:   handler.NewUserImportHandler(mocks.NewMockUserImportProvider(ctrl)),
+					PersonalData: handler.NewPersonalDataHandler(mocks.NewMockPersonalDataProvider(ctrl)),
+					Audit:        handler.NewAuditHandler(mocks.NewMockAuditProvider(ctrl)),
+				},
+				Security{
+					Keyfunc:     keys.Keyfunc,
+					Revocations: mocks.NewMockRevocationChecker(ctrl),
+					Permissions: permissions,
+					APIKeys:     authenticator,
+					Statuses:    statuses,
+				},
+				RateLimits{},
+				[]string{"http://localhost:5173"},
+			)
 
 			req := httptest.NewRequest(tt.method, tt.url, nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
 			w := httptest.NewRecorder()
 
 			r.ServeHTTP(w, req)
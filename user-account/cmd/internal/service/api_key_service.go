@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// apiKeyPrefix отличает API-ключи от других токенов, в том числе в
+	// сканерах утечек секретов
+	apiKeyPrefix = "ak_"
+	// apiKeyShownPrefix - сколько первых символов ключа видно в списке
+	apiKeyShownPrefix = len(apiKeyPrefix) + 8
+	// apiKeyTouchEvery - как часто обновлять время последнего использования
+	apiKeyTouchEvery = time.Minute
+)
+
+var (
+	ErrAPIKeyNotFound     = errors.New("api key not found")
+	ErrInvalidAPIKeyScope = errors.New("api key scope is not granted to the user")
+	ErrAPIKeyTTLTooLong   = errors.New("api key lifetime exceeds the allowed maximum")
+)
+
+// APIKeyConfig - настройки API-ключей
+type APIKeyConfig struct {
+	// DefaultTTL - срок ключа, если при создании он не указан
+	DefaultTTL time.Duration
+	// MaxTTL - максимальный срок ключа
+	MaxTTL time.Duration
+}
+
+// APIKeyService выпускает именованные API-ключи с ограниченными правами и
+// проверяет их при запросах. Ключ показывается один раз, в базе хранится
+// только его хэш.
+type APIKeyService struct {
+	keys        repository.APIKeyRepository
+	orgs        repository.OrganizationRepository
+	permissions *RBACService
+	cfg         APIKeyConfig
+}
+
+func NewAPIKeyService(
+	keys repository.APIKeyRepository,
+	orgs repository.OrganizationRepository,
+	permissions *RBACService,
+	cfg APIKeyConfig,
+) *APIKeyService {
+	return &APIKeyService{keys: keys, orgs: orgs, permissions: permissions, cfg: cfg}
+}
+
+// Create выпускает ключ и возвращает его вместе с открытым значением.
+// Выдать ключу можно только права, которые есть у владельца сейчас; если
+// права у владельца потом заберут, ключ их тоже потеряет. Ключ действует
+// в организации orgID, активной при выпуске.
+func (s *APIKeyService) Create(ctx context.Context, userID uuid.UUID, orgID *uuid.UUID, name string, scopes []string, ttl time.Duration) (*model.APIKey, string, error) {
+	if ttl == 0 {
+		ttl = s.cfg.DefaultTTL
+	}
+	if ttl > s.cfg.MaxTTL {
+		return nil, "", ErrAPIKeyTTLTooLong
+	}
+
+	scopes = uniqueStrings(scopes)
+	for _, scope := range scopes {
+		granted, err := s.permissions.HasPermission(ctx, userID, scope)
+		if err != nil {
+			return nil, "", err
+		}
+		if !granted {
+			return nil, "", fmt.Errorf("%w: %s", ErrInvalidAPIKeyScope, scope)
+		}
+	}
+
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	plain := apiKeyPrefix + secret
+
+	key := &model.APIKey{
+		ID:             uuid.New(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Name:           name,
+		Prefix:         plain[:apiKeyShownPrefix],
+		KeyHash:        hashToken(plain),
+		Scopes:         scopes,
+		ExpiresAt:      time.Now().Add(ttl),
+	}
+	if err = s.keys.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+// List возвращает ключи пользователя, включая отозванные и истёкшие
+func (s *APIKeyService) List(ctx context.Context, userID uuid.UUID) ([]model.APIKey, error) {
+	return s.keys.ListByUser(ctx, userID)
+}
+
+// Revoke отзывает ключ пользователя userID
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	revoked, err := s.keys.Revoke(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate проверяет предъявленный ключ. nil без ошибки - ключ
+// неизвестен, отозван или истёк.
+func (s *APIKeyService) Authenticate(ctx context.Context, plain string) (*model.APIKey, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, nil
+	}
+
+	key, err := s.keys.GetByHash(ctx, hashToken(plain))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if key == nil || !key.Active(now) {
+		return nil, nil
+	}
+
+	// Владельца могли исключить из организации ключа
+	if key.OrganizationID != nil {
+		role, err := s.orgs.MemberRole(ctx, *key.OrganizationID, key.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, nil
+		}
+	}
+
+	// Отметка об использовании - справочная, из-за неё запрос не падает
+	if err = s.keys.TouchLastUsed(ctx, key.ID, now, apiKeyTouchEvery); err != nil {
+		log.Printf("failed to update last use of api key %s: %v", key.ID, err)
+	}
+	return key, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testAPIKeyService(ctrl *gomock.Controller, keys *mocks.MockAPIKeyRepository, userID uuid.UUID, permissions ...string) *APIKeyService {
+	roles := mocks.NewMockRoleRepository(ctrl)
+	roles.EXPECT().GetUserPermissions(gomock.Any(), userID).Return(permissions, nil).AnyTimes()
+	rbac := NewRBACService(roles, mocks.NewMockUserRepository(ctrl), nil, time.Minute)
+
+	return NewAPIKeyService(keys, testOrganizations(ctrl), rbac, APIKeyConfig{DefaultTTL: 24 * time.Hour, MaxTTL: 30 * 24 * time.Hour})
+}
+
+func TestAPIKeyService_Create(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+
+	tests := []struct {
+		name         string
+		scopes       []string
+		ttl          time.Duration
+		mockBehavior func(m *mocks.MockAPIKeyRepository)
+		expectedTTL  time.Duration
+		expectedErr  error
+	}{
+		{
+			name:   "Success With Default TTL",
+			scopes: []string{model.PermUsersRead, model.PermUsersRead},
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedTTL: 24 * time.Hour,
+		},
+		{
+			name:   "Success With Requested TTL",
+			scopes: []string{model.PermUsersRead},
+			ttl:    7 * 24 * time.Hour,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedTTL: 7 * 24 * time.Hour,
+		},
+		{
+			name:         "TTL Too Long",
+			scopes:       []string{model.PermUsersRead},
+			ttl:          365 * 24 * time.Hour,
+			mockBehavior: func(_ *mocks.MockAPIKeyRepository) {},
+			expectedErr:  ErrAPIKeyTTLTooLong,
+		},
+		{
+			name:         "Scope Not Granted To Owner",
+			scopes:       []string{model.PermUsersRead, model.PermUsersManage},
+			mockBehavior: func(_ *mocks.MockAPIKeyRepository) {},
+			expectedErr:  ErrInvalidAPIKeyScope,
+		},
+		{
+			name:   "Repository Error",
+			scopes: []string{model.PermUsersRead},
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+			},
+			expectedErr: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			keys := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mockBehavior(keys)
+			svc := testAPIKeyService(ctrl, keys, userID, model.PermUsersRead)
+
+			key, plain, err := svc.Create(context.Background(), userID, &orgID, "ci", tt.scopes, tt.ttl)
+
+			if tt.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Nil(t, key)
+				assert.Empty(t, plain)
+				if !errors.Is(err, tt.expectedErr) {
+					assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(plain, apiKeyPrefix))
+			assert.Equal(t, plain[:apiKeyShownPrefix], key.Prefix)
+			assert.Equal(t, hashToken(plain), key.KeyHash)
+			assert.NotContains(t, key.KeyHash, plain)
+			assert.Equal(t, []string{model.PermUsersRead}, key.Scopes)
+			assert.Equal(t, &orgID, key.OrganizationID)
+			assert.WithinDuration(t, time.Now().Add(tt.expectedTTL), key.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	plain := apiKeyPrefix + "secret"
+	revokedAt := time.Now().Add(-time.Hour)
+	// testOrganizations: владелец ключа ни в одной организации не состоит
+	formerOrgID := uuid.New()
+
+	tests := []struct {
+		name         string
+		plain        string
+		mockBehavior func(m *mocks.MockAPIKeyRepository)
+		wantKey      bool
+		wantErr      bool
+	}{
+		{
+			name:  "Success",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				key := &model.APIKey{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).Return(key, nil)
+				m.EXPECT().TouchLastUsed(gomock.Any(), key.ID, gomock.Any(), apiKeyTouchEvery).Return(nil)
+			},
+			wantKey: true,
+		},
+		{
+			name:  "Touch Failure Does Not Reject Key",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				key := &model.APIKey{ID: uuid.New(), UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).Return(key, nil)
+				m.EXPECT().TouchLastUsed(gomock.Any(), key.ID, gomock.Any(), apiKeyTouchEvery).Return(errors.New("db down"))
+			},
+			wantKey: true,
+		},
+		{
+			name:  "Owner Left Key Organization",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				key := &model.APIKey{ID: uuid.New(), UserID: userID, OrganizationID: &formerOrgID, ExpiresAt: time.Now().Add(time.Hour)}
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).Return(key, nil)
+			},
+		},
+		{
+			name:         "Foreign Token Format",
+			plain:        "eyJhbGciOi",
+			mockBehavior: func(_ *mocks.MockAPIKeyRepository) {},
+		},
+		{
+			name:  "Unknown Key",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).Return(nil, nil)
+			},
+		},
+		{
+			name:  "Expired Key",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).
+					Return(&model.APIKey{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+			},
+		},
+		{
+			name:  "Revoked Key",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).
+					Return(&model.APIKey{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+			},
+		},
+		{
+			name:  "Repository Error",
+			plain: plain,
+			mockBehavior: func(m *mocks.MockAPIKeyRepository) {
+				m.EXPECT().GetByHash(gomock.Any(), hashToken(plain)).Return(nil, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			keys := mocks.NewMockAPIKeyRepository(ctrl)
+			tt.mockBehavior(keys)
+			svc := testAPIKeyService(ctrl, keys, userID)
+
+			key, err := svc.Authenticate(context.Background(), tt.plain)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantKey, key != nil)
+		})
+	}
+}
+
+func TestAPIKeyService_Revoke(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	keyID := uuid.New()
+
+	keys := mocks.NewMockAPIKeyRepository(ctrl)
+	keys.EXPECT().Revoke(gomock.Any(), userID, keyID).Return(true, nil)
+	keys.EXPECT().Revoke(gomock.Any(), userID, keyID).Return(false, nil)
+	svc := testAPIKeyService(ctrl, keys, userID)
+
+	assert.NoError(t, svc.Revoke(context.Background(), userID, keyID))
+	assert.ErrorIs(t, svc.Revoke(context.Background(), userID, keyID), ErrAPIKeyNotFound)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync/atomic"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+)
+
+// errAuditChainBroken останавливает проход по журналу на первом разрыве
+var errAuditChainBroken = errors.New("audit chain is broken")
+
+// AuditLog пишет события безопасности в журнал аудита и проверяет его
+// целостность. Сервисы вызывают Record после действия; кто и откуда его
+// выполнил, берётся из контекста запроса, если не указано в событии.
+//
+// Журнал только дописывается, и стирание аккаунта его не касается, поэтому
+// адреса в него не пишутся открытым текстом: вместо них - EmailDigest.
+type AuditLog struct {
+	events    repository.AuditEventRepository
+	digestKey []byte
+	// failures - сколько событий не удалось записать с запуска
+	failures atomic.Int64
+}
+
+func NewAuditLog(events repository.AuditEventRepository, digestKey []byte) *AuditLog {
+	return &AuditLog{events: events, digestKey: digestKey}
+}
+
+// Record дописывает событие в журнал. Без Outcome событие считается
+// успешным. Сбой записи не отменяет уже выполненное действие: он пишется в
+// лог и попадает в счётчик WriteFailures, по которому настраивается
+// оповещение. Nil AuditLog ничего не пишет.
+func (a *AuditLog) Record(ctx context.Context, event model.AuditEvent) {
+	if a == nil {
+		return
+	}
+	if event.Outcome == "" {
+		event.Outcome = model.AuditSuccess
+	}
+
+	if event.ActorID == nil {
+		if user := middleware.UserFromContext(ctx); user != nil {
+			event.ActorID = &user.ID
+		}
+	}
+	if key := middleware.APIKeyFromContext(ctx); key != nil {
+		event.Metadata = withMetadata(event.Metadata, "api_key_id", key.ID.String())
+	}
+	if event.IPAddress == "" && event.UserAgent == "" {
+		client := middleware.ClientFromContext(ctx)
+		event.IPAddress = client.IPAddress
+		event.UserAgent = client.UserAgent
+	}
+
+	// Действие уже выполнено, поэтому запись не прерывается, даже если
+	// клиент успел отключиться
+	if err := a.events.Append(context.WithoutCancel(ctx), &event); err != nil {
+		a.failures.Add(1)
+		log.Printf("failed to record audit event %s: %v", event.Action, err)
+	}
+}
+
+// RecordFailure пишет неудачную попытку действия с текстом ошибки
+func (a *AuditLog) RecordFailure(ctx context.Context, event model.AuditEvent, err error) {
+	event.Outcome = model.AuditFailure
+	event.Metadata = withMetadata(event.Metadata, "error", err.Error())
+	a.Record(ctx, event)
+}
+
+// WriteFailures - сколько событий не удалось записать с запуска экземпляра.
+// Публикуется через expvar как audit_write_failures; рост счётчика значит,
+// что в журнале есть пропуски.
+func (a *AuditLog) WriteFailures() int64 {
+	if a == nil {
+		return 0
+	}
+	return a.failures.Load()
+}
+
+// EmailDigest - HMAC-SHA256 адреса без учёта регистра. По нему можно найти
+// все попытки войти в один и тот же неизвестный аккаунт, не храня сам адрес.
+func (a *AuditLog) EmailDigest(email string) string {
+	if a == nil {
+		return ""
+	}
+	mac := hmac.New(sha256.New, a.digestKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// List возвращает страницу журнала. Без размера страницы отдаётся
+// DefaultAuditPageSize, больше MaxAuditPageSize не отдаётся.
+func (a *AuditLog) List(ctx context.Context, query model.AuditEventQuery) (*model.AuditEventPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultAuditPageSize
+	}
+	if query.Limit > model.MaxAuditPageSize {
+		query.Limit = model.MaxAuditPageSize
+	}
+	return a.events.List(ctx, query)
+}
+
+// Verify проходит журнал с начала и пересчитывает цепочку хэшей. Запись,
+// изменённая в обход триггеров, не сойдётся со своим хэшем, а удалённая
+// или вставленная - с номером или хэшем следующей за ней.
+func (a *AuditLog) Verify(ctx context.Context) (*model.AuditChainCheck, error) {
+	check := &model.AuditChainCheck{Valid: true}
+	prev := model.AuditEvent{}
+
+	err := a.events.ForEach(ctx, func(e model.AuditEvent) error {
+		if e.ID != prev.ID+1 || e.PrevHash != prev.Hash || e.ComputeHash(prev.Hash) != e.Hash {
+			check.Valid = false
+			check.BrokenAt = &e.ID
+			return errAuditChainBroken
+		}
+		check.Checked++
+		prev = e
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAuditChainBroken) {
+		return nil, err
+	}
+	return check, nil
+}
+
+// withMetadata возвращает копию metadata с добавленным ключом, чтобы не
+// менять карту, которую передал вызывающий
+func withMetadata(metadata map[string]string, key, value string) map[string]string {
+	res := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		res[k] = v
+	}
+	res[key] = value
+	return res
+}
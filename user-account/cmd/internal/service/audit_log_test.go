@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/middleware"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog_Record(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	targetID := uuid.New()
+	keyID := uuid.New()
+	client := model.ClientInfo{IPAddress: "203.0.113.7", UserAgent: "curl/8.0"}
+
+	tests := []struct {
+		name     string
+		ctx      func() context.Context
+		event    model.AuditEvent
+		expected func(t *testing.T, e *model.AuditEvent)
+	}{
+		{
+			name: "1. Fills Actor And Client From Context",
+			ctx: func() context.Context {
+				ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+				return middleware.ContextWithClient(ctx, client)
+			},
+			event: model.AuditEvent{TargetID: &targetID, Action: model.AuditUserDelete},
+			expected: func(t *testing.T, e *model.AuditEvent) {
+				assert.Equal(t, userID, *e.ActorID)
+				assert.Equal(t, model.AuditSuccess, e.Outcome)
+				assert.Equal(t, client.IPAddress, e.IPAddress)
+				assert.Equal(t, client.UserAgent, e.UserAgent)
+			},
+		},
+		{
+			name: "2. Explicit Actor And Client Are Kept",
+			ctx: func() context.Context {
+				ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+				return middleware.ContextWithClient(ctx, client)
+			},
+			event: model.AuditEvent{ActorID: &targetID, Action: model.AuditLogin, IPAddress: "10.0.0.1"},
+			expected: func(t *testing.T, e *model.AuditEvent) {
+				assert.Equal(t, targetID, *e.ActorID)
+				assert.Equal(t, "10.0.0.1", e.IPAddress)
+				assert.Empty(t, e.UserAgent)
+			},
+		},
+		{
+			name: "3. Marks API Key",
+			ctx: func() context.Context {
+				ctx := middleware.ContextWithUser(context.Background(), &model.User{ID: userID})
+				return middleware.ContextWithAPIKey(ctx, &model.APIKey{ID: keyID, UserID: userID})
+			},
+			event: model.AuditEvent{Action: model.AuditRoleAssign, Metadata: map[string]string{"role": "admin"}},
+			expected: func(t *testing.T, e *model.AuditEvent) {
+				assert.Equal(t, map[string]string{"role": "admin", "api_key_id": keyID.String()}, e.Metadata)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			events := mocks.NewMockAuditEventRepository(ctrl)
+			events.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEvent) error {
+				tt.expected(t, e)
+				return nil
+			})
+
+			NewAuditLog(events, []byte("secret")).Record(tt.ctx(), tt.event)
+		})
+	}
+}
+
+func TestAuditLog_RecordFailure(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	events := mocks.NewMockAuditEventRepository(ctrl)
+	metadata := map[string]string{"email_digest": "abc"}
+
+	// Сбой записи не возвращается вызывающему, а попадает в счётчик
+	events.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEvent) error {
+		assert.Equal(t, model.AuditFailure, e.Outcome)
+		assert.Equal(t, "invalid credentials", e.Metadata["error"])
+		assert.Equal(t, "abc", e.Metadata["email_digest"])
+		return errors.New("db down")
+	})
+
+	audit := NewAuditLog(events, []byte("secret"))
+	audit.RecordFailure(context.Background(), model.AuditEvent{Action: model.AuditLogin, Metadata: metadata}, errInvalidCredentials)
+	assert.Len(t, metadata, 1, "caller metadata is not modified")
+	assert.Equal(t, int64(1), audit.WriteFailures())
+
+	var nilLog *AuditLog
+	nilLog.Record(context.Background(), model.AuditEvent{Action: model.AuditLogin})
+	assert.Zero(t, nilLog.WriteFailures())
+}
+
+func TestAuditLog_EmailDigest(t *testing.T) {
+	t.Parallel()
+
+	audit := NewAuditLog(nil, []byte("secret"))
+	digest := audit.EmailDigest("user@example.com")
+
+	assert.Len(t, digest, 64)
+	assert.NotContains(t, digest, "user")
+	assert.Equal(t, digest, audit.EmailDigest(" User@Example.COM"), "case and spaces are ignored")
+	assert.NotEqual(t, digest, NewAuditLog(nil, []byte("other")).EmailDigest("user@example.com"), "digest depends on the key")
+}
+
+func TestAuditLog_Verify(t *testing.T) {
+	t.Parallel()
+
+	chain := func() []model.AuditEvent {
+		events := make([]model.AuditEvent, 3)
+		prevHash := ""
+		for i := range events {
+			events[i] = model.AuditEvent{
+				ID:        int64(i + 1),
+				Action:    model.AuditLogin,
+				Outcome:   model.AuditSuccess,
+				CreatedAt: time.Now().UTC(),
+				PrevHash:  prevHash,
+			}
+			events[i].Hash = events[i].ComputeHash(prevHash)
+			prevHash = events[i].Hash
+		}
+		return events
+	}
+
+	tests := []struct {
+		name     string
+		events   func() []model.AuditEvent
+		expected model.AuditChainCheck
+	}{
+		{
+			name:     "1. Valid Chain",
+			events:   chain,
+			expected: model.AuditChainCheck{Valid: true, Checked: 3},
+		},
+		{
+			name: "2. Tampered Event",
+			events: func() []model.AuditEvent {
+				events := chain()
+				events[1].Outcome = model.AuditFailure
+				return events
+			},
+			expected: model.AuditChainCheck{Valid: false, Checked: 1, BrokenAt: int64Ptr(2)},
+		},
+		{
+			name: "3. Deleted Event",
+			events: func() []model.AuditEvent {
+				events := chain()
+				return append(events[:1], events[2])
+			},
+			expected: model.AuditChainCheck{Valid: false, Checked: 1, BrokenAt: int64Ptr(3)},
+		},
+		{
+			name:     "4. Empty Log",
+			events:   func() []model.AuditEvent { return nil },
+			expected: model.AuditChainCheck{Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockAuditEventRepository(ctrl)
+			repo.EXPECT().ForEach(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, fn func(model.AuditEvent) error) error {
+				for _, e := range tt.events() {
+					if err := fn(e); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			check, err := NewAuditLog(repo, []byte("secret")).Verify(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, *check)
+		})
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
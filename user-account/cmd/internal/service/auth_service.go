@@ -3,26 +3,105 @@ package service
 import (
 	"context"
 	"errors"
+	"log"
+	"sync"
 	"time"
 	"user-account/cmd/internal/model"
 	"user-account/cmd/internal/repository"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+
+	errInvalidCredentials = errors.New("invalid credentials")
+)
+
+// TokenSigner подписывает access-токены
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// PasswordHasher хэширует пароли и проверяет их. needsRehash - пароль
+// верный, но хэш устарел и его стоит пересчитать.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (ok, needsRehash bool, err error)
+}
+
+// PasswordPolicy проверяет новый пароль. personal - сведения о пользователе,
+// которых не должно быть в пароле.
+type PasswordPolicy interface {
+	Check(ctx context.Context, password string, personal ...string) error
+}
+
+// AuthConfig - настройки выдачи токенов и входа
+type AuthConfig struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// RequireVerifiedEmail запрещает вход, пока почта не подтверждена
+	RequireVerifiedEmail bool
+}
+
 type AuthService struct {
-	repo      repository.UserRepository
-	jwtSecret string
+	repo          repository.UserRepository
+	sessions      repository.SessionRepository
+	orgs          repository.OrganizationRepository
+	revocations   *RevocationService
+	verifications *EmailVerificationService
+	twoFactor     *TwoFactorService
+	guard         *LoginGuard
+	hasher        PasswordHasher
+	policy        PasswordPolicy
+	signer        TokenSigner
+	audit         *AuditLog
+	cfg           AuthConfig
+
+	// dummyHash - хэш случайного пароля с текущими параметрами, с ним
+	// сверяется пароль для неизвестного адреса
+	dummyOnce sync.Once
+	dummyHash string
 }
 
-func NewAuthService(repo repository.UserRepository, secret string) *AuthService {
-	return &AuthService{repo: repo, jwtSecret: secret}
+func NewAuthService(
+	repo repository.UserRepository,
+	sessions repository.SessionRepository,
+	orgs repository.OrganizationRepository,
+	revocations *RevocationService,
+	verifications *EmailVerificationService,
+	twoFactor *TwoFactorService,
+	guard *LoginGuard,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	signer TokenSigner,
+	audit *AuditLog,
+	cfg AuthConfig,
+) *AuthService {
+	return &AuthService{
+		repo:          repo,
+		sessions:      sessions,
+		orgs:          orgs,
+		revocations:   revocations,
+		verifications: verifications,
+		twoFactor:     twoFactor,
+		guard:         guard,
+		hasher:        hasher,
+		policy:        policy,
+		signer:        signer,
+		audit:         audit,
+		cfg:           cfg,
+	}
 }
 
 func (s *AuthService) Register(ctx context.Context, email, password, nickname string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := s.policy.Check(ctx, password, email, nickname); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(password)
 	if err != nil {
 		return err
 	}
@@ -31,33 +110,384 @@ func (s *AuthService) Register(ctx context.Context, email, password, nickname st
 		ID:           uuid.New(),
 		Email:        email,
 		Nickname:     nickname,
-		PasswordHash: string(hash),
-		Role:         "user",
+		PasswordHash: hash,
+		Roles:        []string{model.RoleUser},
+	}
+
+	if err = s.repo.Create(ctx, user); err != nil {
+		return err
 	}
+	s.audit.Record(ctx, model.AuditEvent{ActorID: &user.ID, TargetID: &user.ID, Action: model.AuditRegister})
 
-	return s.repo.Create(ctx, user)
+	// Аккаунт уже создан: если письмо не ушло, пользователь запросит его повторно
+	if err = s.verifications.Send(ctx, user); err != nil {
+		log.Printf("failed to send verification email to user %s: %v", user.ID, err)
+	}
+	return nil
 }
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (string, error) {
+// Login проверяет пароль. Если у пользователя включён второй фактор, токены
+// не выдаются: вместо них возвращается MFAToken для LoginTwoFactor.
+// После череды неудач вход временно блокируется с *LoginLockedError.
+func (s *AuthService) Login(ctx context.Context, email, password string, client model.ClientInfo) (*model.LoginResult, error) {
+	if err := s.guard.Check(ctx, email, client.IPAddress); err != nil {
+		s.auditLoginFailure(ctx, nil, email, client, err)
+		return nil, err
+	}
+
 	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil || user == nil {
+		s.verifyDummy(password)
+		s.recordFailure(ctx, email, client.IPAddress)
+		s.auditLoginFailure(ctx, nil, email, client, errInvalidCredentials)
+		return nil, errInvalidCredentials
+	}
+
+	ok, needsRehash, err := s.hasher.Verify(password, user.PasswordHash)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, err
+	}
+	if !ok {
+		s.recordFailure(ctx, email, client.IPAddress)
+		s.auditLoginFailure(ctx, &user.ID, "", client, errInvalidCredentials)
+		return nil, errInvalidCredentials
+	}
+
+	if err = s.guard.Succeed(ctx, email); err != nil {
+		return nil, err
 	}
+	if needsRehash {
+		s.rehashPassword(ctx, user, password)
+	}
+
+	return s.completeLogin(ctx, user, client)
+}
 
-	if bcrypt.CompareHashAndPassword(
-		[]byte(user.PasswordHash),
-		[]byte(password),
-	) != nil {
-		return "", errors.New("invalid credentials")
+// completeLogin завершает вход пользователя, который уже подтвердил, кто
+// он: паролем или через внешнего провайдера. Второй фактор спрашивается
+// при любом способе входа.
+func (s *AuthService) completeLogin(ctx context.Context, user *model.User, client model.ClientInfo) (*model.LoginResult, error) {
+	if user.Blocked(time.Now()) {
+		s.auditLoginFailure(ctx, &user.ID, "", client, ErrAccountSuspended)
+		return nil, ErrAccountSuspended
+	}
+	if s.cfg.RequireVerifiedEmail && user.EmailVerifiedAt == nil {
+		s.auditLoginFailure(ctx, &user.ID, "", client, ErrEmailNotVerified)
+		return nil, ErrEmailNotVerified
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	enabled, err := s.twoFactor.Enabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		var challenge string
+		if challenge, err = s.twoFactor.IssueChallenge(user.ID); err != nil {
+			return nil, err
+		}
+		return &model.LoginResult{MFAToken: challenge}, nil
+	}
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &model.LoginResult{Tokens: tokens}, nil
+}
+
+// LoginTwoFactor - второй шаг входа: MFAToken из Login и TOTP-код или код
+// восстановления. Неверные коды считаются так же, как неверные пароли.
+func (s *AuthService) LoginTwoFactor(ctx context.Context, mfaToken, code string, client model.ClientInfo) (*model.TokenPair, error) {
+	subject, err := s.twoFactor.ChallengeSubject(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	account := twoFactorAccount(subject)
+	if err = s.guard.Check(ctx, account, client.IPAddress); err != nil {
+		return nil, err
+	}
+
+	userID, err := s.twoFactor.Authenticate(ctx, mfaToken, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			s.recordFailure(ctx, account, client.IPAddress)
+			s.auditLoginFailure(ctx, &subject, "", client, err)
+		}
+		return nil, err
+	}
+	if err = s.guard.Succeed(ctx, account); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidMFAToken
+	}
+	// Доступ могли закрыть между первым и вторым шагом
+	if user.Blocked(time.Now()) {
+		s.auditLoginFailure(ctx, &user.ID, "", client, ErrAccountSuspended)
+		return nil, ErrAccountSuspended
+	}
+
+	return s.startSession(ctx, user, client)
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Каждый refresh-токен
+// одноразовый: повторное предъявление уже использованного токена считается
+// признаком кражи, и вся цепочка сессий (family) отзывается.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenPair, error) {
+	session, err := s.sessions.GetByTokenHash(ctx, hashToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if session.RotatedAt != nil || session.RevokedAt != nil {
+		if err = s.revocations.RevokeSession(ctx, session.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	rotated, err := s.sessions.MarkRotated(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Токен успели использовать между чтением и обновлением
+		if err = s.revocations.RevokeSession(ctx, session.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := s.repo.GetByID(ctx, session.UserID)
+	if err != nil || user == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.Blocked(time.Now()) {
+		return nil, ErrAccountSuspended
+	}
+
+	// Из организации могли исключить, пока сессия жила
+	orgID, err := s.activeOrganization(ctx, user.ID, session.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user, session.FamilyID, orgID, client)
+}
+
+// SwitchOrganization делает активной другую организацию пользователя.
+// Текущая сессия завершается, взамен открывается новая: иначе старый
+// refresh-токен продолжил бы выдавать токены прежней организации.
+func (s *AuthService) SwitchOrganization(ctx context.Context, userID uuid.UUID, token model.TokenClaims, orgID uuid.UUID, client model.ClientInfo) (*model.TokenPair, error) {
+	role, err := s.orgs.MemberRole(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrOrganizationNotFound
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err = s.Logout(ctx, token); err != nil {
+		return nil, err
+	}
+	return s.issueTokens(ctx, user, uuid.New(), &orgID, client)
+}
+
+// Logout завершает текущую сессию: отзывает предъявленный access-токен
+// и цепочку refresh-токенов, к которой он привязан
+func (s *AuthService) Logout(ctx context.Context, token model.TokenClaims) error {
+	if err := s.revocations.RevokeToken(ctx, token.ID, token.ExpiresAt); err != nil {
+		return err
+	}
+	if err := s.revocations.RevokeSession(ctx, token.SessionID); err != nil {
+		return err
+	}
+	event := model.AuditEvent{
+		Action:   model.AuditLogout,
+		Metadata: map[string]string{"session_id": token.SessionID.String()},
+	}
+	if userID, err := uuid.Parse(token.Subject); err == nil {
+		event.ActorID, event.TargetID = &userID, &userID
+	}
+	s.audit.Record(ctx, event)
+	return nil
+}
+
+// LogoutAll завершает все сессии пользователя на всех устройствах
+func (s *AuthService) LogoutAll(ctx context.Context, userID uuid.UUID, token model.TokenClaims) error {
+	if err := s.revocations.RevokeToken(ctx, token.ID, token.ExpiresAt); err != nil {
+		return err
+	}
+	if err := s.revocations.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{ActorID: &userID, TargetID: &userID, Action: model.AuditLogoutAll})
+	return nil
+}
+
+// recordFailure засчитывает неудачную попытку входа. Ошибка хранилища
+// счётчиков только логируется, чтобы не подменять ответ о неверных данных.
+func (s *AuthService) recordFailure(ctx context.Context, account, ip string) {
+	if err := s.guard.Fail(ctx, account, ip); err != nil {
+		log.Printf("failed to record login failure: %v", err)
+	}
+}
+
+// auditLoginFailure пишет в журнал неудачный вход. userID пуст, если
+// аккаунт не нашёлся; тогда во что пытались войти, видно по EmailDigest
+// адреса - сам адрес не пишется, его потом не стереть из журнала.
+func (s *AuthService) auditLoginFailure(ctx context.Context, userID *uuid.UUID, email string, client model.ClientInfo, err error) {
+	event := model.AuditEvent{
+		TargetID:  userID,
+		Action:    model.AuditLogin,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	}
+	if userID == nil && email != "" {
+		event.Metadata = map[string]string{"email_digest": s.audit.EmailDigest(email)}
+	}
+	s.audit.RecordFailure(ctx, event, err)
+}
+
+// verifyDummy проверяет пароль неизвестного адреса по заглушке, чтобы
+// отказ занимал столько же времени, сколько неверный пароль, и по времени
+// ответа нельзя было узнать, есть ли такой аккаунт
+func (s *AuthService) verifyDummy(password string) {
+	s.dummyOnce.Do(func() {
+		hash, err := s.hasher.Hash(uuid.NewString())
+		if err != nil {
+			log.Printf("failed to hash dummy password: %v", err)
+			return
+		}
+		s.dummyHash = hash
+	})
+	if s.dummyHash != "" {
+		_, _, _ = s.hasher.Verify(password, s.dummyHash)
+	}
+}
+
+// rehashPassword пересчитывает устаревший хэш, пока открытый пароль под
+// рукой. Вход из-за неудачи не срывается: попробуем в следующий раз.
+func (s *AuthService) rehashPassword(ctx context.Context, user *model.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err == nil {
+		_, err = s.repo.RehashPassword(ctx, user.ID, user.PasswordHash, hash)
+	}
+	if err != nil {
+		log.Printf("failed to rehash password of user %s: %v", user.ID, err)
+	}
+}
+
+// startSession открывает новую цепочку сессий в организации по умолчанию
+func (s *AuthService) startSession(ctx context.Context, user *model.User, client model.ClientInfo) (*model.TokenPair, error) {
+	orgID, err := s.activeOrganization(ctx, user.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.issueTokens(ctx, user, uuid.New(), orgID, client)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, model.AuditEvent{
+		ActorID:   &user.ID,
+		TargetID:  &user.ID,
+		Action:    model.AuditLogin,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+	})
+	return tokens, nil
+}
+
+// activeOrganization возвращает preferred, если пользователь всё ещё в ней
+// состоит, иначе его организацию по умолчанию. nil - пользователь не
+// состоит ни в одной организации.
+func (s *AuthService) activeOrganization(ctx context.Context, userID uuid.UUID, preferred *uuid.UUID) (*uuid.UUID, error) {
+	if preferred != nil {
+		role, err := s.orgs.MemberRole(ctx, *preferred, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != "" {
+			return preferred, nil
+		}
+	}
+
+	memberships, err := s.orgs.ListForUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(memberships) == 0 {
+		return nil, nil
+	}
+	return &memberships[0].ID, nil
+}
+
+// issueTokens открывает новую сессию в цепочке familyID и подписывает
+// access-токен. Активная организация orgID попадает в claim org.
+func (s *AuthService) issueTokens(ctx context.Context, user *model.User, familyID uuid.UUID, orgID *uuid.UUID, client model.ClientInfo) (*model.TokenPair, error) {
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &model.Session{
+		ID:             uuid.New(),
+		FamilyID:       familyID,
+		UserID:         user.ID,
+		OrganizationID: orgID,
+		TokenHash:      hashToken(refreshToken),
+		UserAgent:      client.UserAgent,
+		IPAddress:      client.IPAddress,
+		ExpiresAt:      now.Add(s.cfg.RefreshTTL),
+	}
+	if err = s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{
 		"sub":      user.ID.String(),
-		"role":     user.Role,
+		"role":     user.PrimaryRole(),
+		"roles":    user.Roles,
 		"nickname": user.Nickname,
 		"email":    user.Email,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(),
-	})
+		"jti":      uuid.NewString(),
+		"sid":      familyID.String(),
+		"iat":      now.Unix(),
+		"exp":      now.Add(s.cfg.AccessTTL).Unix(),
+	}
+	if orgID != nil {
+		claims["org"] = orgID.String()
+	}
+
+	accessToken, err := s.signer.Sign(claims)
+	if err != nil {
+		return nil, err
+	}
 
-	return token.SignedString([]byte(s.jwtSecret))
+	return &model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.cfg.AccessTTL,
+	}, nil
 }
@@ -3,9 +3,18 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/keyring"
+	"user-account/cmd/internal/mailer"
 	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/passhash"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/signedtoken"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
@@ -14,6 +23,49 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+func testSigner(t *testing.T, secret string) *keyring.Keyring {
+	t.Helper()
+	keys, err := keyring.New(keyring.Options{Algorithm: keyring.AlgHS256, Secret: secret})
+	if err != nil {
+		t.Fatalf("failed to create keyring: %v", err)
+	}
+	return keys
+}
+
+// testHasherParams - дешёвые параметры argon2id для тестов
+var testHasherParams = passhash.Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func testHasher() *passhash.Hasher {
+	return passhash.New(testHasherParams, nil)
+}
+
+// testPolicy - политика паролей для тестов: только минимальная длина
+func testPolicy() *passpolicy.Policy {
+	return passpolicy.New(passpolicy.Config{MinLength: 6}, nil)
+}
+
+// strictPolicy - политика, запрещающая ник и почту в пароле
+func strictPolicy() *passpolicy.Policy {
+	return passpolicy.New(passpolicy.Config{MinLength: 6, ForbidPersonal: true}, nil)
+}
+
+// testOrganizations - организации для тестов, где они не важны:
+// пользователь не состоит ни в одной
+func testOrganizations(ctrl *gomock.Controller) *mocks.MockOrganizationRepository {
+	orgs := mocks.NewMockOrganizationRepository(ctrl)
+	orgs.EXPECT().ListForUser(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	orgs.EXPECT().MemberRole(gomock.Any(), gomock.Any(), gomock.Any()).Return("", nil).AnyTimes()
+	return orgs
+}
+
+// assertPasswordHash проверяет, что hash - хэш пароля password
+func assertPasswordHash(t *testing.T, hash, password string) {
+	t.Helper()
+	ok, _, err := testHasher().Verify(password, hash)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
 func TestAuthService_Register(t *testing.T) {
 	t.Parallel()
 
@@ -25,27 +77,44 @@ func TestAuthService_Register(t *testing.T) {
 
 	tests := []struct {
 		name         string
-		mockBehavior func(m *mocks.MockUserRepository)
+		mockBehavior func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer)
 		wantErr      bool
 	}{
 		{
 			name: "Success Registration",
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer) {
 				m.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
 					DoAndReturn(func(ctx context.Context, u *model.User) error {
 						assert.Equal(t, email, u.Email)
 						assert.Equal(t, nickname, u.Nickname)
-						err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
-						assert.NoError(t, err)
+						assert.Equal(t, []string{model.RoleUser}, u.Roles)
+						assertPasswordHash(t, u.PasswordHash, password)
+						return nil
+					})
+				mv.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+						assert.Equal(t, email, msg.To)
+						assert.Contains(t, msg.Body, "https://app.example.com/verify-email?token=")
 						return nil
 					})
 			},
 			wantErr: false,
 		},
+		{
+			name: "Mail Failure Does Not Fail Registration",
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer) {
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mv.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp unavailable"))
+			},
+			wantErr: false,
+		},
 		{
 			name: "DB Error",
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository, _ *mocks.MockMailer) {
 				m.EXPECT().
 					Create(gomock.Any(), gomock.Any()).
 					Return(errors.New("db connection lost"))
@@ -61,15 +130,36 @@ func TestAuthService_Register(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			repo := mocks.NewMockUserRepository(ctrl)
-			tt.mockBehavior(repo)
+			verificationRepo := mocks.NewMockEmailVerificationRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(repo, verificationRepo, mail)
 
-			svc := NewAuthService(repo, "secret")
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+			verifications := NewEmailVerificationService(repo, verificationRepo, signedtoken.New("action-secret"), mail, time.Hour, "https://app.example.com")
+			svc := NewAuthService(repo, sessions, testOrganizations(ctrl), revocations, verifications, nil, nil, testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
 			err := svc.Register(context.Background(), email, password, nickname)
 			assert.Equal(t, tt.wantErr, err != nil)
 		})
 	}
 }
 
+func TestAuthService_Register_RejectsPasswordByPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	// Пароль отклонён до обращения к базе: ни Create, ни письма не ожидаются
+	repo := mocks.NewMockUserRepository(ctrl)
+	svc := NewAuthService(repo, nil, nil, nil, nil, nil, nil, testHasher(), strictPolicy(), testSigner(t, "secret"), nil, AuthConfig{})
+
+	err := svc.Register(context.Background(), "new@example.com", "Wanderer-2024", "wanderer")
+
+	var policyErr *passpolicy.Error
+	if assert.ErrorAs(t, err, &policyErr) {
+		assert.Equal(t, passpolicy.RulePersonalInfo, policyErr.Violations[0].Rule)
+	}
+}
+
 func TestAuthService_Login(t *testing.T) {
 	t.Parallel()
 
@@ -78,38 +168,67 @@ func TestAuthService_Login(t *testing.T) {
 	password := "correct-pass"
 	userID := uuid.New()
 
-	hash, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hash, _ := testHasher().Hash(password)
 	mockUser := &model.User{
 		ID:           userID,
 		Email:        userEmail,
-		PasswordHash: string(hash),
-		Role:         "admin",
+		PasswordHash: hash,
+		Roles:        []string{"admin"},
 	}
+	suspendedUser := *mockUser
+	suspendedUser.Status = model.UserStatusSuspended
+	expired := time.Now().Add(-time.Minute)
+	expiredUser := suspendedUser
+	expiredUser.StatusExpiresAt = &expired
 
 	tests := []struct {
-		name         string
-		email        string
-		password     string
-		mockBehavior func(m *mocks.MockUserRepository)
-		wantErr      bool
-		expectedErr  string
+		name            string
+		email           string
+		password        string
+		requireVerified bool
+		mockBehavior    func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository)
+		wantErr         bool
+		expectedErr     string
 	}{
 		{
 			name:     "Success Login",
 			email:    userEmail,
 			password: password,
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
 				m.EXPECT().
 					GetByEmail(gomock.Any(), userEmail).
 					Return(mockUser, nil)
+				ms.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s *model.Session) error {
+						assert.Equal(t, userID, s.UserID)
+						assert.Equal(t, "test-agent", s.UserAgent)
+						assert.NotEmpty(t, s.TokenHash)
+						return nil
+					})
 			},
 			wantErr: false,
 		},
+		{
+			name:     "Session Store Error",
+			email:    userEmail,
+			password: password,
+			mockBehavior: func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				m.EXPECT().
+					GetByEmail(gomock.Any(), userEmail).
+					Return(mockUser, nil)
+				ms.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(errors.New("db error"))
+			},
+			wantErr:     true,
+			expectedErr: "db error",
+		},
 		{
 			name:     "Invalid Password",
 			email:    userEmail,
 			password: "wrong-password",
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockSessionRepository) {
 				m.EXPECT().
 					GetByEmail(gomock.Any(), userEmail).
 					Return(mockUser, nil)
@@ -117,11 +236,48 @@ func TestAuthService_Login(t *testing.T) {
 			wantErr:     true,
 			expectedErr: "invalid credentials",
 		},
+		{
+			name:            "Unverified Email Blocked When Required",
+			email:           userEmail,
+			password:        password,
+			requireVerified: true,
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockSessionRepository) {
+				m.EXPECT().
+					GetByEmail(gomock.Any(), userEmail).
+					Return(mockUser, nil)
+			},
+			wantErr:     true,
+			expectedErr: "email is not verified",
+		},
+		{
+			name:     "Suspended Account",
+			email:    userEmail,
+			password: password,
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockSessionRepository) {
+				m.EXPECT().
+					GetByEmail(gomock.Any(), userEmail).
+					Return(&suspendedUser, nil)
+			},
+			wantErr:     true,
+			expectedErr: "account is suspended",
+		},
+		{
+			name:     "Expired Suspension Allows Login",
+			email:    userEmail,
+			password: password,
+			mockBehavior: func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				m.EXPECT().
+					GetByEmail(gomock.Any(), userEmail).
+					Return(&expiredUser, nil)
+				ms.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
 		{
 			name:     "User Not Found",
 			email:    "unknown@example.com",
 			password: "any",
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockSessionRepository) {
 				m.EXPECT().
 					GetByEmail(gomock.Any(), "unknown@example.com").
 					Return(nil, errors.New("not found"))
@@ -137,19 +293,32 @@ func TestAuthService_Login(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			repo := mocks.NewMockUserRepository(ctrl)
-			tt.mockBehavior(repo)
-			svc := NewAuthService(repo, secret)
-			token, err := svc.Login(context.Background(), tt.email, tt.password)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(repo, sessions)
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+			totpRepo := mocks.NewMockTOTPRepository(ctrl)
+			totpRepo.EXPECT().Get(gomock.Any(), userID).Return(nil, nil).AnyTimes()
+			twoFactor := NewTwoFactorService(totpRepo, repo, signedtoken.New("action-secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+			svc := NewAuthService(repo, sessions, testOrganizations(ctrl), revocations, nil, twoFactor, testLoginGuard(), testHasher(), testPolicy(), testSigner(t, secret), nil, AuthConfig{
+				AccessTTL:            15 * time.Minute,
+				RefreshTTL:           time.Hour,
+				RequireVerifiedEmail: tt.requireVerified,
+			})
+			result, err := svc.Login(context.Background(), tt.email, tt.password, model.ClientInfo{UserAgent: "test-agent"})
 
 			if tt.wantErr {
 				assert.Error(t, err)
-				assert.Empty(t, token)
+				assert.Nil(t, result)
 				assert.Equal(t, tt.expectedErr, err.Error())
 			} else {
 				assert.NoError(t, err)
-				assert.NotEmpty(t, token)
+				assert.Empty(t, result.MFAToken)
+				tokens := result.Tokens
+				assert.NotEmpty(t, tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
+				assert.Equal(t, 15*time.Minute, tokens.ExpiresIn)
 
-				parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+				parsedToken, err := jwt.Parse(tokens.AccessToken, func(t *jwt.Token) (interface{}, error) {
 					return []byte(secret), nil
 				})
 				assert.NoError(t, err)
@@ -158,7 +327,496 @@ func TestAuthService_Login(t *testing.T) {
 				assert.True(t, ok)
 				assert.Equal(t, userID.String(), claims["sub"])
 				assert.Equal(t, "admin", claims["role"])
+				assert.Equal(t, []interface{}{"admin"}, claims["roles"])
+				assert.NotEmpty(t, claims["jti"])
+				assert.NotEmpty(t, claims["sid"])
+			}
+		})
+	}
+}
+
+func TestAuthService_Login_RehashesOutdatedHash(t *testing.T) {
+	t.Parallel()
+
+	const password = "correct-pass"
+	legacy, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	weaker := testHasherParams
+	weaker.Memory = 32
+	outdated, _ := passhash.New(weaker, nil).Hash(password)
+	current, _ := testHasher().Hash(password)
+
+	tests := []struct {
+		name       string
+		hash       string
+		rehashErr  error
+		wantRehash bool
+	}{
+		{name: "Legacy Bcrypt", hash: string(legacy), wantRehash: true},
+		{name: "Outdated Argon2 Parameters", hash: outdated, wantRehash: true},
+		{name: "Rehash Failure Does Not Fail Login", hash: string(legacy), rehashErr: errors.New("db down"), wantRehash: true},
+		{name: "Current Hash Is Kept", hash: current, wantRehash: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			user := &model.User{ID: uuid.New(), Email: "legacy@example.com", PasswordHash: tt.hash}
+			repo := mocks.NewMockUserRepository(ctrl)
+			repo.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(user, nil)
+			if tt.wantRehash {
+				repo.EXPECT().RehashPassword(gomock.Any(), user.ID, tt.hash, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, _, newHash string) (bool, error) {
+						assert.True(t, strings.HasPrefix(newHash, "$argon2id$v=19$m=64,t=1,p=1$"))
+						assertPasswordHash(t, newHash, password)
+						return tt.rehashErr == nil, tt.rehashErr
+					})
+			}
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			totpRepo := mocks.NewMockTOTPRepository(ctrl)
+			totpRepo.EXPECT().Get(gomock.Any(), user.ID).Return(nil, nil)
+			twoFactor := NewTwoFactorService(totpRepo, repo, signedtoken.New("action-secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+
+			svc := NewAuthService(repo, sessions, testOrganizations(ctrl), nil, nil, twoFactor, testLoginGuard(), testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			result, err := svc.Login(context.Background(), user.Email, password, model.ClientInfo{})
+
+			assert.NoError(t, err)
+			assert.NotNil(t, result.Tokens)
+		})
+	}
+}
+
+// countingHasher считает проверки паролей
+type countingHasher struct {
+	PasswordHasher
+	verified atomic.Int32
+}
+
+func (h *countingHasher) Verify(password, encoded string) (bool, bool, error) {
+	h.verified.Add(1)
+	return h.PasswordHasher.Verify(password, encoded)
+}
+
+func TestAuthService_Login_UnknownEmailVerifiesDummyHash(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	repo := mocks.NewMockUserRepository(ctrl)
+	repo.EXPECT().GetByEmail(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	hasher := &countingHasher{PasswordHasher: testHasher()}
+
+	svc := NewAuthService(repo, nil, nil, nil, nil, nil, testLoginGuard(), hasher, testPolicy(), testSigner(t, "secret"), nil, AuthConfig{})
+	for _, email := range []string{"ghost@example.com", "phantom@example.com"} {
+		_, err := svc.Login(context.Background(), email, "any-pass", model.ClientInfo{})
+		assert.ErrorIs(t, err, errInvalidCredentials)
+	}
+
+	// Неизвестный адрес стоит столько же, сколько неверный пароль
+	assert.Equal(t, int32(2), hasher.verified.Load())
+	assert.NotEmpty(t, svc.dummyHash)
+}
+
+func TestAuthService_Login_AuditsFailure(t *testing.T) {
+	t.Parallel()
+
+	hash, _ := testHasher().Hash("correct-pass")
+	user := &model.User{ID: uuid.New(), Email: "audit@example.com", PasswordHash: hash}
+	audit := NewAuditLog(nil, []byte("audit-secret"))
+
+	tests := []struct {
+		name      string
+		email     string
+		user      *model.User
+		appendErr error
+		expected  func(t *testing.T, e *model.AuditEvent)
+		wantErr   string
+	}{
+		{
+			name:  "1. Unknown Account Keeps Only Email Digest",
+			email: "Ghost@Example.com ",
+			expected: func(t *testing.T, e *model.AuditEvent) {
+				assert.Nil(t, e.TargetID)
+				assert.Equal(t, audit.EmailDigest("ghost@example.com"), e.Metadata["email_digest"])
+				assert.NotContains(t, e.Metadata, "email")
+			},
+			wantErr: "invalid credentials",
+		},
+		{
+			name:  "2. Known Account Keeps Only Target",
+			email: user.Email,
+			user:  user,
+			expected: func(t *testing.T, e *model.AuditEvent) {
+				assert.Equal(t, user.ID, *e.TargetID)
+				assert.Equal(t, map[string]string{"error": "invalid credentials"}, e.Metadata)
+			},
+			wantErr: "invalid credentials",
+		},
+		{
+			name:      "3. Audit Failure Does Not Change The Answer",
+			email:     user.Email,
+			user:      user,
+			appendErr: errors.New("db down"),
+			expected:  func(_ *testing.T, _ *model.AuditEvent) {},
+			wantErr:   "invalid credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			repo := mocks.NewMockUserRepository(ctrl)
+			repo.EXPECT().GetByEmail(gomock.Any(), tt.email).Return(tt.user, nil)
+			events := mocks.NewMockAuditEventRepository(ctrl)
+			events.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEvent) error {
+				assert.Equal(t, model.AuditFailure, e.Outcome)
+				assert.NotContains(t, fmt.Sprint(e.Metadata), "@")
+				tt.expected(t, e)
+				return tt.appendErr
+			})
+
+			svc := NewAuthService(repo, nil, nil, nil, nil, nil, testLoginGuard(), testHasher(), testPolicy(), testSigner(t, "secret"), NewAuditLog(events, []byte("audit-secret")), AuthConfig{})
+			_, err := svc.Login(context.Background(), tt.email, "wrong-pass", model.ClientInfo{})
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	t.Parallel()
+
+	const refreshToken = "opaque-refresh-token"
+	userID := uuid.New()
+	familyID := uuid.New()
+	sessionID := uuid.New()
+	user := &model.User{ID: userID, Email: "refresh@example.com", Roles: []string{"user"}}
+	usedAt := time.Now().Add(-time.Minute)
+
+	activeSession := func() *model.Session {
+		return &model.Session{
+			ID:        sessionID,
+			FamilyID:  familyID,
+			UserID:    userID,
+			TokenHash: hashToken(refreshToken),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository)
+		wantErr      error
+	}{
+		{
+			name: "Success Rotation",
+			mockBehavior: func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				ms.EXPECT().GetByTokenHash(gomock.Any(), hashToken(refreshToken)).Return(activeSession(), nil)
+				ms.EXPECT().MarkRotated(gomock.Any(), sessionID).Return(true, nil)
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+				ms.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, s *model.Session) error {
+						assert.Equal(t, familyID, s.FamilyID)
+						assert.NotEqual(t, sessionID, s.ID)
+						assert.NotEqual(t, hashToken(refreshToken), s.TokenHash)
+						return nil
+					})
+			},
+		},
+		{
+			name: "Unknown Token",
+			mockBehavior: func(_ *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				ms.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Expired Token",
+			mockBehavior: func(_ *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				s := activeSession()
+				s.ExpiresAt = time.Now().Add(-time.Second)
+				ms.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).Return(s, nil)
+			},
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name: "Replayed Token Revokes Family",
+			mockBehavior: func(_ *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				s := activeSession()
+				s.RotatedAt = &usedAt
+				ms.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).Return(s, nil)
+				ms.EXPECT().RevokeFamily(gomock.Any(), familyID).Return(nil)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+		{
+			name: "Concurrent Rotation Revokes Family",
+			mockBehavior: func(_ *mocks.MockUserRepository, ms *mocks.MockSessionRepository) {
+				ms.EXPECT().GetByTokenHash(gomock.Any(), gomock.Any()).Return(activeSession(), nil)
+				ms.EXPECT().MarkRotated(gomock.Any(), sessionID).Return(false, nil)
+				ms.EXPECT().RevokeFamily(gomock.Any(), familyID).Return(nil)
+			},
+			wantErr: ErrRefreshTokenReused,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			repo := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(repo, sessions)
+
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+			svc := NewAuthService(repo, sessions, testOrganizations(ctrl), revocations, nil, nil, nil, testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			tokens, err := svc.Refresh(context.Background(), refreshToken, model.ClientInfo{})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tokens)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, tokens.AccessToken)
+			assert.NotEqual(t, refreshToken, tokens.RefreshToken)
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	token := model.TokenClaims{
+		ID:        uuid.NewString(),
+		Subject:   userID.String(),
+		SessionID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	tests := []struct {
+		name         string
+		all          bool
+		mockBehavior func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository)
+		wantErr      bool
+	}{
+		{
+			name: "Logout Current Device",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository) {
+				mt.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(nil)
+				ms.EXPECT().RevokeFamily(gomock.Any(), token.SessionID).Return(nil)
+			},
+		},
+		{
+			name: "Logout Everywhere",
+			all:  true,
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository) {
+				mt.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(nil)
+				ms.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+			},
+		},
+		{
+			name: "Revocation Store Error",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, _ *mocks.MockSessionRepository) {
+				mt.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(tokens, sessions)
+
+			// В журнале и тот, кто вышел, и чья сессия завершена
+			events := mocks.NewMockAuditEventRepository(ctrl)
+			if !tt.wantErr {
+				events.EXPECT().Append(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEvent) error {
+					assert.Equal(t, &userID, e.ActorID)
+					assert.Equal(t, &userID, e.TargetID)
+					return nil
+				})
+			}
+
+			revocations := NewRevocationService(tokens, sessions, time.Second, time.Minute)
+			svc := NewAuthService(mocks.NewMockUserRepository(ctrl), sessions, testOrganizations(ctrl), revocations, nil, nil, nil, testHasher(), testPolicy(), testSigner(t, "secret"), NewAuditLog(events, []byte("audit-secret")), AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+
+			var err error
+			if tt.all {
+				err = svc.LogoutAll(context.Background(), userID, token)
+			} else {
+				err = svc.Logout(context.Background(), token)
+			}
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestAuthService_Refresh_ActiveOrganization(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	sessionOrg := uuid.New()
+	defaultOrg := uuid.New()
+	refreshToken := "refresh"
+	user := &model.User{ID: userID, Email: "test@example.com", Roles: []string{model.RoleUser}}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(mo *mocks.MockOrganizationRepository)
+		expectedOrg  *uuid.UUID
+	}{
+		{
+			name: "1. Keeps Session Organization",
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), sessionOrg, userID).Return(model.OrgRoleMember, nil)
+			},
+			expectedOrg: &sessionOrg,
+		},
+		{
+			name: "2. Falls Back To Default After Removal",
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), sessionOrg, userID).Return("", nil)
+				mo.EXPECT().ListForUser(gomock.Any(), userID).Return([]model.OrganizationMembership{
+					{Organization: model.Organization{ID: defaultOrg}, Role: model.OrgRoleOwner},
+				}, nil)
+			},
+			expectedOrg: &defaultOrg,
+		},
+		{
+			name: "3. No Organizations Left",
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), sessionOrg, userID).Return("", nil)
+				mo.EXPECT().ListForUser(gomock.Any(), userID).Return(nil, nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			repo := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			tt.mockBehavior(orgs)
+
+			session := &model.Session{
+				ID:             uuid.New(),
+				FamilyID:       uuid.New(),
+				UserID:         userID,
+				OrganizationID: &sessionOrg,
+				TokenHash:      hashToken(refreshToken),
+				ExpiresAt:      time.Now().Add(time.Hour),
+			}
+			sessions.EXPECT().GetByTokenHash(gomock.Any(), hashToken(refreshToken)).Return(session, nil)
+			sessions.EXPECT().MarkRotated(gomock.Any(), session.ID).Return(true, nil)
+			repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+			sessions.EXPECT().
+				Create(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, s *model.Session) error {
+					assert.Equal(t, tt.expectedOrg, s.OrganizationID)
+					return nil
+				})
+
+			keys := testSigner(t, "secret")
+			svc := NewAuthService(repo, sessions, orgs, nil, nil, nil, nil, testHasher(), testPolicy(), keys, nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			tokens, err := svc.Refresh(context.Background(), refreshToken, model.ClientInfo{})
+			assert.NoError(t, err)
+
+			parsed, err := jwt.Parse(tokens.AccessToken, keys.Keyfunc)
+			assert.NoError(t, err)
+			claims := parsed.Claims.(jwt.MapClaims)
+			if tt.expectedOrg != nil {
+				assert.Equal(t, tt.expectedOrg.String(), claims["org"])
+			} else {
+				assert.NotContains(t, claims, "org")
+			}
+		})
+	}
+}
+
+func TestAuthService_SwitchOrganization(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	orgID := uuid.New()
+	user := &model.User{ID: userID, Email: "test@example.com", Roles: []string{model.RoleUser}}
+	token := model.TokenClaims{
+		ID:        uuid.NewString(),
+		SessionID: uuid.New(),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository, mt *mocks.MockRevokedTokenRepository, mo *mocks.MockOrganizationRepository)
+		wantErr      error
+	}{
+		{
+			name: "1. Success Starts New Session",
+			mockBehavior: func(m *mocks.MockUserRepository, ms *mocks.MockSessionRepository, mt *mocks.MockRevokedTokenRepository, mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, userID).Return(model.OrgRoleAdmin, nil)
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+				mt.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(nil)
+				ms.EXPECT().RevokeFamily(gomock.Any(), token.SessionID).Return(nil)
+				ms.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, s *model.Session) error {
+						assert.NotEqual(t, token.SessionID, s.FamilyID)
+						assert.Equal(t, &orgID, s.OrganizationID)
+						return nil
+					})
+			},
+		},
+		{
+			name: "2. Not A Member",
+			mockBehavior: func(_ *mocks.MockUserRepository, _ *mocks.MockSessionRepository, _ *mocks.MockRevokedTokenRepository, mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, userID).Return("", nil)
+			},
+			wantErr: ErrOrganizationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			repo := mocks.NewMockUserRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			revoked := mocks.NewMockRevokedTokenRepository(ctrl)
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			tt.mockBehavior(repo, sessions, revoked, orgs)
+
+			revocations := NewRevocationService(revoked, sessions, time.Second, time.Minute)
+			svc := NewAuthService(repo, sessions, orgs, revocations, nil, nil, nil, testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			tokens, err := svc.SwitchOrganization(context.Background(), userID, token, orgID, model.ClientInfo{})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, tokens)
+				return
 			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, tokens.RefreshToken)
 		})
 	}
 }
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// dataExportStaleAfter - сколько может собираться одна выгрузка. Дольше -
+// значит, собиравший экземпляр упал, и выгрузку забирает другой.
+const dataExportStaleAfter = 15 * time.Minute
+
+// DataExporter собирает выгрузки персональных данных из очереди в
+// ZIP-архивы и удаляет истёкшие
+type DataExporter struct {
+	exports repository.DataExportRepository
+	data    repository.PersonalDataRepository
+	ttl     time.Duration
+}
+
+func NewDataExporter(exports repository.DataExportRepository, data repository.PersonalDataRepository, ttl time.Duration) *DataExporter {
+	return &DataExporter{exports: exports, data: data, ttl: ttl}
+}
+
+// ProcessNext собирает одну выгрузку из очереди. Возвращает false, если
+// очередь пуста. Сбой сборки записывается в выгрузку и ошибкой не считается,
+// ошибка - только если не удалось работать с очередью.
+func (e *DataExporter) ProcessNext(ctx context.Context) (bool, error) {
+	export, err := e.exports.Claim(ctx, time.Now().Add(-dataExportStaleAfter))
+	if err != nil || export == nil {
+		return false, err
+	}
+
+	archive, err := e.build(ctx, export.UserID)
+	if err != nil {
+		log.Printf("data export %s failed: %v", export.ID, err)
+		return true, e.exports.Fail(ctx, export.ID, err.Error(), time.Now().Add(e.ttl))
+	}
+	return true, e.exports.Complete(ctx, export.ID, archive, time.Now().Add(e.ttl))
+}
+
+// Run удаляет истёкшие выгрузки и собирает всю очередь сразу и затем раз в
+// interval, пока не отменён ctx. Ошибки только пишутся в лог, следующий
+// проход повторит попытку.
+func (e *DataExporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.exports.DeleteExpired(ctx, time.Now()); err != nil {
+			log.Printf("expired data exports cleanup failed: %v", err)
+		}
+		for ctx.Err() == nil {
+			processed, err := e.ProcessNext(ctx)
+			if err != nil {
+				log.Printf("data export queue failed: %v", err)
+			}
+			if !processed || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *DataExporter) build(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	data, err := e.data.Collect(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrUserNotFound
+	}
+	return personalDataArchive(data, time.Now())
+}
+
+// personalDataArchive раскладывает данные по JSON-файлам архива, по файлу на
+// раздел. manifest.json описывает выгрузку целиком.
+func personalDataArchive(data *model.PersonalData, generatedAt time.Time) ([]byte, error) {
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"profile.json", data.Profile},
+		{"organizations.json", data.Organizations},
+		{"sessions.json", data.Sessions},
+		{"api_keys.json", data.APIKeys},
+		{"identities.json", data.Identities},
+		{"status_history.json", data.StatusHistory},
+		{"oauth_clients.json", data.OAuthClients},
+		{"invitations.json", data.Invitations},
+		{"audit_events.json", data.AuditEvents},
+	}
+
+	manifest := struct {
+		UserID      uuid.UUID `json:"user_id"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Files       []string  `json:"files"`
+	}{UserID: data.Profile.ID, GeneratedAt: generatedAt.UTC()}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	write := func(name string, content interface{}) error {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: generatedAt})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(content)
+	}
+
+	if err := write("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := write(f.name, f.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExporter_ProcessNext(t *testing.T) {
+	t.Parallel()
+
+	ttl := 24 * time.Hour
+	userID := uuid.New()
+	export := &model.DataExport{ID: uuid.New(), UserID: userID, Status: model.DataExportProcessing}
+	data := &model.PersonalData{
+		Profile:  model.User{ID: userID, Email: "user@example.com", PasswordHash: "secret-hash"},
+		Sessions: []model.Session{{ID: uuid.New(), UserID: userID, TokenHash: "refresh-hash"}},
+		AuditEvents: []model.AuditEvent{
+			{ID: 1, ActorID: &userID, Action: model.AuditLogin, Outcome: model.AuditSuccess},
+			{ID: 2, TargetID: &userID, Action: model.AuditUserSuspend, Outcome: model.AuditSuccess},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(me *mocks.MockDataExportRepository, md *mocks.MockPersonalDataRepository)
+		processed    bool
+		wantErr      bool
+	}{
+		{
+			name: "1. Builds Archive",
+			mockBehavior: func(me *mocks.MockDataExportRepository, md *mocks.MockPersonalDataRepository) {
+				me.EXPECT().Claim(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, staleBefore time.Time) (*model.DataExport, error) {
+					assert.WithinDuration(t, time.Now().Add(-dataExportStaleAfter), staleBefore, time.Minute)
+					return export, nil
+				})
+				md.EXPECT().Collect(gomock.Any(), userID).Return(data, nil)
+				me.EXPECT().Complete(gomock.Any(), export.ID, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, archive []byte, expiresAt time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(ttl), expiresAt, time.Minute)
+
+						files := unzip(t, archive)
+						assert.Len(t, files, 10)
+						assert.Contains(t, string(files["profile.json"]), "user@example.com")
+						assert.NotContains(t, string(files["profile.json"]), "secret-hash")
+						assert.NotContains(t, string(files["sessions.json"]), "refresh-hash")
+
+						var events []model.AuditEvent
+						assert.NoError(t, json.Unmarshal(files["audit_events.json"], &events))
+						assert.Equal(t, data.AuditEvents, events)
+
+						var manifest struct {
+							UserID uuid.UUID `json:"user_id"`
+							Files  []string  `json:"files"`
+						}
+						assert.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+						assert.Equal(t, userID, manifest.UserID)
+						assert.Len(t, manifest.Files, 9)
+						return nil
+					})
+			},
+			processed: true,
+		},
+		{
+			name: "2. Empty Queue",
+			mockBehavior: func(me *mocks.MockDataExportRepository, _ *mocks.MockPersonalDataRepository) {
+				me.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(nil, nil)
+			},
+		},
+		{
+			name: "3. Collect Failure Marks Export Failed",
+			mockBehavior: func(me *mocks.MockDataExportRepository, md *mocks.MockPersonalDataRepository) {
+				me.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(export, nil)
+				md.EXPECT().Collect(gomock.Any(), userID).Return(nil, errors.New("db down"))
+				me.EXPECT().Fail(gomock.Any(), export.ID, "db down", gomock.Any()).Return(nil)
+			},
+			processed: true,
+		},
+		{
+			name: "4. User Gone Marks Export Failed",
+			mockBehavior: func(me *mocks.MockDataExportRepository, md *mocks.MockPersonalDataRepository) {
+				me.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(export, nil)
+				md.EXPECT().Collect(gomock.Any(), userID).Return(nil, nil)
+				me.EXPECT().Fail(gomock.Any(), export.ID, ErrUserNotFound.Error(), gomock.Any()).Return(nil)
+			},
+			processed: true,
+		},
+		{
+			name: "5. Queue Error",
+			mockBehavior: func(me *mocks.MockDataExportRepository, _ *mocks.MockPersonalDataRepository) {
+				me.EXPECT().Claim(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			exports := mocks.NewMockDataExportRepository(ctrl)
+			personal := mocks.NewMockPersonalDataRepository(ctrl)
+			tt.mockBehavior(exports, personal)
+
+			processed, err := NewDataExporter(exports, personal, ttl).ProcessNext(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.processed, processed)
+		})
+	}
+}
+
+func TestDataExporter_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	exports := mocks.NewMockDataExportRepository(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Первый проход чистит истёкшие и разбирает очередь до конца
+	exports.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).Return(int64(0), nil)
+	exports.EXPECT().Claim(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, time.Time) (*model.DataExport, error) {
+			cancel()
+			return nil, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		NewDataExporter(exports, mocks.NewMockPersonalDataRepository(ctrl), time.Hour).Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
+
+func unzip(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	files := make(map[string][]byte, len(r.File))
+	for _, f := range r.File {
+		rc, err := f.Open()
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(rc)
+		rc.Close()
+		assert.NoError(t, err)
+		files[f.Name] = buf.Bytes()
+	}
+	return files
+}
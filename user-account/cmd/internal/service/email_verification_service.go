@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/google/uuid"
+)
+
+const emailVerificationPurpose = "email_verification"
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrEmailNotVerified         = errors.New("email is not verified")
+)
+
+// EmailVerificationService отправляет ссылки подтверждения почты и
+// принимает их. Ссылка подписана и одноразова: использование отмечается
+// в базе по ID токена.
+type EmailVerificationService struct {
+	users         repository.UserRepository
+	verifications repository.EmailVerificationRepository
+	tokens        *signedtoken.Signer
+	mailer        mailer.Mailer
+	ttl           time.Duration
+	publicURL     string
+}
+
+// NewEmailVerificationService создаёт сервис. publicURL - адрес фронтенда,
+// на странице /verify-email которого пользователь подтверждает почту.
+func NewEmailVerificationService(
+	users repository.UserRepository,
+	verifications repository.EmailVerificationRepository,
+	tokens *signedtoken.Signer,
+	mail mailer.Mailer,
+	ttl time.Duration,
+	publicURL string,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		users:         users,
+		verifications: verifications,
+		tokens:        tokens,
+		mailer:        mail,
+		ttl:           ttl,
+		publicURL:     strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Send выпускает новую ссылку и отправляет её на текущий адрес пользователя
+func (s *EmailVerificationService) Send(ctx context.Context, user *model.User) error {
+	verification := &model.EmailVerification{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.verifications.Create(ctx, verification); err != nil {
+		return err
+	}
+
+	token, err := s.tokens.Sign(emailVerificationPurpose, signedtoken.Claims{
+		ID:        verification.ID,
+		Subject:   user.ID.String(),
+		ExpiresAt: verification.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.publicURL + "/verify-email?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Подтверждение почты",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы подтвердить адрес, перейдите по ссылке:\n%s\n\nСсылка действует до %s.\nЕсли вы не регистрировались, просто проигнорируйте это письмо.\n",
+			user.Nickname, link, verification.ExpiresAt.UTC().Format("02.01.2006 15:04 MST"),
+		),
+	})
+}
+
+// Resend повторно отправляет ссылку. Для неизвестных и уже подтверждённых
+// адресов молча ничего не делает, чтобы по ответу нельзя было проверить,
+// зарегистрирован ли адрес.
+func (s *EmailVerificationService) Resend(ctx context.Context, email string) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	return s.Send(ctx, user)
+}
+
+// Verify принимает токен из ссылки и подтверждает почту
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) error {
+	claims, err := s.tokens.Verify(emailVerificationPurpose, token, time.Now())
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	verification, err := s.verifications.Consume(ctx, claims.ID)
+	if err != nil {
+		return err
+	}
+	if verification == nil || verification.UserID.String() != claims.Subject {
+		return ErrInvalidVerificationToken
+	}
+
+	verified, err := s.users.MarkEmailVerified(ctx, verification.UserID, verification.Email)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrInvalidVerificationToken
+	}
+	return nil
+}
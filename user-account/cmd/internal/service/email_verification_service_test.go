@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailVerificationService_Resend(t *testing.T) {
+	t.Parallel()
+
+	const email = "resend@example.com"
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer)
+		wantErr      bool
+	}{
+		{
+			name: "1. Unverified User Gets New Link",
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer) {
+				user := &model.User{ID: uuid.New(), Email: email, Nickname: "wanderer"}
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil)
+				mv.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, v *model.EmailVerification) error {
+						assert.Equal(t, user.ID, v.UserID)
+						assert.Equal(t, email, v.Email)
+						return nil
+					})
+				mm.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+						assert.Equal(t, email, msg.To)
+						assert.Contains(t, msg.Body, "https://app.example.com/verify-email?token=")
+						return nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name: "2. Unknown Email Is Silently Ignored",
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository, _ *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "3. Already Verified Is Silently Ignored",
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository, _ *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(&model.User{ID: uuid.New(), Email: email, EmailVerifiedAt: &verifiedAt}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "4. Mailer Error",
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(&model.User{ID: uuid.New(), Email: email}, nil)
+				mv.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp unavailable"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserRepository(ctrl)
+			verifications := mocks.NewMockEmailVerificationRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(users, verifications, mail)
+
+			svc := NewEmailVerificationService(users, verifications, signedtoken.New("secret"), mail, time.Hour, "https://app.example.com/")
+			err := svc.Resend(context.Background(), email)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestEmailVerificationService_Verify(t *testing.T) {
+	t.Parallel()
+
+	const email = "verify@example.com"
+	tokens := signedtoken.New("secret")
+	userID := uuid.New()
+	verificationID := uuid.New()
+
+	validToken, err := tokens.Sign(emailVerificationPurpose, signedtoken.Claims{
+		ID:        verificationID,
+		Subject:   userID.String(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	expiredToken, err := tokens.Sign(emailVerificationPurpose, signedtoken.Claims{
+		ID:        verificationID,
+		Subject:   userID.String(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	assert.NoError(t, err)
+
+	foreignToken, err := signedtoken.New("other-secret").Sign(emailVerificationPurpose, signedtoken.Claims{
+		ID:        verificationID,
+		Subject:   userID.String(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	verification := &model.EmailVerification{ID: verificationID, UserID: userID, Email: email}
+
+	tests := []struct {
+		name         string
+		token        string
+		mockBehavior func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository)
+		expectedErr  error
+	}{
+		{
+			name:  "1. Success",
+			token: validToken,
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository) {
+				mv.EXPECT().Consume(gomock.Any(), verificationID).Return(verification, nil)
+				m.EXPECT().MarkEmailVerified(gomock.Any(), userID, email).Return(true, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:         "2. Bad Signature",
+			token:        foreignToken,
+			mockBehavior: func(_ *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository) {},
+			expectedErr:  ErrInvalidVerificationToken,
+		},
+		{
+			name:         "3. Expired Link",
+			token:        expiredToken,
+			mockBehavior: func(_ *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository) {},
+			expectedErr:  ErrInvalidVerificationToken,
+		},
+		{
+			name:  "4. Link Already Used",
+			token: validToken,
+			mockBehavior: func(_ *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository) {
+				mv.EXPECT().Consume(gomock.Any(), verificationID).Return(nil, nil)
+			},
+			expectedErr: ErrInvalidVerificationToken,
+		},
+		{
+			name:  "5. Email Changed After Sending",
+			token: validToken,
+			mockBehavior: func(m *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository) {
+				mv.EXPECT().Consume(gomock.Any(), verificationID).Return(verification, nil)
+				m.EXPECT().MarkEmailVerified(gomock.Any(), userID, email).Return(false, nil)
+			},
+			expectedErr: ErrInvalidVerificationToken,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserRepository(ctrl)
+			verifications := mocks.NewMockEmailVerificationRepository(ctrl)
+			tt.mockBehavior(users, verifications)
+
+			svc := NewEmailVerificationService(users, verifications, tokens, mocks.NewMockMailer(ctrl), time.Hour, "https://app.example.com")
+			err := svc.Verify(context.Background(), tt.token)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/google/uuid"
+)
+
+const invitationPurpose = "organization_invitation"
+
+var (
+	ErrInvitationNotFound     = errors.New("invitation not found")
+	ErrInvalidInvitation      = errors.New("invalid or expired invitation")
+	ErrAccountDetailsRequired = errors.New("nickname and password are required to create an account")
+)
+
+// InvitationService приглашает в организацию по почте. Ссылка из письма
+// подписана и привязана к адресу, а её состояние (принята, отклонена,
+// отозвана) хранится в базе, поэтому она одноразова.
+type InvitationService struct {
+	invitations repository.InvitationRepository
+	orgs        repository.OrganizationRepository
+	users       repository.UserRepository
+	tokens      *signedtoken.Signer
+	mailer      mailer.Mailer
+	hasher      PasswordHasher
+	policy      PasswordPolicy
+	ttl         time.Duration
+	publicURL   string
+}
+
+// NewInvitationService создаёт сервис. publicURL - адрес фронтенда, на
+// странице /accept-invitation которого приглашение принимают или отклоняют.
+func NewInvitationService(
+	invitations repository.InvitationRepository,
+	orgs repository.OrganizationRepository,
+	users repository.UserRepository,
+	tokens *signedtoken.Signer,
+	mail mailer.Mailer,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	ttl time.Duration,
+	publicURL string,
+) *InvitationService {
+	return &InvitationService{
+		invitations: invitations,
+		orgs:        orgs,
+		users:       users,
+		tokens:      tokens,
+		mailer:      mail,
+		hasher:      hasher,
+		policy:      policy,
+		ttl:         ttl,
+		publicURL:   strings.TrimRight(publicURL, "/"),
+	}
+}
+
+// Create приглашает адрес в организацию с ролью role и отправляет письмо.
+// Приглашать могут владелец и администратор, в роли владельца - только
+// владелец. Повторное приглашение отзывает предыдущую ссылку.
+func (s *InvitationService) Create(ctx context.Context, orgID, actorID uuid.UUID, email, role string) (*model.Invitation, error) {
+	if !model.ValidOrgRole(role) {
+		return nil, ErrInvalidOrgRole
+	}
+
+	actorRole, err := s.managerRole(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if role == model.OrgRoleOwner && actorRole != model.OrgRoleOwner {
+		return nil, ErrOrganizationForbidden
+	}
+
+	existing, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		memberRole, err := s.orgs.MemberRole(ctx, orgID, existing.ID)
+		if err != nil {
+			return nil, err
+		}
+		if memberRole != "" {
+			return nil, repository.ErrAlreadyMember
+		}
+	}
+
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+
+	invitation := &model.Invitation{
+		ID:             uuid.New(),
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           role,
+		InvitedBy:      &actorID,
+		ExpiresAt:      time.Now().Add(s.ttl),
+	}
+	if err = s.invitations.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if err = s.send(ctx, org, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// List возвращает ожидающие приглашения организации
+func (s *InvitationService) List(ctx context.Context, orgID, actorID uuid.UUID) ([]model.Invitation, error) {
+	if _, err := s.managerRole(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+	return s.invitations.ListPending(ctx, orgID)
+}
+
+// Revoke отзывает ожидающее приглашение, ссылка из письма перестаёт работать
+func (s *InvitationService) Revoke(ctx context.Context, orgID, actorID, invitationID uuid.UUID) error {
+	if _, err := s.managerRole(ctx, orgID, actorID); err != nil {
+		return err
+	}
+
+	revoked, err := s.invitations.Revoke(ctx, orgID, invitationID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationNotFound
+	}
+	return nil
+}
+
+// Accept принимает приглашение по токену из письма. Если на приглашённый
+// адрес аккаунта нет, он создаётся с nickname и password, а почта сразу
+// считается подтверждённой: ссылка пришла на неё. Существующий аккаунт
+// просто добавляется в организацию.
+func (s *InvitationService) Accept(ctx context.Context, token, nickname, password string) (*model.InvitationAcceptance, error) {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.GetByEmail(ctx, invitation.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	created := false
+	if user == nil {
+		if user, err = s.createUser(ctx, invitation.Email, nickname, password); err != nil {
+			return nil, err
+		}
+		created = true
+	} else if user.EmailVerifiedAt == nil {
+		if _, err = s.users.MarkEmailVerified(ctx, user.ID, user.Email); err != nil {
+			return nil, err
+		}
+	}
+
+	accepted, err := s.invitations.Accept(ctx, invitation.ID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if accepted == nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	return &model.InvitationAcceptance{
+		OrganizationID: accepted.OrganizationID,
+		Role:           accepted.Role,
+		UserID:         user.ID,
+		AccountCreated: created,
+	}, nil
+}
+
+// Decline отклоняет приглашение по токену из письма
+func (s *InvitationService) Decline(ctx context.Context, token string) error {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	declined, err := s.invitations.Decline(ctx, invitation.ID)
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return ErrInvalidInvitation
+	}
+	return nil
+}
+
+// pending проверяет токен и возвращает приглашение, которое ещё ждёт ответа
+func (s *InvitationService) pending(ctx context.Context, token string) (*model.Invitation, error) {
+	claims, err := s.tokens.Verify(invitationPurpose, token, time.Now())
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	invitation, err := s.invitations.GetByID(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil || invitation.Email != claims.Subject || !invitation.Pending(time.Now()) {
+		return nil, ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+func (s *InvitationService) createUser(ctx context.Context, email, nickname, password string) (*model.User, error) {
+	if nickname == "" || password == "" {
+		return nil, ErrAccountDetailsRequired
+	}
+	if err := s.policy.Check(ctx, password, email, nickname); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+	user := &model.User{
+		ID:              uuid.New(),
+		Email:           email,
+		Nickname:        nickname,
+		PasswordHash:    hash,
+		Roles:           []string{model.RoleUser},
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err = s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *InvitationService) send(ctx context.Context, org *model.Organization, invitation *model.Invitation) error {
+	token, err := s.tokens.Sign(invitationPurpose, signedtoken.Claims{
+		ID:        invitation.ID,
+		Subject:   invitation.Email,
+		ExpiresAt: invitation.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.publicURL + "/accept-invitation?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      invitation.Email,
+		Subject: "Приглашение в организацию " + org.Name,
+		Body: fmt.Sprintf(
+			"Здравствуйте!\n\nВас пригласили в организацию «%s» с ролью %s.\nЧтобы принять или отклонить приглашение, перейдите по ссылке:\n%s\n\nСсылка действует до %s.\nЕсли вы не ждали приглашения, просто проигнорируйте это письмо.\n",
+			org.Name, invitation.Role, link, invitation.ExpiresAt.UTC().Format("02.01.2006 15:04 MST"),
+		),
+	})
+}
+
+// managerRole возвращает роль вызывающего, если она позволяет приглашать
+func (s *InvitationService) managerRole(ctx context.Context, orgID, actorID uuid.UUID) (string, error) {
+	role, err := s.orgs.MemberRole(ctx, orgID, actorID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrOrganizationNotFound
+	}
+	if !model.CanManageOrganization(role) {
+		return "", ErrOrganizationForbidden
+	}
+	return role, nil
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvitationService_Create(t *testing.T) {
+	t.Parallel()
+
+	const email = "colleague@example.com"
+	orgID := uuid.New()
+	actorID := uuid.New()
+	org := &model.Organization{ID: orgID, Name: "Acme Legal"}
+
+	tests := []struct {
+		name         string
+		role         string
+		mockBehavior func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository, mm *mocks.MockMailer)
+		wantErr      error
+	}{
+		{
+			name: "1. Admin Invites Newcomer",
+			role: model.OrgRoleAdmin,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository, mm *mocks.MockMailer) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleAdmin, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+				mo.EXPECT().GetByID(gomock.Any(), orgID).Return(org, nil)
+				mi.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, inv *model.Invitation) error {
+						assert.Equal(t, orgID, inv.OrganizationID)
+						assert.Equal(t, model.OrgRoleAdmin, inv.Role)
+						assert.Equal(t, &actorID, inv.InvitedBy)
+						return nil
+					})
+				mm.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, msg mailer.Message) error {
+						assert.Equal(t, email, msg.To)
+						assert.Contains(t, msg.Body, "«Acme Legal»")
+						assert.Contains(t, msg.Body, "https://app.example.com/accept-invitation?token=")
+						return nil
+					})
+			},
+		},
+		{
+			name: "2. Admin Cannot Invite Owner",
+			role: model.OrgRoleOwner,
+			mockBehavior: func(_ *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, _ *mocks.MockUserRepository, _ *mocks.MockMailer) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleAdmin, nil)
+			},
+			wantErr: ErrOrganizationForbidden,
+		},
+		{
+			name: "3. Member Cannot Invite",
+			role: model.OrgRoleMember,
+			mockBehavior: func(_ *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, _ *mocks.MockUserRepository, _ *mocks.MockMailer) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleMember, nil)
+			},
+			wantErr: ErrOrganizationForbidden,
+		},
+		{
+			name: "4. Already A Member",
+			role: model.OrgRoleMember,
+			mockBehavior: func(_ *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository, _ *mocks.MockMailer) {
+				existing := &model.User{ID: uuid.New(), Email: email}
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleOwner, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(existing, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, existing.ID).Return(model.OrgRoleMember, nil)
+			},
+			wantErr: repository.ErrAlreadyMember,
+		},
+		{
+			name: "5. Mailer Error",
+			role: model.OrgRoleMember,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository, mm *mocks.MockMailer) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleOwner, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+				mo.EXPECT().GetByID(gomock.Any(), orgID).Return(org, nil)
+				mi.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp unavailable"))
+			},
+			wantErr: errors.New("smtp unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			invitations := mocks.NewMockInvitationRepository(ctrl)
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(invitations, orgs, users, mail)
+
+			svc := NewInvitationService(invitations, orgs, users, signedtoken.New("secret"), mail, testHasher(), testPolicy(), time.Hour, "https://app.example.com/")
+			invitation, err := svc.Create(context.Background(), orgID, actorID, email, tt.role)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if !errors.Is(err, tt.wantErr) {
+					assert.Equal(t, tt.wantErr.Error(), err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, email, invitation.Email)
+		})
+	}
+}
+
+func TestInvitationService_Accept(t *testing.T) {
+	t.Parallel()
+
+	const email = "invitee@example.com"
+	tokens := signedtoken.New("secret")
+	orgID := uuid.New()
+	invitationID := uuid.New()
+
+	sign := func(signer *signedtoken.Signer, subject string, expiresAt time.Time) string {
+		token, err := signer.Sign(invitationPurpose, signedtoken.Claims{ID: invitationID, Subject: subject, ExpiresAt: expiresAt})
+		assert.NoError(t, err)
+		return token
+	}
+	validToken := sign(tokens, email, time.Now().Add(time.Hour))
+	foreignToken := sign(signedtoken.New("other-secret"), email, time.Now().Add(time.Hour))
+
+	pending := &model.Invitation{
+		ID:             invitationID,
+		OrganizationID: orgID,
+		Email:          email,
+		Role:           model.OrgRoleAdmin,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+	acceptedAt := time.Now()
+	accepted := *pending
+	accepted.AcceptedAt = &acceptedAt
+
+	tests := []struct {
+		name         string
+		token        string
+		nickname     string
+		password     string
+		mockBehavior func(mi *mocks.MockInvitationRepository, mu *mocks.MockUserRepository)
+		wantCreated  bool
+		wantErr      error
+	}{
+		{
+			name:     "1. New Account Is Created",
+			token:    validToken,
+			nickname: "invitee",
+			password: "long-password",
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mu *mocks.MockUserRepository) {
+				mi.EXPECT().GetByID(gomock.Any(), invitationID).Return(pending, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+				mu.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *model.User) error {
+						assert.Equal(t, email, u.Email)
+						assert.NotNil(t, u.EmailVerifiedAt)
+						assertPasswordHash(t, u.PasswordHash, "long-password")
+						return nil
+					})
+				mi.EXPECT().Accept(gomock.Any(), invitationID, gomock.Any()).Return(&accepted, nil)
+			},
+			wantCreated: true,
+		},
+		{
+			name:  "2. Existing Account Is Linked",
+			token: validToken,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mu *mocks.MockUserRepository) {
+				user := &model.User{ID: uuid.New(), Email: email}
+				mi.EXPECT().GetByID(gomock.Any(), invitationID).Return(pending, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil)
+				mu.EXPECT().MarkEmailVerified(gomock.Any(), user.ID, email).Return(true, nil)
+				mi.EXPECT().Accept(gomock.Any(), invitationID, user.ID).Return(&accepted, nil)
+			},
+		},
+		{
+			name:  "3. New Account Without Details",
+			token: validToken,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mu *mocks.MockUserRepository) {
+				mi.EXPECT().GetByID(gomock.Any(), invitationID).Return(pending, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+			},
+			wantErr: ErrAccountDetailsRequired,
+		},
+		{
+			name:         "4. Bad Signature",
+			token:        foreignToken,
+			mockBehavior: func(_ *mocks.MockInvitationRepository, _ *mocks.MockUserRepository) {},
+			wantErr:      ErrInvalidInvitation,
+		},
+		{
+			name:  "5. Already Answered",
+			token: validToken,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, _ *mocks.MockUserRepository) {
+				mi.EXPECT().GetByID(gomock.Any(), invitationID).Return(&accepted, nil)
+			},
+			wantErr: ErrInvalidInvitation,
+		},
+		{
+			name:  "6. Accepted Concurrently",
+			token: validToken,
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mu *mocks.MockUserRepository) {
+				verifiedAt := time.Now()
+				user := &model.User{ID: uuid.New(), Email: email, EmailVerifiedAt: &verifiedAt}
+				mi.EXPECT().GetByID(gomock.Any(), invitationID).Return(pending, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil)
+				mi.EXPECT().Accept(gomock.Any(), invitationID, user.ID).Return(nil, nil)
+			},
+			wantErr: ErrInvalidInvitation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			invitations := mocks.NewMockInvitationRepository(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(invitations, users)
+
+			svc := NewInvitationService(invitations, mocks.NewMockOrganizationRepository(ctrl), users, tokens, mocks.NewMockMailer(ctrl), testHasher(), testPolicy(), time.Hour, "https://app.example.com")
+			acceptance, err := svc.Accept(context.Background(), tt.token, tt.nickname, tt.password)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, orgID, acceptance.OrganizationID)
+			assert.Equal(t, model.OrgRoleAdmin, acceptance.Role)
+			assert.Equal(t, tt.wantCreated, acceptance.AccountCreated)
+		})
+	}
+}
+
+func TestInvitationService_Revoke(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	actorID := uuid.New()
+	invitationID := uuid.New()
+
+	tests := []struct {
+		name         string
+		mockBehavior func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository)
+		wantErr      error
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleAdmin, nil)
+				mi.EXPECT().Revoke(gomock.Any(), orgID, invitationID).Return(true, nil)
+			},
+		},
+		{
+			name: "2. Not Pending",
+			mockBehavior: func(mi *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleOwner, nil)
+				mi.EXPECT().Revoke(gomock.Any(), orgID, invitationID).Return(false, nil)
+			},
+			wantErr: ErrInvitationNotFound,
+		},
+		{
+			name: "3. Outsider Does Not See Organization",
+			mockBehavior: func(_ *mocks.MockInvitationRepository, mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return("", nil)
+			},
+			wantErr: ErrOrganizationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			invitations := mocks.NewMockInvitationRepository(ctrl)
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			tt.mockBehavior(invitations, orgs)
+
+			svc := NewInvitationService(invitations, orgs, mocks.NewMockUserRepository(ctrl), signedtoken.New("secret"), mocks.NewMockMailer(ctrl), testHasher(), testPolicy(), time.Hour, "https://app.example.com")
+			err := svc.Revoke(context.Background(), orgID, actorID, invitationID)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
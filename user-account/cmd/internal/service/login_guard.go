@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+// LoginLockedError - вход временно заблокирован. errors.Is(err, ErrLoginLocked)
+// срабатывает и для неё.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLoginLocked, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginLockedError) Is(target error) bool {
+	return target == ErrLoginLocked
+}
+
+// LockoutConfig - настройки защиты входа от перебора
+type LockoutConfig struct {
+	// AccountThreshold - после скольких неудач подряд блокируется аккаунт
+	AccountThreshold int
+	// IPThreshold - после скольких неудач блокируется IP-адрес. Порог выше,
+	// чем для аккаунта: за одним адресом может быть целая сеть.
+	IPThreshold int
+	// BaseLockout - блокировка за первую неудачу сверх порога, каждая
+	// следующая удваивает срок
+	BaseLockout time.Duration
+	// MaxLockout - потолок срока блокировки
+	MaxLockout time.Duration
+	// FailureWindow - через сколько после последней неудачи счёт обнуляется
+	FailureWindow time.Duration
+}
+
+// LoginGuard считает неудачные входы по аккаунту и по IP-адресу и временно
+// блокирует вход, когда неудач становится слишком много
+type LoginGuard struct {
+	attempts repository.LoginAttemptRepository
+	cfg      LockoutConfig
+}
+
+func NewLoginGuard(attempts repository.LoginAttemptRepository, cfg LockoutConfig) *LoginGuard {
+	return &LoginGuard{attempts: attempts, cfg: cfg}
+}
+
+// Check возвращает *LoginLockedError, если заблокирован аккаунт или адрес.
+// Вызывается до проверки пароля, чтобы во время блокировки пароль не
+// проверялся вовсе.
+func (g *LoginGuard) Check(ctx context.Context, account, ip string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+
+	for _, key := range g.keys(account, ip) {
+		attempt, err := g.attempts.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil || attempt.LockedUntil == nil {
+			continue
+		}
+		if wait := attempt.LockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail засчитывает неудачную попытку и при превышении порога блокирует ключ
+func (g *LoginGuard) Fail(ctx context.Context, account, ip string) error {
+	now := time.Now()
+
+	if err := g.fail(ctx, accountKey(account), g.cfg.AccountThreshold, now); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return g.fail(ctx, ipKey(ip), g.cfg.IPThreshold, now)
+}
+
+// Succeed обнуляет счётчик аккаунта. Счётчик адреса не трогается: иначе
+// перебор по многим аккаунтам сбрасывался бы входом в свой собственный.
+func (g *LoginGuard) Succeed(ctx context.Context, account string) error {
+	return g.attempts.Reset(ctx, accountKey(account))
+}
+
+// Unlock снимает блокировку аккаунта вручную
+func (g *LoginGuard) Unlock(ctx context.Context, account string) error {
+	return g.attempts.Reset(ctx, accountKey(account))
+}
+
+func (g *LoginGuard) fail(ctx context.Context, key string, threshold int, now time.Time) error {
+	failures, err := g.attempts.RecordFailure(ctx, key, now, g.cfg.FailureWindow)
+	if err != nil {
+		return err
+	}
+	if threshold <= 0 || failures < threshold {
+		return nil
+	}
+	return g.attempts.Lock(ctx, key, now.Add(g.lockoutFor(failures-threshold)))
+}
+
+// Purge удаляет счётчики, которые уже не влияют на вход, и возвращает,
+// сколько их было. Без неё каждая неудача со случайным адресом или IP
+// оставляла бы запись навсегда.
+func (g *LoginGuard) Purge(ctx context.Context) (int64, error) {
+	now := time.Now()
+	return g.attempts.DeleteStale(ctx, now.Add(-g.cfg.FailureWindow), now)
+}
+
+// Run очищает счётчики сразу и затем раз в interval, пока не отменён ctx.
+// Ошибки только пишутся в лог, следующий проход повторит попытку.
+func (g *LoginGuard) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := g.Purge(ctx)
+		switch {
+		case err != nil:
+			log.Printf("stale login attempts purge failed: %v", err)
+		case purged > 0:
+			log.Printf("purged %d stale login attempts", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lockoutFor - BaseLockout * 2^excess, но не больше MaxLockout
+func (g *LoginGuard) lockoutFor(excess int) time.Duration {
+	lockout := g.cfg.BaseLockout
+	for i := 0; i < excess && lockout < g.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+	if g.cfg.MaxLockout > 0 && lockout > g.cfg.MaxLockout {
+		lockout = g.cfg.MaxLockout
+	}
+	return lockout
+}
+
+func (g *LoginGuard) keys(account, ip string) []string {
+	keys := []string{accountKey(account)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// twoFactorAccount - ключ аккаунта для второго шага входа. Коды перебираются
+// отдельно от паролей, поэтому и считаются отдельно.
+func twoFactorAccount(userID uuid.UUID) string {
+	return "2fa:" + userID.String()
+}
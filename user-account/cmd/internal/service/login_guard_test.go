@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLoginGuard() *LoginGuard {
+	return NewLoginGuard(repository.NewMemoryLoginAttemptRepository(), LockoutConfig{
+		AccountThreshold: 3,
+		IPThreshold:      10,
+		BaseLockout:      time.Minute,
+		MaxLockout:       time.Hour,
+		FailureWindow:    time.Hour,
+	})
+}
+
+func TestLoginGuard_LocksAccountAfterThreshold(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	guard := testLoginGuard()
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, guard.Fail(ctx, "Victim@Example.com", "10.0.0.1"))
+		assert.NoError(t, guard.Check(ctx, "victim@example.com", "10.0.0.1"))
+	}
+
+	assert.NoError(t, guard.Fail(ctx, "victim@example.com", "10.0.0.2"))
+
+	err := guard.Check(ctx, "VICTIM@example.com", "10.0.0.3")
+	var locked *LoginLockedError
+	if assert.ErrorAs(t, err, &locked) {
+		assert.InDelta(t, time.Minute.Seconds(), locked.RetryAfter.Seconds(), 1)
+	}
+	assert.ErrorIs(t, err, ErrLoginLocked)
+	assert.NoError(t, guard.Check(ctx, "other@example.com", "10.0.0.3"), "other accounts are not affected")
+
+	assert.NoError(t, guard.Unlock(ctx, "victim@example.com"))
+	assert.NoError(t, guard.Check(ctx, "victim@example.com", "10.0.0.3"))
+}
+
+func TestLoginGuard_LocksIPAcrossAccounts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	guard := testLoginGuard()
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, guard.Fail(ctx, uuid.NewString()+"@example.com", "10.0.0.9"))
+	}
+	// Успешный вход в свой аккаунт не сбрасывает счётчик адреса
+	assert.NoError(t, guard.Succeed(ctx, "attacker@example.com"))
+
+	assert.ErrorIs(t, guard.Check(ctx, "fresh@example.com", "10.0.0.9"), ErrLoginLocked)
+	assert.NoError(t, guard.Check(ctx, "fresh@example.com", "10.0.0.10"))
+}
+
+func TestLoginGuard_Purge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Now()
+
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	_, _ = attempts.RecordFailure(ctx, "ip:stale", now.Add(-2*time.Hour), time.Hour)
+	_, _ = attempts.RecordFailure(ctx, "ip:recent", now, time.Hour)
+	guard := NewLoginGuard(attempts, LockoutConfig{FailureWindow: time.Hour})
+
+	purged, err := guard.Purge(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	stale, _ := attempts.Get(ctx, "ip:stale")
+	recent, _ := attempts.Get(ctx, "ip:recent")
+	assert.Nil(t, stale)
+	assert.NotNil(t, recent)
+}
+
+func TestLoginGuard_LockoutFor(t *testing.T) {
+	t.Parallel()
+
+	guard := NewLoginGuard(nil, LockoutConfig{BaseLockout: time.Minute, MaxLockout: 10 * time.Minute})
+
+	tests := []struct {
+		excess   int
+		expected time.Duration
+	}{
+		{excess: 0, expected: time.Minute},
+		{excess: 1, expected: 2 * time.Minute},
+		{excess: 3, expected: 8 * time.Minute},
+		{excess: 4, expected: 10 * time.Minute},
+		{excess: 1000, expected: 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, guard.lockoutFor(tt.excess), "excess %d", tt.excess)
+	}
+}
+
+func TestAuthService_Login_Lockout(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	hash, _ := testHasher().Hash("correct-pass")
+	user := &model.User{ID: uuid.New(), Email: "locked@example.com", PasswordHash: hash}
+
+	repo := mocks.NewMockUserRepository(ctrl)
+	// Третья попытка не доходит до базы: аккаунт уже заблокирован
+	repo.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(user, nil).Times(3)
+
+	svc := NewAuthService(repo, nil, nil, nil, nil, nil, NewLoginGuard(repository.NewMemoryLoginAttemptRepository(), LockoutConfig{
+		AccountThreshold: 3,
+		IPThreshold:      100,
+		BaseLockout:      time.Minute,
+		MaxLockout:       time.Hour,
+		FailureWindow:    time.Hour,
+	}), testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	client := model.ClientInfo{IPAddress: "10.0.0.1"}
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.Login(context.Background(), user.Email, "wrong-pass", client)
+		assert.EqualError(t, err, "invalid credentials")
+	}
+
+	_, err := svc.Login(context.Background(), user.Email, "correct-pass", client)
+	var locked *LoginLockedError
+	assert.True(t, errors.As(err, &locked), "correct password must not bypass the lockout")
+}
+
+func TestAuthService_LoginTwoFactor_Lockout(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	confirmedAt := time.Now()
+	totpRepo := mocks.NewMockTOTPRepository(ctrl)
+	totpRepo.EXPECT().Get(gomock.Any(), userID).Return(&model.TOTP{UserID: userID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}, nil).Times(3)
+
+	twoFactor := NewTwoFactorService(totpRepo, nil, signedtoken.New("action-secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+	svc := NewAuthService(nil, nil, nil, nil, nil, twoFactor, testLoginGuard(), testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{})
+	mfaToken, err := twoFactor.IssueChallenge(userID)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = svc.LoginTwoFactor(context.Background(), mfaToken, "000000", model.ClientInfo{})
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	}
+
+	_, err = svc.LoginTwoFactor(context.Background(), mfaToken, currentTOTPCode(t), model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrLoginLocked)
+}
+
+func TestUserService_Unlock(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	user := &model.User{ID: uuid.New(), Email: "unlock@example.com"}
+	repo := mocks.NewMockUserRepository(ctrl)
+	repo.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	guard := testLoginGuard()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, guard.Fail(ctx, user.Email, ""))
+		assert.NoError(t, guard.Fail(ctx, twoFactorAccount(user.ID), ""))
+	}
+
+	svc := NewUserService(repo, nil, guard, testHasher(), testPolicy(), nil)
+	assert.NoError(t, svc.Unlock(ctx, user.ID))
+	assert.NoError(t, guard.Check(ctx, user.Email, ""))
+	assert.NoError(t, guard.Check(ctx, twoFactorAccount(user.ID), ""))
+}
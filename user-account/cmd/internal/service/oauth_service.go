@@ -0,0 +1,638 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// oauthClientSecretPrefix отличает секреты клиентов от других токенов, в
+// том числе в сканерах утечек секретов
+const oauthClientSecretPrefix = "cs_"
+
+// Коды ошибок OAuth2 (RFC 6749, 4.1.2.1 и 5.2)
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthAccessDenied            = "access_denied"
+)
+
+var (
+	ErrOAuthClientNotFound   = errors.New("oauth client not found")
+	ErrInvalidClientMetadata = errors.New("invalid client metadata")
+)
+
+// OAuthError - ошибка протокола OAuth2. Code и Description уходят клиенту
+// как есть.
+type OAuthError struct {
+	Code        string
+	Description string
+	// redirectURI - адрес, на который ошибку можно вернуть клиенту. Пусто,
+	// пока клиент и адрес возврата не подтверждены (RFC 6749, 4.1.2.1).
+	redirectURI string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// TokenKeys подписывает токены сервера авторизации и проверяет их при
+// интроспекции
+type TokenKeys interface {
+	TokenSigner
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	Algorithm() string
+}
+
+// OAuthConfig - настройки сервера авторизации
+type OAuthConfig struct {
+	// Issuer - публичный адрес этого сервиса, iss выданных токенов
+	Issuer string
+	// AuthorizationURL - страница согласия на фронтенде: пользователь
+	// входит и подтверждает доступ, а страница вызывает POST /oauth/authorize
+	AuthorizationURL string
+	AccessTTL        time.Duration
+	CodeTTL          time.Duration
+}
+
+// OAuthService - сервер авторизации OAuth2 для соседних сервисов: выдаёт
+// токены клиентам по client_credentials и пользователям по коду
+// авторизации с их согласия, отвечает на интроспекцию (RFC 7662).
+//
+// Токены подписываются теми же ключами, что и access-токены пользователей,
+// но без sid: API этого сервиса их не принимает, они для соседних сервисов.
+type OAuthService struct {
+	clients     repository.OAuthClientRepository
+	codes       repository.OAuthCodeRepository
+	users       repository.UserRepository
+	revocations *RevocationService
+	statuses    *UserStatusService
+	keys        TokenKeys
+	cfg         OAuthConfig
+}
+
+func NewOAuthService(
+	clients repository.OAuthClientRepository,
+	codes repository.OAuthCodeRepository,
+	users repository.UserRepository,
+	revocations *RevocationService,
+	statuses *UserStatusService,
+	keys TokenKeys,
+	cfg OAuthConfig,
+) *OAuthService {
+	return &OAuthService{
+		clients:     clients,
+		codes:       codes,
+		users:       users,
+		revocations: revocations,
+		statuses:    statuses,
+		keys:        keys,
+		cfg:         cfg,
+	}
+}
+
+// RegisterClient регистрирует клиента и возвращает его вместе с секретом.
+// Секрет показывается один раз; у публичного клиента его нет.
+func (s *OAuthService) RegisterClient(ctx context.Context, createdBy uuid.UUID, reg model.OAuthClientRegistration) (*model.OAuthClient, string, error) {
+	client := &model.OAuthClient{
+		ID:           uuid.New(),
+		Name:         reg.Name,
+		RedirectURIs: uniqueStrings(reg.RedirectURIs),
+		GrantTypes:   uniqueStrings(reg.GrantTypes),
+		Scopes:       uniqueStrings(reg.Scopes),
+		CreatedBy:    &createdBy,
+	}
+	if err := validateClient(client, reg.Public); err != nil {
+		return nil, "", err
+	}
+
+	var secret string
+	if !reg.Public {
+		token, err := newOpaqueToken()
+		if err != nil {
+			return nil, "", err
+		}
+		secret = oauthClientSecretPrefix + token
+		client.SecretHash = hashToken(secret)
+	}
+
+	if err := s.clients.Create(ctx, client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+// ListClients возвращает всех клиентов, включая отозванных
+func (s *OAuthService) ListClients(ctx context.Context) ([]model.OAuthClient, error) {
+	return s.clients.List(ctx)
+}
+
+// RevokeClient отзывает клиента: его токены перестают проходить
+// интроспекцию, новые он получить не может
+func (s *OAuthService) RevokeClient(ctx context.Context, id uuid.UUID) error {
+	revoked, err := s.clients.Revoke(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+// PreviewAuthorization проверяет запрос кода авторизации и возвращает то,
+// что нужно показать пользователю на странице согласия
+func (s *OAuthService) PreviewAuthorization(ctx context.Context, req model.AuthorizationRequest) (*model.AuthorizationPreview, error) {
+	auth, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuthorizationPreview{
+		ClientID:    auth.client.ID,
+		ClientName:  auth.client.Name,
+		RedirectURI: auth.redirectURI,
+		Scopes:      auth.scopes,
+	}, nil
+}
+
+// Authorize выполняет решение пользователя и возвращает адрес клиента, на
+// который его нужно отправить: с кодом авторизации или с ошибкой. Ошибка
+// без адреса означает, что клиент или адрес возврата не подтверждены и
+// отправлять пользователя некуда.
+func (s *OAuthService) Authorize(ctx context.Context, userID uuid.UUID, req model.AuthorizationRequest, approved bool) (string, error) {
+	auth, err := s.checkAuthorization(ctx, req)
+	if err != nil {
+		var oauthErr *OAuthError
+		if errors.As(err, &oauthErr) && oauthErr.redirectURI != "" {
+			return s.errorRedirect(oauthErr, req.State)
+		}
+		return "", err
+	}
+	if !approved {
+		return s.errorRedirect(&OAuthError{
+			Code:        OAuthAccessDenied,
+			Description: "the user denied the request",
+			redirectURI: auth.redirectURI,
+		}, req.State)
+	}
+
+	code, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	// Необменянные коды убираются заодно с выдачей новых
+	if err = s.codes.DeleteExpired(ctx); err != nil {
+		log.Printf("failed to delete expired oauth authorization codes: %v", err)
+	}
+	if err = s.codes.Create(ctx, &model.OAuthAuthorizationCode{
+		CodeHash:      hashToken(code),
+		ClientID:      auth.client.ID,
+		UserID:        userID,
+		RedirectURI:   auth.redirectURI,
+		Scopes:        auth.scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		ExpiresAt:     time.Now().Add(s.cfg.CodeTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	return s.redirect(auth.redirectURI, req.State, url.Values{"code": {code}})
+}
+
+// Token выдаёт токены по запросу к /oauth/token
+func (s *OAuthService) Token(ctx context.Context, req model.TokenRequest) (*model.OAuthTokens, error) {
+	client, err := s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case model.GrantClientCredentials:
+		return s.clientCredentials(client, req)
+	case model.GrantAuthorizationCode:
+		return s.exchangeCode(ctx, client, req)
+	default:
+		return nil, &OAuthError{Code: OAuthUnsupportedGrantType, Description: "grant_type must be authorization_code or client_credentials"}
+	}
+}
+
+// Introspect отвечает, действует ли токен (RFC 7662). Спрашивать может
+// только конфиденциальный клиент. Понимает и токены этого сервера, и
+// access-токены пользователей: отозванные, выданные отозванным клиентам и
+// выданные пользователям, которым доступ закрыт, считаются недействительными.
+func (s *OAuthService) Introspect(ctx context.Context, clientID, clientSecret, token string) (*model.TokenIntrospection, error) {
+	caller, err := s.authenticateClient(ctx, clientID, clientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if caller.Public() {
+		return nil, &OAuthError{Code: OAuthInvalidClient, Description: "public clients cannot introspect tokens"}
+	}
+
+	inactive := &model.TokenIntrospection{Active: false}
+	parsed, err := jwt.Parse(token, s.keys.Keyfunc, jwt.WithExpirationRequired())
+	if err != nil || !parsed.Valid {
+		return inactive, nil
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok {
+		return inactive, nil
+	}
+
+	subject, _ := claims["sub"].(string)
+	tokenID, _ := claims["jti"].(string)
+	issuer, _ := claims["iss"].(string)
+	result := &model.TokenIntrospection{
+		Active:    true,
+		TokenType: "Bearer",
+		Subject:   subject,
+		TokenID:   tokenID,
+		Issuer:    issuer,
+	}
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		result.ExpiresAt = exp.Unix()
+	}
+	if iat, _ := claims.GetIssuedAt(); iat != nil {
+		result.IssuedAt = iat.Unix()
+	}
+
+	if owner, _ := claims["client_id"].(string); owner != "" {
+		if issuer != s.cfg.Issuer {
+			return inactive, nil
+		}
+		client, err := s.activeClient(ctx, owner)
+		if err != nil {
+			return nil, err
+		}
+		if client == nil {
+			return inactive, nil
+		}
+		// У токенов client_credentials субъект - сам клиент, у остальных -
+		// пользователь
+		if subject != owner {
+			blocked, err := s.subjectBlocked(ctx, subject)
+			if err != nil {
+				return nil, err
+			}
+			if blocked {
+				return inactive, nil
+			}
+		}
+		result.ClientID = owner
+		result.Scope, _ = claims["scope"].(string)
+		return result, nil
+	}
+
+	// Остальное - access-токены пользователей; у ID-токенов нет ни
+	// client_id, ни sid, и доступа они не дают
+	sidRaw, _ := claims["sid"].(string)
+	sessionID, err := uuid.Parse(sidRaw)
+	if err != nil || tokenID == "" {
+		return inactive, nil
+	}
+	revoked, err := s.revocations.IsRevoked(ctx, tokenID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return inactive, nil
+	}
+	blocked, err := s.subjectBlocked(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return inactive, nil
+	}
+	result.Username, _ = claims["nickname"].(string)
+	return result, nil
+}
+
+// Metadata - документ discovery (OpenID Connect Discovery 1.0)
+func (s *OAuthService) Metadata() model.OAuthServerMetadata {
+	base := strings.TrimSuffix(s.cfg.Issuer, "/")
+	return model.OAuthServerMetadata{
+		Issuer:                            s.cfg.Issuer,
+		AuthorizationEndpoint:             s.cfg.AuthorizationURL,
+		TokenEndpoint:                     base + "/oauth/token",
+		IntrospectionEndpoint:             base + "/oauth/introspect",
+		JWKSURI:                           base + "/.well-known/jwks.json",
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{model.GrantAuthorizationCode, model.GrantClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{s.keys.Algorithm()},
+		ScopesSupported:                   []string{model.ScopeOpenID, model.ScopeProfile, model.ScopeEmail},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "email", "email_verified", "preferred_username"},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	}
+}
+
+// authorization - проверенный запрос кода авторизации
+type authorization struct {
+	client      *model.OAuthClient
+	redirectURI string
+	scopes      []string
+}
+
+func (s *OAuthService) checkAuthorization(ctx context.Context, req model.AuthorizationRequest) (*authorization, error) {
+	client, err := s.activeClient(ctx, req.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, &OAuthError{Code: OAuthInvalidRequest, Description: "unknown client_id"}
+	}
+
+	// Без redirect_uri подставляется единственный зарегистрированный адрес
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if !client.AllowsRedirect(redirectURI) {
+		return nil, &OAuthError{Code: OAuthInvalidRequest, Description: "redirect_uri is not registered for the client"}
+	}
+
+	fail := func(code, description string) error {
+		return &OAuthError{Code: code, Description: description, redirectURI: redirectURI}
+	}
+	if req.ResponseType != "code" {
+		return nil, fail(OAuthUnsupportedResponseType, "response_type must be code")
+	}
+	if !client.AllowsGrant(model.GrantAuthorizationCode) {
+		return nil, fail(OAuthUnauthorizedClient, "the client may not use authorization_code")
+	}
+	scopes, ok := requestedScopes(client, req.Scope)
+	if !ok {
+		return nil, fail(OAuthInvalidScope, "the requested scope is not allowed for the client")
+	}
+	if req.CodeChallenge == "" {
+		// Перехваченный код публичного клиента без PKCE обменяет кто угодно
+		if client.Public() {
+			return nil, fail(OAuthInvalidRequest, "code_challenge is required for public clients")
+		}
+	} else if req.CodeChallengeMethod != "S256" {
+		return nil, fail(OAuthInvalidRequest, "code_challenge_method must be S256")
+	}
+
+	return &authorization{client: client, redirectURI: redirectURI, scopes: scopes}, nil
+}
+
+// authenticateClient проверяет client_id и секрет. Публичный клиент
+// предъявляет только client_id.
+func (s *OAuthService) authenticateClient(ctx context.Context, clientID, secret string) (*model.OAuthClient, error) {
+	client, err := s.activeClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	failed := &OAuthError{Code: OAuthInvalidClient, Description: "client authentication failed"}
+	if client == nil {
+		return nil, failed
+	}
+	if client.Public() {
+		if secret != "" {
+			return nil, failed
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return nil, failed
+	}
+	return client, nil
+}
+
+func (s *OAuthService) clientCredentials(client *model.OAuthClient, req model.TokenRequest) (*model.OAuthTokens, error) {
+	if client.Public() || !client.AllowsGrant(model.GrantClientCredentials) {
+		return nil, &OAuthError{Code: OAuthUnauthorizedClient, Description: "the client may not use client_credentials"}
+	}
+	scopes, ok := requestedScopes(client, req.Scope)
+	if !ok {
+		return nil, &OAuthError{Code: OAuthInvalidScope, Description: "the requested scope is not allowed for the client"}
+	}
+	// Scopes OpenID Connect описывают пользователя, а его здесь нет
+	scopes = withoutIdentityScopes(scopes)
+
+	accessToken, err := s.signAccessToken(client.ID.String(), client, scopes, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &model.OAuthTokens{AccessToken: accessToken, ExpiresIn: s.cfg.AccessTTL, Scopes: scopes}, nil
+}
+
+func (s *OAuthService) exchangeCode(ctx context.Context, client *model.OAuthClient, req model.TokenRequest) (*model.OAuthTokens, error) {
+	if !client.AllowsGrant(model.GrantAuthorizationCode) {
+		return nil, &OAuthError{Code: OAuthUnauthorizedClient, Description: "the client may not use authorization_code"}
+	}
+	if req.Code == "" {
+		return nil, &OAuthError{Code: OAuthInvalidRequest, Description: "code is required"}
+	}
+
+	invalid := &OAuthError{Code: OAuthInvalidGrant, Description: "authorization code is invalid or expired"}
+	code, err := s.codes.Consume(ctx, hashToken(req.Code), client.ID)
+	if err != nil {
+		return nil, err
+	}
+	if code == nil {
+		return nil, invalid
+	}
+	redirectURI := req.RedirectURI
+	if redirectURI == "" && len(client.RedirectURIs) == 1 {
+		redirectURI = client.RedirectURIs[0]
+	}
+	if redirectURI != code.RedirectURI {
+		return nil, &OAuthError{Code: OAuthInvalidGrant, Description: "redirect_uri does not match the authorization request"}
+	}
+	if code.CodeChallenge != "" &&
+		subtle.ConstantTimeCompare([]byte(oidc.CodeChallenge(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, &OAuthError{Code: OAuthInvalidGrant, Description: "code_verifier does not match code_challenge"}
+	}
+
+	user, err := s.users.GetByID(ctx, code.UserID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// Удалённый пользователь не находится, приостановленный - как при входе
+	if user == nil || user.Blocked(now) {
+		return nil, invalid
+	}
+
+	accessToken, err := s.signAccessToken(user.ID.String(), client, code.Scopes, now)
+	if err != nil {
+		return nil, err
+	}
+	tokens := &model.OAuthTokens{AccessToken: accessToken, ExpiresIn: s.cfg.AccessTTL, Scopes: code.Scopes}
+	if containsScope(code.Scopes, model.ScopeOpenID) {
+		if tokens.IDToken, err = s.signIDToken(user, client, code, now); err != nil {
+			return nil, err
+		}
+	}
+	return tokens, nil
+}
+
+// subjectBlocked сообщает, закрыт ли доступ пользователю - субъекту токена.
+// Токен с субъектом, который не разбирается как ID, недействителен.
+func (s *OAuthService) subjectBlocked(ctx context.Context, subject string) (bool, error) {
+	userID, err := uuid.Parse(subject)
+	if err != nil {
+		return true, nil
+	}
+	return s.statuses.IsBlocked(ctx, userID)
+}
+
+// signAccessToken подписывает токен доступа для соседних сервисов. sid в
+// нём нет, поэтому JWTAuth этого сервиса такой токен не примет.
+func (s *OAuthService) signAccessToken(subject string, client *model.OAuthClient, scopes []string, now time.Time) (string, error) {
+	return s.keys.Sign(jwt.MapClaims{
+		"iss":       s.cfg.Issuer,
+		"sub":       subject,
+		"client_id": client.ID.String(),
+		"scope":     strings.Join(scopes, " "),
+		"jti":       uuid.NewString(),
+		"iat":       now.Unix(),
+		"exp":       now.Add(s.cfg.AccessTTL).Unix(),
+	})
+}
+
+// signIDToken подписывает ID-токен (OpenID Connect Core, 2). Состав claims
+// зависит от выданных scopes.
+func (s *OAuthService) signIDToken(user *model.User, client *model.OAuthClient, code *model.OAuthAuthorizationCode, now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"iss": s.cfg.Issuer,
+		"sub": user.ID.String(),
+		"aud": client.ID.String(),
+		"iat": now.Unix(),
+		"exp": now.Add(s.cfg.AccessTTL).Unix(),
+	}
+	if code.Nonce != "" {
+		claims["nonce"] = code.Nonce
+	}
+	if containsScope(code.Scopes, model.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.EmailVerifiedAt != nil
+	}
+	if containsScope(code.Scopes, model.ScopeProfile) {
+		claims["preferred_username"] = user.Nickname
+	}
+	return s.keys.Sign(claims)
+}
+
+// redirect собирает адрес возврата клиенту с параметрами, state и iss
+// (RFC 9207, защита от подмены сервера авторизации)
+func (s *OAuthService) redirect(redirectURI, state string, params url.Values) (string, error) {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		return "", err
+	}
+	query := target.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	query.Set("iss", s.cfg.Issuer)
+	target.RawQuery = query.Encode()
+	return target.String(), nil
+}
+
+func (s *OAuthService) errorRedirect(oauthErr *OAuthError, state string) (string, error) {
+	return s.redirect(oauthErr.redirectURI, state, url.Values{
+		"error":             {oauthErr.Code},
+		"error_description": {oauthErr.Description},
+	})
+}
+
+// activeClient ищет действующего клиента. nil без ошибки - клиент
+// неизвестен или отозван.
+func (s *OAuthService) activeClient(ctx context.Context, rawID string) (*model.OAuthClient, error) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, nil
+	}
+	client, err := s.clients.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil || client.RevokedAt != nil {
+		return nil, nil
+	}
+	return client, nil
+}
+
+// validateClient проверяет, что параметры клиента согласованы между собой
+func validateClient(client *model.OAuthClient, public bool) error {
+	if len(client.GrantTypes) == 0 {
+		return fmt.Errorf("%w: at least one grant type is required", ErrInvalidClientMetadata)
+	}
+	for _, grant := range client.GrantTypes {
+		switch grant {
+		case model.GrantAuthorizationCode:
+			if len(client.RedirectURIs) == 0 {
+				return fmt.Errorf("%w: authorization_code requires a redirect uri", ErrInvalidClientMetadata)
+			}
+		case model.GrantClientCredentials:
+			if public {
+				return fmt.Errorf("%w: public clients cannot use client_credentials", ErrInvalidClientMetadata)
+			}
+		default:
+			return fmt.Errorf("%w: unsupported grant type %q", ErrInvalidClientMetadata, grant)
+		}
+	}
+	for _, uri := range client.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		// RFC 6749, 3.1.2: адрес абсолютный и без фрагмента
+		if err != nil || !parsed.IsAbs() || parsed.Fragment != "" {
+			return fmt.Errorf("%w: invalid redirect uri %q", ErrInvalidClientMetadata, uri)
+		}
+	}
+	return nil
+}
+
+// requestedScopes разбирает scope запроса. Без scope клиент получает все
+// свои scopes; false - запрошено то, что клиенту не разрешено.
+func requestedScopes(client *model.OAuthClient, raw string) ([]string, bool) {
+	scopes := uniqueStrings(strings.Fields(raw))
+	if len(scopes) == 0 {
+		return client.Scopes, true
+	}
+	return scopes, client.AllowsScopes(scopes)
+}
+
+func withoutIdentityScopes(scopes []string) []string {
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		switch scope {
+		case model.ScopeOpenID, model.ScopeProfile, model.ScopeEmail:
+		default:
+			out = append(out, scope)
+		}
+	}
+	return out
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
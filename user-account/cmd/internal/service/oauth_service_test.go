@@ -0,0 +1,661 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOAuthIssuer = "https://accounts.example.com"
+
+// oauthFixture - OAuthService с моками хранилищ; коды авторизации живут в
+// памяти, Consume одноразовый, как и в Postgres
+type oauthFixture struct {
+	svc      *OAuthService
+	clients  *mocks.MockOAuthClientRepository
+	users    *mocks.MockUserRepository
+	tokens   *mocks.MockRevokedTokenRepository
+	sessions *mocks.MockSessionRepository
+	keys     TokenKeys
+}
+
+func newOAuthFixture(t *testing.T, ctrl *gomock.Controller) *oauthFixture {
+	saved := make(map[string]model.OAuthAuthorizationCode)
+	codes := mocks.NewMockOAuthCodeRepository(ctrl)
+	codes.EXPECT().DeleteExpired(gomock.Any()).Return(nil).AnyTimes()
+	codes.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, code *model.OAuthAuthorizationCode) error {
+		saved[code.CodeHash] = *code
+		return nil
+	}).AnyTimes()
+	codes.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, codeHash string, clientID uuid.UUID) (*model.OAuthAuthorizationCode, error) {
+		code, ok := saved[codeHash]
+		if !ok || code.ClientID != clientID {
+			return nil, nil
+		}
+		delete(saved, codeHash)
+		return &code, nil
+	}).AnyTimes()
+
+	f := &oauthFixture{
+		clients:  mocks.NewMockOAuthClientRepository(ctrl),
+		users:    mocks.NewMockUserRepository(ctrl),
+		tokens:   mocks.NewMockRevokedTokenRepository(ctrl),
+		sessions: mocks.NewMockSessionRepository(ctrl),
+		keys:     testSigner(t, "secret"),
+	}
+	revocations := NewRevocationService(f.tokens, f.sessions, time.Minute, time.Minute)
+	statuses := NewUserStatusService(mocks.NewMockUserStatusRepository(ctrl), f.users, nil, nil, time.Minute)
+	f.svc = NewOAuthService(f.clients, codes, f.users, revocations, statuses, f.keys, OAuthConfig{
+		Issuer:           testOAuthIssuer,
+		AuthorizationURL: "https://app.example.com/oauth/authorize",
+		AccessTTL:        time.Minute,
+		CodeTTL:          time.Minute,
+	})
+	return f
+}
+
+// register кладёт клиента с секретом secret в мок хранилища
+func (f *oauthFixture) register(client *model.OAuthClient, secret string) {
+	if secret != "" {
+		client.SecretHash = hashToken(secret)
+	}
+	f.clients.EXPECT().GetByID(gomock.Any(), client.ID).Return(client, nil).AnyTimes()
+}
+
+func (f *oauthFixture) claims(t *testing.T, token string) jwt.MapClaims {
+	t.Helper()
+	parsed, err := jwt.Parse(token, f.keys.Keyfunc)
+	require.NoError(t, err)
+	return parsed.Claims.(jwt.MapClaims)
+}
+
+func TestOAuthService_RegisterClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		reg        model.OAuthClientRegistration
+		wantErr    error
+		wantSecret bool
+	}{
+		{
+			name: "Confidential Client",
+			reg: model.OAuthClientRegistration{
+				Name:         "billing",
+				RedirectURIs: []string{"https://billing.example.com/callback"},
+				GrantTypes:   []string{model.GrantAuthorizationCode, model.GrantClientCredentials},
+				Scopes:       []string{"openid", "billing:read"},
+			},
+			wantSecret: true,
+		},
+		{
+			name: "Public Client",
+			reg: model.OAuthClientRegistration{
+				Name:         "spa",
+				RedirectURIs: []string{"https://spa.example.com/callback"},
+				GrantTypes:   []string{model.GrantAuthorizationCode},
+				Public:       true,
+			},
+		},
+		{
+			name: "Public Client With Client Credentials",
+			reg: model.OAuthClientRegistration{
+				Name:       "spa",
+				GrantTypes: []string{model.GrantClientCredentials},
+				Public:     true,
+			},
+			wantErr: ErrInvalidClientMetadata,
+		},
+		{
+			name:    "Authorization Code Without Redirect URI",
+			reg:     model.OAuthClientRegistration{Name: "web", GrantTypes: []string{model.GrantAuthorizationCode}},
+			wantErr: ErrInvalidClientMetadata,
+		},
+		{
+			name: "Redirect URI With Fragment",
+			reg: model.OAuthClientRegistration{
+				Name:         "web",
+				RedirectURIs: []string{"https://web.example.com/callback#frag"},
+				GrantTypes:   []string{model.GrantAuthorizationCode},
+			},
+			wantErr: ErrInvalidClientMetadata,
+		},
+		{
+			name:    "Unsupported Grant Type",
+			reg:     model.OAuthClientRegistration{Name: "legacy", GrantTypes: []string{"password"}},
+			wantErr: ErrInvalidClientMetadata,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := newOAuthFixture(t, ctrl)
+			adminID := uuid.New()
+			if tt.wantErr == nil {
+				f.clients.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			client, secret, err := f.svc.RegisterClient(context.Background(), adminID, tt.reg)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &adminID, client.CreatedBy)
+			assert.Equal(t, tt.wantSecret, secret != "")
+			assert.Equal(t, !tt.wantSecret, client.Public())
+			if tt.wantSecret {
+				assert.Equal(t, hashToken(secret), client.SecretHash)
+			}
+		})
+	}
+}
+
+func TestOAuthService_ClientCredentials(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	f := newOAuthFixture(t, ctrl)
+
+	client := &model.OAuthClient{
+		ID:         uuid.New(),
+		GrantTypes: []string{model.GrantClientCredentials},
+		Scopes:     []string{"openid", "billing:read", "billing:write"},
+	}
+	f.register(client, "cs_secret")
+
+	tokens, err := f.svc.Token(context.Background(), model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     client.ID.String(),
+		ClientSecret: "cs_secret",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, tokens.IDToken)
+	assert.Equal(t, []string{"billing:read", "billing:write"}, tokens.Scopes)
+
+	claims := f.claims(t, tokens.AccessToken)
+	assert.Equal(t, client.ID.String(), claims["sub"])
+	assert.Equal(t, client.ID.String(), claims["client_id"])
+	assert.Equal(t, testOAuthIssuer, claims["iss"])
+	assert.Equal(t, "billing:read billing:write", claims["scope"])
+	assert.NotContains(t, claims, "sid")
+
+	var oauthErr *OAuthError
+	_, err = f.svc.Token(context.Background(), model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     client.ID.String(),
+		ClientSecret: "cs_wrong",
+	})
+	require.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, OAuthInvalidClient, oauthErr.Code)
+
+	_, err = f.svc.Token(context.Background(), model.TokenRequest{
+		GrantType:    model.GrantClientCredentials,
+		ClientID:     client.ID.String(),
+		ClientSecret: "cs_secret",
+		Scope:        "users:manage",
+	})
+	require.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, OAuthInvalidScope, oauthErr.Code)
+}
+
+func TestOAuthService_AuthorizationCodeFlow(t *testing.T) {
+	t.Parallel()
+
+	verifiedAt := time.Now()
+	user := &model.User{ID: uuid.New(), Email: "alice@example.com", Nickname: "alice", EmailVerifiedAt: &verifiedAt}
+	// Пример верификатора из RFC 7636, приложение B
+	verifier := "dBjftJeZ4CVP-mJ92K9WUOeSnMjGYBTpH0CBkFkbmGo"
+
+	tests := []struct {
+		name string
+		// exchange меняет запрос обмена кода перед отправкой
+		exchange func(req *model.TokenRequest)
+		// suspended - пользователя приостановили между согласием и обменом
+		suspended bool
+		wantErr   string
+	}{
+		{name: "Success"},
+		{
+			name:     "Wrong Code Verifier",
+			exchange: func(req *model.TokenRequest) { req.CodeVerifier = "wrong-verifier-wrong-verifier-wrong-verifier" },
+			wantErr:  OAuthInvalidGrant,
+		},
+		{
+			name:     "Wrong Redirect URI",
+			exchange: func(req *model.TokenRequest) { req.RedirectURI = "https://evil.example.com/callback" },
+			wantErr:  OAuthInvalidGrant,
+		},
+		{
+			name:     "Unknown Code",
+			exchange: func(req *model.TokenRequest) { req.Code = "forged" },
+			wantErr:  OAuthInvalidGrant,
+		},
+		{
+			name:     "Secret From Public Client",
+			exchange: func(req *model.TokenRequest) { req.ClientSecret = "cs_secret" },
+			wantErr:  OAuthInvalidClient,
+		},
+		{
+			name:      "Suspended User",
+			suspended: true,
+			wantErr:   OAuthInvalidGrant,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := newOAuthFixture(t, ctrl)
+
+			client := &model.OAuthClient{
+				ID:           uuid.New(),
+				Name:         "spa",
+				RedirectURIs: []string{"https://spa.example.com/callback"},
+				GrantTypes:   []string{model.GrantAuthorizationCode},
+				Scopes:       []string{"openid", "profile", "email"},
+			}
+			f.register(client, "")
+			owner := *user
+			if tt.suspended {
+				owner.Status = model.UserStatusSuspended
+			}
+			f.users.EXPECT().GetByID(gomock.Any(), user.ID).Return(&owner, nil).AnyTimes()
+
+			authReq := model.AuthorizationRequest{
+				ResponseType:        "code",
+				ClientID:            client.ID.String(),
+				Scope:               "openid email profile",
+				State:               "xyz",
+				Nonce:               "n-0S6",
+				CodeChallenge:       oidc.CodeChallenge(verifier),
+				CodeChallengeMethod: "S256",
+			}
+			preview, err := f.svc.PreviewAuthorization(context.Background(), authReq)
+			require.NoError(t, err)
+			assert.Equal(t, "spa", preview.ClientName)
+			assert.Equal(t, "https://spa.example.com/callback", preview.RedirectURI)
+
+			redirectTo, err := f.svc.Authorize(context.Background(), user.ID, authReq, true)
+			require.NoError(t, err)
+			redirect, err := url.Parse(redirectTo)
+			require.NoError(t, err)
+			assert.Equal(t, "xyz", redirect.Query().Get("state"))
+			assert.Equal(t, testOAuthIssuer, redirect.Query().Get("iss"))
+
+			tokenReq := model.TokenRequest{
+				GrantType:    model.GrantAuthorizationCode,
+				ClientID:     client.ID.String(),
+				Code:         redirect.Query().Get("code"),
+				RedirectURI:  "https://spa.example.com/callback",
+				CodeVerifier: verifier,
+			}
+			if tt.exchange != nil {
+				tt.exchange(&tokenReq)
+			}
+			tokens, err := f.svc.Token(context.Background(), tokenReq)
+			if tt.wantErr != "" {
+				var oauthErr *OAuthError
+				require.True(t, errors.As(err, &oauthErr))
+				assert.Equal(t, tt.wantErr, oauthErr.Code)
+				return
+			}
+			require.NoError(t, err)
+
+			access := f.claims(t, tokens.AccessToken)
+			assert.Equal(t, user.ID.String(), access["sub"])
+			assert.Equal(t, client.ID.String(), access["client_id"])
+
+			id := f.claims(t, tokens.IDToken)
+			assert.Equal(t, user.ID.String(), id["sub"])
+			assert.Equal(t, client.ID.String(), id["aud"])
+			assert.Equal(t, "n-0S6", id["nonce"])
+			assert.Equal(t, "alice@example.com", id["email"])
+			assert.Equal(t, true, id["email_verified"])
+			assert.Equal(t, "alice", id["preferred_username"])
+
+			// Код одноразовый
+			_, err = f.svc.Token(context.Background(), tokenReq)
+			var oauthErr *OAuthError
+			require.True(t, errors.As(err, &oauthErr))
+			assert.Equal(t, OAuthInvalidGrant, oauthErr.Code)
+		})
+	}
+}
+
+func TestOAuthService_ExchangeCodeOfAnotherClient(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	f := newOAuthFixture(t, ctrl)
+	user := &model.User{ID: uuid.New()}
+	f.users.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil).AnyTimes()
+	verifier := "dBjftJeZ4CVP-mJ92K9WUOeSnMjGYBTpH0CBkFkbmGo"
+
+	owner := &model.OAuthClient{
+		ID:           uuid.New(),
+		RedirectURIs: []string{"https://spa.example.com/callback"},
+		GrantTypes:   []string{model.GrantAuthorizationCode},
+		Scopes:       []string{"profile"},
+	}
+	attacker := &model.OAuthClient{
+		ID:           uuid.New(),
+		RedirectURIs: []string{"https://spa.example.com/callback"},
+		GrantTypes:   []string{model.GrantAuthorizationCode},
+	}
+	f.register(owner, "")
+	f.register(attacker, "")
+
+	redirectTo, err := f.svc.Authorize(context.Background(), user.ID, model.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            owner.ID.String(),
+		Scope:               "profile",
+		CodeChallenge:       oidc.CodeChallenge(verifier),
+		CodeChallengeMethod: "S256",
+	}, true)
+	require.NoError(t, err)
+	redirect, err := url.Parse(redirectTo)
+	require.NoError(t, err)
+	tokenReq := model.TokenRequest{
+		GrantType:    model.GrantAuthorizationCode,
+		ClientID:     attacker.ID.String(),
+		Code:         redirect.Query().Get("code"),
+		CodeVerifier: verifier,
+	}
+
+	_, err = f.svc.Token(context.Background(), tokenReq)
+	var oauthErr *OAuthError
+	require.True(t, errors.As(err, &oauthErr))
+	assert.Equal(t, OAuthInvalidGrant, oauthErr.Code)
+
+	// Попытка чужого клиента не сжигает код
+	tokenReq.ClientID = owner.ID.String()
+	tokens, err := f.svc.Token(context.Background(), tokenReq)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), f.claims(t, tokens.AccessToken)["sub"])
+}
+
+func TestOAuthService_Authorize_Errors(t *testing.T) {
+	t.Parallel()
+
+	publicClient := &model.OAuthClient{
+		ID:           uuid.New(),
+		RedirectURIs: []string{"https://spa.example.com/callback", "https://spa.example.com/other"},
+		GrantTypes:   []string{model.GrantAuthorizationCode},
+		Scopes:       []string{"openid"},
+	}
+	valid := model.AuthorizationRequest{
+		ResponseType:        "code",
+		ClientID:            publicClient.ID.String(),
+		RedirectURI:         "https://spa.example.com/callback",
+		State:               "xyz",
+		CodeChallenge:       oidc.CodeChallenge("dBjftJeZ4CVP-mJ92K9WUOeSnMjGYBTpH0CBkFkbmGo"),
+		CodeChallengeMethod: "S256",
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(req *model.AuthorizationRequest)
+		approved bool
+		// wantRedirectErr - код ошибки в адресе возврата; пусто - ошибка
+		// возвращается без адреса
+		wantRedirectErr string
+	}{
+		{name: "User Denied", modify: func(req *model.AuthorizationRequest) {}, wantRedirectErr: OAuthAccessDenied},
+		{
+			name:            "Missing PKCE For Public Client",
+			modify:          func(req *model.AuthorizationRequest) { req.CodeChallenge = "" },
+			approved:        true,
+			wantRedirectErr: OAuthInvalidRequest,
+		},
+		{
+			name:            "Plain PKCE Method",
+			modify:          func(req *model.AuthorizationRequest) { req.CodeChallengeMethod = "plain" },
+			approved:        true,
+			wantRedirectErr: OAuthInvalidRequest,
+		},
+		{
+			name:            "Scope Not Allowed",
+			modify:          func(req *model.AuthorizationRequest) { req.Scope = "openid users:manage" },
+			approved:        true,
+			wantRedirectErr: OAuthInvalidScope,
+		},
+		{
+			name:            "Unsupported Response Type",
+			modify:          func(req *model.AuthorizationRequest) { req.ResponseType = "token" },
+			approved:        true,
+			wantRedirectErr: OAuthUnsupportedResponseType,
+		},
+		{
+			name:     "Unregistered Redirect URI",
+			modify:   func(req *model.AuthorizationRequest) { req.RedirectURI = "https://evil.example.com/callback" },
+			approved: true,
+		},
+		{
+			name:     "Ambiguous Missing Redirect URI",
+			modify:   func(req *model.AuthorizationRequest) { req.RedirectURI = "" },
+			approved: true,
+		},
+		{
+			name:     "Unknown Client",
+			modify:   func(req *model.AuthorizationRequest) { req.ClientID = uuid.NewString() },
+			approved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := newOAuthFixture(t, ctrl)
+			f.register(publicClient, "")
+			f.clients.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			req := valid
+			tt.modify(&req)
+			redirectTo, err := f.svc.Authorize(context.Background(), uuid.New(), req, tt.approved)
+			if tt.wantRedirectErr == "" {
+				var oauthErr *OAuthError
+				require.True(t, errors.As(err, &oauthErr))
+				assert.Equal(t, OAuthInvalidRequest, oauthErr.Code)
+				assert.Empty(t, redirectTo)
+				return
+			}
+			require.NoError(t, err)
+			redirect, err := url.Parse(redirectTo)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRedirectErr, redirect.Query().Get("error"))
+			assert.Equal(t, "xyz", redirect.Query().Get("state"))
+			assert.Empty(t, redirect.Query().Get("code"))
+		})
+	}
+}
+
+func TestOAuthService_Introspect(t *testing.T) {
+	t.Parallel()
+
+	revokedAt := time.Now()
+	resourceServer := &model.OAuthClient{ID: uuid.New(), GrantTypes: []string{model.GrantClientCredentials}, Scopes: []string{"billing:read"}}
+	revokedClient := &model.OAuthClient{ID: uuid.New(), GrantTypes: []string{model.GrantClientCredentials}, RevokedAt: &revokedAt}
+	publicClient := &model.OAuthClient{ID: uuid.New(), GrantTypes: []string{model.GrantAuthorizationCode}}
+
+	sign := func(f *oauthFixture, claims jwt.MapClaims) string {
+		token, err := f.keys.Sign(claims)
+		require.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		// token строит токен для интроспекции и настраивает моки
+		token      func(f *oauthFixture) string
+		callerID   uuid.UUID
+		secret     string
+		wantActive bool
+		wantErr    string
+	}{
+		{
+			name: "Active Client Token",
+			token: func(f *oauthFixture) string {
+				tokens, err := f.svc.Token(context.Background(), model.TokenRequest{
+					GrantType: model.GrantClientCredentials, ClientID: resourceServer.ID.String(), ClientSecret: "cs_rs",
+				})
+				require.NoError(t, err)
+				return tokens.AccessToken
+			},
+			callerID:   resourceServer.ID,
+			secret:     "cs_rs",
+			wantActive: true,
+		},
+		{
+			name: "Token Of Revoked Client",
+			token: func(f *oauthFixture) string {
+				return sign(f, jwt.MapClaims{
+					"iss": testOAuthIssuer, "sub": revokedClient.ID.String(), "client_id": revokedClient.ID.String(),
+					"jti": uuid.NewString(), "exp": now.Add(time.Minute).Unix(),
+				})
+			},
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name: "Active User Token",
+			token: func(f *oauthFixture) string {
+				userID := uuid.New()
+				f.tokens.EXPECT().IsRevoked(gomock.Any(), "user-jti").Return(false, nil)
+				f.sessions.EXPECT().IsFamilyRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+				f.users.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				return sign(f, jwt.MapClaims{
+					"sub": userID.String(), "nickname": "alice", "jti": "user-jti", "sid": uuid.NewString(),
+					"exp": now.Add(time.Minute).Unix(),
+				})
+			},
+			callerID:   resourceServer.ID,
+			secret:     "cs_rs",
+			wantActive: true,
+		},
+		{
+			name: "Token Of Suspended User",
+			token: func(f *oauthFixture) string {
+				userID := uuid.New()
+				f.tokens.EXPECT().IsRevoked(gomock.Any(), "user-jti").Return(false, nil)
+				f.sessions.EXPECT().IsFamilyRevoked(gomock.Any(), gomock.Any()).Return(false, nil)
+				f.users.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Status: model.UserStatusSuspended}, nil)
+				return sign(f, jwt.MapClaims{
+					"sub": userID.String(), "jti": "user-jti", "sid": uuid.NewString(), "exp": now.Add(time.Minute).Unix(),
+				})
+			},
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name: "Client Token Of Deleted User",
+			token: func(f *oauthFixture) string {
+				userID := uuid.New()
+				f.users.EXPECT().GetByID(gomock.Any(), userID).Return(nil, nil)
+				return sign(f, jwt.MapClaims{
+					"iss": testOAuthIssuer, "sub": userID.String(), "client_id": resourceServer.ID.String(),
+					"jti": uuid.NewString(), "exp": now.Add(time.Minute).Unix(),
+				})
+			},
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name: "Revoked User Token",
+			token: func(f *oauthFixture) string {
+				f.tokens.EXPECT().IsRevoked(gomock.Any(), "user-jti").Return(true, nil)
+				return sign(f, jwt.MapClaims{
+					"sub": uuid.NewString(), "jti": "user-jti", "sid": uuid.NewString(), "exp": now.Add(time.Minute).Unix(),
+				})
+			},
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name: "Expired Token",
+			token: func(f *oauthFixture) string {
+				return sign(f, jwt.MapClaims{
+					"iss": testOAuthIssuer, "client_id": resourceServer.ID.String(), "exp": now.Add(-time.Minute).Unix(),
+				})
+			},
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name:     "Garbage Token",
+			token:    func(f *oauthFixture) string { return "not-a-jwt" },
+			callerID: resourceServer.ID,
+			secret:   "cs_rs",
+		},
+		{
+			name:     "Public Caller",
+			token:    func(f *oauthFixture) string { return "not-a-jwt" },
+			callerID: publicClient.ID,
+			wantErr:  OAuthInvalidClient,
+		},
+		{
+			name:     "Wrong Caller Secret",
+			token:    func(f *oauthFixture) string { return "not-a-jwt" },
+			callerID: resourceServer.ID,
+			secret:   "cs_wrong",
+			wantErr:  OAuthInvalidClient,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := newOAuthFixture(t, ctrl)
+			rs, revoked, public := *resourceServer, *revokedClient, *publicClient
+			f.register(&rs, "cs_rs")
+			f.register(&revoked, "cs_revoked")
+			f.register(&public, "")
+
+			result, err := f.svc.Introspect(context.Background(), tt.callerID.String(), tt.secret, tt.token(f))
+			if tt.wantErr != "" {
+				var oauthErr *OAuthError
+				require.True(t, errors.As(err, &oauthErr))
+				assert.Equal(t, tt.wantErr, oauthErr.Code)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantActive, result.Active)
+			if !tt.wantActive {
+				assert.Equal(t, &model.TokenIntrospection{}, result)
+			}
+		})
+	}
+}
+
+func TestOAuthService_Metadata(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	f := newOAuthFixture(t, ctrl)
+
+	metadata := f.svc.Metadata()
+	assert.Equal(t, testOAuthIssuer, metadata.Issuer)
+	assert.Equal(t, "https://app.example.com/oauth/authorize", metadata.AuthorizationEndpoint)
+	assert.Equal(t, testOAuthIssuer+"/oauth/token", metadata.TokenEndpoint)
+	assert.Equal(t, testOAuthIssuer+"/.well-known/jwks.json", metadata.JWKSURI)
+	assert.Equal(t, []string{"HS256"}, metadata.IDTokenSigningAlgValuesSupported)
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	nicknameMinLength = 3
+	nicknameMaxLength = 30
+	nicknameAttempts  = 5
+)
+
+var (
+	ErrUnknownProvider          = errors.New("unknown identity provider")
+	ErrInvalidOIDCState         = errors.New("invalid or expired login state")
+	ErrIdentityEmailNotVerified = errors.New("identity provider has not verified the email")
+	ErrAccountLinkRequired      = errors.New("an account with this email already exists: sign in with the password and verify the email before using this provider")
+)
+
+// IdentityProvider - внешний провайдер OpenID Connect
+type IdentityProvider interface {
+	AuthCodeURL(ctx context.Context, req oidc.AuthRequest) (string, error)
+	Exchange(ctx context.Context, req oidc.ExchangeRequest) (*oidc.Claims, error)
+}
+
+// OIDCConfig - настройки входа через внешних провайдеров
+type OIDCConfig struct {
+	// RedirectURL - страница фронтенда, на которую провайдер возвращает
+	// пользователя с кодом; должна быть зарегистрирована у каждого провайдера
+	RedirectURL string
+	// StateTTL - сколько ждать возврата пользователя от провайдера
+	StateTTL time.Duration
+}
+
+// OIDCService - вход через внешних провайдеров OpenID Connect. Учётная
+// запись провайдера привязывается к пользователю с той же подтверждённой
+// почтой, а если такого нет - для неё заводится новый пользователь.
+type OIDCService struct {
+	providers  map[string]IdentityProvider
+	states     repository.OIDCStateRepository
+	identities repository.IdentityRepository
+	users      repository.UserRepository
+	auth       *AuthService
+	hasher     PasswordHasher
+	cfg        OIDCConfig
+}
+
+func NewOIDCService(
+	providers map[string]IdentityProvider,
+	states repository.OIDCStateRepository,
+	identities repository.IdentityRepository,
+	users repository.UserRepository,
+	auth *AuthService,
+	hasher PasswordHasher,
+	cfg OIDCConfig,
+) *OIDCService {
+	return &OIDCService{
+		providers:  providers,
+		states:     states,
+		identities: identities,
+		users:      users,
+		auth:       auth,
+		hasher:     hasher,
+		cfg:        cfg,
+	}
+}
+
+// Providers возвращает имена настроенных провайдеров
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Authorize начинает вход: запоминает state, nonce и PKCE code_verifier и
+// возвращает адрес провайдера, куда фронтенд перенаправит пользователя, и
+// значение привязки, которое браузер должен предъявить в Callback
+func (s *OIDCService) Authorize(ctx context.Context, provider string) (string, string, error) {
+	idp, ok := s.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return "", "", err
+	}
+	binding, err := newOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := idp.AuthCodeURL(ctx, oidc.AuthRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: oidc.CodeChallenge(verifier),
+		RedirectURI:   s.cfg.RedirectURL,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// Брошенные на полпути входы убираются заодно с началом новых
+	if err = s.states.DeleteExpired(ctx); err != nil {
+		log.Printf("failed to delete expired oidc login states: %v", err)
+	}
+	if err = s.states.Create(ctx, &model.OIDCLoginState{
+		StateHash:    hashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		BindingHash:  hashToken(binding),
+		ExpiresAt:    time.Now().Add(s.cfg.StateTTL),
+	}); err != nil {
+		return "", "", err
+	}
+	return authURL, binding, nil
+}
+
+// Callback завершает вход по коду и state, с которыми провайдер вернул
+// пользователя. binding - значение из Authorize: state, начатый в другом
+// браузере, не примется, даже если код и state настоящие. Результат - как
+// у пароля: токены или MFAToken, если у пользователя включён второй фактор.
+func (s *OIDCService) Callback(ctx context.Context, code, state, binding string, client model.ClientInfo) (*model.LoginResult, error) {
+	if binding == "" {
+		return nil, ErrInvalidOIDCState
+	}
+	pending, err := s.states.Consume(ctx, hashToken(state), hashToken(binding))
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil {
+		return nil, ErrInvalidOIDCState
+	}
+	idp, ok := s.providers[pending.Provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	claims, err := idp.Exchange(ctx, oidc.ExchangeRequest{
+		Code:         code,
+		CodeVerifier: pending.CodeVerifier,
+		RedirectURI:  s.cfg.RedirectURL,
+		Nonce:        pending.Nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, pending.Provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.auth.completeLogin(ctx, user, client)
+}
+
+// Identities возвращает учётные записи провайдеров, привязанные к пользователю
+func (s *OIDCService) Identities(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	return s.identities.ListByUser(ctx, userID)
+}
+
+// resolveUser находит пользователя по учётной записи провайдера или
+// привязывает её: к существующему пользователю с той же почтой или к новому
+func (s *OIDCService) resolveUser(ctx context.Context, provider string, claims *oidc.Claims) (*model.User, error) {
+	identity, err := s.identities.GetByProviderSubject(ctx, provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		if err = s.identities.TouchLogin(ctx, identity.ID); err != nil {
+			log.Printf("failed to record login of identity %s: %v", identity.ID, err)
+		}
+		user, err := s.users.GetByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, ErrUserNotFound
+		}
+		return user, nil
+	}
+
+	// Привязка по почте безопасна, только если её подтвердил провайдер:
+	// иначе любой, кто заведёт у провайдера чужой адрес, войдёт в чужой аккаунт
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrIdentityEmailNotVerified
+	}
+
+	user, err := s.users.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user != nil {
+		// Неподтверждённую почту мог указать при регистрации кто угодно,
+		// в том числе чтобы дождаться входа настоящего владельца адреса
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrAccountLinkRequired
+		}
+	} else if user, err = s.createUser(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err = s.identities.Create(ctx, &model.UserIdentity{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser заводит пользователя для учётной записи провайдера. Пароля у
+// него нет: хэш случайного значения не совпадёт ни с чем, задать пароль
+// можно через сброс по почте.
+func (s *OIDCService) createUser(ctx context.Context, claims *oidc.Claims) (*model.User, error) {
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := s.hasher.Hash(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedAt := time.Now()
+	base := nicknameBase(claims)
+	for attempt := 0; attempt < nicknameAttempts; attempt++ {
+		nickname := base
+		if attempt > 0 {
+			if nickname, err = withRandomSuffix(base); err != nil {
+				return nil, err
+			}
+		}
+
+		user := &model.User{
+			ID:              uuid.New(),
+			Email:           claims.Email,
+			Nickname:        nickname,
+			PasswordHash:    hash,
+			Roles:           []string{model.RoleUser},
+			EmailVerifiedAt: &verifiedAt,
+		}
+		err = s.users.Create(ctx, user)
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(err, repository.ErrNicknameTaken) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// nicknameBase - ник из preferred_username, имени или почты, обрезанный
+// до допустимой длины
+func nicknameBase(claims *oidc.Claims) string {
+	local, _, _ := strings.Cut(claims.Email, "@")
+	for _, candidate := range []string{claims.PreferredUsername, claims.Name, local} {
+		candidate = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return '_'
+			}
+			return r
+		}, strings.TrimSpace(candidate))
+		if utf8.RuneCountInString(candidate) >= nicknameMinLength {
+			return truncateRunes(candidate, nicknameMaxLength)
+		}
+	}
+	return "user"
+}
+
+// withRandomSuffix дописывает к нику четыре случайные цифры
+func withRandomSuffix(base string) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(10000))
+	if err != nil {
+		return "", err
+	}
+	suffix := fmt.Sprintf("_%04d", n.Int64())
+	return truncateRunes(base, nicknameMaxLength-len(suffix)) + suffix, nil
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/oidc"
+	"user-account/cmd/internal/oidc/oidctest"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// oidcFixture - OIDCService с настоящим клиентом провайдера против
+// поддельного провайдера и моками хранилищ
+type oidcFixture struct {
+	server     *oidctest.Server
+	svc        *OIDCService
+	users      *mocks.MockUserRepository
+	identities *mocks.MockIdentityRepository
+}
+
+func newOIDCFixture(t *testing.T, ctrl *gomock.Controller) *oidcFixture {
+	server := oidctest.NewServer(t)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:       server.Issuer(),
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+	}, server.Client())
+
+	users := mocks.NewMockUserRepository(ctrl)
+	identities := mocks.NewMockIdentityRepository(ctrl)
+
+	// Хранилище state в памяти: Consume одноразовый, как и в Postgres
+	saved := make(map[string]model.OIDCLoginState)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	states.EXPECT().DeleteExpired(gomock.Any()).Return(nil).AnyTimes()
+	states.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, state *model.OIDCLoginState) error {
+		saved[state.StateHash] = *state
+		return nil
+	}).AnyTimes()
+	states.EXPECT().Consume(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, stateHash, bindingHash string) (*model.OIDCLoginState, error) {
+		state, ok := saved[stateHash]
+		if !ok || state.BindingHash != bindingHash {
+			return nil, nil
+		}
+		delete(saved, stateHash)
+		return &state, nil
+	}).AnyTimes()
+
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	totpRepo := mocks.NewMockTOTPRepository(ctrl)
+	totpRepo.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	twoFactor := NewTwoFactorService(totpRepo, users, signedtoken.New("action-secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+	auth := NewAuthService(users, sessions, testOrganizations(ctrl), nil, nil, twoFactor, nil, testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{
+		AccessTTL:  time.Minute,
+		RefreshTTL: time.Hour,
+	})
+
+	svc := NewOIDCService(map[string]IdentityProvider{"corp": provider}, states, identities, users, auth, testHasher(), OIDCConfig{
+		RedirectURL: "https://app.example.com/oauth/callback",
+		StateTTL:    time.Minute,
+	})
+	return &oidcFixture{server: server, svc: svc, users: users, identities: identities}
+}
+
+// login проходит вход целиком: Authorize, вход у провайдера, Callback
+func (f *oidcFixture) login(t *testing.T, identity oidctest.Identity) (*model.LoginResult, error) {
+	t.Helper()
+	authURL, binding, err := f.svc.Authorize(context.Background(), "corp")
+	require.NoError(t, err)
+
+	code, state := f.server.Authorize(t, authURL, identity)
+	return f.svc.Callback(context.Background(), code, state, binding, model.ClientInfo{UserAgent: "test-agent"})
+}
+
+func TestOIDCService_Callback(t *testing.T) {
+	t.Parallel()
+
+	verifiedAt := time.Now()
+	existing := &model.User{
+		ID:              uuid.New(),
+		Email:           "alice@example.com",
+		Nickname:        "alice",
+		Roles:           []string{model.RoleUser},
+		EmailVerifiedAt: &verifiedAt,
+	}
+	unverified := *existing
+	unverified.EmailVerifiedAt = nil
+
+	alice := oidctest.Identity{
+		Subject:           "alice-sub",
+		Email:             "alice@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "alice",
+	}
+	unverifiedAlice := alice
+	unverifiedAlice.EmailVerified = false
+
+	tests := []struct {
+		name         string
+		identity     oidctest.Identity
+		mockBehavior func(f *oidcFixture)
+		wantErr      error
+	}{
+		{
+			name:     "Known Identity",
+			identity: alice,
+			mockBehavior: func(f *oidcFixture) {
+				identityID := uuid.New()
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").
+					Return(&model.UserIdentity{ID: identityID, UserID: existing.ID, Provider: "corp", Subject: "alice-sub"}, nil)
+				f.identities.EXPECT().TouchLogin(gomock.Any(), identityID).Return(nil)
+				f.users.EXPECT().GetByID(gomock.Any(), existing.ID).Return(existing, nil)
+			},
+		},
+		{
+			name:     "Links Existing Verified Account By Email",
+			identity: alice,
+			mockBehavior: func(f *oidcFixture) {
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").Return(nil, nil)
+				f.users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(existing, nil)
+				f.identities.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, identity *model.UserIdentity) error {
+					assert.Equal(t, existing.ID, identity.UserID)
+					assert.Equal(t, "corp", identity.Provider)
+					assert.Equal(t, "alice-sub", identity.Subject)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "Refuses To Link Unverified Local Account",
+			identity: alice,
+			mockBehavior: func(f *oidcFixture) {
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").Return(nil, nil)
+				f.users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(&unverified, nil)
+			},
+			wantErr: ErrAccountLinkRequired,
+		},
+		{
+			name:     "Refuses Email Not Verified By Provider",
+			identity: unverifiedAlice,
+			mockBehavior: func(f *oidcFixture) {
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").Return(nil, nil)
+			},
+			wantErr: ErrIdentityEmailNotVerified,
+		},
+		{
+			name:     "Creates New User",
+			identity: alice,
+			mockBehavior: func(f *oidcFixture) {
+				var created *model.User
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").Return(nil, nil)
+				f.users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(nil, nil)
+				f.users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *model.User) error {
+					assert.Equal(t, "alice@example.com", user.Email)
+					assert.Equal(t, "alice", user.Nickname)
+					assert.Equal(t, []string{model.RoleUser}, user.Roles)
+					assert.NotNil(t, user.EmailVerifiedAt)
+					assert.NotEmpty(t, user.PasswordHash)
+					created = user
+					return nil
+				})
+				f.identities.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, identity *model.UserIdentity) error {
+					assert.Equal(t, created.ID, identity.UserID)
+					return nil
+				})
+			},
+		},
+		{
+			name:     "New User With Taken Nickname",
+			identity: alice,
+			mockBehavior: func(f *oidcFixture) {
+				f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "alice-sub").Return(nil, nil)
+				f.users.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(nil, nil)
+				gomock.InOrder(
+					f.users.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrNicknameTaken),
+					f.users.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *model.User) error {
+						assert.Regexp(t, regexp.MustCompile(`^alice_\d{4}$`), user.Nickname)
+						return nil
+					}),
+				)
+				f.identities.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			f := newOIDCFixture(t, ctrl)
+			tt.mockBehavior(f)
+
+			result, err := f.login(t, tt.identity)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			if assert.NotNil(t, result.Tokens) {
+				assert.NotEmpty(t, result.Tokens.AccessToken)
+				assert.NotEmpty(t, result.Tokens.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestOIDCService_Callback_StateIsSingleUse(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	f := newOIDCFixture(t, ctrl)
+
+	_, err := f.svc.Callback(context.Background(), "code", "forged-state", "binding", model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+
+	authURL, binding, err := f.svc.Authorize(context.Background(), "corp")
+	require.NoError(t, err)
+	code, state := f.server.Authorize(t, authURL, oidctest.Identity{Subject: "bob", Email: "bob@example.com"})
+
+	// Первый возврат доходит до привязки (и упирается в неподтверждённую
+	// почту), повтор с тем же state отклоняется сразу
+	f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "bob").Return(nil, nil)
+	_, err = f.svc.Callback(context.Background(), code, state, binding, model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrIdentityEmailNotVerified)
+
+	_, err = f.svc.Callback(context.Background(), code, state, binding, model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+}
+
+func TestOIDCService_Callback_RequiresBinding(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	f := newOIDCFixture(t, ctrl)
+
+	// Атакующий начинает вход сам и подсовывает жертве свои code и state:
+	// у браузера жертвы нет привязки или она от другого входа
+	authURL, binding, err := f.svc.Authorize(context.Background(), "corp")
+	require.NoError(t, err)
+	code, state := f.server.Authorize(t, authURL, oidctest.Identity{Subject: "mallory", Email: "mallory@example.com", EmailVerified: true})
+
+	_, err = f.svc.Callback(context.Background(), code, state, "", model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+
+	_, otherBinding, err := f.svc.Authorize(context.Background(), "corp")
+	require.NoError(t, err)
+	_, err = f.svc.Callback(context.Background(), code, state, otherBinding, model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidOIDCState)
+
+	// Отклонённые попытки не расходуют state начавшего вход браузера
+	f.identities.EXPECT().GetByProviderSubject(gomock.Any(), "corp", "mallory").Return(&model.UserIdentity{ID: uuid.New(), UserID: uuid.New()}, nil)
+	f.identities.EXPECT().TouchLogin(gomock.Any(), gomock.Any()).Return(nil)
+	f.users.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(nil, nil)
+	_, err = f.svc.Callback(context.Background(), code, state, binding, model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestOIDCService_Authorize_UnknownProvider(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	f := newOIDCFixture(t, ctrl)
+
+	_, _, err := f.svc.Authorize(context.Background(), "myspace")
+	assert.True(t, errors.Is(err, ErrUnknownProvider))
+	assert.Equal(t, []string{"corp"}, f.svc.Providers())
+}
+
+func TestNicknameBase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		claims oidc.Claims
+		want   string
+	}{
+		{name: "Preferred Username", claims: oidc.Claims{PreferredUsername: "jdoe", Name: "John Doe", Email: "john@example.com"}, want: "jdoe"},
+		{name: "Name With Spaces", claims: oidc.Claims{Name: "John Doe", Email: "john@example.com"}, want: "John_Doe"},
+		{name: "Email Local Part", claims: oidc.Claims{PreferredUsername: "jd", Email: "john.doe@example.com"}, want: "john.doe"},
+		{name: "Truncated", claims: oidc.Claims{PreferredUsername: "a-very-long-preferred-username-from-the-provider"}, want: "a-very-long-preferred-username"},
+		{name: "Fallback", claims: oidc.Claims{Email: "jd@example.com"}, want: "user"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, nicknameBase(&tt.claims))
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOrganizationNotFound  = errors.New("organization not found")
+	ErrMemberNotFound        = errors.New("organization member not found")
+	ErrOrganizationForbidden = errors.New("insufficient organization role")
+	ErrInvalidOrgRole        = errors.New("unknown organization role")
+)
+
+// OrganizationService управляет организациями и их составом. Права
+// проверяются по роли в организации, глобальные роли здесь не учитываются.
+type OrganizationService struct {
+	orgs        repository.OrganizationRepository
+	invitations *InvitationService
+}
+
+func NewOrganizationService(orgs repository.OrganizationRepository, invitations *InvitationService) *OrganizationService {
+	return &OrganizationService{orgs: orgs, invitations: invitations}
+}
+
+// Create заводит организацию, создатель становится её владельцем
+func (s *OrganizationService) Create(ctx context.Context, ownerID uuid.UUID, name string) (*model.Organization, error) {
+	org := &model.Organization{ID: uuid.New(), Name: name}
+	if err := s.orgs.Create(ctx, org, ownerID); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// ListForUser возвращает организации пользователя. Первая из них -
+// организация по умолчанию, в которую попадает вход.
+func (s *OrganizationService) ListForUser(ctx context.Context, userID uuid.UUID) ([]model.OrganizationMembership, error) {
+	return s.orgs.ListForUser(ctx, userID)
+}
+
+// MemberRole возвращает роль пользователя в организации, пустую - если он
+// в ней не состоит
+func (s *OrganizationService) MemberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	return s.orgs.MemberRole(ctx, orgID, userID)
+}
+
+// Get возвращает организацию вместе с ролью в ней пользователя userID
+func (s *OrganizationService) Get(ctx context.Context, orgID, userID uuid.UUID) (*model.OrganizationMembership, error) {
+	role, err := s.actorRole(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return &model.OrganizationMembership{Organization: *org, Role: role}, nil
+}
+
+// Rename меняет название организации, доступно владельцу и администратору
+func (s *OrganizationService) Rename(ctx context.Context, orgID, actorID uuid.UUID, name string) (*model.OrganizationMembership, error) {
+	role, err := s.managerRole(ctx, orgID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed, err := s.orgs.Rename(ctx, orgID, name)
+	if err != nil {
+		return nil, err
+	}
+	if !renamed {
+		return nil, ErrOrganizationNotFound
+	}
+
+	org, err := s.orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	return &model.OrganizationMembership{Organization: *org, Role: role}, nil
+}
+
+// Members возвращает состав организации, виден любому её участнику
+func (s *OrganizationService) Members(ctx context.Context, orgID, actorID uuid.UUID) ([]model.OrganizationMember, error) {
+	if _, err := s.actorRole(ctx, orgID, actorID); err != nil {
+		return nil, err
+	}
+	return s.orgs.ListMembers(ctx, orgID)
+}
+
+// AddMember приглашает адрес в организацию. Без согласия владельца адреса
+// в организацию никто не попадает, поэтому зарегистрированный пользователь
+// тоже получает приглашение, а ответ не зависит от того, есть ли аккаунт.
+// Права те же, что у InvitationService.Create.
+func (s *OrganizationService) AddMember(ctx context.Context, orgID, actorID uuid.UUID, email, role string) error {
+	_, err := s.invitations.Create(ctx, orgID, actorID, email, role)
+	return err
+}
+
+// ChangeRole меняет роль участника. Роль владельца выдаёт и забирает
+// только владелец, последнего владельца понизить нельзя:
+// repository.ErrLastOwner.
+func (s *OrganizationService) ChangeRole(ctx context.Context, orgID, actorID, userID uuid.UUID, role string) error {
+	if !model.ValidOrgRole(role) {
+		return ErrInvalidOrgRole
+	}
+
+	actorRole, err := s.managerRole(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.memberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if (current == model.OrgRoleOwner || role == model.OrgRoleOwner) && actorRole != model.OrgRoleOwner {
+		return ErrOrganizationForbidden
+	}
+
+	updated, err := s.orgs.UpdateMemberRole(ctx, orgID, userID, role)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// RemoveMember исключает участника. Покинуть организацию может любой её
+// участник, исключать других - владелец и администратор, владельца -
+// только владелец. Последнего владельца исключить нельзя:
+// repository.ErrLastOwner.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID, actorID, userID uuid.UUID) error {
+	actorRole, err := s.actorRole(ctx, orgID, actorID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.memberRole(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if actorID != userID {
+		if !model.CanManageOrganization(actorRole) ||
+			(current == model.OrgRoleOwner && actorRole != model.OrgRoleOwner) {
+			return ErrOrganizationForbidden
+		}
+	}
+
+	removed, err := s.orgs.RemoveMember(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// actorRole возвращает роль вызывающего. Для того, кто в организации не
+// состоит, её как будто нет.
+func (s *OrganizationService) actorRole(ctx context.Context, orgID, actorID uuid.UUID) (string, error) {
+	role, err := s.orgs.MemberRole(ctx, orgID, actorID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrOrganizationNotFound
+	}
+	return role, nil
+}
+
+// managerRole возвращает роль вызывающего, если она позволяет управлять организацией
+func (s *OrganizationService) managerRole(ctx context.Context, orgID, actorID uuid.UUID) (string, error) {
+	role, err := s.actorRole(ctx, orgID, actorID)
+	if err != nil {
+		return "", err
+	}
+	if !model.CanManageOrganization(role) {
+		return "", ErrOrganizationForbidden
+	}
+	return role, nil
+}
+
+func (s *OrganizationService) memberRole(ctx context.Context, orgID, userID uuid.UUID) (string, error) {
+	role, err := s.orgs.MemberRole(ctx, orgID, userID)
+	if err != nil {
+		return "", err
+	}
+	if role == "" {
+		return "", ErrMemberNotFound
+	}
+	return role, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrganizationService_AddMember(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	actorID := uuid.New()
+	org := &model.Organization{ID: orgID, Name: "Acme Legal"}
+	registered := &model.User{ID: uuid.New(), Email: "registered@test.com"}
+
+	tests := []struct {
+		name         string
+		email        string
+		role         string
+		mockBehavior func(mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository)
+		wantErr      error
+	}{
+		{
+			name:  "1. Registered User Is Invited, Not Added",
+			email: registered.Email,
+			role:  model.OrgRoleMember,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleAdmin, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), registered.Email).Return(registered, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, registered.ID).Return("", nil)
+				mo.EXPECT().GetByID(gomock.Any(), orgID).Return(org, nil)
+			},
+		},
+		{
+			name:  "2. Unknown Email Gets The Same Invitation",
+			email: "ghost@test.com",
+			role:  model.OrgRoleMember,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository, mu *mocks.MockUserRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, actorID).Return(model.OrgRoleAdmin, nil)
+				mu.EXPECT().GetByEmail(gomock.Any(), "ghost@test.com").Return(nil, nil)
+				mo.EXPECT().GetByID(gomock.Any(), orgID).Return(org, nil)
+			},
+		},
+		{
+			name:         "3. Unknown Role",
+			email:        registered.Email,
+			role:         "superuser",
+			mockBehavior: func(_ *mocks.MockOrganizationRepository, _ *mocks.MockUserRepository) {},
+			wantErr:      ErrInvalidOrgRole,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			invitations := mocks.NewMockInvitationRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(orgs, users)
+			// Участник появляется только после принятия приглашения,
+			// orgs.AddMember здесь не вызывается
+			if tt.wantErr == nil {
+				invitations.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mail.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg mailer.Message) error {
+					assert.Equal(t, tt.email, msg.To)
+					return nil
+				})
+			}
+
+			invitationService := NewInvitationService(invitations, orgs, users, signedtoken.New("secret"), mail, testHasher(), testPolicy(), time.Hour, "https://app.example.com")
+			svc := NewOrganizationService(orgs, invitationService)
+			err := svc.AddMember(context.Background(), orgID, actorID, tt.email, tt.role)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOrganizationService_ChangeRole(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	ownerID := uuid.New()
+	memberID := uuid.New()
+
+	tests := []struct {
+		name         string
+		actorID      uuid.UUID
+		targetID     uuid.UUID
+		role         string
+		mockBehavior func(mo *mocks.MockOrganizationRepository)
+		wantErr      error
+	}{
+		{
+			name:     "1. Owner Promotes Member",
+			actorID:  ownerID,
+			targetID: memberID,
+			role:     model.OrgRoleOwner,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return(model.OrgRoleMember, nil)
+				mo.EXPECT().UpdateMemberRole(gomock.Any(), orgID, memberID, model.OrgRoleOwner).Return(true, nil)
+			},
+		},
+		{
+			name:     "2. Last Owner Cannot Step Down",
+			actorID:  ownerID,
+			targetID: ownerID,
+			role:     model.OrgRoleAdmin,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil).Times(2)
+				mo.EXPECT().UpdateMemberRole(gomock.Any(), orgID, ownerID, model.OrgRoleAdmin).Return(false, repository.ErrLastOwner)
+			},
+			wantErr: repository.ErrLastOwner,
+		},
+		{
+			name:     "3. Admin Cannot Demote Owner",
+			actorID:  memberID,
+			targetID: ownerID,
+			role:     model.OrgRoleMember,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return(model.OrgRoleAdmin, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil)
+			},
+			wantErr: ErrOrganizationForbidden,
+		},
+		{
+			name:     "4. Target Not A Member",
+			actorID:  ownerID,
+			targetID: memberID,
+			role:     model.OrgRoleAdmin,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return("", nil)
+			},
+			wantErr: ErrMemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			tt.mockBehavior(orgs)
+
+			svc := NewOrganizationService(orgs, nil)
+			err := svc.ChangeRole(context.Background(), orgID, tt.actorID, tt.targetID, tt.role)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOrganizationService_RemoveMember(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	ownerID := uuid.New()
+	memberID := uuid.New()
+	otherID := uuid.New()
+
+	tests := []struct {
+		name         string
+		actorID      uuid.UUID
+		targetID     uuid.UUID
+		mockBehavior func(mo *mocks.MockOrganizationRepository)
+		wantErr      error
+	}{
+		{
+			name:     "1. Member Leaves",
+			actorID:  memberID,
+			targetID: memberID,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return(model.OrgRoleMember, nil).Times(2)
+				mo.EXPECT().RemoveMember(gomock.Any(), orgID, memberID).Return(true, nil)
+			},
+		},
+		{
+			name:     "2. Member Cannot Remove Others",
+			actorID:  memberID,
+			targetID: otherID,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return(model.OrgRoleMember, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, otherID).Return(model.OrgRoleMember, nil)
+			},
+			wantErr: ErrOrganizationForbidden,
+		},
+		{
+			name:     "3. Owner Removes Member",
+			actorID:  ownerID,
+			targetID: memberID,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil)
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, memberID).Return(model.OrgRoleMember, nil)
+				mo.EXPECT().RemoveMember(gomock.Any(), orgID, memberID).Return(true, nil)
+			},
+		},
+		{
+			name:     "4. Last Owner Cannot Leave",
+			actorID:  ownerID,
+			targetID: ownerID,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return(model.OrgRoleOwner, nil).Times(2)
+				mo.EXPECT().RemoveMember(gomock.Any(), orgID, ownerID).Return(false, repository.ErrLastOwner)
+			},
+			wantErr: repository.ErrLastOwner,
+		},
+		{
+			name:     "5. Storage Error",
+			actorID:  ownerID,
+			targetID: memberID,
+			mockBehavior: func(mo *mocks.MockOrganizationRepository) {
+				mo.EXPECT().MemberRole(gomock.Any(), orgID, ownerID).Return("", errors.New("db down"))
+			},
+			wantErr: errors.New("db down"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			orgs := mocks.NewMockOrganizationRepository(ctrl)
+			tt.mockBehavior(orgs)
+
+			svc := NewOrganizationService(orgs, nil)
+			err := svc.RemoveMember(context.Background(), orgID, tt.actorID, tt.targetID)
+
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if !errors.Is(err, tt.wantErr) {
+					assert.Equal(t, tt.wantErr.Error(), err.Error())
+				}
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
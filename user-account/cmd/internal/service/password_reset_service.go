@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+// PasswordResetConfig - настройки сброса пароля
+type PasswordResetConfig struct {
+	// TTL - сколько действует ссылка из письма
+	TTL time.Duration
+	// PublicURL - адрес фронтенда со страницей /reset-password
+	PublicURL string
+	// MinResponseTime - не быстрее скольки отвечает запрос сброса. Выравнивает
+	// время ответа для известных и неизвестных адресов.
+	MinResponseTime time.Duration
+}
+
+// PasswordResetService выдаёт одноразовые токены сброса пароля и меняет
+// пароль по ним. В базе хранится только хэш токена.
+type PasswordResetService struct {
+	users       repository.UserRepository
+	resets      repository.PasswordResetRepository
+	revocations *RevocationService
+	mailer      mailer.Mailer
+	hasher      PasswordHasher
+	policy      PasswordPolicy
+	audit       *AuditLog
+	cfg         PasswordResetConfig
+}
+
+func NewPasswordResetService(
+	users repository.UserRepository,
+	resets repository.PasswordResetRepository,
+	revocations *RevocationService,
+	mail mailer.Mailer,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	audit *AuditLog,
+	cfg PasswordResetConfig,
+) *PasswordResetService {
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &PasswordResetService{
+		users:       users,
+		resets:      resets,
+		revocations: revocations,
+		mailer:      mail,
+		hasher:      hasher,
+		policy:      policy,
+		audit:       audit,
+		cfg:         cfg,
+	}
+}
+
+// RequestReset отправляет ссылку сброса, если адрес зарегистрирован.
+// Для неизвестных адресов ничего не делает, а отвечает не раньше
+// MinResponseTime в любом случае, чтобы ни результат, ни время ответа не
+// выдавали, есть ли такой пользователь.
+func (s *PasswordResetService) RequestReset(ctx context.Context, email string) error {
+	defer waitUntil(ctx, time.Now().Add(s.cfg.MinResponseTime))
+
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	reset := &model.PasswordReset{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.cfg.TTL),
+	}
+	if err = s.resets.Create(ctx, reset); err != nil {
+		return err
+	}
+
+	link := s.cfg.PublicURL + "/reset-password?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля",
+		Body: fmt.Sprintf(
+			"Здравствуйте, %s!\n\nЧтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действует до %s и сработает один раз.\nЕсли вы не запрашивали сброс, просто проигнорируйте это письмо - пароль останется прежним.\n",
+			user.Nickname, link, reset.ExpiresAt.UTC().Format("02.01.2006 15:04 MST"),
+		),
+	})
+	if err != nil {
+		// Ошибку не возвращаем: ответ с ошибкой только для существующих
+		// адресов раскрыл бы, что адрес зарегистрирован
+		log.Printf("failed to send password reset email to user %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по токену из письма. После смены
+// гасятся остальные выданные токены и завершаются все сессии пользователя.
+// Пароль проверяется по политике до того, как токен будет израсходован,
+// чтобы с отклонённым паролем можно было попробовать снова по той же ссылке.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	tokenHash := hashToken(token)
+
+	pending, err := s.resets.GetActive(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	if pending == nil {
+		return ErrInvalidResetToken
+	}
+	user, err := s.users.GetByID(ctx, pending.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	if err = s.policy.Check(ctx, newPassword, user.Email, user.Nickname); err != nil {
+		return err
+	}
+
+	reset, err := s.resets.Consume(ctx, tokenHash)
+	if err != nil {
+		return err
+	}
+	if reset == nil {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	if err = s.users.UpdatePassword(ctx, reset.UserID, hash); err != nil {
+		return err
+	}
+
+	if err = s.resets.InvalidateForUser(ctx, reset.UserID); err != nil {
+		return err
+	}
+	if err = s.revocations.RevokeUserSessions(ctx, reset.UserID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{ActorID: &reset.UserID, TargetID: &reset.UserID, Action: model.AuditPasswordReset})
+	return nil
+}
+
+// waitUntil ждёт наступления deadline или отмены ctx
+func waitUntil(ctx context.Context, deadline time.Time) {
+	delay := time.Until(deadline)
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/mailer"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/passpolicy"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordResetService_RequestReset(t *testing.T) {
+	t.Parallel()
+
+	const email = "forgot@example.com"
+	user := &model.User{ID: uuid.New(), Email: email, Nickname: "forgetful"}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, mm *mocks.MockMailer)
+		wantErr      bool
+	}{
+		{
+			name: "1. Known Email Gets Link With Hashed Token Stored",
+			mockBehavior: func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, mm *mocks.MockMailer) {
+				var stored string
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil)
+				mr.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, r *model.PasswordReset) error {
+						assert.Equal(t, user.ID, r.UserID)
+						stored = r.TokenHash
+						return nil
+					})
+				mm.EXPECT().
+					Send(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, msg mailer.Message) error {
+						assert.Equal(t, email, msg.To)
+						_, after, found := strings.Cut(msg.Body, "/reset-password?token=")
+						assert.True(t, found)
+						token, _, _ := strings.Cut(after, "\n")
+						assert.Equal(t, stored, hashToken(token))
+						return nil
+					})
+			},
+			wantErr: false,
+		},
+		{
+			name: "2. Unknown Email Is Silently Ignored",
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockPasswordResetRepository, _ *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "3. Mail Failure Is Not Reported",
+			mockBehavior: func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, mm *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(user, nil)
+				mr.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp unavailable"))
+			},
+			wantErr: false,
+		},
+		{
+			name: "4. Repository Error",
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockPasswordResetRepository, _ *mocks.MockMailer) {
+				m.EXPECT().GetByEmail(gomock.Any(), email).Return(nil, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserRepository(ctrl)
+			resets := mocks.NewMockPasswordResetRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(users, resets, mail)
+
+			svc := NewPasswordResetService(users, resets, nil, mail, testHasher(), testPolicy(), nil, PasswordResetConfig{TTL: time.Hour, PublicURL: "https://app.example.com"})
+			err := svc.RequestReset(context.Background(), email)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
+
+func TestPasswordResetService_RequestReset_MinResponseTime(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(nil, nil)
+
+	const floor = 50 * time.Millisecond
+	svc := NewPasswordResetService(users, mocks.NewMockPasswordResetRepository(ctrl), nil, mocks.NewMockMailer(ctrl), testHasher(), testPolicy(), nil, PasswordResetConfig{
+		TTL:             time.Hour,
+		MinResponseTime: floor,
+	})
+
+	start := time.Now()
+	assert.NoError(t, svc.RequestReset(context.Background(), "nobody@example.com"))
+	assert.GreaterOrEqual(t, time.Since(start), floor)
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	t.Parallel()
+
+	const token = "reset-token"
+	const newPassword = "brand-new-password"
+	userID := uuid.New()
+	user := &model.User{ID: userID, Email: "john@example.com", Nickname: "wanderer"}
+
+	tests := []struct {
+		name         string
+		newPassword  string
+		mockBehavior func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, ms *mocks.MockSessionRepository)
+		expectedErr  error
+	}{
+		{
+			name: "1. Success Revokes All Sessions",
+			mockBehavior: func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, ms *mocks.MockSessionRepository) {
+				gomock.InOrder(
+					mr.EXPECT().GetActive(gomock.Any(), hashToken(token)).Return(&model.PasswordReset{ID: uuid.New(), UserID: userID}, nil),
+					m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil),
+					mr.EXPECT().Consume(gomock.Any(), hashToken(token)).Return(&model.PasswordReset{ID: uuid.New(), UserID: userID}, nil),
+					m.EXPECT().
+						UpdatePassword(gomock.Any(), userID, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, id uuid.UUID, hash string) error {
+							assertPasswordHash(t, hash, newPassword)
+							return nil
+						}),
+					mr.EXPECT().InvalidateForUser(gomock.Any(), userID).Return(nil),
+					ms.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil),
+				)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "2. Unknown, Used Or Expired Token",
+			mockBehavior: func(_ *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, _ *mocks.MockSessionRepository) {
+				mr.EXPECT().GetActive(gomock.Any(), hashToken(token)).Return(nil, nil)
+			},
+			expectedErr: ErrInvalidResetToken,
+		},
+		{
+			name:        "3. Rejected Password Keeps Token Usable",
+			newPassword: "john-1234",
+			mockBehavior: func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, _ *mocks.MockSessionRepository) {
+				mr.EXPECT().GetActive(gomock.Any(), hashToken(token)).Return(&model.PasswordReset{ID: uuid.New(), UserID: userID}, nil)
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+				// Consume не вызывается: по той же ссылке можно повторить попытку
+			},
+			expectedErr: passpolicy.ErrPolicyViolated,
+		},
+		{
+			name: "4. Token Consumed Concurrently",
+			mockBehavior: func(m *mocks.MockUserRepository, mr *mocks.MockPasswordResetRepository, _ *mocks.MockSessionRepository) {
+				mr.EXPECT().GetActive(gomock.Any(), hashToken(token)).Return(&model.PasswordReset{ID: uuid.New(), UserID: userID}, nil)
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+				mr.EXPECT().Consume(gomock.Any(), hashToken(token)).Return(nil, nil)
+			},
+			expectedErr: ErrInvalidResetToken,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			users := mocks.NewMockUserRepository(ctrl)
+			resets := mocks.NewMockPasswordResetRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(users, resets, sessions)
+
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+			svc := NewPasswordResetService(users, resets, revocations, mocks.NewMockMailer(ctrl), testHasher(), strictPolicy(), nil, PasswordResetConfig{TTL: time.Hour})
+			password := tt.newPassword
+			if password == "" {
+				password = newPassword
+			}
+			err := svc.ResetPassword(context.Background(), token, password)
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
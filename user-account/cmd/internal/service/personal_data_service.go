@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDataExportNotFound = errors.New("data export not found")
+	ErrDataExportNotReady = errors.New("data export is not ready")
+)
+
+// PersonalDataService отвечает на запросы субъекта персональных данных
+// (GDPR, 152-ФЗ): выгрузка всего, что о нём хранится, и стирание. Сама
+// выгрузка собирается в фоне DataExporter.
+type PersonalDataService struct {
+	exports     repository.DataExportRepository
+	data        repository.PersonalDataRepository
+	users       *UserService
+	revocations *RevocationService
+	guard       *LoginGuard
+	audit       *AuditLog
+}
+
+func NewPersonalDataService(
+	exports repository.DataExportRepository,
+	data repository.PersonalDataRepository,
+	users *UserService,
+	revocations *RevocationService,
+	guard *LoginGuard,
+	audit *AuditLog,
+) *PersonalDataService {
+	return &PersonalDataService{exports: exports, data: data, users: users, revocations: revocations, guard: guard, audit: audit}
+}
+
+// RequestExport ставит выгрузку данных пользователя в очередь. Пока
+// предыдущая выгрузка не собрана, новая не создаётся - возвращается
+// текущая, так что повторные нажатия не забивают очередь.
+func (s *PersonalDataService) RequestExport(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	active, err := s.exports.Active(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return active, nil
+	}
+
+	export := &model.DataExport{ID: uuid.New(), UserID: userID}
+	if err = s.exports.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetExport возвращает выгрузку пользователя
+func (s *PersonalDataService) GetExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	export, err := s.exports.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, ErrDataExportNotFound
+	}
+	return export, nil
+}
+
+// DownloadExport возвращает ZIP-архив готовой выгрузки
+func (s *PersonalDataService) DownloadExport(ctx context.Context, userID, id uuid.UUID) ([]byte, error) {
+	export, err := s.GetExport(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !export.Downloadable(now) {
+		return nil, ErrDataExportNotReady
+	}
+
+	archive, err := s.exports.Archive(ctx, userID, id, now)
+	if err != nil {
+		return nil, err
+	}
+	// Архив мог истечь между двумя запросами
+	if archive == nil {
+		return nil, ErrDataExportNotReady
+	}
+	return archive, nil
+}
+
+// Erase стирает пользователя по запросу, полученному не через API:
+// адрес и ник обезличиваются, связанные личные данные удаляются, входы
+// и выданные токены отзываются. Стирание необратимо.
+func (s *PersonalDataService) Erase(ctx context.Context, id uuid.UUID) error {
+	user, err := s.data.Erase(ctx, id)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	// Сессии уже отозваны в базе, здесь сбрасывается кэш проверок
+	if err = s.revocations.RevokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+	// Счётчики неудачных входов хранятся по адресу, который больше не
+	// принадлежит пользователю
+	if err = s.guard.Unlock(ctx, user.Email); err != nil {
+		return err
+	}
+	if err = s.guard.Unlock(ctx, twoFactorAccount(user.ID)); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditUserErase})
+	return nil
+}
+
+// EraseAccount стирает аккаунт владельца после проверки пароля. Как и в
+// CloseAccount, предъявленный access-токен отзывается отдельно.
+func (s *PersonalDataService) EraseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error {
+	if _, err := s.users.verifyPassword(ctx, id, password); err != nil {
+		if errors.Is(err, ErrInvalidPassword) {
+			s.audit.RecordFailure(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditUserErase}, err)
+		}
+		return err
+	}
+	if err := s.Erase(ctx, id); err != nil {
+		return err
+	}
+	return s.revocations.RevokeToken(ctx, token.ID, token.ExpiresAt)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalDataService_RequestExport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	active := &model.DataExport{ID: uuid.New(), UserID: userID, Status: model.DataExportProcessing}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockDataExportRepository)
+		expectedID   *uuid.UUID
+		wantErr      bool
+	}{
+		{
+			name: "1. Creates Pending Export",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Active(gomock.Any(), userID).Return(nil, nil)
+				m.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.DataExport) error {
+					assert.Equal(t, userID, e.UserID)
+					assert.NotEqual(t, uuid.Nil, e.ID)
+					return nil
+				})
+			},
+		},
+		{
+			name: "2. Returns Export In Progress",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Active(gomock.Any(), userID).Return(active, nil)
+			},
+			expectedID: &active.ID,
+		},
+		{
+			name: "3. Repository Error",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Active(gomock.Any(), userID).Return(nil, errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			exports := mocks.NewMockDataExportRepository(ctrl)
+			tt.mockBehavior(exports)
+
+			svc := NewPersonalDataService(exports, nil, nil, nil, nil, nil)
+			export, err := svc.RequestExport(context.Background(), userID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expectedID != nil {
+				assert.Equal(t, *tt.expectedID, export.ID)
+			}
+		})
+	}
+}
+
+func TestPersonalDataService_DownloadExport(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exportID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	ready := &model.DataExport{ID: exportID, UserID: userID, Status: model.DataExportReady, ExpiresAt: &expiresAt}
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockDataExportRepository)
+		expected     []byte
+		expectedErr  error
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Get(gomock.Any(), userID, exportID).Return(ready, nil)
+				m.EXPECT().Archive(gomock.Any(), userID, exportID, gomock.Any()).Return([]byte("zip"), nil)
+			},
+			expected: []byte("zip"),
+		},
+		{
+			name: "2. Not Found",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Get(gomock.Any(), userID, exportID).Return(nil, nil)
+			},
+			expectedErr: ErrDataExportNotFound,
+		},
+		{
+			name: "3. Still Processing",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Get(gomock.Any(), userID, exportID).
+					Return(&model.DataExport{ID: exportID, UserID: userID, Status: model.DataExportProcessing}, nil)
+			},
+			expectedErr: ErrDataExportNotReady,
+		},
+		{
+			name: "4. Expired Between Requests",
+			mockBehavior: func(m *mocks.MockDataExportRepository) {
+				m.EXPECT().Get(gomock.Any(), userID, exportID).Return(ready, nil)
+				m.EXPECT().Archive(gomock.Any(), userID, exportID, gomock.Any()).Return(nil, nil)
+			},
+			expectedErr: ErrDataExportNotReady,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			exports := mocks.NewMockDataExportRepository(ctrl)
+			tt.mockBehavior(exports)
+
+			svc := NewPersonalDataService(exports, nil, nil, nil, nil, nil)
+			archive, err := svc.DownloadExport(context.Background(), userID, exportID)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, archive)
+		})
+	}
+}
+
+func TestPersonalDataService_Erase(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	t.Run("1. Success Revokes Sessions And Clears Lockouts", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		ctrl := gomock.NewController(t)
+		data := mocks.NewMockPersonalDataRepository(ctrl)
+		sessions := mocks.NewMockSessionRepository(ctrl)
+		guard := testLoginGuard()
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, guard.Fail(ctx, "victim@example.com", "10.0.0.1"))
+		}
+		data.EXPECT().Erase(gomock.Any(), userID).Return(&model.User{ID: userID, Email: "victim@example.com"}, nil)
+		sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+
+		revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+		svc := NewPersonalDataService(nil, data, nil, revocations, guard, nil)
+		assert.NoError(t, svc.Erase(ctx, userID))
+		assert.NoError(t, guard.Check(ctx, "victim@example.com", "10.0.0.2"))
+	})
+
+	t.Run("2. Unknown Or Already Erased", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		data := mocks.NewMockPersonalDataRepository(ctrl)
+		data.EXPECT().Erase(gomock.Any(), userID).Return(nil, nil)
+
+		svc := NewPersonalDataService(nil, data, nil, nil, nil, nil)
+		assert.ErrorIs(t, svc.Erase(context.Background(), userID), ErrUserNotFound)
+	})
+}
+
+func TestPersonalDataService_EraseAccount(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	hash, _ := testHasher().Hash("current-pass")
+	user := &model.User{ID: userID, Email: "owner@example.com", PasswordHash: hash}
+	token := model.TokenClaims{ID: uuid.NewString(), SessionID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("1. Success Revokes Presented Token", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockUserRepository(ctrl)
+		data := mocks.NewMockPersonalDataRepository(ctrl)
+		tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+		sessions := mocks.NewMockSessionRepository(ctrl)
+
+		repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+		data.EXPECT().Erase(gomock.Any(), userID).Return(user, nil)
+		sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+		tokens.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(nil)
+
+		revocations := NewRevocationService(tokens, sessions, time.Second, time.Minute)
+		users := NewUserService(repo, revocations, testLoginGuard(), testHasher(), testPolicy(), nil)
+		svc := NewPersonalDataService(nil, data, users, revocations, testLoginGuard(), nil)
+		assert.NoError(t, svc.EraseAccount(context.Background(), userID, "current-pass", token))
+	})
+
+	t.Run("2. Wrong Password Keeps Account", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockUserRepository(ctrl)
+		repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+
+		users := NewUserService(repo, nil, testLoginGuard(), testHasher(), testPolicy(), nil)
+		svc := NewPersonalDataService(nil, mocks.NewMockPersonalDataRepository(ctrl), users, nil, nil, nil)
+		err := svc.EraseAccount(context.Background(), userID, "guess", token)
+		assert.ErrorIs(t, err, ErrInvalidPassword)
+	})
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleNotFound   = errors.New("role not found")
+	ErrUserNotFound   = errors.New("user not found")
+	ErrRoleNotGranted = errors.New("role is not assigned to the user")
+	ErrSelfRoleRevoke = errors.New("cannot revoke your own role")
+)
+
+type cachedPermissions struct {
+	permissions map[string]struct{}
+	expiresAt   time.Time
+}
+
+// RBACService проверяет права пользователей и управляет назначением ролей.
+// Права берутся из базы на каждый запрос, а не из токена, поэтому снятая
+// роль перестаёт действовать сразу на этом экземпляре и не позже чем через
+// cacheTTL на остальных.
+type RBACService struct {
+	roles    repository.RoleRepository
+	users    repository.UserRepository
+	audit    *AuditLog
+	cacheTTL time.Duration
+
+	mu        sync.Mutex
+	cache     map[uuid.UUID]cachedPermissions
+	lastSweep time.Time
+}
+
+func NewRBACService(roles repository.RoleRepository, users repository.UserRepository, audit *AuditLog, cacheTTL time.Duration) *RBACService {
+	return &RBACService{
+		roles:     roles,
+		users:     users,
+		audit:     audit,
+		cacheTTL:  cacheTTL,
+		cache:     make(map[uuid.UUID]cachedPermissions),
+		lastSweep: time.Now(),
+	}
+}
+
+// HasPermission сообщает, даёт ли какая-либо из ролей пользователя право permission
+func (s *RBACService) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+
+	if !ok || now.After(cached.expiresAt) {
+		list, err := s.roles.GetUserPermissions(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+
+		cached = cachedPermissions{
+			permissions: make(map[string]struct{}, len(list)),
+			expiresAt:   now.Add(s.cacheTTL),
+		}
+		for _, p := range list {
+			cached.permissions[p] = struct{}{}
+		}
+
+		s.remember(userID, cached, now)
+	}
+
+	_, granted := cached.permissions[permission]
+	return granted, nil
+}
+
+func (s *RBACService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	return s.roles.List(ctx)
+}
+
+func (s *RBACService) UserRoles(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.roles.GetUserRoles(ctx, userID)
+}
+
+// AssignRole назначает пользователю роль от имени grantedBy
+func (s *RBACService) AssignRole(ctx context.Context, userID uuid.UUID, role string, grantedBy uuid.UUID) error {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+
+	exists, err := s.roles.Exists(ctx, role)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrRoleNotFound
+	}
+
+	if err = s.roles.AssignToUser(ctx, userID, role, grantedBy); err != nil {
+		return err
+	}
+	s.forget(userID)
+	s.audit.Record(ctx, model.AuditEvent{
+		ActorID:  &grantedBy,
+		TargetID: &userID,
+		Action:   model.AuditRoleAssign,
+		Metadata: map[string]string{"role": role},
+	})
+	return nil
+}
+
+// RevokeRole снимает роль с пользователя. Снять роль с самого себя нельзя,
+// чтобы последний администратор не лишил себя доступа случайно.
+func (s *RBACService) RevokeRole(ctx context.Context, userID uuid.UUID, role string, revokedBy uuid.UUID) error {
+	if userID == revokedBy {
+		return ErrSelfRoleRevoke
+	}
+
+	revoked, err := s.roles.RevokeFromUser(ctx, userID, role)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrRoleNotGranted
+	}
+	s.forget(userID)
+	s.audit.Record(ctx, model.AuditEvent{
+		ActorID:  &revokedBy,
+		TargetID: &userID,
+		Action:   model.AuditRoleRevoke,
+		Metadata: map[string]string{"role": role},
+	})
+	return nil
+}
+
+func (s *RBACService) ensureUser(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *RBACService) remember(userID uuid.UUID, cached cachedPermissions, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache[userID] = cached
+
+	if now.Sub(s.lastSweep) < revocationSweepInterval {
+		return
+	}
+	for id, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, id)
+		}
+	}
+	s.lastSweep = now
+}
+
+func (s *RBACService) forget(userID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.mu.Unlock()
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBACService_HasPermission(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	userID := uuid.New()
+
+	// Второй и третий вопрос отвечаются из кэша
+	roles.EXPECT().GetUserPermissions(gomock.Any(), userID).
+		Return([]string{model.PermUsersRead}, nil).
+		Times(1)
+
+	svc := NewRBACService(roles, users, nil, time.Minute)
+	ctx := context.Background()
+
+	ok, err := svc.HasPermission(ctx, userID, model.PermUsersRead)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = svc.HasPermission(ctx, userID, model.PermUsersManage)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = svc.HasPermission(ctx, userID, model.PermUsersRead)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestRBACService_HasPermission_Error(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	userID := uuid.New()
+	roles.EXPECT().GetUserPermissions(gomock.Any(), userID).Return(nil, errors.New("db down"))
+
+	svc := NewRBACService(roles, mocks.NewMockUserRepository(ctrl), nil, time.Minute)
+
+	ok, err := svc.HasPermission(context.Background(), userID, model.PermUsersRead)
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
+
+func TestRBACService_AssignRole(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name         string
+		role         string
+		mockBehavior func(mr *mocks.MockRoleRepository, mu *mocks.MockUserRepository)
+		expectedErr  error
+	}{
+		{
+			name: "Success",
+			role: model.RoleAdmin,
+			mockBehavior: func(mr *mocks.MockRoleRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				mr.EXPECT().Exists(gomock.Any(), model.RoleAdmin).Return(true, nil)
+				mr.EXPECT().AssignToUser(gomock.Any(), userID, model.RoleAdmin, adminID).Return(nil)
+			},
+		},
+		{
+			name: "Unknown User",
+			role: model.RoleAdmin,
+			mockBehavior: func(_ *mocks.MockRoleRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name: "Unknown Role",
+			role: "superuser",
+			mockBehavior: func(mr *mocks.MockRoleRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				mr.EXPECT().Exists(gomock.Any(), "superuser").Return(false, nil)
+			},
+			expectedErr: ErrRoleNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			roles := mocks.NewMockRoleRepository(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(roles, users)
+
+			svc := NewRBACService(roles, users, nil, time.Minute)
+			err := svc.AssignRole(context.Background(), userID, tt.role, adminID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRBACService_RevokeRole(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name         string
+		target       uuid.UUID
+		mockBehavior func(mr *mocks.MockRoleRepository)
+		expectedErr  error
+	}{
+		{
+			name:   "Success",
+			target: userID,
+			mockBehavior: func(mr *mocks.MockRoleRepository) {
+				mr.EXPECT().RevokeFromUser(gomock.Any(), userID, model.RoleAdmin).Return(true, nil)
+			},
+		},
+		{
+			name:   "Role Not Assigned",
+			target: userID,
+			mockBehavior: func(mr *mocks.MockRoleRepository) {
+				mr.EXPECT().RevokeFromUser(gomock.Any(), userID, model.RoleAdmin).Return(false, nil)
+			},
+			expectedErr: ErrRoleNotGranted,
+		},
+		{
+			name:         "Revoke From Self",
+			target:       adminID,
+			mockBehavior: func(_ *mocks.MockRoleRepository) {},
+			expectedErr:  ErrSelfRoleRevoke,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			roles := mocks.NewMockRoleRepository(ctrl)
+			tt.mockBehavior(roles)
+
+			svc := NewRBACService(roles, mocks.NewMockUserRepository(ctrl), nil, time.Minute)
+			err := svc.RevokeRole(context.Background(), tt.target, model.RoleAdmin, adminID)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRBACService_RoleChangeInvalidatesCache(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	roles := mocks.NewMockRoleRepository(ctrl)
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	gomock.InOrder(
+		roles.EXPECT().GetUserPermissions(gomock.Any(), userID).Return([]string{model.PermUsersManage}, nil),
+		roles.EXPECT().RevokeFromUser(gomock.Any(), userID, model.RoleAdmin).Return(true, nil),
+		roles.EXPECT().GetUserPermissions(gomock.Any(), userID).Return(nil, nil),
+	)
+
+	svc := NewRBACService(roles, mocks.NewMockUserRepository(ctrl), nil, time.Hour)
+	ctx := context.Background()
+
+	ok, err := svc.HasPermission(ctx, userID, model.PermUsersManage)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, svc.RevokeRole(ctx, userID, model.RoleAdmin, adminID))
+
+	ok, err = svc.HasPermission(ctx, userID, model.PermUsersManage)
+	assert.NoError(t, err)
+	assert.False(t, ok, "revoked role must stop working immediately")
+}
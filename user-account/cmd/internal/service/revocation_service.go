@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const revocationSweepInterval = time.Minute
+
+// RevocationService хранит отзывы токенов и сессий в Postgres и держит
+// перед базой кэш в памяти, чтобы не ходить в неё на каждый запрос.
+// Отзывы, сделанные через этот экземпляр, видны сразу; сделанные другими
+// экземплярами сервиса - не позже чем через cacheTTL.
+type RevocationService struct {
+	tokens    repository.RevokedTokenRepository
+	sessions  repository.SessionRepository
+	cacheTTL  time.Duration
+	retention time.Duration
+
+	mu        sync.Mutex
+	revoked   map[string]time.Time
+	valid     map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewRevocationService создаёт сервис отзыва. cacheTTL - сколько доверять
+// ответу "не отозван", accessTTL - сколько помнить отозванные ключи в памяти
+// (дольше access-токен всё равно не живёт).
+func NewRevocationService(
+	tokens repository.RevokedTokenRepository,
+	sessions repository.SessionRepository,
+	cacheTTL, accessTTL time.Duration,
+) *RevocationService {
+	return &RevocationService{
+		tokens:    tokens,
+		sessions:  sessions,
+		cacheTTL:  cacheTTL,
+		retention: accessTTL,
+		revoked:   make(map[string]time.Time),
+		valid:     make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// IsRevoked сообщает, отозван ли сам токен или сессия, к которой он привязан
+func (s *RevocationService) IsRevoked(ctx context.Context, tokenID string, sessionID uuid.UUID) (bool, error) {
+	tokenKey, sessionKey := "jti:"+tokenID, "sid:"+sessionID.String()
+	now := time.Now()
+
+	s.mu.Lock()
+	_, tokenRevoked := s.revoked[tokenKey]
+	_, sessionRevoked := s.revoked[sessionKey]
+	validUntil, checked := s.valid[tokenKey]
+	s.sweepIfDue(now)
+	s.mu.Unlock()
+
+	if tokenRevoked || sessionRevoked {
+		return true, nil
+	}
+	if checked && now.Before(validUntil) {
+		return false, nil
+	}
+
+	revoked, err := s.tokens.IsRevoked(ctx, tokenID)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		s.remember(tokenKey)
+		return true, nil
+	}
+
+	revoked, err = s.sessions.IsFamilyRevoked(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+	if revoked {
+		s.remember(sessionKey)
+		return true, nil
+	}
+
+	s.mu.Lock()
+	s.valid[tokenKey] = now.Add(s.cacheTTL)
+	s.mu.Unlock()
+	return false, nil
+}
+
+// RevokeToken отзывает один access-токен до истечения его срока
+func (s *RevocationService) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if err := s.tokens.Revoke(ctx, tokenID, expiresAt); err != nil {
+		return err
+	}
+	s.remember("jti:" + tokenID)
+	return nil
+}
+
+// RevokeSession отзывает цепочку сессий вместе со всеми её токенами
+func (s *RevocationService) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	if err := s.sessions.RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+	s.remember("sid:" + sessionID.String())
+	return nil
+}
+
+// RevokeUserSessions отзывает все сессии пользователя
+func (s *RevocationService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	// Какие сессии были у пользователя, кэш не знает, поэтому забываем все
+	// положительные проверки - следующие запросы сверятся с базой
+	s.mu.Lock()
+	s.valid = make(map[string]time.Time)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *RevocationService) remember(key string) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.revoked[key] = now.Add(s.retention)
+	delete(s.valid, key)
+	s.sweepIfDue(now)
+}
+
+// PurgeExpired удаляет из базы отзывы истёкших токенов и возвращает, сколько
+// их было. Заодно чистит кэш, чтобы он не копился на экземпляре без запросов.
+func (s *RevocationService) PurgeExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	s.sweep(time.Now())
+	s.mu.Unlock()
+
+	return s.tokens.DeleteExpired(ctx, time.Now())
+}
+
+// Run очищает сразу и затем раз в interval, пока не отменён ctx. Ошибки
+// только пишутся в лог, следующий проход повторит попытку.
+func (s *RevocationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeExpired(ctx)
+		switch {
+		case err != nil:
+			log.Printf("revoked tokens purge failed: %v", err)
+		case purged > 0:
+			log.Printf("purged %d expired revoked tokens", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweepIfDue чистит кэш не чаще раза в revocationSweepInterval. Вызывается
+// под s.mu.
+func (s *RevocationService) sweepIfDue(now time.Time) {
+	if now.Sub(s.lastSweep) >= revocationSweepInterval {
+		s.sweep(now)
+	}
+}
+
+// sweep удаляет из кэша истёкшие записи. Вызывается под s.mu.
+func (s *RevocationService) sweep(now time.Time) {
+	for k, until := range s.revoked {
+		if now.After(until) {
+			delete(s.revoked, k)
+		}
+	}
+	for k, until := range s.valid {
+		if now.After(until) {
+			delete(s.valid, k)
+		}
+	}
+	s.lastSweep = now
+}
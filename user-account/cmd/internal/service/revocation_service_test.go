@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevocationService_IsRevoked(t *testing.T) {
+	t.Parallel()
+
+	tokenID := uuid.NewString()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name         string
+		mockBehavior func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository)
+		want         bool
+		wantErr      bool
+	}{
+		{
+			name: "Valid Token",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository) {
+				mt.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(false, nil)
+				ms.EXPECT().IsFamilyRevoked(gomock.Any(), sessionID).Return(false, nil)
+			},
+			want: false,
+		},
+		{
+			name: "Revoked Token",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, _ *mocks.MockSessionRepository) {
+				mt.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "Revoked Session",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, ms *mocks.MockSessionRepository) {
+				mt.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(false, nil)
+				ms.EXPECT().IsFamilyRevoked(gomock.Any(), sessionID).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "Storage Error",
+			mockBehavior: func(mt *mocks.MockRevokedTokenRepository, _ *mocks.MockSessionRepository) {
+				mt.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(false, errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(tokens, sessions)
+
+			svc := NewRevocationService(tokens, sessions, time.Minute, time.Minute)
+			revoked, err := svc.IsRevoked(context.Background(), tokenID, sessionID)
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+}
+
+func TestRevocationService_Cache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	svc := NewRevocationService(tokens, sessions, time.Minute, time.Minute)
+	ctx := context.Background()
+
+	tokenID := uuid.NewString()
+	sessionID := uuid.New()
+
+	// Первая проверка идёт в базу, повторная отвечается из кэша
+	tokens.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(false, nil).Times(1)
+	sessions.EXPECT().IsFamilyRevoked(gomock.Any(), sessionID).Return(false, nil).Times(1)
+
+	for i := 0; i < 3; i++ {
+		revoked, err := svc.IsRevoked(ctx, tokenID, sessionID)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	}
+
+	// Локальный отзыв сессии виден сразу, несмотря на закэшированный ответ
+	sessions.EXPECT().RevokeFamily(gomock.Any(), sessionID).Return(nil)
+	assert.NoError(t, svc.RevokeSession(ctx, sessionID))
+
+	revoked, err := svc.IsRevoked(ctx, tokenID, sessionID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevocationService_RevokeUserSessionsDropsCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	svc := NewRevocationService(tokens, sessions, time.Minute, time.Minute)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	tokenID := uuid.NewString()
+	sessionID := uuid.New()
+
+	tokens.EXPECT().IsRevoked(gomock.Any(), tokenID).Return(false, nil).Times(2)
+	gomock.InOrder(
+		sessions.EXPECT().IsFamilyRevoked(gomock.Any(), sessionID).Return(false, nil),
+		sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil),
+		sessions.EXPECT().IsFamilyRevoked(gomock.Any(), sessionID).Return(true, nil),
+	)
+
+	revoked, err := svc.IsRevoked(ctx, tokenID, sessionID)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	assert.NoError(t, svc.RevokeUserSessions(ctx, userID))
+
+	revoked, err = svc.IsRevoked(ctx, tokenID, sessionID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevocationService_PurgeExpired(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+	svc := NewRevocationService(tokens, mocks.NewMockSessionRepository(ctrl), time.Millisecond, time.Millisecond)
+	ctx := context.Background()
+
+	tokenID := uuid.NewString()
+	tokens.EXPECT().Revoke(gomock.Any(), tokenID, gomock.Any()).Return(nil)
+	assert.NoError(t, svc.RevokeToken(ctx, tokenID, time.Now().Add(time.Millisecond)))
+	time.Sleep(5 * time.Millisecond)
+
+	tokens.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, now time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now(), now, time.Second)
+			return 1, nil
+		})
+
+	purged, err := svc.PurgeExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Empty(t, svc.revoked, "expired revocations are dropped from the cache")
+}
+
+func TestRevocationService_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+	svc := NewRevocationService(tokens, mocks.NewMockSessionRepository(ctrl), time.Minute, time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Первый проход идёт сразу, не дожидаясь интервала
+	tokens.EXPECT().DeleteExpired(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, time.Time) (int64, error) {
+			cancel()
+			return 0, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		svc.Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const opaqueTokenBytes = 32
+
+// newOpaqueToken генерирует случайный непрозрачный токен для передачи клиенту
+func newOpaqueToken() (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken - хэш непрозрачного токена для хранения в базе.
+// Токены высокоэнтропийные, поэтому соль и медленный хэш не нужны.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"image/png"
+	"log"
+	"strings"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/google/uuid"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	mfaChallengePurpose = "mfa_challenge"
+
+	totpPeriod        = 30 * time.Second
+	totpSkew          = 1
+	totpQRSize        = 256
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var (
+	ErrTOTPAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled      = errors.New("two-factor enrollment has not been started")
+	ErrTOTPNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrInvalidMFAToken      = errors.New("invalid or expired two-factor login token")
+)
+
+// TwoFactorConfig - настройки второго фактора
+type TwoFactorConfig struct {
+	// Issuer - название сервиса в приложении-аутентификаторе
+	Issuer string
+	// ChallengeTTL - сколько после ввода пароля можно ввести код
+	ChallengeTTL time.Duration
+}
+
+// TwoFactorService управляет TOTP-фактором и кодами восстановления и
+// проверяет второй шаг входа
+type TwoFactorService struct {
+	totp   repository.TOTPRepository
+	users  repository.UserRepository
+	tokens *signedtoken.Signer
+	guard  *LoginGuard
+	cfg    TwoFactorConfig
+}
+
+func NewTwoFactorService(
+	totpRepo repository.TOTPRepository,
+	users repository.UserRepository,
+	tokens *signedtoken.Signer,
+	guard *LoginGuard,
+	cfg TwoFactorConfig,
+) *TwoFactorService {
+	return &TwoFactorService{
+		totp:   totpRepo,
+		users:  users,
+		tokens: tokens,
+		guard:  guard,
+		cfg:    cfg,
+	}
+}
+
+// Enroll выпускает новый секрет. Фактор начнёт спрашиваться при входе только
+// после подтверждения кодом из приложения.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uuid.UUID) (*model.TOTPEnrollment, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	current, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Enabled() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.cfg.Issuer,
+		AccountName: user.Email,
+		Period:      uint(totpPeriod.Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.totp.SavePending(ctx, userID, key.Secret()); err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(totpQRSize, totpQRSize)
+	if err != nil {
+		return nil, err
+	}
+	var qr bytes.Buffer
+	if err = png.Encode(&qr, img); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPEnrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: base64.StdEncoding.EncodeToString(qr.Bytes()),
+	}, nil
+}
+
+// Confirm включает фактор по первому коду из приложения и возвращает коды
+// восстановления. Коды показываются один раз, в базе хранятся только хэши.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	current, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Enabled() {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if current == nil {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	step, ok := matchTOTP(current.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	confirmed, err := s.totp.Confirm(ctx, userID, step, hashes)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, ErrTOTPNotEnrolled
+	}
+	return codes, nil
+}
+
+// Disable выключает фактор. Нужен действующий код или код восстановления,
+// чтобы украденного access-токена не хватило для отключения. Неверные коды
+// считаются тем же счётчиком, что и на втором шаге входа.
+func (s *TwoFactorService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	current, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !current.Enabled() {
+		return ErrTOTPNotEnabled
+	}
+
+	account := twoFactorAccount(userID)
+	if err = s.guard.Check(ctx, account, ""); err != nil {
+		return err
+	}
+	if err = s.verifyCode(ctx, current, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			if failErr := s.guard.Fail(ctx, account, ""); failErr != nil {
+				log.Printf("failed to record two-factor code failure: %v", failErr)
+			}
+		}
+		return err
+	}
+	if err = s.guard.Succeed(ctx, account); err != nil {
+		return err
+	}
+	return s.totp.Delete(ctx, userID)
+}
+
+// Enabled сообщает, нужен ли пользователю второй шаг входа
+func (s *TwoFactorService) Enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	current, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return current.Enabled(), nil
+}
+
+// IssueChallenge выдаёт короткоживущий токен, подтверждающий, что пароль
+// уже проверен. Его предъявляют вместе с кодом на втором шаге.
+func (s *TwoFactorService) IssueChallenge(userID uuid.UUID) (string, error) {
+	return s.tokens.Sign(mfaChallengePurpose, signedtoken.Claims{
+		ID:        uuid.New(),
+		Subject:   userID.String(),
+		ExpiresAt: time.Now().Add(s.cfg.ChallengeTTL),
+	})
+}
+
+// ChallengeSubject проверяет подпись и срок MFAToken и возвращает ID
+// пользователя, не проверяя код
+func (s *TwoFactorService) ChallengeSubject(mfaToken string) (uuid.UUID, error) {
+	claims, err := s.tokens.Verify(mfaChallengePurpose, mfaToken, time.Now())
+	if err != nil {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+	return userID, nil
+}
+
+// Authenticate проверяет второй шаг входа и возвращает ID пользователя
+func (s *TwoFactorService) Authenticate(ctx context.Context, mfaToken, code string) (uuid.UUID, error) {
+	userID, err := s.ChallengeSubject(mfaToken)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	current, err := s.totp.Get(ctx, userID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !current.Enabled() {
+		return uuid.Nil, ErrInvalidMFAToken
+	}
+
+	if err = s.verifyCode(ctx, current, code); err != nil {
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+// verifyCode принимает либо шестизначный TOTP-код, либо код восстановления.
+// Оба одноразовые: для TOTP запоминается использованное окно.
+func (s *TwoFactorService) verifyCode(ctx context.Context, current *model.TOTP, code string) error {
+	code = strings.TrimSpace(code)
+
+	if isTOTPCode(code) {
+		step, ok := matchTOTP(current.Secret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		fresh, err := s.totp.MarkStepUsed(ctx, current.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	consumed, err := s.totp.ConsumeRecoveryCode(ctx, current.UserID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// matchTOTP ищет code в соседних окнах и возвращает номер совпавшего окна
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    uint(totpPeriod.Seconds()),
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		at := now.Add(time.Duration(skew) * totpPeriod)
+		expected, err := totp.GenerateCodeCustom(secret, at, opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / int64(totpPeriod.Seconds()), true
+		}
+	}
+	return 0, false
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRecoveryCode генерирует код вида xxxx-xxxx-xxxx-xxxx (80 бит)
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))
+	return raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16], nil
+}
+
+// normalizeRecoveryCode прощает регистр, дефисы и пробелы при вводе
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func currentTOTPCode(t *testing.T) string {
+	t.Helper()
+	code, err := totp.GenerateCode(testTOTPSecret, time.Now())
+	if err != nil {
+		t.Fatalf("failed to generate totp code: %v", err)
+	}
+	return code
+}
+
+func TestTwoFactorService_Enroll(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	userID := uuid.New()
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Email: "totp@example.com"}, nil)
+
+	totpRepo := mocks.NewMockTOTPRepository(ctrl)
+	totpRepo.EXPECT().Get(gomock.Any(), userID).Return(nil, nil)
+	var saved string
+	totpRepo.EXPECT().
+		SavePending(gomock.Any(), userID, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id uuid.UUID, secret string) error {
+			saved = secret
+			return nil
+		})
+
+	svc := NewTwoFactorService(totpRepo, users, signedtoken.New("secret"), testLoginGuard(), TwoFactorConfig{Issuer: "Actium", ChallengeTTL: time.Minute})
+	enrollment, err := svc.Enroll(context.Background(), userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, saved, enrollment.Secret)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/Actium:totp@example.com")
+	assert.Contains(t, enrollment.URI, "secret="+saved)
+	assert.NotEmpty(t, enrollment.QRCode)
+}
+
+func TestTwoFactorService_Confirm(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	confirmedAt := time.Now()
+	pending := &model.TOTP{UserID: userID, Secret: testTOTPSecret}
+
+	tests := []struct {
+		name         string
+		code         func(t *testing.T) string
+		mockBehavior func(m *mocks.MockTOTPRepository)
+		expectedErr  error
+	}{
+		{
+			name: "1. Valid Code Enables Factor",
+			code: currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(pending, nil)
+				m.EXPECT().
+					Confirm(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id uuid.UUID, step int64, hashes []string) (bool, error) {
+						assert.Len(t, hashes, recoveryCodeCount)
+						assert.InDelta(t, time.Now().Unix()/30, step, 1)
+						return true, nil
+					})
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "2. Wrong Code",
+			code: func(*testing.T) string { return "000000" },
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(pending, nil)
+			},
+			expectedErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name: "3. Not Enrolled",
+			code: currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedErr: ErrTOTPNotEnrolled,
+		},
+		{
+			name: "4. Already Enabled",
+			code: currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(&model.TOTP{UserID: userID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}, nil)
+			},
+			expectedErr: ErrTOTPAlreadyEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			totpRepo := mocks.NewMockTOTPRepository(ctrl)
+			tt.mockBehavior(totpRepo)
+
+			svc := NewTwoFactorService(totpRepo, mocks.NewMockUserRepository(ctrl), signedtoken.New("secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+			codes, err := svc.Confirm(context.Background(), userID, tt.code(t))
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				assert.Len(t, codes, recoveryCodeCount)
+				assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`, codes[0])
+			}
+		})
+	}
+}
+
+func TestTwoFactorService_Authenticate(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	confirmedAt := time.Now()
+	enabled := &model.TOTP{UserID: userID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}
+	tokens := signedtoken.New("secret")
+
+	tests := []struct {
+		name         string
+		mfaToken     func(svc *TwoFactorService) string
+		code         func(t *testing.T) string
+		mockBehavior func(m *mocks.MockTOTPRepository)
+		expectedErr  error
+	}{
+		{
+			name:     "1. Valid TOTP Code",
+			mfaToken: func(svc *TwoFactorService) string { token, _ := svc.IssueChallenge(userID); return token },
+			code:     currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				m.EXPECT().MarkStepUsed(gomock.Any(), userID, gomock.Any()).Return(true, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "2. Replayed TOTP Code",
+			mfaToken: func(svc *TwoFactorService) string { token, _ := svc.IssueChallenge(userID); return token },
+			code:     currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				m.EXPECT().MarkStepUsed(gomock.Any(), userID, gomock.Any()).Return(false, nil)
+			},
+			expectedErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name:     "3. Recovery Code Is Normalized",
+			mfaToken: func(svc *TwoFactorService) string { token, _ := svc.IssueChallenge(userID); return token },
+			code:     func(*testing.T) string { return " ABCD-efgh-ijkl-mnop " },
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				m.EXPECT().ConsumeRecoveryCode(gomock.Any(), userID, hashToken("abcdefghijklmnop")).Return(true, nil)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "4. Used Recovery Code",
+			mfaToken: func(svc *TwoFactorService) string { token, _ := svc.IssueChallenge(userID); return token },
+			code:     func(*testing.T) string { return "abcd-efgh-ijkl-mnop" },
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil)
+				m.EXPECT().ConsumeRecoveryCode(gomock.Any(), userID, gomock.Any()).Return(false, nil)
+			},
+			expectedErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			name:         "5. Forged MFA Token",
+			mfaToken:     func(*TwoFactorService) string { return "forged.token" },
+			code:         currentTOTPCode,
+			mockBehavior: func(*mocks.MockTOTPRepository) {},
+			expectedErr:  ErrInvalidMFAToken,
+		},
+		{
+			name:     "6. Factor Disabled Since Password Step",
+			mfaToken: func(svc *TwoFactorService) string { token, _ := svc.IssueChallenge(userID); return token },
+			code:     currentTOTPCode,
+			mockBehavior: func(m *mocks.MockTOTPRepository) {
+				m.EXPECT().Get(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedErr: ErrInvalidMFAToken,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			totpRepo := mocks.NewMockTOTPRepository(ctrl)
+			tt.mockBehavior(totpRepo)
+
+			svc := NewTwoFactorService(totpRepo, mocks.NewMockUserRepository(ctrl), tokens, testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+			id, err := svc.Authenticate(context.Background(), tt.mfaToken(svc), tt.code(t))
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			if tt.expectedErr == nil {
+				assert.Equal(t, userID, id)
+			}
+		})
+	}
+}
+
+func TestTwoFactorService_Disable(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	confirmedAt := time.Now()
+	enabled := &model.TOTP{UserID: userID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}
+	totpRepo := mocks.NewMockTOTPRepository(ctrl)
+	totpRepo.EXPECT().Get(gomock.Any(), userID).Return(enabled, nil).Times(4)
+	totpRepo.EXPECT().ConsumeRecoveryCode(gomock.Any(), userID, gomock.Any()).Return(false, nil).Times(3)
+
+	svc := NewTwoFactorService(totpRepo, mocks.NewMockUserRepository(ctrl), signedtoken.New("secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, svc.Disable(ctx, userID, "abcd-efgh-ijkl-mnop"), ErrInvalidTwoFactorCode)
+	}
+
+	// После порога код не проверяется вовсе, даже верный
+	assert.ErrorIs(t, svc.Disable(ctx, userID, currentTOTPCode(t)), ErrLoginLocked)
+}
+
+func TestAuthService_LoginTwoFactor(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	const password = "correct-pass"
+	hash, _ := testHasher().Hash(password)
+	confirmedAt := time.Now()
+	user := &model.User{ID: uuid.New(), Email: "mfa@example.com", PasswordHash: hash, Roles: []string{model.RoleUser}}
+
+	repo := mocks.NewMockUserRepository(ctrl)
+	repo.EXPECT().GetByEmail(gomock.Any(), user.Email).Return(user, nil)
+	repo.EXPECT().GetByID(gomock.Any(), user.ID).Return(user, nil)
+
+	totpRepo := mocks.NewMockTOTPRepository(ctrl)
+	totpRepo.EXPECT().Get(gomock.Any(), user.ID).Return(&model.TOTP{UserID: user.ID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}, nil).Times(2)
+	totpRepo.EXPECT().MarkStepUsed(gomock.Any(), user.ID, gomock.Any()).Return(true, nil)
+
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	sessions.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+	twoFactor := NewTwoFactorService(totpRepo, repo, signedtoken.New("action-secret"), testLoginGuard(), TwoFactorConfig{ChallengeTTL: time.Minute})
+	svc := NewAuthService(repo, sessions, testOrganizations(ctrl), revocations, nil, twoFactor, testLoginGuard(), testHasher(), testPolicy(), testSigner(t, "secret"), nil, AuthConfig{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+
+	result, err := svc.Login(context.Background(), user.Email, password, model.ClientInfo{})
+	assert.NoError(t, err)
+	assert.Nil(t, result.Tokens, "tokens must not be issued before the second factor")
+	assert.NotEmpty(t, result.MFAToken)
+
+	tokens, err := svc.LoginTwoFactor(context.Background(), result.MFAToken, currentTOTPCode(t), model.ClientInfo{})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	_, err = svc.LoginTwoFactor(context.Background(), result.MFAToken+"x", "123456", model.ClientInfo{})
+	assert.ErrorIs(t, err, ErrInvalidMFAToken)
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/passpolicy"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// userImportHashWorkers - сколько паролей импорта хэшируется одновременно.
+// Хэш argon2id занимает десятки мегабайт памяти, поэтому число ограничено.
+const userImportHashWorkers = 4
+
+// UserImportService создаёт пользователей пачкой из файла, например при
+// подключении новой компании
+type UserImportService struct {
+	users         repository.UserRepository
+	verifications *EmailVerificationService
+	hasher        PasswordHasher
+	policy        PasswordPolicy
+}
+
+func NewUserImportService(
+	users repository.UserRepository,
+	verifications *EmailVerificationService,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+) *UserImportService {
+	return &UserImportService{users: users, verifications: verifications, hasher: hasher, policy: policy}
+}
+
+// Import создаёт пользователей из строк, поля которых уже проверены.
+// Строка пропускается, если аккаунт с её почтой уже есть или почта
+// встретилась в файле раньше; строка с занятым ником или паролем не по
+// политике считается ошибочной. Пользователи создаются пачками по
+// model.UserImportChunkSize в отдельных транзакциях, с orgID - сразу
+// участниками организации. С dryRun только проверяет строки.
+func (s *UserImportService) Import(ctx context.Context, orgID uuid.UUID, rows []model.UserImportRow, dryRun bool) ([]model.UserImportResult, error) {
+	results := make([]model.UserImportResult, 0, len(rows))
+
+	existingEmails, existingNicknames, err := s.existing(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	seenEmails := make(map[string]struct{}, len(rows))
+	seenNicknames := make(map[string]struct{}, len(rows))
+	var accepted []model.UserImportRow
+	for _, row := range rows {
+		result := model.UserImportResult{Line: row.Line, Email: row.Email}
+
+		_, existing := existingEmails[row.Email]
+		_, duplicate := seenEmails[row.Email]
+		_, nicknameTaken := existingNicknames[row.Nickname]
+		_, nicknameRepeated := seenNicknames[row.Nickname]
+		switch {
+		case existing:
+			result.Status, result.Error = model.UserImportSkipped, repository.ErrEmailTaken.Error()
+		case duplicate:
+			result.Status, result.Error = model.UserImportSkipped, "email is repeated in the file"
+		case nicknameTaken:
+			result.Status, result.Error = model.UserImportFailed, repository.ErrNicknameTaken.Error()
+		case nicknameRepeated:
+			result.Status, result.Error = model.UserImportFailed, "nickname is repeated in the file"
+		}
+		seenEmails[row.Email] = struct{}{}
+		seenNicknames[row.Nickname] = struct{}{}
+		if result.Status != "" {
+			results = append(results, result)
+			continue
+		}
+
+		if err = s.policy.Check(ctx, row.Password, row.Email, row.Nickname); err != nil {
+			if !errors.Is(err, passpolicy.ErrPolicyViolated) {
+				return nil, err
+			}
+			results = append(results, model.UserImportResult{Line: row.Line, Email: row.Email, Status: model.UserImportFailed, Error: err.Error()})
+			continue
+		}
+		accepted = append(accepted, row)
+	}
+
+	if dryRun {
+		for _, row := range accepted {
+			results = append(results, model.UserImportResult{Line: row.Line, Email: row.Email, Status: model.UserImportCreated})
+		}
+		return results, nil
+	}
+
+	var org *uuid.UUID
+	if orgID != uuid.Nil {
+		org = &orgID
+	}
+	for start := 0; start < len(accepted); start += model.UserImportChunkSize {
+		end := min(start+model.UserImportChunkSize, len(accepted))
+		chunkResults, err := s.createChunk(ctx, accepted[start:end], org)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunkResults...)
+	}
+	return results, nil
+}
+
+// createChunk создаёт пачку пользователей одной транзакцией. Если пачка
+// не сохранилась, все её строки отмечаются ошибочными, а импорт
+// продолжается со следующей.
+func (s *UserImportService) createChunk(ctx context.Context, rows []model.UserImportRow, orgID *uuid.UUID) ([]model.UserImportResult, error) {
+	hashes, err := s.hashPasswords(rows)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*model.User, len(rows))
+	for i, row := range rows {
+		users[i] = &model.User{
+			ID:           uuid.New(),
+			Email:        row.Email,
+			Nickname:     row.Nickname,
+			PasswordHash: hashes[i],
+			Roles:        []string{model.RoleUser},
+		}
+	}
+
+	results := make([]model.UserImportResult, len(rows))
+	if err := s.users.CreateBatch(ctx, users, orgID); err != nil {
+		// Почту или ник могли занять между проверкой и вставкой
+		message := "failed to create user"
+		if errors.Is(err, repository.ErrEmailTaken) || errors.Is(err, repository.ErrNicknameTaken) {
+			message = err.Error()
+		}
+		log.Printf("failed to import %d users: %v", len(users), err)
+		for i, row := range rows {
+			results[i] = model.UserImportResult{Line: row.Line, Email: row.Email, Status: model.UserImportFailed, Error: message}
+		}
+		return results, nil
+	}
+
+	for i, row := range rows {
+		results[i] = model.UserImportResult{Line: row.Line, Email: row.Email, Status: model.UserImportCreated}
+		// Аккаунт уже создан: если письмо не ушло, пользователь запросит его повторно
+		if err := s.verifications.Send(ctx, users[i]); err != nil {
+			log.Printf("failed to send verification email to user %s: %v", users[i].ID, err)
+		}
+	}
+	return results, nil
+}
+
+// hashPasswords хэширует пароли строк параллельно, не больше
+// userImportHashWorkers за раз
+func (s *UserImportService) hashPasswords(rows []model.UserImportRow) ([]string, error) {
+	hashes := make([]string, len(rows))
+	errs := make([]error, len(rows))
+	workers := make(chan struct{}, userImportHashWorkers)
+	var wg sync.WaitGroup
+	for i, row := range rows {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			hashes[i], errs[i] = s.hasher.Hash(row.Password)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// existing возвращает почты и ники из строк, которые уже заняты
+func (s *UserImportService) existing(ctx context.Context, rows []model.UserImportRow) (map[string]struct{}, map[string]struct{}, error) {
+	emails := make([]string, len(rows))
+	nicknames := make([]string, len(rows))
+	for i, row := range rows {
+		emails[i] = row.Email
+		nicknames[i] = row.Nickname
+	}
+
+	accounts, err := s.users.ExistingAccounts(ctx, emails, nicknames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	takenEmails := make(map[string]struct{}, len(accounts))
+	takenNicknames := make(map[string]struct{}, len(accounts))
+	for _, account := range accounts {
+		takenEmails[account.Email] = struct{}{}
+		takenNicknames[account.Nickname] = struct{}{}
+	}
+	return takenEmails, takenNicknames, nil
+}
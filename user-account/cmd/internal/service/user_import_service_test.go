@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+	"user-account/cmd/internal/signedtoken"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserImportService_Import(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	rows := []model.UserImportRow{
+		{Line: 2, Email: "new@test.com", Nickname: "newbie", Password: "secret-1"},
+		{Line: 3, Email: "old@test.com", Nickname: "oldie", Password: "secret-2"},
+		{Line: 4, Email: "new@test.com", Nickname: "again", Password: "secret-3"},
+		{Line: 5, Email: "taken@test.com", Nickname: "busy_nick", Password: "secret-4"},
+		{Line: 6, Email: "weak@test.com", Nickname: "weakling", Password: "123"},
+	}
+	expected := map[int]string{
+		2: model.UserImportCreated,
+		3: model.UserImportSkipped,
+		4: model.UserImportSkipped,
+		5: model.UserImportFailed,
+		6: model.UserImportFailed,
+	}
+
+	tests := []struct {
+		name         string
+		dryRun       bool
+		mockBehavior func(mu *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer)
+	}{
+		{
+			name:   "1. Dry Run Creates Nothing",
+			dryRun: true,
+			mockBehavior: func(mu *mocks.MockUserRepository, _ *mocks.MockEmailVerificationRepository, _ *mocks.MockMailer) {
+				mu.EXPECT().ExistingAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]model.User{{Email: "old@test.com", Nickname: "oldie"}, {Email: "someone@test.com", Nickname: "busy_nick"}}, nil)
+			},
+		},
+		{
+			name: "2. Import",
+			mockBehavior: func(mu *mocks.MockUserRepository, mv *mocks.MockEmailVerificationRepository, mm *mocks.MockMailer) {
+				mu.EXPECT().ExistingAccounts(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return([]model.User{{Email: "old@test.com", Nickname: "oldie"}, {Email: "someone@test.com", Nickname: "busy_nick"}}, nil)
+				mu.EXPECT().CreateBatch(gomock.Any(), gomock.Any(), &orgID).
+					DoAndReturn(func(_ context.Context, users []*model.User, _ *uuid.UUID) error {
+						require.Len(t, users, 1)
+						assert.Equal(t, "newbie", users[0].Nickname)
+						assert.Equal(t, []string{model.RoleUser}, users[0].Roles)
+						assertPasswordHash(t, users[0].PasswordHash, "secret-1")
+						return nil
+					})
+				mv.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mm.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			users := mocks.NewMockUserRepository(ctrl)
+			verificationRepo := mocks.NewMockEmailVerificationRepository(ctrl)
+			mail := mocks.NewMockMailer(ctrl)
+			tt.mockBehavior(users, verificationRepo, mail)
+
+			verifications := NewEmailVerificationService(users, verificationRepo, signedtoken.New("action-secret"), mail, time.Hour, "https://app.example.com")
+			svc := NewUserImportService(users, verifications, testHasher(), testPolicy())
+			results, err := svc.Import(context.Background(), orgID, rows, tt.dryRun)
+
+			require.NoError(t, err)
+			require.Len(t, results, len(rows))
+			for _, r := range results {
+				assert.Equal(t, expected[r.Line], r.Status, "line %d", r.Line)
+			}
+		})
+	}
+}
+
+func TestUserImportService_Import_Chunks(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	rows := make([]model.UserImportRow, model.UserImportChunkSize+1)
+	for i := range rows {
+		rows[i] = model.UserImportRow{
+			Line:     i + 2,
+			Email:    fmt.Sprintf("user%d@test.com", i),
+			Nickname: fmt.Sprintf("user_%d", i),
+			Password: "secret-pass",
+		}
+	}
+
+	users := mocks.NewMockUserRepository(ctrl)
+	users.EXPECT().ExistingAccounts(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	// Первая пачка откатилась целиком, вторая сохранилась; без организации
+	// пользователи ни к кому не добавляются
+	gomock.InOrder(
+		users.EXPECT().CreateBatch(gomock.Any(), gomock.Len(model.UserImportChunkSize), nil).Return(repository.ErrNicknameTaken),
+		users.EXPECT().CreateBatch(gomock.Any(), gomock.Len(1), nil).Return(nil),
+	)
+	verificationRepo := mocks.NewMockEmailVerificationRepository(ctrl)
+	verificationRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+
+	verifications := NewEmailVerificationService(users, verificationRepo, signedtoken.New("action-secret"), mocks.NewMockMailer(ctrl), time.Hour, "https://app.example.com")
+	svc := NewUserImportService(users, verifications, testHasher(), testPolicy())
+	results, err := svc.Import(context.Background(), uuid.Nil, rows, false)
+
+	require.NoError(t, err)
+	report := model.NewUserImportReport(results, false)
+	assert.Equal(t, model.UserImportChunkSize+1, report.Total)
+	assert.Equal(t, 1, report.Created)
+	assert.Equal(t, model.UserImportChunkSize, report.Failed)
+	assert.Equal(t, repository.ErrNicknameTaken.Error(), report.Rows[0].Error)
+	assert.Equal(t, model.UserImportCreated, report.Rows[model.UserImportChunkSize].Status)
+}
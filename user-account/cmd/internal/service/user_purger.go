@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"user-account/cmd/internal/repository"
+)
+
+// UserPurger окончательно удаляет пользователей, которые пробыли удалёнными
+// дольше срока хранения. До этого их можно восстановить.
+type UserPurger struct {
+	users     repository.UserRepository
+	retention time.Duration
+}
+
+func NewUserPurger(users repository.UserRepository, retention time.Duration) *UserPurger {
+	return &UserPurger{users: users, retention: retention}
+}
+
+// Purge удаляет пользователей с истёкшим сроком хранения и возвращает,
+// сколько их было
+func (p *UserPurger) Purge(ctx context.Context) (int64, error) {
+	return p.users.Purge(ctx, time.Now().Add(-p.retention))
+}
+
+// Run очищает сразу и затем раз в interval, пока не отменён ctx. Первый
+// проход не ждёт интервала, иначе частые перезапуски откладывали бы очистку.
+// Ошибки только пишутся в лог, следующий проход повторит попытку.
+func (p *UserPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.Purge(ctx)
+		switch {
+		case err != nil:
+			log.Printf("deleted users purge failed: %v", err)
+		case purged > 0:
+			log.Printf("purged %d deleted users", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
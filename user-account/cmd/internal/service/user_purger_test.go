@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPurger_Purge(t *testing.T) {
+	t.Parallel()
+
+	retention := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository)
+		expected     int64
+		wantErr      bool
+	}{
+		{
+			name: "1. Removes Users Past Retention",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().Purge(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, deletedBefore time.Time) (int64, error) {
+						assert.WithinDuration(t, time.Now().Add(-retention), deletedBefore, time.Minute)
+						return 3, nil
+					})
+			},
+			expected: 3,
+		},
+		{
+			name: "2. Repository Error",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().Purge(gomock.Any(), gomock.Any()).Return(int64(0), errors.New("db down"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(repo)
+
+			purged, err := NewUserPurger(repo, retention).Purge(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, purged)
+		})
+	}
+}
+
+func TestUserPurger_Run(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockUserRepository(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Первый проход идёт сразу, не дожидаясь интервала
+	repo.EXPECT().Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, time.Time) (int64, error) {
+			cancel()
+			return 0, nil
+		})
+
+	done := make(chan struct{})
+	go func() {
+		NewUserPurger(repo, time.Hour).Run(ctx, time.Hour)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after the context was cancelled")
+	}
+}
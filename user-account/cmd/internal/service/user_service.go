@@ -2,33 +2,254 @@ package service
 
 import (
 	"context"
+	"errors"
+	"log"
+	"strings"
 	"user-account/cmd/internal/model"
 	"user-account/cmd/internal/repository"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrInvalidPassword = errors.New("current password is incorrect")
+
 type UserService struct {
-	repo repository.UserRepository
+	repo        repository.UserRepository
+	revocations *RevocationService
+	guard       *LoginGuard
+	hasher      PasswordHasher
+	policy      PasswordPolicy
+	audit       *AuditLog
 }
 
-func NewUserService(repo repository.UserRepository) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(
+	repo repository.UserRepository,
+	revocations *RevocationService,
+	guard *LoginGuard,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	audit *AuditLog,
+) *UserService {
+	return &UserService{repo: repo, revocations: revocations, guard: guard, hasher: hasher, policy: policy, audit: audit}
 }
 
+// Delete удаляет пользователя мягко: он пропадает из выборок и не может
+// войти, но до окончательной очистки его можно восстановить. Сессии
+// отзываются сразу вместе с выданными по ним токенами.
 func (s *UserService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := s.revocations.RevokeUserSessions(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditUserDelete})
+	return nil
+}
+
+// Restore отменяет удаление пользователя. Отозванные сессии не
+// возвращаются, после восстановления нужно войти заново.
+func (s *UserService) Restore(ctx context.Context, id uuid.UUID) error {
+	restored, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return ErrUserNotFound
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditUserRestore})
+	return nil
 }
 
+// UpdatePassword задаёт пароль без проверки текущего
 func (s *UserService) UpdatePassword(ctx context.Context, id uuid.UUID, newPassword string) error {
-	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	user, err := s.Get(ctx, id)
 	if err != nil {
 		return err
 	}
-	return s.repo.UpdatePassword(ctx, id, string(hash))
+	if err = s.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditPasswordChange})
+	return nil
 }
 
-func (s *UserService) List(ctx context.Context) ([]model.User, error) {
-	return s.repo.List(ctx)
+// List возвращает страницу пользователей организации. Без размера
+// страницы отдаётся DefaultUserPageSize, больше MaxUserPageSize не отдаётся.
+func (s *UserService) List(ctx context.Context, query model.UserListQuery) (*model.UserPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultUserPageSize
+	}
+	if query.Limit > model.MaxUserPageSize {
+		query.Limit = model.MaxUserPageSize
+	}
+	if query.SortBy == "" {
+		query.SortBy = model.UserSortCreatedAt
+	}
+	return s.repo.List(ctx, query)
+}
+
+// Export передаёт fn всех участников организации, подходящих под фильтры
+// query, без деления на страницы. Без сортировки выгрузка идёт по дате
+// создания.
+func (s *UserService) Export(ctx context.Context, query model.UserListQuery, fn func(model.User) error) error {
+	if query.SortBy == "" {
+		query.SortBy = model.UserSortCreatedAt
+	}
+	return s.repo.ForEach(ctx, query, fn)
+}
+
+// Search ищет участников организации по части ника или адреса и отмечает
+// совпавшие со словами поиска фрагменты. Пустая строка поиска ничего не
+// находит, размер выдачи ограничен так же, как у List.
+func (s *UserService) Search(ctx context.Context, query model.UserSearchQuery) ([]model.UserSearchHit, error) {
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Text == "" {
+		return []model.UserSearchHit{}, nil
+	}
+	if query.Limit <= 0 {
+		query.Limit = model.DefaultUserSearchLimit
+	}
+	if query.Limit > model.MaxUserSearchLimit {
+		query.Limit = model.MaxUserSearchLimit
+	}
+
+	hits, err := s.repo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := model.SearchTerms(query.Text)
+	for i := range hits {
+		hits[i].Highlights = model.UserHighlights{
+			Nickname: model.Highlight(hits[i].User.Nickname, terms),
+			Email:    model.Highlight(hits[i].User.Email, terms),
+		}
+	}
+	return hits, nil
+}
+
+// Get возвращает пользователя по ID
+func (s *UserService) Get(ctx context.Context, id uuid.UUID) (*model.User, error) {
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// UpdateProfile применяет изменения профиля и возвращает обновлённого пользователя
+func (s *UserService) UpdateProfile(ctx context.Context, id uuid.UUID, update model.ProfileUpdate) (*model.User, error) {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Nickname != nil {
+		user.Nickname = *update.Nickname
+	}
+	if update.Preferences != nil {
+		user.Preferences = *update.Preferences
+	}
+
+	if err = s.repo.UpdateProfile(ctx, id, user.Nickname, user.Preferences); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangePassword меняет пароль после проверки текущего
+func (s *UserService) ChangePassword(ctx context.Context, id uuid.UUID, currentPassword, newPassword string) error {
+	user, err := s.verifyPassword(ctx, id, currentPassword)
+	if err != nil {
+		s.auditPasswordFailure(ctx, id, model.AuditPasswordChange, err)
+		return err
+	}
+	if err = s.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditPasswordChange})
+	return nil
+}
+
+// Unlock снимает блокировку входа после неудачных попыток, в том числе
+// на втором шаге. Блокировки по IP-адресу не снимаются.
+func (s *UserService) Unlock(ctx context.Context, id uuid.UUID) error {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err = s.guard.Unlock(ctx, user.Email); err != nil {
+		return err
+	}
+	if err = s.guard.Unlock(ctx, twoFactorAccount(user.ID)); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, model.AuditEvent{TargetID: &id, Action: model.AuditUserUnlock})
+	return nil
+}
+
+// CloseAccount удаляет аккаунт владельца после проверки пароля так же,
+// как Delete. Предъявленный access-токен отзывается отдельно, чтобы им
+// нельзя было воспользоваться до истечения срока.
+func (s *UserService) CloseAccount(ctx context.Context, id uuid.UUID, password string, token model.TokenClaims) error {
+	if _, err := s.verifyPassword(ctx, id, password); err != nil {
+		s.auditPasswordFailure(ctx, id, model.AuditUserDelete, err)
+		return err
+	}
+	if err := s.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.revocations.RevokeToken(ctx, token.ID, token.ExpiresAt)
+}
+
+// setPassword проверяет новый пароль по политике и сохраняет его хэш
+func (s *UserService) setPassword(ctx context.Context, user *model.User, newPassword string) error {
+	if err := s.policy.Check(ctx, newPassword, user.Email, user.Nickname); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdatePassword(ctx, user.ID, hash)
+}
+
+// verifyPassword проверяет пароль владельца перед чувствительным действием.
+// Неудачи считаются тем же счётчиком, что и вход по почте: иначе укравший
+// access-токен подбирал бы пароль здесь без ограничений.
+func (s *UserService) verifyPassword(ctx context.Context, id uuid.UUID, password string) (*model.User, error) {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.guard.Check(ctx, user.Email, ""); err != nil {
+		return nil, err
+	}
+	ok, _, err := s.hasher.Verify(password, user.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err = s.guard.Fail(ctx, user.Email, ""); err != nil {
+			log.Printf("failed to record password check failure: %v", err)
+		}
+		return nil, ErrInvalidPassword
+	}
+	if err = s.guard.Succeed(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// auditPasswordFailure пишет в журнал действие владельца, отклонённое из-за
+// неверного текущего пароля. Прочие ошибки попыткой подбора не считаются.
+func (s *UserService) auditPasswordFailure(ctx context.Context, id uuid.UUID, action string, err error) {
+	if errors.Is(err, ErrInvalidPassword) {
+		s.audit.RecordFailure(ctx, model.AuditEvent{TargetID: &id, Action: action}, err)
+	}
 }
@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 	"user-account/cmd/internal/gen/mocks"
 	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/bcrypt"
 )
 
 func TestUserService_UpdatePassword(t *testing.T) {
@@ -18,6 +19,7 @@ func TestUserService_UpdatePassword(t *testing.T) {
 
 	userID := uuid.New()
 	newPass := "new-secure-password"
+	user := &model.User{ID: userID, Email: "john@example.com", Nickname: "wanderer"}
 
 	tests := []struct {
 		name         string
@@ -31,11 +33,11 @@ func TestUserService_UpdatePassword(t *testing.T) {
 			id:       userID,
 			password: newPass,
 			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
 				m.EXPECT().
 					UpdatePassword(gomock.Any(), userID, gomock.Any()).
 					DoAndReturn(func(ctx context.Context, id uuid.UUID, hash string) error {
-						err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPass))
-						assert.NoError(t, err)
+						assertPasswordHash(t, hash, newPass)
 						return nil
 					})
 			},
@@ -46,12 +48,31 @@ func TestUserService_UpdatePassword(t *testing.T) {
 			id:       userID,
 			password: "any-password",
 			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
 				m.EXPECT().
 					UpdatePassword(gomock.Any(), gomock.Any(), gomock.Any()).
 					Return(errors.New("db error"))
 			},
 			wantErr: true,
 		},
+		{
+			name:     "3. Password Contains Nickname",
+			id:       userID,
+			password: "wanderer-2024",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+			},
+			wantErr: true,
+		},
+		{
+			name:     "4. User Not Found",
+			id:       userID,
+			password: newPass,
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(nil, nil)
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -61,7 +82,7 @@ func TestUserService_UpdatePassword(t *testing.T) {
 			repo := mocks.NewMockUserRepository(ctrl)
 			tt.mockBehavior(repo)
 
-			svc := NewUserService(repo)
+			svc := NewUserService(repo, nil, nil, testHasher(), strictPolicy(), nil)
 			err := svc.UpdatePassword(context.Background(), tt.id, tt.password)
 			assert.Equal(t, tt.wantErr, err != nil)
 		})
@@ -71,64 +92,172 @@ func TestUserService_UpdatePassword(t *testing.T) {
 func TestUserService_List(t *testing.T) {
 	t.Parallel()
 
-	mockUsers := []model.User{
+	orgID := uuid.New()
+	page := &model.UserPage{Users: []model.User{
 		{ID: uuid.New(), Email: "user1@test.com"},
 		{ID: uuid.New(), Email: "user2@test.com"},
-	}
+	}}
 
 	tests := []struct {
 		name         string
+		query        model.UserListQuery
 		mockBehavior func(m *mocks.MockUserRepository)
 		expectedLen  int
 		wantErr      bool
 	}{
 		{
-			name: "Success Get List",
+			name:  "1. Defaults Are Applied",
+			query: model.UserListQuery{OrganizationID: orgID},
 			mockBehavior: func(m *mocks.MockUserRepository) {
 				m.EXPECT().
-					List(gomock.Any()).
-					Return(mockUsers, nil)
+					List(gomock.Any(), model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt, Limit: model.DefaultUserPageSize}).
+					Return(page, nil)
 			},
 			expectedLen: 2,
-			wantErr:     false,
 		},
 		{
-			name: "Empty List",
+			name:  "2. Page Size Is Capped",
+			query: model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortEmail, Limit: 10000},
 			mockBehavior: func(m *mocks.MockUserRepository) {
 				m.EXPECT().
-					List(gomock.Any()).
-					Return([]model.User{}, nil)
+					List(gomock.Any(), model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortEmail, Limit: model.MaxUserPageSize}).
+					Return(&model.UserPage{Users: []model.User{}}, nil)
 			},
 			expectedLen: 0,
-			wantErr:     false,
 		},
 		{
-			name: "Repository Error",
+			name:  "3. Repository Error",
+			query: model.UserListQuery{OrganizationID: orgID},
 			mockBehavior: func(m *mocks.MockUserRepository) {
 				m.EXPECT().
-					List(gomock.Any()).
+					List(gomock.Any(), gomock.Any()).
 					Return(nil, errors.New("query error"))
 			},
-			expectedLen: 0,
-			wantErr:     true,
+			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
+		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			ctrl := gomock.NewController(t)
 			repo := mocks.NewMockUserRepository(ctrl)
 			tt.mockBehavior(repo)
 
-			svc := NewUserService(repo)
-			users, err := svc.List(context.Background())
+			svc := NewUserService(repo, nil, nil, testHasher(), testPolicy(), nil)
+			got, err := svc.List(context.Background(), tt.query)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Len(t, users, tt.expectedLen)
+				assert.Len(t, got.Users, tt.expectedLen)
+			}
+		})
+	}
+}
+
+func TestUserService_Export(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockUserRepository(ctrl)
+
+	orgID := uuid.New()
+	repo.EXPECT().
+		ForEach(gomock.Any(), model.UserListQuery{OrganizationID: orgID, SortBy: model.UserSortCreatedAt}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ model.UserListQuery, fn func(model.User) error) error {
+			return fn(model.User{Email: "user1@test.com"})
+		})
+
+	var emails []string
+	svc := NewUserService(repo, nil, nil, testHasher(), testPolicy(), nil)
+	err := svc.Export(context.Background(), model.UserListQuery{OrganizationID: orgID}, func(u model.User) error {
+		emails = append(emails, u.Email)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user1@test.com"}, emails)
+}
+
+func TestUserService_Search(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	found := model.User{ID: uuid.New(), Email: "John.Doe@example.com", Nickname: "johnny_b"}
+
+	tests := []struct {
+		name               string
+		query              model.UserSearchQuery
+		mockBehavior       func(m *mocks.MockUserRepository)
+		expectedLen        int
+		expectedHighlights model.UserHighlights
+		wantErr            bool
+	}{
+		{
+			name:  "1. Highlights Matched Words",
+			query: model.UserSearchQuery{OrganizationID: orgID, Text: "  JOHN doe "},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().
+					Search(gomock.Any(), model.UserSearchQuery{OrganizationID: orgID, Text: "JOHN doe", Limit: model.DefaultUserSearchLimit}).
+					Return([]model.UserSearchHit{{User: found, Rank: 0.9}}, nil)
+			},
+			expectedLen: 1,
+			expectedHighlights: model.UserHighlights{
+				Nickname: []model.TextRange{{Start: 0, End: 4}},
+				Email:    []model.TextRange{{Start: 0, End: 4}, {Start: 5, End: 8}},
+			},
+		},
+		{
+			name:  "2. Limit Is Capped",
+			query: model.UserSearchQuery{OrganizationID: orgID, Text: "jonh", Limit: 1000},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().
+					Search(gomock.Any(), model.UserSearchQuery{OrganizationID: orgID, Text: "jonh", Limit: model.MaxUserSearchLimit}).
+					Return([]model.UserSearchHit{{User: found, Rank: 0.4}}, nil)
+			},
+			expectedLen: 1,
+			expectedHighlights: model.UserHighlights{
+				Nickname: []model.TextRange{},
+				Email:    []model.TextRange{},
+			},
+		},
+		{
+			name:         "3. Blank Text Finds Nothing",
+			query:        model.UserSearchQuery{OrganizationID: orgID, Text: "   "},
+			mockBehavior: func(_ *mocks.MockUserRepository) {},
+			expectedLen:  0,
+		},
+		{
+			name:  "4. Repository Error",
+			query: model.UserSearchQuery{OrganizationID: orgID, Text: "john"},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, errors.New("query error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(repo)
+
+			svc := NewUserService(repo, nil, nil, testHasher(), testPolicy(), nil)
+			got, err := svc.Search(context.Background(), tt.query)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got, tt.expectedLen)
+			if tt.expectedLen > 0 {
+				assert.Equal(t, tt.expectedHighlights, got[0].Highlights)
 			}
 		})
 	}
@@ -142,23 +271,24 @@ func TestUserService_Delete(t *testing.T) {
 	tests := []struct {
 		name         string
 		id           uuid.UUID
-		mockBehavior func(m *mocks.MockUserRepository)
+		mockBehavior func(m *mocks.MockUserRepository, sessions *mocks.MockSessionRepository)
 		wantErr      bool
 	}{
 		{
-			name: "Success Delete",
+			name: "Success Delete Revokes Sessions",
 			id:   userID,
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, sessions *mocks.MockSessionRepository) {
 				m.EXPECT().
 					Delete(gomock.Any(), userID).
 					Return(nil)
+				sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
 			},
 			wantErr: false,
 		},
 		{
 			name: "Delete Not Found or Error",
 			id:   userID,
-			mockBehavior: func(m *mocks.MockUserRepository) {
+			mockBehavior: func(m *mocks.MockUserRepository, _ *mocks.MockSessionRepository) {
 				m.EXPECT().
 					Delete(gomock.Any(), userID).
 					Return(errors.New("user not found"))
@@ -173,11 +303,236 @@ func TestUserService_Delete(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			repo := mocks.NewMockUserRepository(ctrl)
-			tt.mockBehavior(repo)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(repo, sessions)
 
-			svc := NewUserService(repo)
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+			svc := NewUserService(repo, revocations, nil, testHasher(), testPolicy(), nil)
 			err := svc.Delete(context.Background(), tt.id)
 			assert.Equal(t, tt.wantErr, err != nil)
 		})
 	}
 }
+
+func TestUserService_Restore(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		mockBehavior func(m *mocks.MockUserRepository)
+		wantErr      error
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().Restore(gomock.Any(), userID).Return(true, nil)
+			},
+		},
+		{
+			name: "2. Not Deleted Or Already Purged",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().Restore(gomock.Any(), userID).Return(false, nil)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(repo)
+
+			svc := NewUserService(repo, nil, nil, testHasher(), testPolicy(), nil)
+			err := svc.Restore(context.Background(), userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	newNick := "renamed"
+	prefs := model.Preferences{Language: "en", Theme: "dark"}
+
+	tests := []struct {
+		name         string
+		update       model.ProfileUpdate
+		mockBehavior func(m *mocks.MockUserRepository)
+		wantErr      error
+		wantNickname string
+	}{
+		{
+			name:   "1. Nickname Only Keeps Preferences",
+			update: model.ProfileUpdate{Nickname: &newNick},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).
+					Return(&model.User{ID: userID, Nickname: "old", Preferences: prefs}, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), userID, newNick, prefs).Return(nil)
+			},
+			wantNickname: newNick,
+		},
+		{
+			name:   "2. Preferences Only Keeps Nickname",
+			update: model.ProfileUpdate{Preferences: &prefs},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).
+					Return(&model.User{ID: userID, Nickname: "old"}, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), userID, "old", prefs).Return(nil)
+			},
+			wantNickname: "old",
+		},
+		{
+			name:   "3. User Not Found",
+			update: model.ProfileUpdate{Nickname: &newNick},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(nil, nil)
+			},
+			wantErr: ErrUserNotFound,
+		},
+		{
+			name:   "4. Nickname Taken",
+			update: model.ProfileUpdate{Nickname: &newNick},
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID}, nil)
+				m.EXPECT().UpdateProfile(gomock.Any(), userID, newNick, gomock.Any()).Return(repository.ErrNicknameTaken)
+			},
+			wantErr: repository.ErrNicknameTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(repo)
+
+			svc := NewUserService(repo, nil, nil, testHasher(), testPolicy(), nil)
+			user, err := svc.UpdateProfile(context.Background(), userID, tt.update)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNickname, user.Nickname)
+		})
+	}
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	hash, _ := testHasher().Hash("current-pass")
+	user := &model.User{ID: userID, PasswordHash: hash}
+
+	tests := []struct {
+		name         string
+		current      string
+		mockBehavior func(m *mocks.MockUserRepository)
+		wantErr      error
+	}{
+		{
+			name:    "1. Success",
+			current: "current-pass",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+				m.EXPECT().UpdatePassword(gomock.Any(), userID, gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) error {
+						assertPasswordHash(t, hash, "brand-new-pass")
+						return nil
+					})
+			},
+		},
+		{
+			name:    "2. Wrong Current Password",
+			current: "guess",
+			mockBehavior: func(m *mocks.MockUserRepository) {
+				m.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+			},
+			wantErr: ErrInvalidPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(repo)
+
+			svc := NewUserService(repo, nil, testLoginGuard(), testHasher(), testPolicy(), nil)
+			err := svc.ChangePassword(context.Background(), userID, tt.current, "brand-new-pass")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_CloseAccount(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	hash, _ := testHasher().Hash("current-pass")
+	user := &model.User{ID: userID, PasswordHash: hash}
+	token := model.TokenClaims{ID: uuid.NewString(), SessionID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}
+
+	t.Run("1. Success Revokes Sessions And Presented Token", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockUserRepository(ctrl)
+		tokens := mocks.NewMockRevokedTokenRepository(ctrl)
+		sessions := mocks.NewMockSessionRepository(ctrl)
+
+		repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+		repo.EXPECT().Delete(gomock.Any(), userID).Return(nil)
+		sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+		tokens.EXPECT().Revoke(gomock.Any(), token.ID, token.ExpiresAt).Return(nil)
+
+		revocations := NewRevocationService(tokens, sessions, time.Second, time.Minute)
+		svc := NewUserService(repo, revocations, testLoginGuard(), testHasher(), testPolicy(), nil)
+		assert.NoError(t, svc.CloseAccount(context.Background(), userID, "current-pass", token))
+	})
+
+	t.Run("2. Wrong Password Keeps Account", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockUserRepository(ctrl)
+		repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil)
+
+		svc := NewUserService(repo, nil, testLoginGuard(), testHasher(), testPolicy(), nil)
+		err := svc.CloseAccount(context.Background(), userID, "guess", token)
+		assert.ErrorIs(t, err, ErrInvalidPassword)
+	})
+
+	t.Run("3. Repeated Wrong Passwords Lock Further Checks", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mocks.NewMockUserRepository(ctrl)
+		repo.EXPECT().GetByID(gomock.Any(), userID).Return(user, nil).Times(4)
+
+		svc := NewUserService(repo, nil, testLoginGuard(), testHasher(), testPolicy(), nil)
+		for i := 0; i < 3; i++ {
+			assert.ErrorIs(t, svc.CloseAccount(context.Background(), userID, "guess", token), ErrInvalidPassword)
+		}
+		// Во время блокировки не подходит и верный пароль
+		err := svc.CloseAccount(context.Background(), userID, "current-pass", token)
+		assert.ErrorIs(t, err, ErrLoginLocked)
+	})
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"user-account/cmd/internal/model"
+	"user-account/cmd/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidUserStatus    = errors.New("status must be suspended or locked")
+	ErrStatusReasonRequired = errors.New("reason is required")
+	ErrInvalidStatusExpiry  = errors.New("expires_at must be in the future")
+	ErrCannotSuspendSelf    = errors.New("cannot suspend your own account")
+	ErrUserNotSuspended     = errors.New("user is not suspended")
+	ErrAccountSuspended     = errors.New("account is suspended")
+)
+
+type cachedStatus struct {
+	// user - nil, если пользователь удалён
+	user      *model.User
+	expiresAt time.Time
+}
+
+// UserStatusService приостанавливает и возвращает доступ пользователям.
+// Статус проверяется на каждый запрос, поэтому приостановка закрывает и уже
+// выданные токены: сразу на этом экземпляре и не позже чем через cacheTTL
+// на остальных.
+type UserStatusService struct {
+	statuses    repository.UserStatusRepository
+	users       repository.UserRepository
+	revocations *RevocationService
+	audit       *AuditLog
+	cacheTTL    time.Duration
+
+	mu        sync.Mutex
+	cache     map[uuid.UUID]cachedStatus
+	lastSweep time.Time
+}
+
+func NewUserStatusService(
+	statuses repository.UserStatusRepository,
+	users repository.UserRepository,
+	revocations *RevocationService,
+	audit *AuditLog,
+	cacheTTL time.Duration,
+) *UserStatusService {
+	return &UserStatusService{
+		statuses:    statuses,
+		users:       users,
+		revocations: revocations,
+		audit:       audit,
+		cacheTTL:    cacheTTL,
+		cache:       make(map[uuid.UUID]cachedStatus),
+		lastSweep:   time.Now(),
+	}
+}
+
+// Suspend закрывает пользователю доступ от имени actorID. Без expiresAt
+// статус действует до Reinstate. Сессии пользователя отзываются сразу.
+func (s *UserStatusService) Suspend(
+	ctx context.Context,
+	actorID, userID uuid.UUID,
+	status, reason string,
+	expiresAt *time.Time,
+) (*model.UserStatusChange, error) {
+	if status != model.UserStatusSuspended && status != model.UserStatusLocked {
+		return nil, ErrInvalidUserStatus
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrStatusReasonRequired
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrInvalidStatusExpiry
+	}
+	if actorID == userID {
+		return nil, ErrCannotSuspendSelf
+	}
+
+	change, err := s.change(ctx, actorID, userID, status, reason, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.revocations.RevokeUserSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+	s.auditChange(ctx, model.AuditUserSuspend, change)
+	return change, nil
+}
+
+// Reinstate возвращает доступ пользователю, доступ которому закрыли.
+// Отозванные при приостановке сессии не возвращаются.
+func (s *UserStatusService) Reinstate(ctx context.Context, actorID, userID uuid.UUID, reason string) (*model.UserStatusChange, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrStatusReasonRequired
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Status == "" || user.Status == model.UserStatusActive {
+		return nil, ErrUserNotSuspended
+	}
+
+	change, err := s.change(ctx, actorID, userID, model.UserStatusActive, reason, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.auditChange(ctx, model.AuditUserReinstate, change)
+	return change, nil
+}
+
+// History - смены статуса пользователя, новые первыми
+func (s *UserStatusService) History(ctx context.Context, userID uuid.UUID) ([]model.UserStatusChange, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return s.statuses.History(ctx, userID)
+}
+
+// IsBlocked сообщает, закрыт ли пользователю доступ. Удалённый
+// пользователь тоже считается заблокированным.
+func (s *UserStatusService) IsBlocked(ctx context.Context, userID uuid.UUID) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+
+	if !ok || now.After(cached.expiresAt) {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return false, err
+		}
+		cached = cachedStatus{user: user, expiresAt: now.Add(s.cacheTTL)}
+		s.remember(userID, cached, now)
+	}
+
+	return cached.user == nil || cached.user.Blocked(now), nil
+}
+
+func (s *UserStatusService) change(
+	ctx context.Context,
+	actorID, userID uuid.UUID,
+	status, reason string,
+	expiresAt *time.Time,
+) (*model.UserStatusChange, error) {
+	change := &model.UserStatusChange{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Status:    status,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		ChangedBy: &actorID,
+	}
+	changed, err := s.statuses.Change(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, ErrUserNotFound
+	}
+	s.forget(userID)
+	return change, nil
+}
+
+// auditChange пишет смену статуса в журнал вместе с причиной
+func (s *UserStatusService) auditChange(ctx context.Context, action string, change *model.UserStatusChange) {
+	metadata := map[string]string{"status": change.Status, "reason": change.Reason}
+	if change.ExpiresAt != nil {
+		metadata["expires_at"] = change.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	s.audit.Record(ctx, model.AuditEvent{
+		ActorID:  change.ChangedBy,
+		TargetID: &change.UserID,
+		Action:   action,
+		Metadata: metadata,
+	})
+}
+
+func (s *UserStatusService) remember(userID uuid.UUID, cached cachedStatus, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache[userID] = cached
+
+	if now.Sub(s.lastSweep) < revocationSweepInterval {
+		return
+	}
+	for id, entry := range s.cache {
+		if now.After(entry.expiresAt) {
+			delete(s.cache, id)
+		}
+	}
+	s.lastSweep = now
+}
+
+func (s *UserStatusService) forget(userID uuid.UUID) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.mu.Unlock()
+}
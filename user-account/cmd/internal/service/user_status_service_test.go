@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-account/cmd/internal/gen/mocks"
+	"user-account/cmd/internal/model"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatusService_Suspend(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		actorID      uuid.UUID
+		status       string
+		reason       string
+		expiresAt    *time.Time
+		mockBehavior func(ms *mocks.MockUserStatusRepository, mss *mocks.MockSessionRepository)
+		expectedErr  error
+	}{
+		{
+			name:      "1. Success",
+			actorID:   adminID,
+			status:    model.UserStatusSuspended,
+			reason:    "  left the company ",
+			expiresAt: &future,
+			mockBehavior: func(ms *mocks.MockUserStatusRepository, mss *mocks.MockSessionRepository) {
+				ms.EXPECT().Change(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c *model.UserStatusChange) (bool, error) {
+					assert.Equal(t, userID, c.UserID)
+					assert.Equal(t, model.UserStatusSuspended, c.Status)
+					assert.Equal(t, "left the company", c.Reason)
+					assert.Equal(t, &future, c.ExpiresAt)
+					assert.Equal(t, adminID, *c.ChangedBy)
+					return true, nil
+				})
+				mss.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+			},
+		},
+		{
+			name:         "2. Active Is Not A Suspension",
+			actorID:      adminID,
+			status:       model.UserStatusActive,
+			reason:       "reason",
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, _ *mocks.MockSessionRepository) {},
+			expectedErr:  ErrInvalidUserStatus,
+		},
+		{
+			name:         "3. Reason Required",
+			actorID:      adminID,
+			status:       model.UserStatusLocked,
+			reason:       "   ",
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, _ *mocks.MockSessionRepository) {},
+			expectedErr:  ErrStatusReasonRequired,
+		},
+		{
+			name:         "4. Expiry In The Past",
+			actorID:      adminID,
+			status:       model.UserStatusSuspended,
+			reason:       "reason",
+			expiresAt:    &past,
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, _ *mocks.MockSessionRepository) {},
+			expectedErr:  ErrInvalidStatusExpiry,
+		},
+		{
+			name:         "5. Cannot Suspend Self",
+			actorID:      userID,
+			status:       model.UserStatusSuspended,
+			reason:       "reason",
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, _ *mocks.MockSessionRepository) {},
+			expectedErr:  ErrCannotSuspendSelf,
+		},
+		{
+			name:    "6. User Not Found",
+			actorID: adminID,
+			status:  model.UserStatusLocked,
+			reason:  "reason",
+			mockBehavior: func(ms *mocks.MockUserStatusRepository, _ *mocks.MockSessionRepository) {
+				ms.EXPECT().Change(gomock.Any(), gomock.Any()).Return(false, nil)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			statuses := mocks.NewMockUserStatusRepository(ctrl)
+			sessions := mocks.NewMockSessionRepository(ctrl)
+			tt.mockBehavior(statuses, sessions)
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+
+			svc := NewUserStatusService(statuses, mocks.NewMockUserRepository(ctrl), revocations, nil, time.Minute)
+			change, err := svc.Suspend(context.Background(), tt.actorID, userID, tt.status, tt.reason, tt.expiresAt)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, change)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.status, change.Status)
+			}
+		})
+	}
+}
+
+func TestUserStatusService_Reinstate(t *testing.T) {
+	t.Parallel()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name         string
+		mockBehavior func(ms *mocks.MockUserStatusRepository, mu *mocks.MockUserRepository)
+		expectedErr  error
+	}{
+		{
+			name: "1. Success",
+			mockBehavior: func(ms *mocks.MockUserStatusRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Status: model.UserStatusLocked}, nil)
+				ms.EXPECT().Change(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c *model.UserStatusChange) (bool, error) {
+					assert.Equal(t, model.UserStatusActive, c.Status)
+					assert.Nil(t, c.ExpiresAt)
+					return true, nil
+				})
+			},
+		},
+		{
+			name: "2. User Is Active",
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Status: model.UserStatusActive}, nil)
+			},
+			expectedErr: ErrUserNotSuspended,
+		},
+		{
+			name: "3. User Not Found",
+			mockBehavior: func(_ *mocks.MockUserStatusRepository, mu *mocks.MockUserRepository) {
+				mu.EXPECT().GetByID(gomock.Any(), userID).Return(nil, nil)
+			},
+			expectedErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			statuses := mocks.NewMockUserStatusRepository(ctrl)
+			users := mocks.NewMockUserRepository(ctrl)
+			tt.mockBehavior(statuses, users)
+			revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), mocks.NewMockSessionRepository(ctrl), time.Second, time.Minute)
+
+			svc := NewUserStatusService(statuses, users, revocations, nil, time.Minute)
+			_, err := svc.Reinstate(context.Background(), adminID, userID, "back from leave")
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserStatusService_IsBlocked(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	statuses := mocks.NewMockUserStatusRepository(ctrl)
+	users := mocks.NewMockUserRepository(ctrl)
+	sessions := mocks.NewMockSessionRepository(ctrl)
+	adminID := uuid.New()
+	userID := uuid.New()
+	deletedID := uuid.New()
+
+	// Второй вопрос отвечается из кэша, после приостановки кэш сброшен
+	gomock.InOrder(
+		users.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Status: model.UserStatusActive}, nil),
+		users.EXPECT().GetByID(gomock.Any(), userID).Return(&model.User{ID: userID, Status: model.UserStatusSuspended}, nil),
+	)
+	users.EXPECT().GetByID(gomock.Any(), deletedID).Return(nil, nil)
+	statuses.EXPECT().Change(gomock.Any(), gomock.Any()).Return(true, nil)
+	sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+
+	revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), sessions, time.Second, time.Minute)
+	svc := NewUserStatusService(statuses, users, revocations, nil, time.Minute)
+	ctx := context.Background()
+
+	blocked, err := svc.IsBlocked(ctx, userID)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	blocked, err = svc.IsBlocked(ctx, userID)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	_, err = svc.Suspend(ctx, adminID, userID, model.UserStatusSuspended, "investigation", nil)
+	assert.NoError(t, err)
+
+	blocked, err = svc.IsBlocked(ctx, userID)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+
+	blocked, err = svc.IsBlocked(ctx, deletedID)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+}
+
+func TestUserStatusService_IsBlocked_Error(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUserRepository(ctrl)
+	userID := uuid.New()
+	users.EXPECT().GetByID(gomock.Any(), userID).Return(nil, errors.New("db down"))
+
+	revocations := NewRevocationService(mocks.NewMockRevokedTokenRepository(ctrl), mocks.NewMockSessionRepository(ctrl), time.Second, time.Minute)
+	svc := NewUserStatusService(mocks.NewMockUserStatusRepository(ctrl), users, revocations, nil, time.Minute)
+
+	blocked, err := svc.IsBlocked(context.Background(), userID)
+	assert.Error(t, err)
+	assert.False(t, blocked)
+}
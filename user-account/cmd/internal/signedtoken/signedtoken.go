@@ -0,0 +1,86 @@
+package signedtoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalid = errors.New("invalid token")
+	ErrExpired = errors.New("token expired")
+)
+
+// Claims - содержимое подписанного токена
+type Claims struct {
+	// ID - идентификатор токена, по нему сервис отмечает использование
+	ID uuid.UUID `json:"jti"`
+	// Subject - к кому относится токен, например ID пользователя
+	Subject string `json:"sub"`
+	// ExpiresAt - момент, после которого токен не принимается
+	ExpiresAt time.Time `json:"exp"`
+}
+
+// Signer выпускает и проверяет токены для ссылок в письмах. Токен -
+// это payload и HMAC-SHA256 от него вместе с назначением (purpose), так что
+// токен подтверждения почты нельзя предъявить, например, как приглашение.
+// Одноразовость обеспечивает вызывающий код по Claims.ID.
+type Signer struct {
+	secret []byte
+}
+
+func New(secret string) *Signer {
+	return &Signer{secret: []byte(secret)}
+}
+
+// Sign подписывает claims для назначения purpose
+func (s *Signer) Sign(purpose string, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + s.mac(purpose, encoded), nil
+}
+
+// Verify проверяет подпись и срок действия токена назначения purpose
+func (s *Signer) Verify(purpose, token string, now time.Time) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalid
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(s.mac(purpose, encoded))) {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalid
+	}
+
+	var claims Claims
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrInvalid
+	}
+
+	if now.After(claims.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	return &claims, nil
+}
+
+func (s *Signer) mac(purpose, encoded string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(purpose))
+	h.Write([]byte{0})
+	h.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package signedtoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigner(t *testing.T) {
+	t.Parallel()
+
+	signer := New("test-secret")
+	now := time.Now()
+	claims := Claims{ID: uuid.New(), Subject: "user-1", ExpiresAt: now.Add(time.Hour).Truncate(time.Second)}
+
+	token, err := signer.Sign("email_verification", claims)
+	require.NoError(t, err)
+
+	tampered := []byte(token)
+	tampered[2] ^= 1
+
+	tests := []struct {
+		name    string
+		signer  *Signer
+		purpose string
+		token   string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "Valid", signer: signer, purpose: "email_verification", token: token, now: now},
+		{name: "Expired", signer: signer, purpose: "email_verification", token: token, now: now.Add(2 * time.Hour), wantErr: ErrExpired},
+		{name: "Other Purpose", signer: signer, purpose: "invitation", token: token, now: now, wantErr: ErrInvalid},
+		{name: "Other Secret", signer: New("other-secret"), purpose: "email_verification", token: token, now: now, wantErr: ErrInvalid},
+		{name: "Tampered Payload", signer: signer, purpose: "email_verification", token: string(tampered), now: now, wantErr: ErrInvalid},
+		{name: "No Signature", signer: signer, purpose: "email_verification", token: strings.Split(token, ".")[0], now: now, wantErr: ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.signer.Verify(tt.purpose, tt.token, tt.now)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, claims.ID, got.ID)
+			assert.Equal(t, claims.Subject, got.Subject)
+			assert.True(t, claims.ExpiresAt.Equal(got.ExpiresAt))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Форматы таблиц. JSON только записывается: массив объектов с колонками
+// в качестве ключей.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatJSON = "json"
+)
+
+// xlsxUnzipLimit - сколько можно распаковать из xlsx. Файл - zip-архив,
+// и без предела небольшой файл разворачивается в гигабайты.
+const xlsxUnzipLimit = 64 << 20
+
+const csvHeadSize = 4096
+
+var (
+	ErrUnknownFormat = errors.New("file must be .csv or .xlsx")
+	ErrNoHeader      = errors.New("file has no header row")
+)
+
+// Table - таблица с заголовком. Названия колонок обрезаны по краям,
+// колонки без названия отбрасываются.
+type Table struct {
+	Columns []string
+	Rows    []Row
+}
+
+// Row - непустая строка таблицы. Line - её номер в файле, заголовок - 1.
+type Row struct {
+	Line   int
+	Values map[string]string
+}
+
+// Get возвращает значение колонки без пробелов по краям
+func (r Row) Get(column string) string {
+	return strings.TrimSpace(r.Values[column])
+}
+
+// FormatFromName определяет формат по расширению файла
+func FormatFromName(name string) (string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Read читает таблицу в формате format
+func Read(format string, r io.Reader) (*Table, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(r)
+	case FormatXLSX:
+		return ReadXLSX(r)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// ReadCSV читает CSV с заголовком в первой строке. Разделитель - запятая
+// или точка с запятой (так сохраняет CSV Excel в русской локали), он
+// определяется по заголовку.
+func ReadCSV(r io.Reader) (*Table, error) {
+	br := bufio.NewReader(r)
+	// BOM добавляет Excel при сохранении в UTF-8
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = br.Discard(3)
+	}
+	// Для выбора разделителя хватает начала файла; короткий файл Peek
+	// возвращает целиком вместе с io.EOF
+	head, err := br.Peek(csvHeadSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = csvDelimiter(head)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		// Пустые строки csv.Reader пропускает сам, поэтому номер строки
+		// берётся у него, а не считается по записям
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return fromRecords(records, lines)
+}
+
+// ReadXLSX читает первый лист книги, заголовок - первая строка листа
+func ReadXLSX(r io.Reader) (*Table, error) {
+	book, err := excelize.OpenReader(r, excelize.Options{UnzipSizeLimit: xlsxUnzipLimit})
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	defer func() { _ = book.Close() }()
+
+	sheets := book.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrNoHeader
+	}
+	records, err := book.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	// GetRows отдаёт и пустые строки между заполненными
+	lines := make([]int, len(records))
+	for i := range lines {
+		lines[i] = i + 1
+	}
+	return fromRecords(records, lines)
+}
+
+// fromRecords собирает таблицу из записей; lines - номера их строк в файле
+func fromRecords(records [][]string, lines []int) (*Table, error) {
+	if len(records) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	header := make([]string, len(records[0]))
+	table := &Table{}
+	for i, cell := range records[0] {
+		header[i] = strings.TrimSpace(cell)
+		if header[i] != "" {
+			table.Columns = append(table.Columns, header[i])
+		}
+	}
+	if len(table.Columns) == 0 {
+		return nil, ErrNoHeader
+	}
+
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		row := Row{Line: lines[i+1], Values: make(map[string]string, len(table.Columns))}
+		for j, column := range header {
+			if column != "" && j < len(record) {
+				row.Values[column] = record[j]
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// csvDelimiter выбирает разделитель по первой строке файла
+func csvDelimiter(head []byte) rune {
+	if i := bytes.IndexAny(head, "\r\n"); i >= 0 {
+		head = head[:i]
+	}
+	if bytes.Count(head, []byte(";")) > bytes.Count(head, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package tabular
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		columns  []string
+		lines    []int
+		emails   []string
+		expected error
+	}{
+		{
+			name:    "1. Comma Separated",
+			content: " email ,nickname\nann@test.com,ann\n\n,\nbob@test.com,bob\n",
+			columns: []string{"email", "nickname"},
+			lines:   []int{2, 5},
+			emails:  []string{"ann@test.com", "bob@test.com"},
+		},
+		{
+			name:    "2. Semicolon With BOM",
+			content: "\xef\xbb\xbfemail;nickname;comment\r\nann@test.com;ann;a, b\r\n",
+			columns: []string{"email", "nickname", "comment"},
+			lines:   []int{2},
+			emails:  []string{"ann@test.com"},
+		},
+		{
+			name:    "3. Short Rows And Unnamed Columns",
+			content: "email,,nickname\nann@test.com\n",
+			columns: []string{"email", "nickname"},
+			lines:   []int{2},
+			emails:  []string{"ann@test.com"},
+		},
+		{
+			name:     "4. Empty File",
+			content:  "",
+			expected: ErrNoHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			table, err := ReadCSV(strings.NewReader(tt.content))
+
+			if tt.expected != nil {
+				assert.ErrorIs(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.columns, table.Columns)
+			require.Len(t, table.Rows, len(tt.lines))
+			for i, row := range table.Rows {
+				assert.Equal(t, tt.lines[i], row.Line)
+				assert.Equal(t, tt.emails[i], row.Get("email"))
+			}
+		})
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	t.Parallel()
+
+	book := excelize.NewFile()
+	sheet := book.GetSheetName(0)
+	require.NoError(t, book.SetSheetRow(sheet, "A1", &[]interface{}{"email", "nickname"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A2", &[]interface{}{"ann@test.com", "ann"}))
+	require.NoError(t, book.SetSheetRow(sheet, "A4", &[]interface{}{"bob@test.com", 42}))
+	var buf bytes.Buffer
+	require.NoError(t, book.Write(&buf))
+
+	table, err := Read(FormatXLSX, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "nickname"}, table.Columns)
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, 4, table.Rows[1].Line)
+	assert.Equal(t, "42", table.Rows[1].Get("nickname"))
+
+	_, err = ReadXLSX(strings.NewReader("not a zip"))
+	assert.Error(t, err)
+}
+
+func TestFormatFromName(t *testing.T) {
+	t.Parallel()
+
+	format, err := FormatFromName("staff.CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = FormatFromName("staff.xlsx")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = FormatFromName("staff.xls")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,188 @@
+package tabular
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer пишет таблицу построчно, не собирая её в памяти. Close дописывает
+// то, что формат требует в конце; без него файл неполон.
+type Writer interface {
+	Write(values []string) error
+	Close() error
+}
+
+// ContentType - MIME-тип формата для ответа
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
+
+// NewWriter начинает таблицу в формате format. CSV и XLSX получают строку
+// заголовка header, в JSON ключами объектов становятся columns. Значения
+// строк идут в порядке columns.
+func NewWriter(format string, w io.Writer, columns, header []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, header)
+	case FormatXLSX:
+		return newXLSXWriter(w, header)
+	case FormatJSON:
+		return newJSONWriter(w, columns)
+	}
+	return nil, ErrUnknownFormat
+}
+
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	// Без BOM Excel открывает UTF-8 как однобайтовую кодировку
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{csv: csv.NewWriter(w)}
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write экранирует значения, которые табличный редактор принял бы за
+// формулу. В XLSX значения пишутся строковыми ячейками и не вычисляются,
+// в JSON формул нет, поэтому экранируется только CSV.
+func (w *csvWriter) Write(values []string) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeFormula(value)
+	}
+	return w.csv.Write(escaped)
+}
+
+// escapeFormula ставит апостроф перед значением, которое начинается как
+// формула (CSV/formula injection)
+func escapeFormula(value string) string {
+	if value == "" {
+		return value
+	}
+	switch value[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + value
+	}
+	return value
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// xlsxWriter пишет первый лист потоком excelize: строки сверх его буфера
+// уходят во временный файл, книга собирается в Close
+type xlsxWriter struct {
+	out    io.Writer
+	book   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, header []string) (*xlsxWriter, error) {
+	book := excelize.NewFile()
+	stream, err := book.NewStreamWriter(book.GetSheetList()[0])
+	if err != nil {
+		_ = book.Close()
+		return nil, err
+	}
+	xw := &xlsxWriter{out: w, book: book, stream: stream}
+	if err = xw.Write(header); err != nil {
+		_ = book.Close()
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (w *xlsxWriter) Write(values []string) error {
+	w.row++
+	cell, err := excelize.CoordinatesToCellName(1, w.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, value := range values {
+		row[i] = value
+	}
+	return w.stream.SetRow(cell, row)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer func() { _ = w.book.Close() }()
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	return w.book.Write(w.out)
+}
+
+type jsonWriter struct {
+	out     *bufio.Writer
+	columns [][]byte
+	rows    int
+}
+
+func newJSONWriter(w io.Writer, columns []string) (*jsonWriter, error) {
+	jw := &jsonWriter{out: bufio.NewWriter(w), columns: make([][]byte, len(columns))}
+	for i, column := range columns {
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		jw.columns[i] = key
+	}
+	return jw, jw.out.WriteByte('[')
+}
+
+// Write пишет объект вручную: map отсортировала бы ключи, а порядок
+// колонок выбирает вызывающий
+func (w *jsonWriter) Write(values []string) error {
+	if w.rows > 0 {
+		_ = w.out.WriteByte(',')
+	}
+	w.rows++
+	_ = w.out.WriteByte('{')
+	for i, key := range w.columns {
+		if i > 0 {
+			_ = w.out.WriteByte(',')
+		}
+		var value string
+		if i < len(values) {
+			value = values[i]
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		_, _ = w.out.Write(key)
+		_ = w.out.WriteByte(':')
+		_, _ = w.out.Write(encoded)
+	}
+	// bufio.Writer запоминает первую ошибку записи, её вернёт WriteByte
+	return w.out.WriteByte('}')
+}
+
+func (w *jsonWriter) Close() error {
+	if _, err := w.out.WriteString("]\n"); err != nil {
+		return err
+	}
+	return w.out.Flush()
+}
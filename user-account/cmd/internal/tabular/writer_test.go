@@ -0,0 +1,73 @@
+package tabular
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWriter(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"email", "nickname"}
+	header := []string{"Почта", "Ник"}
+	rows := [][]string{{"ann@test.com", "ann, k"}, {"bob@test.com", "bob \"b\""}}
+
+	tests := []struct {
+		name   string
+		format string
+	}{
+		{name: "1. CSV", format: FormatCSV},
+		{name: "2. XLSX", format: FormatXLSX},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			w, err := NewWriter(tt.format, &buf, columns, header)
+			require.NoError(t, err)
+			for _, row := range rows {
+				require.NoError(t, w.Write(row))
+			}
+			require.NoError(t, w.Close())
+
+			table, err := Read(tt.format, &buf)
+			require.NoError(t, err)
+			assert.Equal(t, header, table.Columns)
+			require.Len(t, table.Rows, len(rows))
+			for i, row := range rows {
+				assert.Equal(t, row[0], table.Rows[i].Get("Почта"))
+				assert.Equal(t, row[1], table.Rows[i].Get("Ник"))
+			}
+		})
+	}
+}
+
+func TestNewWriter_JSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatJSON, &buf, []string{"nickname", "email"}, []string{"Ник", "Почта"})
+	require.NoError(t, err)
+	require.NoError(t, w.Write([]string{"ann", "ann@test.com"}))
+	require.NoError(t, w.Write([]string{"bob"}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, `[{"nickname":"ann","email":"ann@test.com"},{"nickname":"bob","email":""}]`+"\n", buf.String())
+	var decoded []map[string]string
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+
+	buf.Reset()
+	w, err = NewWriter(FormatJSON, &buf, []string{"email"}, nil)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "[]\n", buf.String())
+
+	_, err = NewWriter("xls", &buf, nil, nil)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -1,11 +1,326 @@
 CREATE TABLE IF NOT EXISTS users
+(
+    id                UUID PRIMARY KEY,
+    email             TEXT UNIQUE NOT NULL,
+    nickname          TEXT UNIQUE NOT NULL,
+    password_hash     TEXT        NOT NULL,
+    preferences       JSONB       NOT NULL DEFAULT '{}',
+    email_verified_at TIMESTAMP WITH TIME ZONE,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at        TIMESTAMP WITH TIME ZONE,
+    status            TEXT        NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'locked')),
+    status_reason     TEXT        NOT NULL DEFAULT '',
+    status_expires_at TIMESTAMP WITH TIME ZONE,
+    erased_at         TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_nickname ON users(nickname);
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS organizations
+(
+    id         UUID PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members
+(
+    organization_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role            TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id         UUID PRIMARY KEY,
+    family_id  UUID                     NOT NULL,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT UNIQUE              NOT NULL,
+    user_agent TEXT                     NOT NULL DEFAULT '',
+    ip_address TEXT                     NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    organization_id UUID REFERENCES organizations (id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+CREATE TABLE IF NOT EXISTS roles
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS permissions
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    granted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles(role);
+
+INSERT INTO roles (name, description)
+VALUES ('user', 'Regular account'),
+       ('admin', 'Full access to user and role management')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name, description)
+VALUES ('users:read', 'List and view other accounts'),
+       ('users:manage', 'Modify and delete other accounts'),
+       ('roles:manage', 'Assign and revoke roles'),
+       ('oauth_clients:manage', 'Register and revoke OAuth clients'),
+       ('audit:read', 'View and verify the security audit log')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'users:read'),
+       ('admin', 'users:manage'),
+       ('admin', 'roles:manage'),
+       ('admin', 'oauth_clients:manage'),
+       ('admin', 'audit:read')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS email_verifications
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      TEXT                     NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user_id ON email_verifications(user_id);
+
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT                     NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_resets_user_id ON password_resets(user_id);
+
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         TEXT   NOT NULL,
+    confirmed_at   TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  TEXT NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts
+(
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER                  NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until    TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT                     NOT NULL,
+    prefix       TEXT                     NOT NULL,
+    key_hash     TEXT                     NOT NULL UNIQUE,
+    scopes       TEXT                     NOT NULL DEFAULT '',
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS user_identities
 (
     id            UUID PRIMARY KEY,
-    email         TEXT UNIQUE NOT NULL,
-    nickname      TEXT UNIQUE NOT NULL,
-    password_hash TEXT        NOT NULL,
-    role          TEXT        NOT NULL DEFAULT 'user',
+    user_id       UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider      TEXT                     NOT NULL,
+    subject       TEXT                     NOT NULL,
+    email         TEXT                     NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject),
+    CONSTRAINT user_identities_user_id_provider_key UNIQUE (user_id, provider)
+);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states
+(
+    state_hash    TEXT PRIMARY KEY,
+    provider      TEXT                     NOT NULL,
+    nonce         TEXT                     NOT NULL,
+    code_verifier TEXT                     NOT NULL,
+    binding_hash  TEXT                     NOT NULL,
+    expires_at    TIMESTAMP WITH TIME ZONE NOT NULL,
     created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 );
 
-CREATE INDEX IF NOT EXISTS idx_users_nickname ON users(nickname);
+CREATE TABLE IF NOT EXISTS oauth_clients
+(
+    id            UUID PRIMARY KEY,
+    name          TEXT NOT NULL,
+    secret_hash   TEXT NOT NULL DEFAULT '',
+    redirect_uris TEXT NOT NULL DEFAULT '',
+    grant_types   TEXT NOT NULL DEFAULT '',
+    scopes        TEXT NOT NULL DEFAULT '',
+    created_by    UUID REFERENCES users (id) ON DELETE SET NULL,
+    revoked_at    TIMESTAMP WITH TIME ZONE,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes
+(
+    code_hash      TEXT PRIMARY KEY,
+    client_id      UUID                     NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id        UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    redirect_uri   TEXT                     NOT NULL,
+    scopes         TEXT                     NOT NULL DEFAULT '',
+    nonce          TEXT                     NOT NULL DEFAULT '',
+    code_challenge TEXT                     NOT NULL DEFAULT '',
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_invitations
+(
+    id              UUID PRIMARY KEY,
+    organization_id UUID                     NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email           TEXT                     NOT NULL,
+    role            TEXT                     NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    invited_by      UUID REFERENCES users (id) ON DELETE SET NULL,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at     TIMESTAMP WITH TIME ZONE,
+    declined_at     TIMESTAMP WITH TIME ZONE,
+    revoked_at      TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS organization_invitations_pending_email_key
+    ON organization_invitations (organization_id, email)
+    WHERE accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS users_nickname_trgm_idx ON users USING GIN (LOWER(nickname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_email_trgm_idx ON users USING GIN (LOWER(email) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_search_document_idx ON users
+    USING GIN (to_tsvector('simple', nickname || ' ' || translate(email, '@.-_+', '     ')));
+
+CREATE TABLE IF NOT EXISTS user_status_history
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status     TEXT                     NOT NULL CHECK (status IN ('active', 'suspended', 'locked')),
+    reason     TEXT                     NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    changed_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_status_history_user_idx ON user_status_history (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS data_exports
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       TEXT                     NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+    archive      BYTEA,
+    error        TEXT                     NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at   TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_idx ON data_exports (user_id, created_at);
+CREATE INDEX IF NOT EXISTS data_exports_queue_idx ON data_exports (created_at) WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS audit_events
+(
+    id         BIGINT PRIMARY KEY,
+    actor_id   UUID,
+    target_id  UUID,
+    action     TEXT                     NOT NULL,
+    ip_address TEXT                     NOT NULL DEFAULT '',
+    user_agent TEXT                     NOT NULL DEFAULT '',
+    outcome    TEXT                     NOT NULL CHECK (outcome IN ('success', 'failure')),
+    metadata   JSONB                    NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash  TEXT                     NOT NULL,
+    hash       TEXT                     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_action_idx ON audit_events (action, id);
+CREATE INDEX IF NOT EXISTS audit_events_created_idx ON audit_events (created_at);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER audit_events_no_change
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+CREATE OR REPLACE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE
+    ON audit_events
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only();
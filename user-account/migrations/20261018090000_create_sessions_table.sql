@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions
+(
+    id         UUID PRIMARY KEY,
+    family_id  UUID                     NOT NULL,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT UNIQUE              NOT NULL,
+    user_agent TEXT                     NOT NULL DEFAULT '',
+    ip_address TEXT                     NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_sessions_family_id ON sessions(family_id);
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE roles
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE permissions
+(
+    name        TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions
+(
+    role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission TEXT NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE user_roles
+(
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role       TEXT NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    granted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX idx_user_roles_role ON user_roles(role);
+
+INSERT INTO roles (name, description)
+VALUES ('user', 'Regular account'),
+       ('admin', 'Full access to user and role management');
+
+INSERT INTO permissions (name, description)
+VALUES ('users:read', 'List and view other accounts'),
+       ('users:manage', 'Modify and delete other accounts'),
+       ('roles:manage', 'Assign and revoke roles');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'users:read'),
+       ('admin', 'users:manage'),
+       ('admin', 'roles:manage');
+
+-- Переносим строковые роли из users: неизвестные значения становятся
+-- ролями без прав, чтобы их можно было разобрать вручную
+INSERT INTO roles (name)
+SELECT DISTINCT role FROM users
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_roles (user_id, role)
+SELECT id, role FROM users;
+
+ALTER TABLE users DROP COLUMN role;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+
+UPDATE users SET role = 'admin'
+WHERE id IN (SELECT user_id FROM user_roles WHERE role = 'admin');
+
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN preferences JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users DROP COLUMN IF EXISTS preferences;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Аккаунты, созданные до появления подтверждения, считаем подтверждёнными,
+-- иначе включение EMAIL_VERIFICATION_REQUIRED заблокирует всех
+UPDATE users SET email_verified_at = created_at;
+
+CREATE TABLE email_verifications
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      TEXT                     NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_verifications_user_id ON email_verifications(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
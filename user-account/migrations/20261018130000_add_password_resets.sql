@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- В базе хранится только sha256 от токена: утечка таблицы не даёт
+-- воспользоваться ещё не использованными ссылками
+CREATE TABLE password_resets
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT                     NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_resets_user_id ON password_resets(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS password_resets;
+-- +goose StatementEnd
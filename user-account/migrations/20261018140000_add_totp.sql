@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Второй фактор включён, когда confirmed_at заполнен. last_used_step - номер
+-- последнего принятого 30-секундного окна, чтобы один код нельзя было
+-- предъявить дважды.
+CREATE TABLE user_totp
+(
+    user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         TEXT   NOT NULL,
+    confirmed_at   TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE recovery_codes
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash  TEXT NOT NULL,
+    used_at    TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Счётчики неудачных входов. key - "account:<email>", "ip:<адрес>" или
+-- "2fa:<user_id>"; после успешного входа строка аккаунта удаляется.
+CREATE TABLE login_attempts
+(
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER                  NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until    TIMESTAMP WITH TIME ZONE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS login_attempts;
+-- +goose StatementEnd
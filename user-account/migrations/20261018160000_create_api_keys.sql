@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Ключи для скриптов и интеграций. Сам ключ показывается один раз при
+-- создании, в базе - только sha256 от него и короткий префикс для списка.
+-- scopes - права через пробел, ключ не может больше своего владельца.
+CREATE TABLE api_keys
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         TEXT                     NOT NULL,
+    prefix       TEXT                     NOT NULL,
+    key_hash     TEXT                     NOT NULL UNIQUE,
+    scopes       TEXT                     NOT NULL DEFAULT '',
+    expires_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
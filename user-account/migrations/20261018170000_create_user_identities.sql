@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Учётные записи внешних провайдеров OpenID Connect, привязанные к
+-- пользователю. subject - claim sub из ID-токена, уникален в пределах
+-- провайдера; email - адрес, под которым провайдер знал пользователя
+-- на момент привязки.
+CREATE TABLE user_identities
+(
+    id            UUID PRIMARY KEY,
+    user_id       UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    provider      TEXT                     NOT NULL,
+    subject       TEXT                     NOT NULL,
+    email         TEXT                     NOT NULL DEFAULT '',
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT user_identities_provider_subject_key UNIQUE (provider, subject),
+    CONSTRAINT user_identities_user_id_provider_key UNIQUE (user_id, provider)
+);
+
+-- Незавершённые входы через провайдера: между редиректом и возвратом
+-- пользователя здесь лежат nonce и PKCE code_verifier. Запись удаляется
+-- при возврате, поэтому state одноразовый.
+CREATE TABLE oidc_login_states
+(
+    state_hash    TEXT PRIMARY KEY,
+    provider      TEXT                     NOT NULL,
+    nonce         TEXT                     NOT NULL,
+    code_verifier TEXT                     NOT NULL,
+    expires_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS oidc_login_states;
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
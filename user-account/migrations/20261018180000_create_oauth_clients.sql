@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Клиенты сервера авторизации: соседние сервисы и фронтенды. Секрет
+-- показывается один раз при регистрации, в базе - только sha256 от него.
+-- У публичных клиентов (SPA, мобильные приложения) секрета нет, им
+-- обязателен PKCE. redirect_uris, grant_types и scopes - через пробел.
+CREATE TABLE oauth_clients
+(
+    id            UUID PRIMARY KEY,
+    name          TEXT NOT NULL,
+    secret_hash   TEXT NOT NULL DEFAULT '',
+    redirect_uris TEXT NOT NULL DEFAULT '',
+    grant_types   TEXT NOT NULL DEFAULT '',
+    scopes        TEXT NOT NULL DEFAULT '',
+    created_by    UUID REFERENCES users (id) ON DELETE SET NULL,
+    revoked_at    TIMESTAMP WITH TIME ZONE,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Выданные, но ещё не обменянные коды авторизации. Запись удаляется при
+-- обмене, поэтому код одноразовый.
+CREATE TABLE oauth_authorization_codes
+(
+    code_hash      TEXT PRIMARY KEY,
+    client_id      UUID                     NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+    user_id        UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    redirect_uri   TEXT                     NOT NULL,
+    scopes         TEXT                     NOT NULL DEFAULT '',
+    nonce          TEXT                     NOT NULL DEFAULT '',
+    code_challenge TEXT                     NOT NULL DEFAULT '',
+    expires_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO permissions (name, description)
+VALUES ('oauth_clients:manage', 'Register and revoke OAuth clients');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'oauth_clients:manage');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'oauth_clients:manage';
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_clients;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Организации (рабочие пространства): клиенты сервиса не должны видеть
+-- пользователей друг друга. Роль в организации (owner, admin, member) не
+-- связана с глобальными ролями из user_roles.
+CREATE TABLE organizations
+(
+    id         UUID PRIMARY KEY,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE organization_members
+(
+    organization_id UUID NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id         UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role            TEXT NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+-- Активная организация сессии переживает ротацию refresh-токена, а
+-- API-ключ действует в организации, в которой его выпустили
+ALTER TABLE sessions ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE SET NULL;
+ALTER TABLE api_keys ADD COLUMN organization_id UUID REFERENCES organizations (id) ON DELETE CASCADE;
+
+-- До появления организаций все пользователи видели друг друга: переносим
+-- их в одну общую организацию, администраторы становятся её владельцами
+INSERT INTO organizations (id, name)
+SELECT gen_random_uuid(), 'Default'
+WHERE EXISTS (SELECT 1 FROM users);
+
+INSERT INTO organization_members (organization_id, user_id, role)
+SELECT o.id,
+       u.id,
+       CASE WHEN EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id AND ur.role = 'admin')
+            THEN 'owner' ELSE 'member' END
+FROM users u
+         CROSS JOIN organizations o;
+
+UPDATE api_keys SET organization_id = (SELECT id FROM organizations LIMIT 1);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_keys DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS organization_id;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Приглашения в организацию. Роль назначается заранее и выдаётся при
+-- принятии приглашения. Сама ссылка подписана, в базе хранится её состояние.
+CREATE TABLE organization_invitations
+(
+    id              UUID PRIMARY KEY,
+    organization_id UUID                     NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    email           TEXT                     NOT NULL,
+    role            TEXT                     NOT NULL CHECK (role IN ('owner', 'admin', 'member')),
+    invited_by      UUID REFERENCES users (id) ON DELETE SET NULL,
+    expires_at      TIMESTAMP WITH TIME ZONE NOT NULL,
+    accepted_at     TIMESTAMP WITH TIME ZONE,
+    declined_at     TIMESTAMP WITH TIME ZONE,
+    revoked_at      TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- На один адрес в организации действует не больше одного приглашения:
+-- повторное приглашение отзывает предыдущее
+CREATE UNIQUE INDEX organization_invitations_pending_email_key
+    ON organization_invitations (organization_id, email)
+    WHERE accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS organization_invitations;
+-- +goose StatementEnd
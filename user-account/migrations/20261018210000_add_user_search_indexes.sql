@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Поиск пользователей: триграммы находят части ников и адреса с опечатками,
+-- полнотекстовый индекс - слова ника и адреса по префиксу. Выражения
+-- индексов должны совпадать с выражениями в запросе поиска.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS users_nickname_trgm_idx ON users USING GIN (LOWER(nickname) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_email_trgm_idx ON users USING GIN (LOWER(email) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS users_search_document_idx ON users
+    USING GIN (to_tsvector('simple', nickname || ' ' || translate(email, '@.-_+', '     ')));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_search_document_idx;
+DROP INDEX IF EXISTS users_email_trgm_idx;
+DROP INDEX IF EXISTS users_nickname_trgm_idx;
+-- +goose StatementEnd
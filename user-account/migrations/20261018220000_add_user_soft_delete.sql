@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Мягкое удаление: удалённый пользователь скрыт из всех выборок, но его
+-- можно восстановить, пока фоновая очистка не удалила его окончательно.
+-- Адрес и ник до окончательного удаления остаются занятыми, чтобы
+-- восстановление не упиралось в уникальность.
+ALTER TABLE users
+    ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_deleted_at_idx;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Статус аккаунта: suspended - административная приостановка (например,
+-- уволившийся сотрудник), locked - блокировка. Оба закрывают вход и
+-- отключают выданные токены; со сроком status_expires_at статус снимается
+-- сам. Каждая смена статуса пишется в историю.
+ALTER TABLE users
+    ADD COLUMN status            TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'suspended', 'locked')),
+    ADD COLUMN status_reason     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN status_expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE user_status_history
+(
+    id         UUID PRIMARY KEY,
+    user_id    UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status     TEXT                     NOT NULL CHECK (status IN ('active', 'suspended', 'locked')),
+    reason     TEXT                     NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    changed_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_status_history_user_idx ON user_status_history (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_status_history;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS status_expires_at,
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS status;
+-- +goose StatementEnd
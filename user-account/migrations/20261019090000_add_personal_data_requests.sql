@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Запросы субъекта персональных данных (GDPR, 152-ФЗ). Выгрузка собирается
+-- фоновой задачей в ZIP-архив, который хранится до expires_at. Стёртый
+-- пользователь остаётся строкой с обезличенными адресом и ником, чтобы
+-- ссылки на него из истории и журналов не повисли; erased_at отличает его
+-- от просто удалённого - такого не восстановить.
+ALTER TABLE users
+    ADD COLUMN erased_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE data_exports
+(
+    id           UUID PRIMARY KEY,
+    user_id      UUID                     NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    status       TEXT                     NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+    archive      BYTEA,
+    error        TEXT                     NOT NULL DEFAULT '',
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    started_at   TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS data_exports_user_idx ON data_exports (user_id, created_at);
+CREATE INDEX IF NOT EXISTS data_exports_queue_idx ON data_exports (created_at) WHERE status IN ('pending', 'processing');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS data_exports;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS erased_at;
+-- +goose StatementEnd
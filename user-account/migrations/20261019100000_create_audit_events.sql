@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Журнал событий безопасности: входы, смены паролей, удаления, выдача
+-- ролей. Записи только дописываются, изменить или удалить их не дают
+-- триггеры. Каждая запись хранит хэш предыдущей (prev_hash) и свой (hash),
+-- поэтому правка или удаление записи в обход триггеров рвёт цепочку и
+-- видна при проверке. actor_id и target_id не ссылаются на users: журнал
+-- переживает окончательное удаление пользователей.
+CREATE TABLE audit_events
+(
+    id         BIGINT PRIMARY KEY,
+    actor_id   UUID,
+    target_id  UUID,
+    action     TEXT                     NOT NULL,
+    ip_address TEXT                     NOT NULL DEFAULT '',
+    user_agent TEXT                     NOT NULL DEFAULT '',
+    outcome    TEXT                     NOT NULL CHECK (outcome IN ('success', 'failure')),
+    metadata   JSONB                    NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash  TEXT                     NOT NULL,
+    hash       TEXT                     NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events (actor_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_target_idx ON audit_events (target_id, id);
+CREATE INDEX IF NOT EXISTS audit_events_action_idx ON audit_events (action, id);
+CREATE INDEX IF NOT EXISTS audit_events_created_idx ON audit_events (created_at);
+
+CREATE FUNCTION audit_events_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_no_change
+    BEFORE UPDATE OR DELETE
+    ON audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE
+    ON audit_events
+    FOR EACH STATEMENT
+EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO permissions (name, description)
+VALUES ('audit:read', 'View and verify the security audit log');
+
+INSERT INTO role_permissions (role, permission)
+VALUES ('admin', 'audit:read');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'audit:read';
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+-- +goose StatementEnd
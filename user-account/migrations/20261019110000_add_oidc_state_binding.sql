@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Привязка незавершённого входа к браузеру, который его начал: хэш
+-- случайного значения из cookie. Без неё чужой state с кодом можно
+-- подсунуть жертве и залогинить её в аккаунт атакующего. Начатые до
+-- миграции входы без привязки не завершить, их проще удалить.
+DELETE FROM oidc_login_states;
+
+ALTER TABLE oidc_login_states
+    ADD COLUMN binding_hash TEXT NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE oidc_login_states
+    DROP COLUMN IF EXISTS binding_hash;
+-- +goose StatementEnd